@@ -1,7 +1,61 @@
 package main
 
-import "github.com/edy4c7/works-uploader/internal/wu"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/edy4c7/works-uploader/internal/config"
+	"github.com/edy4c7/works-uploader/internal/wu"
+)
+
+const usage = `usage:
+  wu [flags]                          start the server
+  wu migrate up|down|status [flags]   manage the database schema
+  wu verify [flags]                   re-hash stored files and report mismatches
+
+run "wu -h" to list flags`
 
 func main() {
-	wu.Run()
+	args := os.Args[1:]
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+
+		conf := loadConfig(args[2:])
+		if err := wu.Migrate(conf, args[1], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "verify" {
+		conf := loadConfig(args[1:])
+		if err := wu.Verify(conf, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	conf := loadConfig(args)
+	if err := wu.Run(conf); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadConfig(args []string) *config.Config {
+	conf, err := config.Load(args, os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return conf
 }
@@ -0,0 +1,23 @@
+package beans
+
+import "github.com/edy4c7/works-uploader/internal/common/constants"
+
+// CollectionFormBean は、コレクションの登録・更新フォームを表す
+type CollectionFormBean struct {
+	Title       string `json:"title" binding:"required,max=40"`
+	Description string `json:"description" binding:"max=200"`
+	// Visibility は、コレクションの公開範囲。登録時に省略した場合は公開にし、更新時に省略した場合は変更しない。
+	Visibility constants.Visibility `json:"visibility" binding:"omitempty,oneof=1 2 3"`
+	// CoverWorkID は、表紙にする作品のID。コレクションに含まれる作品のみ指定できる。省略した場合は表紙なしにする。
+	CoverWorkID *uint64 `json:"coverWorkId"`
+}
+
+// CollectionWorkFormBean は、コレクションに追加する作品を表す
+type CollectionWorkFormBean struct {
+	WorkID uint64 `json:"workId" binding:"required"`
+}
+
+// CollectionOrderFormBean は、コレクションの作品の並び順を表す。指定しなかった作品は、現在の順でその後に並べる。
+type CollectionOrderFormBean struct {
+	WorkIDs []uint64 `json:"workIds" binding:"required"`
+}
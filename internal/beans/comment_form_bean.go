@@ -0,0 +1,8 @@
+package beans
+
+// CommentFormBean は、コメントの投稿・編集フォームを表す。本文の検証は、エラーメッセージを翻訳するためサービスで行う。
+type CommentFormBean struct {
+	Body string `json:"body"`
+	// ParentID は、返信先のコメントのID。投稿時のみ指定でき、編集時は無視する。
+	ParentID *uint64 `json:"parentId"`
+}
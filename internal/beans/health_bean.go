@@ -0,0 +1,6 @@
+package beans
+
+type HealthBean struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
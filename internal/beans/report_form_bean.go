@@ -0,0 +1,18 @@
+package beans
+
+import "github.com/edy4c7/works-uploader/internal/common/constants"
+
+// ReportFormBean は、作品の通報フォームを表す
+type ReportFormBean struct {
+	Reason constants.ReportReason `json:"reason" binding:"required,oneof=1 2 3 4 5"`
+	// Note は、通報の補足。省略できる。
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ModerationFormBean は、モデレーターの操作フォームを表す。
+// Action は、作品に対しては hide, unhide, remove, dismiss、ユーザーに対しては suspend, unsuspend のいずれか。
+type ModerationFormBean struct {
+	Action string `json:"action" binding:"required"`
+	// Note は、操作の理由。監査のため記録する。
+	Note string `json:"note" binding:"max=500"`
+}
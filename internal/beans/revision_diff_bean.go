@@ -0,0 +1,15 @@
+package beans
+
+// RevisionDiffBean は、作品の2つのバージョンの間で変更された項目を表す
+type RevisionDiffBean struct {
+	From    uint               `json:"from"`
+	To      uint               `json:"to"`
+	Changes []*FieldChangeBean `json:"changes"`
+}
+
+// FieldChangeBean は、変更された項目と、変更前後の値を表す
+type FieldChangeBean struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
@@ -0,0 +1,15 @@
+package beans
+
+// StagedFileBean は、ストレージの一時領域にアップロード済みで、公開前のファイルを表す
+type StagedFileBean struct {
+	Key      string `form:"-"`
+	Filename string `form:"-"`
+	// Size は、保存したバイト数。画像のメタデータを取り除いた場合は、受信したバイト数と異なる。
+	Size int64 `form:"-"`
+	// SHA256 は、保存した内容のSHA-256を16進数で表したもの
+	SHA256 string `form:"-"`
+	// ContentType は、ファイルの内容から判定したContent-Type
+	ContentType string `form:"-"`
+	// UploadID は、再開可能なアップロードで受信したファイルの場合に、そのアップロードのIDを表す
+	UploadID string `form:"-"`
+}
@@ -0,0 +1,20 @@
+package beans
+
+import "time"
+
+// UploadSessionFormBean は、ストレージへ直接アップロードするファイルの申告内容を表す
+type UploadSessionFormBean struct {
+	Filename    string `json:"filename" binding:"required,max=255"`
+	ContentType string `json:"contentType" binding:"required"`
+	Length      int64  `json:"length" binding:"min=0"`
+}
+
+// UploadSessionBean は、作成したアップロードと、クライアントがファイルを送信する署名付きリクエストを表す。
+// 送信後、IDを作品の登録時に thumbnailUploadId / contentUploadId で指定する。
+type UploadSessionBean struct {
+	ID        string            `json:"id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
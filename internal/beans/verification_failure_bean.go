@@ -0,0 +1,10 @@
+package beans
+
+// VerificationFailureBean は、保存されている内容が公開時と一致しないファイルを表す
+type VerificationFailureBean struct {
+	Key string
+	// Reason は、"missing"、"size"、"checksum" のいずれか
+	Reason   string
+	Expected string
+	Actual   string
+}
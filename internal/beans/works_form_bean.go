@@ -1,16 +1,51 @@
 package beans
 
 import (
-	"mime/multipart"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/common/constants"
 )
 
+// WorksFormBean は、作品の登録フォームを表す。URLの作品ではTitleを省略でき、リンク先のプレビューのタイトルを使用する。
 type WorksFormBean struct {
-	Type        constants.WorkType    `form:"type" binding:"required"`
-	Title       string                `form:"title" binding:"required,max=40"`
-	Description string                `form:"description" binding:"max=200"`
-	ContentURL  string                `form:"url" binding:"required_if=Type 1,omitempty,url"`
-	Thumbnail   *multipart.FileHeader `form:"thumbnail" binding:"required_if=Type 2"`
-	Content     *multipart.FileHeader `form:"content" binding:"required_if=Type 2"`
+	Type        constants.WorkType `form:"type" binding:"required,oneof=1 2"`
+	Title       string             `form:"title" binding:"required_if=Type 2,max=40"`
+	Description string             `form:"description" binding:"max=200"`
+	ContentURL  string             `form:"url" binding:"required_if=Type 1,omitempty,url"`
+	// Tags は、作品に付けるタグ。項目を繰り返すか、カンマで区切って複数指定する。
+	Tags []string `form:"tags"`
+	// Visibility は、作品の公開範囲。省略した場合は公開にする。
+	Visibility constants.Visibility `form:"visibility" binding:"omitempty,oneof=1 2 3"`
+	// Status は、作品の公開状況。省略した場合はすぐに公開する。公開予定の場合は、PublishAtに公開する日時を指定する。
+	Status    constants.WorkStatus `form:"status" binding:"omitempty,oneof=1 2 3"`
+	PublishAt time.Time            `form:"publishAt" time_format:"2006-01-02T15:04:05Z07:00"`
+	// ThumbnailUploadID, ContentUploadID は、ファイルの代わりに指定する完了済みのアップロードのID
+	ThumbnailUploadID string `form:"thumbnailUploadId"`
+	ContentUploadID   string `form:"contentUploadId"`
+	// Thumbnail, Content は、フォーム項目 "thumbnail", "content" を受信しながら一時領域に保存したファイル、
+	// またはアップロードのIDで指定されたファイル。Contentが画像の場合は、Thumbnailを省略できる。
+	Thumbnail *StagedFileBean `form:"-"`
+	Content   *StagedFileBean `form:"-" binding:"required_if=Type 2"`
+}
+
+// WorkUpdateFormBean は、作品の更新フォームを表す。作品の種類と公開範囲は変更できない。
+// タイトル、公開状況、ファイルとリンク先を省略した場合は、現在のものを引き続き使用する。
+type WorkUpdateFormBean struct {
+	// Version は、更新する作品を取得した時のバージョン。他の更新と競合していないことを確認する。
+	Version     uint   `form:"version" binding:"required"`
+	Title       string `form:"title" binding:"max=40"`
+	Description string `form:"description" binding:"max=200"`
+	ContentURL  string `form:"url" binding:"omitempty,url"`
+	// Tags は、作品のタグ。現在のタグを置き換えるため、省略した場合はタグを外す。
+	Tags []string `form:"tags"`
+	// ThumbnailUploadID, ContentUploadID は、ファイルの代わりに指定する完了済みのアップロードのID
+	ThumbnailUploadID string `form:"thumbnailUploadId"`
+	ContentUploadID   string `form:"contentUploadId"`
+	// Thumbnail, Content は、WorksFormBeanと同様に一時領域に保存した差し替えるファイル
+	Thumbnail *StagedFileBean `form:"-"`
+	Content   *StagedFileBean `form:"-"`
+	// Status, PublishAt は、下書きと公開予定の作品の公開状況。WorksFormBeanと同様に指定する。
+	// 公開済みの作品は、公開前の状態に戻せない。
+	Status    constants.WorkStatus `form:"status" binding:"omitempty,oneof=1 2 3"`
+	PublishAt time.Time            `form:"publishAt" time_format:"2006-01-02T15:04:05Z07:00"`
 }
@@ -12,4 +12,110 @@ type ActivityType int
 const (
 	ActivityAdded ActivityType = iota + 1
 	ActivityUpdated
+	// ActivityLinkBroken は、URLの作品のリンク先に一定期間接続できていないことを作者に知らせる
+	ActivityLinkBroken
+	// ActivityRestored は、削除した作品をゴミ箱から復元したことを表す
+	ActivityRestored
+	// ActivityAddedToCollection は、作品をコレクションに追加したことを表す
+	ActivityAddedToCollection
+	// ActivityFavorited は、作品をお気に入りに登録したことを表す。お気に入りを解除すると削除する。
+	ActivityFavorited
+	// ActivityCommented は、作品にコメントを投稿したことを表す。コメントを削除すると削除する。
+	ActivityCommented
+	// ActivityReplied は、コメントに返信したことを表す。返信を削除すると削除する。
+	ActivityReplied
+)
+
+// ScanStatus は、作品のファイルのマルウェアの検査状況を表す
+type ScanStatus int
+
+const (
+	// ScanPending は、検査が終わっていないことを表す。作品は公開しない。
+	ScanPending ScanStatus = iota + 1
+	// ScanClean は、検出しなかった、または検査の対象でないことを表す
+	ScanClean
+	// ScanInfected は、マルウェアを検出したことを表す。作品は公開しない。
+	ScanInfected
+)
+
+// Visibility は、作品を閲覧できる範囲を表す
+type Visibility int
+
+const (
+	// VisibilityPublic は、誰でも閲覧でき、一覧にも表示することを表す
+	VisibilityPublic Visibility = iota + 1
+	// VisibilityUnlisted は、URLを知っていれば閲覧できるが、作者以外の一覧には表示しないことを表す
+	VisibilityUnlisted
+	// VisibilityPrivate は、作者のみが閲覧できることを表す。ファイルは公開せず、署名付きURLで配信する。
+	VisibilityPrivate
+)
+
+// WorkStatus は、作品の公開状況を表す
+type WorkStatus int
+
+const (
+	// WorkPublished は、公開済みであることを表す。公開予定だった作品のPublishAtは公開した日時。
+	WorkPublished WorkStatus = iota + 1
+	// WorkDraft は、下書きであることを表す。作者以外には表示しない。
+	WorkDraft
+	// WorkScheduled は、PublishAtに公開する予定であることを表す。公開するまで作者以外には表示しない。
+	WorkScheduled
+)
+
+// WorkOrder は、作品の一覧の並び順を表す
+type WorkOrder int
+
+const (
+	// OrderDefault は、既定の並び順を表す
+	OrderDefault WorkOrder = iota
+	// OrderMostFavorited は、お気に入りに登録したユーザーの多い順を表す。同数の場合は新しい作品から並べる。
+	OrderMostFavorited
+)
+
+// ReportReason は、作品を通報する理由の分類を表す
+type ReportReason int
+
+const (
+	// ReportSpam は、宣伝や無関係な内容の繰り返しを表す
+	ReportSpam ReportReason = iota + 1
+	// ReportHarassment は、嫌がらせや差別を表す
+	ReportHarassment
+	// ReportInappropriate は、性的または暴力的な内容を表す
+	ReportInappropriate
+	// ReportCopyright は、著作権などの権利の侵害を表す
+	ReportCopyright
+	// ReportOther は、その他の理由を表す。内容は通報のメモに記入する。
+	ReportOther
+)
+
+// ReportStatus は、通報の対応状況を表す
+type ReportStatus int
+
+const (
+	// ReportOpen は、モデレーターが対応していないことを表す。モデレーションの待ち行列に表示する。
+	ReportOpen ReportStatus = iota + 1
+	// ReportResolved は、作品を非表示または削除して対応したことを表す
+	ReportResolved
+	// ReportDismissed は、対応の必要がないと判断したことを表す
+	ReportDismissed
+)
+
+// ModerationActionType は、モデレーターが行った操作の種別を表す
+type ModerationActionType int
+
+const (
+	// ModerationHide は、作品を作者以外に表示しないようにしたことを表す
+	ModerationHide ModerationActionType = iota + 1
+	// ModerationUnhide は、非表示にした作品を再び表示するようにしたことを表す
+	ModerationUnhide
+	// ModerationRemove は、作品を非表示にしてゴミ箱に移したことを表す
+	ModerationRemove
+	// ModerationDismiss は、作品の通報を対応不要として閉じたことを表す
+	ModerationDismiss
+	// ModerationSuspend は、ユーザーのアカウントを停止したことを表す
+	ModerationSuspend
+	// ModerationUnsuspend は、ユーザーのアカウントの停止を解除したことを表す
+	ModerationUnsuspend
+	// ModerationAutoHide は、通報の数がしきい値に達した作品を自動で非表示にしたことを表す。モデレーターはいない。
+	ModerationAutoHide
 )
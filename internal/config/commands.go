@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"gorm.io/gorm"
+)
+
+// InitIntegrityService は、"wu verify" で使用するIntegrityServiceを生成する
+func InitIntegrityService(db *gorm.DB, conf *Config) services.IntegrityService {
+	blobsRepo := infrastructures.NewBlobsRepositoryImpl(db)
+	fileUploader := newStorageClient(&conf.Storage)
+
+	return services.NewIntegrityServiceImpl(blobsRepo, fileUploader)
+}
@@ -0,0 +1,625 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config は、アプリケーションの設定を表す。
+// 値は デフォルト値 < 設定ファイル < 環境変数 < コマンドライン引数 の順に上書きされる。
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Image    ImageConfig    `yaml:"image"`
+	Scan     ScanConfig     `yaml:"scan"`
+	// LinkPreview は、URLの作品のリンク先からのプレビューの取得に関する設定
+	LinkPreview LinkPreviewConfig `yaml:"linkPreview"`
+	// LinkCheck は、URLの作品のリンク切れの確認に関する設定
+	LinkCheck LinkCheckConfig `yaml:"linkCheck"`
+	// Trash は、削除した作品をごみ箱に残す期間の設定
+	Trash TrashConfig `yaml:"trash"`
+	// Moderation は、作品の通報とモデレーションに関する設定
+	Moderation ModerationConfig `yaml:"moderation"`
+}
+
+type ServerConfig struct {
+	Port              int           `yaml:"port"`
+	PublicDir         string        `yaml:"publicDir"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout"`
+	// ShutdownDelay は、停止の開始から新しい接続の受付を止めるまでの待ち時間。
+	// この間、readinessはリクエストを受け付けられないことを返し、ロードバランサーが振り分けを止められるようにする。
+	ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type DatabaseConfig struct {
+	// Driver は、"postgres" または "sqlite"
+	Driver string `yaml:"driver"`
+	// Path は、SQLiteのデータベースファイルのパス。":memory:"を指定するとメモリ上に作成する。
+	Path               string `yaml:"path"`
+	Host               string `yaml:"host"`
+	Port               string `yaml:"port"`
+	User               string `yaml:"user"`
+	Password           string `yaml:"password"`
+	Name               string `yaml:"name"`
+	ConnectMaxAttempts int    `yaml:"connectMaxAttempts"`
+	// MigrateOnStart は、サーバー起動時に未適用のマイグレーションを適用するかを表す
+	MigrateOnStart bool `yaml:"migrateOnStart"`
+}
+
+type AuthConfig struct {
+	Audience string `yaml:"audience"`
+	Issuer   string `yaml:"issuer"`
+	JWKSURL  string `yaml:"jwksUrl"`
+	// AdminScope は、管理者のアクセストークンが持つscope。空の場合は管理者を認めない。
+	AdminScope string `yaml:"adminScope"`
+	// ModeratorScope は、モデレーターのアクセストークンが持つscope。空の場合はモデレーターを認めない。
+	ModeratorScope string `yaml:"moderatorScope"`
+}
+
+const (
+	StorageDriverS3    = "s3"
+	StorageDriverLocal = "local"
+)
+
+type StorageConfig struct {
+	// Driver は、"s3" または "local"
+	Driver    string `yaml:"driver"`
+	Bucket    string `yaml:"bucket"`
+	CDNDomain string `yaml:"cdnDomain"`
+	// Endpoint は、MinIOなどS3互換のストレージを使用する場合にそのURLを指定する
+	Endpoint string `yaml:"endpoint"`
+	// Path は、driver: local の場合にファイルを保存するディレクトリ
+	Path string `yaml:"path"`
+	// SigningKey は、driver: local の場合に直接アップロードするURLの署名に使用する鍵
+	SigningKey string `yaml:"signingKey"`
+	// MaxThumbnailSize, MaxContentSize は、アップロードできるファイルの最大バイト数
+	MaxThumbnailSize int64 `yaml:"maxThumbnailSize"`
+	MaxContentSize   int64 `yaml:"maxContentSize"`
+	// AllowedThumbnailTypes, AllowedContentTypes は、ファイルの内容から判定したContent-Typeの許可リスト。
+	// "image/*" のようにサブタイプを省略できる。
+	AllowedThumbnailTypes []string `yaml:"allowedThumbnailTypes"`
+	AllowedContentTypes   []string `yaml:"allowedContentTypes"`
+	// UploadExpiration は、再開可能なアップロードを作成してから作品に使用するまでの期限
+	UploadExpiration time.Duration `yaml:"uploadExpiration"`
+	// PresignExpiration は、直接アップロードするための署名付きURLの有効期間
+	PresignExpiration time.Duration `yaml:"presignExpiration"`
+	// PrivateURLExpiration は、非公開の作品のファイルを閲覧するための署名付きURLの有効期間
+	PrivateURLExpiration time.Duration `yaml:"privateURLExpiration"`
+	// Deduplicate は、内容のSHA-256を公開後のキーにして、同じ内容のファイルを共有するか
+	Deduplicate bool `yaml:"deduplicate"`
+}
+
+// ImageConfig は、アップロードされた画像の加工に関する設定を表す
+type ImageConfig struct {
+	// VariantWidths は、縮小した画像を生成する幅。サムネイルを省略した場合は、最も小さいものを使用する。
+	VariantWidths []int `yaml:"variantWidths"`
+	// MaxPixels は、加工する画像の最大画素数。超える画像は縮小した画像を生成しない。
+	MaxPixels int64 `yaml:"maxPixels"`
+	// StripMetadata は、公開前に画像からEXIFなどのメタデータを取り除くか
+	StripMetadata bool `yaml:"stripMetadata"`
+}
+
+const (
+	ScanDriverNone  = "none"
+	ScanDriverClamd = "clamd"
+)
+
+// ScanConfig は、アップロードされたファイルのマルウェアの検査に関する設定を表す
+type ScanConfig struct {
+	// Driver は、"none" または "clamd"。"none" の場合は検査しない。
+	Driver string `yaml:"driver"`
+	// ClamdAddress は、driver: clamd の場合に接続するアドレス。
+	// "tcp://host:port" または "unix:///path/to/clamd.sock" の形式で指定する。
+	ClamdAddress string `yaml:"clamdAddress"`
+	// Timeout は、clamdへの接続と、送受信が進まない場合の待ち時間
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// LinkPreviewConfig は、URLの作品のリンク先からタイトル、説明、画像を取得する設定を表す
+type LinkPreviewConfig struct {
+	// Enabled は、リンク先からプレビューを取得するか
+	Enabled bool `yaml:"enabled"`
+	// Timeout は、ページや画像を1回取得する全体の制限時間
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxSize は、リンク先のHTMLとして読み込む最大バイト数。画像はサムネイルの最大バイト数で制限する。
+	MaxSize int64 `yaml:"maxSize"`
+	// MaxRedirects は、追跡するリダイレクトの最大回数
+	MaxRedirects int `yaml:"maxRedirects"`
+}
+
+// LinkCheckConfig は、URLの作品のリンク先に到達できるかを定期的に確認する設定を表す
+type LinkCheckConfig struct {
+	// Enabled は、バックグラウンドでリンク先を確認するか
+	Enabled bool `yaml:"enabled"`
+	// Interval は、同じ作品のリンク先を確認する間隔
+	Interval time.Duration `yaml:"interval"`
+	// Timeout は、1回の確認の制限時間
+	Timeout time.Duration `yaml:"timeout"`
+	// Concurrency は、同時に確認する作品の数
+	Concurrency int `yaml:"concurrency"`
+	// HostInterval は、同じホストへのリクエストの最小の間隔
+	HostInterval time.Duration `yaml:"hostInterval"`
+	// BrokenAfter は、確認に失敗し続けた作品をリンク切れとし、作者に知らせるまでの期間
+	BrokenAfter time.Duration `yaml:"brokenAfter"`
+}
+
+// TrashConfig は、削除した作品を復元できるようにごみ箱に残す設定を表す
+type TrashConfig struct {
+	// Retention は、削除した作品を完全に削除するまでの期間
+	Retention time.Duration `yaml:"retention"`
+}
+
+// ModerationConfig は、通報された作品のモデレーションに関する設定を表す
+type ModerationConfig struct {
+	// ReportThreshold は、作品を自動で非表示にする未対応の通報の数。0の場合は自動で非表示にしない。
+	ReportThreshold int `yaml:"reportThreshold"`
+}
+
+// LookupEnvFunc は、環境変数の取得方法を表す。通常はos.LookupEnvを指定する。
+type LookupEnvFunc func(string) (string, bool)
+
+// ValidationError は、設定値の検証で見つかった問題をまとめて保持する
+type ValidationError struct {
+	Problems []string
+}
+
+func (r *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(r.Problems, "\n  ")
+}
+
+// Default は、デフォルト値を設定したConfigを返す
+func Default() *Config {
+	publicDir := "public"
+	if wd, err := os.Getwd(); err == nil {
+		publicDir = filepath.Join(wd, "public")
+	}
+
+	return &Config{
+		Server: ServerConfig{
+			Port:      8000,
+			PublicDir: publicDir,
+			// 大きなファイルのアップロードを考慮し、ボディの読み書きには長めの時間を許容する
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Minute,
+			WriteTimeout:      30 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   5 * time.Minute,
+			ShutdownDelay:     5 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:             DriverPostgres,
+			Port:               "5432",
+			ConnectMaxAttempts: 10,
+			MigrateOnStart:     true,
+		},
+		Auth: AuthConfig{
+			AdminScope:     "admin:works",
+			ModeratorScope: "moderate:works",
+		},
+		Storage: StorageConfig{
+			Driver:           StorageDriverS3,
+			MaxThumbnailSize: 10 << 20,
+			MaxContentSize:   4 << 30,
+			AllowedThumbnailTypes: []string{
+				"image/png", "image/jpeg", "image/gif", "image/webp",
+			},
+			AllowedContentTypes: []string{
+				"image/*", "audio/*", "video/*", "application/pdf", "application/zip",
+			},
+			UploadExpiration:     24 * time.Hour,
+			PresignExpiration:    15 * time.Minute,
+			PrivateURLExpiration: 15 * time.Minute,
+		},
+		Image: ImageConfig{
+			VariantWidths: []int{320, 640, 1280},
+			MaxPixels:     50_000_000,
+			StripMetadata: true,
+		},
+		Scan: ScanConfig{
+			Driver:  ScanDriverNone,
+			Timeout: 30 * time.Second,
+		},
+		LinkPreview: LinkPreviewConfig{
+			Enabled:      true,
+			Timeout:      10 * time.Second,
+			MaxSize:      1 << 20,
+			MaxRedirects: 5,
+		},
+		LinkCheck: LinkCheckConfig{
+			Enabled:      true,
+			Interval:     24 * time.Hour,
+			Timeout:      10 * time.Second,
+			Concurrency:  4,
+			HostInterval: time.Second,
+			BrokenAfter:  72 * time.Hour,
+		},
+		Trash: TrashConfig{
+			Retention: 30 * 24 * time.Hour,
+		},
+		Moderation: ModerationConfig{
+			ReportThreshold: 5,
+		},
+	}
+}
+
+// setting は、1つの設定項目と、それに対応する環境変数名・コマンドライン引数名を表す
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(*Config, string) error
+}
+
+func stringSetting(env, flagName, usage string, field func(*Config) *string) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}}
+}
+
+func intSetting(env, flagName, usage string, field func(*Config) *int) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = i
+		return nil
+	}}
+}
+
+func int64Setting(env, flagName, usage string, field func(*Config) *int64) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = i
+		return nil
+	}}
+}
+
+func boolSetting(env, flagName, usage string, field func(*Config) *bool) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	}}
+}
+
+// stringsSetting は、カンマ区切りの値をリストとして設定する
+func stringsSetting(env, flagName, usage string, field func(*Config) *[]string) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		values := []string{}
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+		*field(c) = values
+		return nil
+	}}
+}
+
+// intsSetting は、カンマ区切りの整数をリストとして設定する
+func intsSetting(env, flagName, usage string, field func(*Config) *[]int) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		values := []int{}
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			i, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			values = append(values, i)
+		}
+		*field(c) = values
+		return nil
+	}}
+}
+
+func durationSetting(env, flagName, usage string, field func(*Config) *time.Duration) setting {
+	return setting{env: env, flag: flagName, usage: usage, set: func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}}
+}
+
+var settings = []setting{
+	intSetting("PORT", "port", "port to listen on", func(c *Config) *int { return &c.Server.Port }),
+	stringSetting("PUBLIC_DIR", "public-dir", "directory of the generated front-end", func(c *Config) *string { return &c.Server.PublicDir }),
+	durationSetting("SERVER_READ_HEADER_TIMEOUT", "read-header-timeout", "timeout for reading request headers", func(c *Config) *time.Duration { return &c.Server.ReadHeaderTimeout }),
+	durationSetting("SERVER_READ_TIMEOUT", "read-timeout", "timeout for reading a whole request", func(c *Config) *time.Duration { return &c.Server.ReadTimeout }),
+	durationSetting("SERVER_WRITE_TIMEOUT", "write-timeout", "timeout for writing a response", func(c *Config) *time.Duration { return &c.Server.WriteTimeout }),
+	durationSetting("SERVER_IDLE_TIMEOUT", "idle-timeout", "keep-alive idle timeout", func(c *Config) *time.Duration { return &c.Server.IdleTimeout }),
+	durationSetting("SERVER_SHUTDOWN_TIMEOUT", "shutdown-timeout", "time to wait for in-flight requests on shutdown", func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout }),
+	durationSetting("SERVER_SHUTDOWN_DELAY", "shutdown-delay", "time to report not ready before closing the listener on shutdown", func(c *Config) *time.Duration { return &c.Server.ShutdownDelay }),
+	stringSetting("DB_DRIVER", "db-driver", "database driver (postgres or sqlite)", func(c *Config) *string { return &c.Database.Driver }),
+	stringSetting("DB_PATH", "db-path", "database file path (sqlite)", func(c *Config) *string { return &c.Database.Path }),
+	stringSetting("DB_HOST", "db-host", "database host", func(c *Config) *string { return &c.Database.Host }),
+	stringSetting("DB_PORT", "db-port", "database port", func(c *Config) *string { return &c.Database.Port }),
+	stringSetting("DB_USER", "db-user", "database user", func(c *Config) *string { return &c.Database.User }),
+	stringSetting("DB_PASSWORD", "db-password", "database password", func(c *Config) *string { return &c.Database.Password }),
+	stringSetting("DB_SCHEMA", "db-name", "database name", func(c *Config) *string { return &c.Database.Name }),
+	intSetting("DB_CONNECT_MAX_ATTEMPTS", "db-connect-max-attempts", "number of connection attempts at startup", func(c *Config) *int { return &c.Database.ConnectMaxAttempts }),
+	boolSetting("DB_MIGRATE_ON_START", "db-migrate-on-start", "apply pending migrations at startup", func(c *Config) *bool { return &c.Database.MigrateOnStart }),
+	stringSetting("AUTH0_AUDIENCE", "auth-audience", "expected 'aud' claim", func(c *Config) *string { return &c.Auth.Audience }),
+	stringSetting("AUTH0_ISSUER", "auth-issuer", "expected 'iss' claim", func(c *Config) *string { return &c.Auth.Issuer }),
+	stringSetting("AUTH0_JWK", "auth-jwks-url", "URL of the JWKS", func(c *Config) *string { return &c.Auth.JWKSURL }),
+	stringSetting("AUTH_ADMIN_SCOPE", "auth-admin-scope", "scope of administrators' access tokens", func(c *Config) *string { return &c.Auth.AdminScope }),
+	stringSetting("AUTH_MODERATOR_SCOPE", "auth-moderator-scope", "scope of moderators' access tokens", func(c *Config) *string { return &c.Auth.ModeratorScope }),
+	stringSetting("STORAGE_DRIVER", "storage-driver", "storage for uploaded files (s3 or local)", func(c *Config) *string { return &c.Storage.Driver }),
+	stringSetting("S3_BUCKET", "s3-bucket", "S3 bucket for uploaded files", func(c *Config) *string { return &c.Storage.Bucket }),
+	stringSetting("CDN_DOMAIN", "cdn-domain", "domain which serves uploaded files", func(c *Config) *string { return &c.Storage.CDNDomain }),
+	stringSetting("S3_ENDPOINT", "s3-endpoint", "endpoint of an S3 compatible storage", func(c *Config) *string { return &c.Storage.Endpoint }),
+	stringSetting("STORAGE_PATH", "storage-path", "directory for uploaded files (local)", func(c *Config) *string { return &c.Storage.Path }),
+	stringSetting("STORAGE_SIGNING_KEY", "storage-signing-key", "key to sign direct upload URLs (local)", func(c *Config) *string { return &c.Storage.SigningKey }),
+	int64Setting("UPLOAD_MAX_THUMBNAIL_SIZE", "max-thumbnail-size", "maximum size of a thumbnail in bytes", func(c *Config) *int64 { return &c.Storage.MaxThumbnailSize }),
+	int64Setting("UPLOAD_MAX_CONTENT_SIZE", "max-content-size", "maximum size of a content file in bytes", func(c *Config) *int64 { return &c.Storage.MaxContentSize }),
+	stringsSetting("UPLOAD_ALLOWED_THUMBNAIL_TYPES", "allowed-thumbnail-types", "comma separated content types allowed for a thumbnail", func(c *Config) *[]string { return &c.Storage.AllowedThumbnailTypes }),
+	stringsSetting("UPLOAD_ALLOWED_CONTENT_TYPES", "allowed-content-types", "comma separated content types allowed for a content file", func(c *Config) *[]string { return &c.Storage.AllowedContentTypes }),
+	durationSetting("UPLOAD_EXPIRATION", "upload-expiration", "lifetime of a resumable upload", func(c *Config) *time.Duration { return &c.Storage.UploadExpiration }),
+	durationSetting("UPLOAD_PRESIGN_EXPIRATION", "upload-presign-expiration", "lifetime of a direct upload URL", func(c *Config) *time.Duration { return &c.Storage.PresignExpiration }),
+	durationSetting("STORAGE_PRIVATE_URL_EXPIRATION", "storage-private-url-expiration", "lifetime of a URL to view a file of a private work", func(c *Config) *time.Duration { return &c.Storage.PrivateURLExpiration }),
+	boolSetting("UPLOAD_DEDUPLICATE", "upload-deduplicate", "store identical files once under content-addressed keys", func(c *Config) *bool { return &c.Storage.Deduplicate }),
+	intsSetting("IMAGE_VARIANT_WIDTHS", "image-variant-widths", "comma separated widths of resized images", func(c *Config) *[]int { return &c.Image.VariantWidths }),
+	int64Setting("IMAGE_MAX_PIXELS", "image-max-pixels", "maximum number of pixels of an image to resize", func(c *Config) *int64 { return &c.Image.MaxPixels }),
+	boolSetting("IMAGE_STRIP_METADATA", "image-strip-metadata", "strip EXIF, XMP and IPTC metadata from uploaded images", func(c *Config) *bool { return &c.Image.StripMetadata }),
+	stringSetting("SCAN_DRIVER", "scan-driver", "malware scanner for uploaded files (none or clamd)", func(c *Config) *string { return &c.Scan.Driver }),
+	stringSetting("CLAMD_ADDRESS", "clamd-address", "address of clamd (tcp://host:port or unix:///path)", func(c *Config) *string { return &c.Scan.ClamdAddress }),
+	durationSetting("SCAN_TIMEOUT", "scan-timeout", "timeout for connecting to and talking with the scanner", func(c *Config) *time.Duration { return &c.Scan.Timeout }),
+	boolSetting("LINK_PREVIEW_ENABLED", "link-preview-enabled", "fetch title, description and image of URL works", func(c *Config) *bool { return &c.LinkPreview.Enabled }),
+	durationSetting("LINK_PREVIEW_TIMEOUT", "link-preview-timeout", "timeout for fetching a linked page or its image", func(c *Config) *time.Duration { return &c.LinkPreview.Timeout }),
+	int64Setting("LINK_PREVIEW_MAX_SIZE", "link-preview-max-size", "maximum size of a linked page to read in bytes", func(c *Config) *int64 { return &c.LinkPreview.MaxSize }),
+	intSetting("LINK_PREVIEW_MAX_REDIRECTS", "link-preview-max-redirects", "maximum number of redirects to follow", func(c *Config) *int { return &c.LinkPreview.MaxRedirects }),
+	boolSetting("LINK_CHECK_ENABLED", "link-check-enabled", "periodically check whether links of URL works are alive", func(c *Config) *bool { return &c.LinkCheck.Enabled }),
+	durationSetting("LINK_CHECK_INTERVAL", "link-check-interval", "interval between checks of the same link", func(c *Config) *time.Duration { return &c.LinkCheck.Interval }),
+	durationSetting("LINK_CHECK_TIMEOUT", "link-check-timeout", "timeout for checking a link", func(c *Config) *time.Duration { return &c.LinkCheck.Timeout }),
+	intSetting("LINK_CHECK_CONCURRENCY", "link-check-concurrency", "number of links to check concurrently", func(c *Config) *int { return &c.LinkCheck.Concurrency }),
+	durationSetting("LINK_CHECK_HOST_INTERVAL", "link-check-host-interval", "minimum interval between requests to the same host", func(c *Config) *time.Duration { return &c.LinkCheck.HostInterval }),
+	durationSetting("LINK_CHECK_BROKEN_AFTER", "link-check-broken-after", "how long a link keeps failing before it is flagged as broken", func(c *Config) *time.Duration { return &c.LinkCheck.BrokenAfter }),
+	durationSetting("TRASH_RETENTION", "trash-retention", "how long deleted works are kept in the trash", func(c *Config) *time.Duration { return &c.Trash.Retention }),
+	intSetting("MODERATION_REPORT_THRESHOLD", "moderation-report-threshold", "number of open reports that hides a work automatically (0 disables)", func(c *Config) *int { return &c.Moderation.ReportThreshold }),
+}
+
+const configFileEnv = "WU_CONFIG"
+const configFileFlag = "config"
+
+// Load は、設定ファイル、環境変数、コマンドライン引数から設定を読み込み、検証する
+func Load(args []string, lookupEnv LookupEnvFunc) (*Config, error) {
+	fs := flag.NewFlagSet("wu", flag.ContinueOnError)
+	configFile := fs.String(configFileFlag, "", fmt.Sprintf("path to a YAML config file (env %s)", configFileEnv))
+	flagValues := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flagValues[s.flag] = fs.String(s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	conf := Default()
+
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(configFileEnv)
+	}
+	if path != "" {
+		if err := loadFile(path, conf); err != nil {
+			return nil, err
+		}
+	}
+
+	var problems []string
+
+	for _, s := range settings {
+		if v, ok := lookupEnv(s.env); ok && v != "" {
+			if err := s.set(conf, v); err != nil {
+				problems = append(problems, fmt.Sprintf("env %s: %v", s.env, err))
+			}
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag != f.Name {
+				continue
+			}
+			if err := s.set(conf, *flagValues[s.flag]); err != nil {
+				problems = append(problems, fmt.Sprintf("flag -%s: %v", s.flag, err))
+			}
+		}
+	})
+
+	problems = append(problems, conf.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return conf, nil
+}
+
+func loadFile(path string, conf *Config) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(b, conf); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (r *Config) validate() []string {
+	var problems []string
+	required := func(value string, name string, env string, flagName string) {
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("%s is required (env %s, flag -%s)", name, env, flagName))
+		}
+	}
+	positive := func(value int64, name string) {
+		if value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be greater than 0", name))
+		}
+	}
+	mediaTypes := func(values []string, name string) {
+		if len(values) == 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be empty", name))
+		}
+		for _, v := range values {
+			parts := strings.Split(v, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[0] == "*" || parts[1] == "" {
+				problems = append(problems, fmt.Sprintf("%s must be in the form type/subtype or type/*, got %q", name, v))
+			}
+		}
+	}
+
+	if r.Server.Port <= 0 || r.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port must be between 1 and 65535, got %d", r.Server.Port))
+	}
+	required(r.Server.PublicDir, "server.publicDir", "PUBLIC_DIR", "public-dir")
+	positive(int64(r.Server.ReadHeaderTimeout), "server.readHeaderTimeout")
+	positive(int64(r.Server.ReadTimeout), "server.readTimeout")
+	positive(int64(r.Server.WriteTimeout), "server.writeTimeout")
+	positive(int64(r.Server.IdleTimeout), "server.idleTimeout")
+	positive(int64(r.Server.ShutdownTimeout), "server.shutdownTimeout")
+	if r.Server.ShutdownDelay < 0 {
+		problems = append(problems, fmt.Sprintf("server.shutdownDelay must not be negative, got %s", r.Server.ShutdownDelay))
+	}
+
+	switch r.Database.Driver {
+	case DriverPostgres:
+		required(r.Database.Host, "database.host", "DB_HOST", "db-host")
+		required(r.Database.Port, "database.port", "DB_PORT", "db-port")
+		required(r.Database.User, "database.user", "DB_USER", "db-user")
+		required(r.Database.Name, "database.name", "DB_SCHEMA", "db-name")
+	case DriverSQLite:
+		required(r.Database.Path, "database.path", "DB_PATH", "db-path")
+	default:
+		problems = append(problems, fmt.Sprintf("database.driver must be %q or %q, got %q",
+			DriverPostgres, DriverSQLite, r.Database.Driver))
+	}
+	positive(int64(r.Database.ConnectMaxAttempts), "database.connectMaxAttempts")
+
+	required(r.Auth.Audience, "auth.audience", "AUTH0_AUDIENCE", "auth-audience")
+	required(r.Auth.Issuer, "auth.issuer", "AUTH0_ISSUER", "auth-issuer")
+	required(r.Auth.JWKSURL, "auth.jwksUrl", "AUTH0_JWK", "auth-jwks-url")
+
+	switch r.Storage.Driver {
+	case StorageDriverS3:
+		required(r.Storage.Bucket, "storage.bucket", "S3_BUCKET", "s3-bucket")
+	case StorageDriverLocal:
+		required(r.Storage.Path, "storage.path", "STORAGE_PATH", "storage-path")
+		required(r.Storage.SigningKey, "storage.signingKey", "STORAGE_SIGNING_KEY", "storage-signing-key")
+	default:
+		problems = append(problems, fmt.Sprintf("storage.driver must be %q or %q, got %q",
+			StorageDriverS3, StorageDriverLocal, r.Storage.Driver))
+	}
+	positive(r.Storage.MaxThumbnailSize, "storage.maxThumbnailSize")
+	positive(r.Storage.MaxContentSize, "storage.maxContentSize")
+	mediaTypes(r.Storage.AllowedThumbnailTypes, "storage.allowedThumbnailTypes")
+	mediaTypes(r.Storage.AllowedContentTypes, "storage.allowedContentTypes")
+	positive(int64(r.Storage.UploadExpiration), "storage.uploadExpiration")
+	positive(int64(r.Storage.PresignExpiration), "storage.presignExpiration")
+	positive(int64(r.Storage.PrivateURLExpiration), "storage.privateURLExpiration")
+
+	if len(r.Image.VariantWidths) == 0 {
+		problems = append(problems, "image.variantWidths must not be empty")
+	}
+	for _, w := range r.Image.VariantWidths {
+		positive(int64(w), "image.variantWidths")
+	}
+	positive(r.Image.MaxPixels, "image.maxPixels")
+
+	switch r.Scan.Driver {
+	case ScanDriverNone:
+	case ScanDriverClamd:
+		required(r.Scan.ClamdAddress, "scan.clamdAddress", "CLAMD_ADDRESS", "clamd-address")
+		if _, _, err := r.Scan.clamdNetwork(); r.Scan.ClamdAddress != "" && err != nil {
+			problems = append(problems, fmt.Sprintf("scan.clamdAddress %v", err))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("scan.driver must be %q or %q, got %q",
+			ScanDriverNone, ScanDriverClamd, r.Scan.Driver))
+	}
+	positive(int64(r.Scan.Timeout), "scan.timeout")
+
+	positive(int64(r.LinkPreview.Timeout), "linkPreview.timeout")
+	positive(r.LinkPreview.MaxSize, "linkPreview.maxSize")
+	if r.LinkPreview.MaxRedirects < 0 {
+		problems = append(problems, fmt.Sprintf("linkPreview.maxRedirects must not be negative, got %d", r.LinkPreview.MaxRedirects))
+	}
+
+	positive(int64(r.LinkCheck.Interval), "linkCheck.interval")
+	positive(int64(r.LinkCheck.Timeout), "linkCheck.timeout")
+	positive(int64(r.LinkCheck.Concurrency), "linkCheck.concurrency")
+	if r.LinkCheck.HostInterval < 0 {
+		problems = append(problems, fmt.Sprintf("linkCheck.hostInterval must not be negative, got %s", r.LinkCheck.HostInterval))
+	}
+	positive(int64(r.LinkCheck.BrokenAfter), "linkCheck.brokenAfter")
+
+	positive(int64(r.Trash.Retention), "trash.retention")
+
+	if r.Moderation.ReportThreshold < 0 {
+		problems = append(problems, fmt.Sprintf("moderation.reportThreshold must not be negative, got %d", r.Moderation.ReportThreshold))
+	}
+
+	return problems
+}
+
+// DSN は、Driverに応じたDBへの接続文字列を返す
+func (r *DatabaseConfig) DSN() string {
+	if r.Driver == DriverSQLite {
+		// 外部キー制約を有効にし、書き込みの競合時はエラーにせず待機する
+		return r.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	}
+
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		r.Host, r.User, r.Password, r.Name, r.Port)
+}
+
+// maxUploadSize は、再開可能なアップロードで受け付ける最大バイト数を返す
+func (r *StorageConfig) maxUploadSize() int64 {
+	if r.MaxThumbnailSize > r.MaxContentSize {
+		return r.MaxThumbnailSize
+	}
+	return r.MaxContentSize
+}
+
+// clamdNetwork は、ClamdAddressを接続に使用するネットワークとアドレスに分ける
+func (r *ScanConfig) clamdNetwork() (string, string, error) {
+	parts := strings.SplitN(r.ClamdAddress, "://", 2)
+	if len(parts) != 2 || (parts[0] != "tcp" && parts[0] != "unix") || parts[1] == "" {
+		return "", "", fmt.Errorf("must be in the form tcp://host:port or unix:///path, got %q", r.ClamdAddress)
+	}
+	return parts[0], parts[1], nil
+}
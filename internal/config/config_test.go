@@ -0,0 +1,538 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var requiredEnv = map[string]string{
+	"DB_HOST":        "localhost",
+	"DB_USER":        "works_uploader",
+	"DB_SCHEMA":      "works_uploader",
+	"AUTH0_AUDIENCE": "works-uploader",
+	"AUTH0_ISSUER":   "https://example.com/",
+	"AUTH0_JWK":      "https://example.com/.well-known/jwks.json",
+	"S3_BUCKET":      "works-uploader-dev",
+}
+
+func lookupEnv(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func mergeEnv(envs ...map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, env := range envs {
+		for k, v := range env {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "wu-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config.yml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Is valid with environment variables", func(t *testing.T) {
+		conf, err := Load(nil, lookupEnv(requiredEnv))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 8000, conf.Server.Port)
+		assert.Equal(t, 30*time.Minute, conf.Server.ReadTimeout)
+		assert.Equal(t, 5*time.Second, conf.Server.ShutdownDelay)
+		assert.Equal(t, "localhost", conf.Database.Host)
+		assert.Equal(t, "5432", conf.Database.Port)
+		assert.Equal(t, "works-uploader-dev", conf.Storage.Bucket)
+		assert.Equal(t, int64(10<<20), conf.Storage.MaxThumbnailSize)
+		assert.Equal(t, int64(4<<30), conf.Storage.MaxContentSize)
+		assert.Equal(t, 24*time.Hour, conf.Storage.UploadExpiration)
+		assert.Equal(t, StorageDriverS3, conf.Storage.Driver)
+		assert.Equal(t, 15*time.Minute, conf.Storage.PresignExpiration)
+		assert.Equal(t, 15*time.Minute, conf.Storage.PrivateURLExpiration)
+		assert.Equal(t, []string{"image/png", "image/jpeg", "image/gif", "image/webp"}, conf.Storage.AllowedThumbnailTypes)
+		assert.Equal(t, []string{"image/*", "audio/*", "video/*", "application/pdf", "application/zip"}, conf.Storage.AllowedContentTypes)
+		assert.Equal(t, []int{320, 640, 1280}, conf.Image.VariantWidths)
+		assert.Equal(t, int64(50_000_000), conf.Image.MaxPixels)
+		assert.True(t, conf.Image.StripMetadata)
+		assert.False(t, conf.Storage.Deduplicate)
+		assert.Equal(t, ScanDriverNone, conf.Scan.Driver)
+		assert.Equal(t, 30*time.Second, conf.Scan.Timeout)
+		assert.True(t, conf.LinkPreview.Enabled)
+		assert.Equal(t, 10*time.Second, conf.LinkPreview.Timeout)
+		assert.Equal(t, int64(1<<20), conf.LinkPreview.MaxSize)
+		assert.Equal(t, 5, conf.LinkPreview.MaxRedirects)
+		assert.True(t, conf.LinkCheck.Enabled)
+		assert.Equal(t, 24*time.Hour, conf.LinkCheck.Interval)
+		assert.Equal(t, 10*time.Second, conf.LinkCheck.Timeout)
+		assert.Equal(t, 4, conf.LinkCheck.Concurrency)
+		assert.Equal(t, time.Second, conf.LinkCheck.HostInterval)
+		assert.Equal(t, 72*time.Hour, conf.LinkCheck.BrokenAfter)
+		assert.Equal(t, "admin:works", conf.Auth.AdminScope)
+		assert.Equal(t, 30*24*time.Hour, conf.Trash.Retention)
+		assert.Equal(t, "moderate:works", conf.Auth.ModeratorScope)
+		assert.Equal(t, 5, conf.Moderation.ReportThreshold)
+	})
+
+	t.Run("Environment variables override config file", func(t *testing.T) {
+		path := writeConfigFile(t, `
+server:
+  port: 9000
+  readTimeout: 1h
+database:
+  host: db.example.com
+`)
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG": path,
+			"PORT":      "9100",
+		})
+		delete(env, "DB_HOST")
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 9100, conf.Server.Port)
+		assert.Equal(t, time.Hour, conf.Server.ReadTimeout)
+		assert.Equal(t, "db.example.com", conf.Database.Host)
+	})
+
+	t.Run("Flags override environment variables", func(t *testing.T) {
+		path := writeConfigFile(t, "server:\n  port: 9000\n")
+		env := mergeEnv(requiredEnv, map[string]string{"PORT": "9100"})
+
+		conf, err := Load([]string{"-config", path, "-port", "9200", "-db-host", "flag.example.com"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 9200, conf.Server.Port)
+		assert.Equal(t, "flag.example.com", conf.Database.Host)
+	})
+
+	t.Run("Missing required values", func(t *testing.T) {
+		_, err := Load(nil, lookupEnv(map[string]string{}))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Contains(t, vErr.Problems, "database.host is required (env DB_HOST, flag -db-host)")
+			assert.Contains(t, vErr.Problems, "storage.bucket is required (env S3_BUCKET, flag -s3-bucket)")
+		}
+	})
+
+	t.Run("Is valid with SQLite", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{
+			"DB_DRIVER": "sqlite",
+			"DB_PATH":   ":memory:",
+		})
+		delete(env, "DB_HOST")
+		delete(env, "DB_USER")
+		delete(env, "DB_SCHEMA")
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, DriverSQLite, conf.Database.Driver)
+		assert.Equal(t, ":memory:?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", conf.Database.DSN())
+	})
+
+	t.Run("Unknown driver", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{"DB_DRIVER": "mysql"})
+
+		_, err := Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{`database.driver must be "postgres" or "sqlite", got "mysql"`}, vErr.Problems)
+		}
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{
+			"PORT":                "eighty",
+			"SERVER_READ_TIMEOUT": "-1s",
+		})
+
+		_, err := Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Len(t, vErr.Problems, 2)
+			assert.Contains(t, vErr.Problems, "server.readTimeout must be greater than 0")
+		}
+	})
+
+	t.Run("Shutdown delay", func(t *testing.T) {
+		conf, err := Load([]string{"-shutdown-delay", "0s"}, lookupEnv(requiredEnv))
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Duration(0), conf.Server.ShutdownDelay)
+
+		_, err = Load([]string{"-shutdown-delay", "-1s"}, lookupEnv(requiredEnv))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"server.shutdownDelay must not be negative, got -1s"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Upload size limits", func(t *testing.T) {
+		path := writeConfigFile(t, "storage:\n  maxContentSize: 1073741824\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":                 path,
+			"UPLOAD_MAX_THUMBNAIL_SIZE": "0",
+		})
+
+		conf, err := Load([]string{"-max-content-size", "8589934592"}, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"storage.maxThumbnailSize must be greater than 0"}, vErr.Problems)
+		}
+		assert.Nil(t, conf)
+
+		env["UPLOAD_MAX_THUMBNAIL_SIZE"] = "1048576"
+		conf, err = Load([]string{"-max-content-size", "8589934592"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1<<20), conf.Storage.MaxThumbnailSize)
+		assert.Equal(t, int64(8<<30), conf.Storage.MaxContentSize)
+	})
+
+	t.Run("Allowed content types", func(t *testing.T) {
+		path := writeConfigFile(t, "storage:\n  allowedThumbnailTypes: [image/png]\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":                    path,
+			"UPLOAD_ALLOWED_CONTENT_TYPES": "image/*, application/pdf",
+		})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"image/png"}, conf.Storage.AllowedThumbnailTypes)
+		assert.Equal(t, []string{"image/*", "application/pdf"}, conf.Storage.AllowedContentTypes)
+
+		conf, err = Load([]string{"-allowed-thumbnail-types", "", "-allowed-content-types", "pdf,*/*"}, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{
+				"storage.allowedThumbnailTypes must not be empty",
+				`storage.allowedContentTypes must be in the form type/subtype or type/*, got "pdf"`,
+				`storage.allowedContentTypes must be in the form type/subtype or type/*, got "*/*"`,
+			}, vErr.Problems)
+		}
+		assert.Nil(t, conf)
+	})
+
+	t.Run("Image variants", func(t *testing.T) {
+		path := writeConfigFile(t, "image:\n  variantWidths: [200]\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":        path,
+			"IMAGE_MAX_PIXELS": "1000000",
+		})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{200}, conf.Image.VariantWidths)
+		assert.Equal(t, int64(1000000), conf.Image.MaxPixels)
+		assert.True(t, conf.Image.StripMetadata)
+
+		conf, err = Load([]string{"-image-variant-widths", "480, 960", "-image-strip-metadata=false"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{480, 960}, conf.Image.VariantWidths)
+		assert.False(t, conf.Image.StripMetadata)
+
+		_, err = Load([]string{"-image-variant-widths", "480,wide"}, lookupEnv(env))
+
+		assert.EqualError(t, err, "invalid configuration:\n  flag -image-variant-widths: strconv.Atoi: parsing \"wide\": invalid syntax")
+
+		_, err = Load([]string{"-image-variant-widths", "0"}, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"image.variantWidths must be greater than 0"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Scan with clamd", func(t *testing.T) {
+		path := writeConfigFile(t, "scan:\n  driver: clamd\n  clamdAddress: unix:///var/run/clamav/clamd.ctl\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":    path,
+			"SCAN_TIMEOUT": "5s",
+		})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ScanDriverClamd, conf.Scan.Driver)
+		assert.Equal(t, 5*time.Second, conf.Scan.Timeout)
+		network, address, err := conf.Scan.clamdNetwork()
+		assert.Nil(t, err)
+		assert.Equal(t, "unix", network)
+		assert.Equal(t, "/var/run/clamav/clamd.ctl", address)
+
+		conf, err = Load([]string{"-clamd-address", "tcp://clamav:3310"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		network, address, _ = conf.Scan.clamdNetwork()
+		assert.Equal(t, "tcp", network)
+		assert.Equal(t, "clamav:3310", address)
+	})
+
+	t.Run("Scan is invalid", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{"SCAN_DRIVER": "clamd"})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"scan.clamdAddress is required (env CLAMD_ADDRESS, flag -clamd-address)"}, vErr.Problems)
+		}
+		assert.Nil(t, conf)
+
+		_, err = Load([]string{"-clamd-address", "localhost:3310", "-scan-timeout", "0s"}, lookupEnv(env))
+
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{
+				`scan.clamdAddress must be in the form tcp://host:port or unix:///path, got "localhost:3310"`,
+				"scan.timeout must be greater than 0",
+			}, vErr.Problems)
+		}
+
+		_, err = Load([]string{"-scan-driver", "virustotal"}, lookupEnv(env))
+
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{`scan.driver must be "none" or "clamd", got "virustotal"`}, vErr.Problems)
+		}
+	})
+
+	t.Run("Link preview", func(t *testing.T) {
+		path := writeConfigFile(t, "linkPreview:\n  enabled: false\n  maxRedirects: 0\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":            path,
+			"LINK_PREVIEW_TIMEOUT": "3s",
+		})
+
+		conf, err := Load([]string{"-link-preview-max-size", "65536"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.False(t, conf.LinkPreview.Enabled)
+		assert.Equal(t, 3*time.Second, conf.LinkPreview.Timeout)
+		assert.Equal(t, int64(65536), conf.LinkPreview.MaxSize)
+		assert.Equal(t, 0, conf.LinkPreview.MaxRedirects)
+	})
+
+	t.Run("Link preview is invalid", func(t *testing.T) {
+		_, err := Load([]string{
+			"-link-preview-timeout", "0s",
+			"-link-preview-max-size", "0",
+			"-link-preview-max-redirects", "-1",
+		}, lookupEnv(requiredEnv))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{
+				"linkPreview.timeout must be greater than 0",
+				"linkPreview.maxSize must be greater than 0",
+				"linkPreview.maxRedirects must not be negative, got -1",
+			}, vErr.Problems)
+		}
+	})
+
+	t.Run("Link check", func(t *testing.T) {
+		path := writeConfigFile(t, "linkCheck:\n  interval: 12h\n  hostInterval: 0s\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":               path,
+			"LINK_CHECK_CONCURRENCY":  "8",
+			"LINK_CHECK_BROKEN_AFTER": "168h",
+		})
+
+		conf, err := Load([]string{"-link-check-enabled=false", "-link-check-timeout", "5s"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.False(t, conf.LinkCheck.Enabled)
+		assert.Equal(t, 12*time.Hour, conf.LinkCheck.Interval)
+		assert.Equal(t, 5*time.Second, conf.LinkCheck.Timeout)
+		assert.Equal(t, 8, conf.LinkCheck.Concurrency)
+		assert.Zero(t, conf.LinkCheck.HostInterval)
+		assert.Equal(t, 168*time.Hour, conf.LinkCheck.BrokenAfter)
+	})
+
+	t.Run("Link check is invalid", func(t *testing.T) {
+		_, err := Load([]string{
+			"-link-check-interval", "0s",
+			"-link-check-timeout", "0s",
+			"-link-check-concurrency", "0",
+			"-link-check-host-interval", "-1s",
+			"-link-check-broken-after", "0s",
+		}, lookupEnv(requiredEnv))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{
+				"linkCheck.interval must be greater than 0",
+				"linkCheck.timeout must be greater than 0",
+				"linkCheck.concurrency must be greater than 0",
+				"linkCheck.hostInterval must not be negative, got -1s",
+				"linkCheck.brokenAfter must be greater than 0",
+			}, vErr.Problems)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{
+			"AUTH_ADMIN_SCOPE": "admin",
+			"TRASH_RETENTION":  "168h",
+		})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "admin", conf.Auth.AdminScope)
+		assert.Equal(t, 168*time.Hour, conf.Trash.Retention)
+	})
+
+	t.Run("Trash retention is invalid", func(t *testing.T) {
+		_, err := Load([]string{"-trash-retention", "0s"}, lookupEnv(requiredEnv))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"trash.retention must be greater than 0"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Moderation", func(t *testing.T) {
+		path := writeConfigFile(t, "moderation:\n  reportThreshold: 3\n")
+		env := mergeEnv(requiredEnv, map[string]string{
+			"WU_CONFIG":            path,
+			"AUTH_MODERATOR_SCOPE": "moderator",
+		})
+
+		conf, err := Load([]string{"-moderation-report-threshold", "0"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "moderator", conf.Auth.ModeratorScope)
+		assert.Equal(t, 0, conf.Moderation.ReportThreshold)
+	})
+
+	t.Run("Report threshold is invalid", func(t *testing.T) {
+		_, err := Load([]string{"-moderation-report-threshold", "-1"}, lookupEnv(requiredEnv))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"moderation.reportThreshold must not be negative, got -1"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Deduplicate", func(t *testing.T) {
+		path := writeConfigFile(t, "storage:\n  deduplicate: true\n")
+		env := mergeEnv(requiredEnv, map[string]string{"WU_CONFIG": path})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.True(t, conf.Storage.Deduplicate)
+
+		env["UPLOAD_DEDUPLICATE"] = "false"
+		conf, err = Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.False(t, conf.Storage.Deduplicate)
+
+		conf, err = Load([]string{"-upload-deduplicate=true"}, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.True(t, conf.Storage.Deduplicate)
+	})
+
+	t.Run("Private URL expiration", func(t *testing.T) {
+		path := writeConfigFile(t, "storage:\n  privateURLExpiration: 5m\n")
+		env := mergeEnv(requiredEnv, map[string]string{"WU_CONFIG": path})
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5*time.Minute, conf.Storage.PrivateURLExpiration)
+
+		env["STORAGE_PRIVATE_URL_EXPIRATION"] = "0s"
+		_, err = Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"storage.privateURLExpiration must be greater than 0"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Is valid with local storage", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{
+			"STORAGE_DRIVER":      "local",
+			"STORAGE_PATH":        "/var/lib/works-uploader",
+			"STORAGE_SIGNING_KEY": "secret",
+		})
+		delete(env, "S3_BUCKET")
+
+		conf, err := Load(nil, lookupEnv(env))
+
+		assert.Nil(t, err)
+		assert.Equal(t, StorageDriverLocal, conf.Storage.Driver)
+		assert.Equal(t, "/var/lib/works-uploader", conf.Storage.Path)
+	})
+
+	t.Run("Local storage requires signing key", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{
+			"STORAGE_DRIVER": "local",
+			"STORAGE_PATH":   "/var/lib/works-uploader",
+		})
+
+		_, err := Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{"storage.signingKey is required (env STORAGE_SIGNING_KEY, flag -storage-signing-key)"}, vErr.Problems)
+		}
+	})
+
+	t.Run("Unknown storage driver", func(t *testing.T) {
+		env := mergeEnv(requiredEnv, map[string]string{"STORAGE_DRIVER": "gcs"})
+
+		_, err := Load(nil, lookupEnv(env))
+
+		var vErr *ValidationError
+		if assert.True(t, errors.As(err, &vErr)) {
+			assert.Equal(t, []string{`storage.driver must be "s3" or "local", got "gcs"`}, vErr.Problems)
+		}
+	})
+
+	t.Run("Unknown key in config file", func(t *testing.T) {
+		path := writeConfigFile(t, "server:\n  prot: 9000\n")
+
+		_, err := Load([]string{"-config", path}, lookupEnv(requiredEnv))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Config file does not exist", func(t *testing.T) {
+		_, err := Load([]string{"-config", "/nonexistent/config.yml"}, lookupEnv(requiredEnv))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"gorm.io/gorm"
+)
+
+// uploadPurgeInterval は、期限切れのアップロードを削除する間隔
+const uploadPurgeInterval = 10 * time.Minute
+
+// linkCheckJobInterval は、確認する時期になったURLの作品を探す間隔。各作品を確認する間隔はLinkCheckConfig.Interval。
+const linkCheckJobInterval = 10 * time.Minute
+
+// publishJobInterval は、公開日時を過ぎた公開予定の作品を探す間隔
+const publishJobInterval = time.Minute
+
+// trashPurgeJobInterval は、保存期間を過ぎた削除済みの作品を探す間隔。作品を残す期間はTrashConfig.Retention。
+const trashPurgeJobInterval = time.Hour
+
+// linkCheckMaxRedirects は、リンク先の確認で追跡するリダイレクトの最大回数
+const linkCheckMaxRedirects = 10
+
+// Job は、一定の間隔で実行するバックグラウンド処理を表す
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(context.Context) error
+}
+
+// InitJobs は、サーバーの起動中にバックグラウンドで実行する処理を生成する
+func InitJobs(db *gorm.DB, conf *Config) []*Job {
+	tranRnr := infrastructures.NewTransactionRunnerImpl(db)
+	uploadsRepo := infrastructures.NewUploadsRepositoryImpl(db)
+	uuidGen := &infrastructures.UUIDGeneratorImpl{}
+	fileUploader := newStorageClient(&conf.Storage)
+
+	uploadsService := services.NewUploadsServiceImpl(tranRnr, uploadsRepo, uuidGen, fileUploader, conf.Storage.maxUploadSize(), conf.Storage.UploadExpiration, conf.Storage.PresignExpiration)
+	publishService := services.NewPublishServiceImpl(
+		tranRnr,
+		infrastructures.NewWorksRepositoryImpl(db),
+		infrastructures.NewActivitiesRepositoryImpl(db),
+	)
+	trashService := services.NewTrashServiceImpl(
+		tranRnr,
+		infrastructures.NewWorksRepositoryImpl(db),
+		infrastructures.NewActivitiesRepositoryImpl(db),
+		infrastructures.NewBlobsRepositoryImpl(db),
+		fileUploader,
+		conf.Auth.AdminScope,
+		conf.Trash.Retention,
+		conf.Storage.PrivateURLExpiration,
+	)
+
+	jobs := []*Job{
+		{
+			Name:     "purge expired uploads",
+			Interval: uploadPurgeInterval,
+			Run: func(ctx context.Context) error {
+				n, err := uploadsService.PurgeExpired(ctx)
+				if n > 0 {
+					log.Printf("purged %d expired uploads", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "publish scheduled works",
+			Interval: publishJobInterval,
+			Run: func(ctx context.Context) error {
+				n, err := publishService.PublishDue(ctx)
+				if n > 0 {
+					log.Printf("published %d scheduled works", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "purge expired trash",
+			Interval: trashPurgeJobInterval,
+			Run: func(ctx context.Context) error {
+				n, err := trashService.PurgeExpired(ctx)
+				if n > 0 {
+					log.Printf("purged %d works from trash", n)
+				}
+				return err
+			},
+		},
+	}
+
+	if conf.LinkCheck.Enabled {
+		linkCheckService := services.NewLinkCheckServiceImpl(
+			tranRnr,
+			infrastructures.NewWorksRepositoryImpl(db),
+			infrastructures.NewActivitiesRepositoryImpl(db),
+			infrastructures.NewHTTPLinkCheckerImpl(conf.LinkCheck.Timeout, linkCheckMaxRedirects),
+			conf.LinkCheck.Interval,
+			conf.LinkCheck.BrokenAfter,
+			conf.LinkCheck.Concurrency,
+			conf.LinkCheck.HostInterval,
+		)
+		jobs = append(jobs, &Job{
+			Name:     "check links of URL works",
+			Interval: linkCheckJobInterval,
+			Run: func(ctx context.Context) error {
+				n, err := linkCheckService.CheckLinks(ctx)
+				if n > 0 {
+					log.Printf("checked links of %d works", n)
+				}
+				return err
+			},
+		})
+	}
+
+	return jobs
+}
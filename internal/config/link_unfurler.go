@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+// newLinkUnfurler は、設定に応じてURLの作品のリンク先からプレビューを取得するLinkUnfurlerを生成する
+func newLinkUnfurler(conf *LinkPreviewConfig) lib.LinkUnfurler {
+	if conf.Enabled {
+		return infrastructures.NewHTTPLinkUnfurlerImpl(conf.Timeout, conf.MaxSize, conf.MaxRedirects)
+	}
+
+	return &infrastructures.NopLinkUnfurlerImpl{}
+}
@@ -2,11 +2,12 @@ package config
 
 import (
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/edy4c7/works-uploader/internal/controllers"
 	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/middlewares"
 	"github.com/edy4c7/works-uploader/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -14,44 +15,167 @@ import (
 
 const apiPath = "/api"
 
-func InitRoutes(r *gin.Engine, db *gorm.DB) {
+func InitRoutes(r *gin.Engine, db *gorm.DB, conf *Config, checkers map[string]lib.HealthChecker) {
 	tranRnr := infrastructures.NewTransactionRunnerImpl(db)
 	worksRepo := infrastructures.NewWorksRepositoryImpl(db)
 	actRepo := infrastructures.NewActivitiesRepositoryImpl(db)
 	userRepo := infrastructures.NewUserRepositoryImpl(db)
+	uploadsRepo := infrastructures.NewUploadsRepositoryImpl(db)
+	blobsRepo := infrastructures.NewBlobsRepositoryImpl(db)
+	scanResultsRepo := infrastructures.NewScanResultsRepositoryImpl(db)
+	revisionsRepo := infrastructures.NewWorkRevisionsRepositoryImpl(db)
+	tagsRepo := infrastructures.NewTagsRepositoryImpl(db)
+	collectionsRepo := infrastructures.NewCollectionsRepositoryImpl(db)
+	favoritesRepo := infrastructures.NewFavoritesRepositoryImpl(db)
+	commentsRepo := infrastructures.NewCommentsRepositoryImpl(db)
+	reportsRepo := infrastructures.NewReportsRepositoryImpl(db)
+	moderationActionsRepo := infrastructures.NewModerationActionsRepositoryImpl(db)
 	uuidGen := &infrastructures.UUIDGeneratorImpl{}
-	fileUploader := infrastructures.NewStorageClientImpl()
+	fileUploader := newStorageClient(&conf.Storage)
 
-	worksService := services.NewWorksServiceImpl(tranRnr, worksRepo, actRepo, uuidGen, fileUploader)
+	worksService := services.NewWorksServiceImpl(tranRnr, worksRepo, actRepo, uuidGen, fileUploader, services.WorksServiceDeps{
+		UploadsRepository:     uploadsRepo,
+		BlobsRepository:       blobsRepo,
+		ScanResultsRepository: scanResultsRepo,
+		RevisionsRepository:   revisionsRepo,
+		TagsRepository:        tagsRepo,
+		CollectionsRepository: collectionsRepo,
+		ImageProcessor:        infrastructures.NewImageProcessorImpl(conf.Image.MaxPixels, conf.Image.StripMetadata),
+		Scanner:               newScanner(&conf.Scan),
+		LinkUnfurler:          newLinkUnfurler(&conf.LinkPreview),
+		UploadPolicies: map[string]services.UploadPolicy{
+			services.FieldThumbnail: {MaxSize: conf.Storage.MaxThumbnailSize, AllowedTypes: conf.Storage.AllowedThumbnailTypes},
+			services.FieldContent:   {MaxSize: conf.Storage.MaxContentSize, AllowedTypes: conf.Storage.AllowedContentTypes},
+		},
+		VariantWidths:        conf.Image.VariantWidths,
+		Deduplicate:          conf.Storage.Deduplicate,
+		PrivateURLExpiration: conf.Storage.PrivateURLExpiration,
+	})
 	worksCtrl := controllers.NewWorksController(worksService)
 
+	revisionsService := services.NewRevisionsServiceImpl(tranRnr, worksRepo, revisionsRepo, fileUploader, conf.Storage.PrivateURLExpiration)
+	revisionsCtrl := controllers.NewRevisionsController(revisionsService)
+
+	trashService := services.NewTrashServiceImpl(tranRnr, worksRepo, actRepo, blobsRepo, fileUploader, conf.Auth.AdminScope, conf.Trash.Retention, conf.Storage.PrivateURLExpiration)
+	trashCtrl := controllers.NewTrashController(trashService)
+
+	tagsService := services.NewTagsServiceImpl(tagsRepo)
+	tagsCtrl := controllers.NewTagsController(tagsService)
+
+	collectionsService := services.NewCollectionsServiceImpl(tranRnr, collectionsRepo, worksRepo, actRepo, fileUploader, conf.Storage.PrivateURLExpiration)
+	collectionsCtrl := controllers.NewCollectionsController(collectionsService)
+
+	favoritesService := services.NewFavoritesServiceImpl(tranRnr, favoritesRepo, worksRepo, actRepo, fileUploader, conf.Storage.PrivateURLExpiration)
+	favoritesCtrl := controllers.NewFavoritesController(favoritesService)
+
+	commentsService := services.NewCommentsServiceImpl(tranRnr, commentsRepo, worksRepo, actRepo)
+	commentsCtrl := controllers.NewCommentsController(commentsService)
+
+	reportsService := services.NewReportsServiceImpl(tranRnr, reportsRepo, worksRepo, moderationActionsRepo, conf.Moderation.ReportThreshold)
+	reportsCtrl := controllers.NewReportsController(reportsService)
+
+	moderationService := services.NewModerationServiceImpl(tranRnr, reportsRepo, moderationActionsRepo, worksRepo, userRepo, conf.Auth.ModeratorScope)
+	moderationCtrl := controllers.NewModerationController(moderationService)
+
+	uploadsService := services.NewUploadsServiceImpl(tranRnr, uploadsRepo, uuidGen, fileUploader, conf.Storage.maxUploadSize(), conf.Storage.UploadExpiration, conf.Storage.PresignExpiration)
+	uploadsCtrl := controllers.NewUploadsController(uploadsService, conf.Storage.maxUploadSize())
+	uploadSessionsCtrl := controllers.NewUploadSessionsController(uploadsService)
+
 	actsService := services.NewActivitiesServiceImpl(actRepo)
 	actsCtrl := controllers.NewActivitiesController(actsService)
 
 	usersService := services.NewUsersServiceImpl(userRepo)
 	usersCtrl := controllers.NewUsersController(usersService)
 
+	healthCheckers := map[string]lib.HealthChecker{
+		"database": infrastructures.NewDBHealthCheckerImpl(db),
+		"storage":  fileUploader,
+	}
+	for name, c := range checkers {
+		healthCheckers[name] = c
+	}
+	healthService := services.NewHealthServiceImpl(healthCheckers)
+	healthCtrl := controllers.NewHealthController(healthService)
+
+	r.GET("/healthz", healthCtrl.Live)
+	r.GET("/readyz", healthCtrl.Ready)
+
 	api := r.Group(apiPath)
-	v1 := api.Group("/v1")
+	// アカウントが停止されたユーザーは閲覧のみ行える。ログイン時のプロフィールの同期は、停止中も受け付ける。
+	v1 := api.Group("/v1", middlewares.NewSuspensionMiddleware(usersService, func(r *http.Request) bool {
+		return r.Method == http.MethodPut && r.URL.Path == apiPath+"/v1/users"
+	}))
 
 	worksRoutes := v1.Group("/works")
 	worksRoutes.GET("", worksCtrl.Get)
 	worksRoutes.GET("/:id", worksCtrl.FindByID)
 	worksRoutes.POST("", worksCtrl.Post)
+	worksRoutes.PUT("/:id", worksCtrl.Put)
 	worksRoutes.DELETE("/:id", worksCtrl.Delete)
+	worksRoutes.GET("/:id/revisions", revisionsCtrl.Get)
+	worksRoutes.GET("/:id/revisions/:version", revisionsCtrl.FindByVersion)
+	worksRoutes.POST("/:id/revisions/:version/restore", revisionsCtrl.Restore)
+	worksRoutes.GET("/:id/diff", revisionsCtrl.Diff)
+	worksRoutes.POST("/:id/restore", trashCtrl.Restore)
+	worksRoutes.PUT("/:id/favorite", favoritesCtrl.Put)
+	worksRoutes.DELETE("/:id/favorite", favoritesCtrl.Delete)
+	worksRoutes.GET("/:id/comments", commentsCtrl.Get)
+	worksRoutes.POST("/:id/comments", commentsCtrl.Post)
+	worksRoutes.POST("/:id/reports", reportsCtrl.Post)
+
+	commentsRoutes := v1.Group("/comments")
+	commentsRoutes.PUT("/:id", commentsCtrl.Put)
+	commentsRoutes.DELETE("/:id", commentsCtrl.Delete)
+	commentsRoutes.GET("/:id/replies", commentsCtrl.GetReplies)
+
+	v1.DELETE("/trash/:id", trashCtrl.Purge)
+
+	moderationRoutes := v1.Group("/moderation")
+	moderationRoutes.GET("/reports", moderationCtrl.GetReports)
+	moderationRoutes.GET("/actions", moderationCtrl.GetActions)
+	moderationRoutes.POST("/works/:id/actions", moderationCtrl.ModerateWork)
+	moderationRoutes.POST("/users/:"+controllers.ModerationIDKey+"/actions", moderationCtrl.ModerateUser)
+
+	v1.GET("/tags", tagsCtrl.Get)
+
+	collectionsRoutes := v1.Group("/collections")
+	collectionsRoutes.GET("", collectionsCtrl.Get)
+	collectionsRoutes.GET("/:id", collectionsCtrl.FindByID)
+	collectionsRoutes.POST("", collectionsCtrl.Post)
+	collectionsRoutes.PUT("/:id", collectionsCtrl.Put)
+	collectionsRoutes.DELETE("/:id", collectionsCtrl.Delete)
+	collectionsRoutes.GET("/:id/works", collectionsCtrl.GetWorks)
+	collectionsRoutes.POST("/:id/works", collectionsCtrl.AddWork)
+	collectionsRoutes.PUT("/:id/works", collectionsCtrl.Reorder)
+	collectionsRoutes.DELETE("/:id/works/:workId", collectionsCtrl.RemoveWork)
+
+	uploadsRoutes := v1.Group("/uploads")
+	uploadsRoutes.OPTIONS("", uploadsCtrl.Options)
+	uploadsRoutes.POST("", uploadsCtrl.Post)
+	uploadsRoutes.HEAD("/:id", uploadsCtrl.Head)
+	uploadsRoutes.PATCH("/:id", uploadsCtrl.Patch)
+	uploadsRoutes.DELETE("/:id", uploadsCtrl.Delete)
+
+	v1.POST("/upload-sessions", uploadSessionsCtrl.Post)
+
+	// S3の場合、クライアントはS3へ直接送信し、CDNまたはS3から配信される
+	if local, ok := fileUploader.(*infrastructures.LocalStorageClientImpl); ok {
+		storageCtrl := controllers.NewStorageController(local, local, local)
+		r.PUT(StorageUploadPath+"/:"+controllers.StorageKeyKey, storageCtrl.Put)
+		r.GET(StorageUploadPath+"/:"+controllers.StorageKeyKey, storageCtrl.Get)
+		r.StaticFS(storageFilesPath, gin.Dir(local.PublicDir(), false))
+	}
 
 	actsRoutes := v1.Group("/activities")
 	actsRoutes.GET("", actsCtrl.Get)
 
 	userRoutes := v1.Group("/users")
 	userRoutes.PUT("", usersCtrl.Save)
+	// ginでは /me と /:id を同じ階層に登録できないため、ゴミ箱は /:id で受けて me 以外を拒否する
+	userRoutes.GET("/:"+controllers.UsersIDKey+"/trash", trashCtrl.Get)
+	userRoutes.GET("/:"+controllers.UsersIDKey+"/favorites", favoritesCtrl.Get)
 
-	wd, err := os.Getwd()
-	if err != nil {
-		panic(err)
-	}
-	publicDir := wd + "/public"
-	indexCtrl := controllers.NewIndexController(http.Dir(publicDir))
+	indexCtrl := controllers.NewIndexController(http.Dir(conf.Server.PublicDir))
 	r.NoRoute(func(c *gin.Context) {
 		if strings.HasPrefix(c.Request.URL.Path, apiPath) {
 			c.AbortWithStatus(http.StatusNotFound)
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+// newScanner は、設定されたドライバのマルウェアのスキャナーを生成する
+func newScanner(conf *ScanConfig) lib.Scanner {
+	if conf.Driver == ScanDriverClamd {
+		// アドレスは設定の読み込み時に検証済み
+		network, address, _ := conf.clamdNetwork()
+		return infrastructures.NewClamdScannerImpl(network, address, conf.Timeout)
+	}
+
+	return &infrastructures.NopScannerImpl{}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/edy4c7/works-uploader/internal/infrastructures"
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+const (
+	// StorageUploadPath は、driver: local の場合に署名付きURLへ直接送信されたファイルを受信するパス。
+	// 署名で認可するため、JWTによる認可の対象外とする。
+	StorageUploadPath = apiPath + "/v1/storage"
+	// storageFilesPath は、driver: local の場合に公開済みのファイルを配信するパス
+	storageFilesPath = "/files"
+)
+
+type storageClient interface {
+	lib.StorageClient
+	lib.HealthChecker
+}
+
+// newStorageClient は、設定されたドライバのストレージを生成する
+func newStorageClient(conf *StorageConfig) storageClient {
+	if conf.Driver == StorageDriverLocal {
+		baseURL := storageFilesPath
+		if conf.CDNDomain != "" {
+			baseURL = fmt.Sprintf("https://%s", conf.CDNDomain)
+		}
+		return infrastructures.NewLocalStorageClientImpl(conf.Path, baseURL, StorageUploadPath, conf.SigningKey)
+	}
+
+	return infrastructures.NewStorageClientImpl(conf.Bucket, conf.CDNDomain, conf.Endpoint)
+}
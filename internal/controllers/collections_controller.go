@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const CollectionsIDKey = "id"
+const CollectionsWorkIDKey = "workId"
+
+// CollectionsController は、作品のコレクションの閲覧と管理を受け付ける
+type CollectionsController struct {
+	service services.CollectionsService
+}
+
+//NewCollectionsController add /collections
+func NewCollectionsController(service services.CollectionsService) *CollectionsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &CollectionsController{
+		service: service,
+	}
+}
+
+// Get は、コレクションの一覧を返す。クエリパラメータuserを指定した場合は、そのユーザーのコレクションに絞り込む。
+func (ctrl *CollectionsController) Get(c *gin.Context) {
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetAll(c.Request.Context(), c.Query(UserKey), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (ctrl *CollectionsController) FindByID(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	res, err := ctrl.service.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (ctrl *CollectionsController) Post(c *gin.Context) {
+	form := &beans.CollectionFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Create(c.Request.Context(), form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (ctrl *CollectionsController) Put(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.CollectionFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Update(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (ctrl *CollectionsController) Delete(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.DeleteByID(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWorks は、コレクションの作品を並び順に返す
+func (ctrl *CollectionsController) GetWorks(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetWorks(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AddWork は、作品をコレクションの末尾に追加する
+func (ctrl *CollectionsController) AddWork(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.CollectionWorkFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	if err := ctrl.service.AddWork(c.Request.Context(), id, form.WorkID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reorder は、コレクションの作品を指定した順に並べ替える
+func (ctrl *CollectionsController) Reorder(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.CollectionOrderFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	if err := ctrl.service.Reorder(c.Request.Context(), id, form.WorkIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveWork は、作品をコレクションから取り除く
+func (ctrl *CollectionsController) RemoveWork(c *gin.Context) {
+	id, err := extractCollectionID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+	workID, err := strconv.ParseUint(c.Param(CollectionsWorkIDKey), 10, 64)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.RemoveWork(c.Request.Context(), id, workID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func extractCollectionID(c *gin.Context) (uint64, error) {
+	return strconv.ParseUint(c.Param(CollectionsIDKey), 10, 64)
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollectionsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCollectionsService(ctrl)
+		collectionsCtrl := NewCollectionsController(service)
+
+		assert.Same(t, service, collectionsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCollectionsController(nil)
+		})
+	})
+}
+
+func collectionsRoutes(service *mocks.MockCollectionsService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		collectionsCtrl := NewCollectionsController(service)
+		r.GET("/collections", collectionsCtrl.Get)
+		r.GET("/collections/:id", collectionsCtrl.FindByID)
+		r.POST("/collections", collectionsCtrl.Post)
+		r.PUT("/collections/:id", collectionsCtrl.Put)
+		r.DELETE("/collections/:id", collectionsCtrl.Delete)
+		r.GET("/collections/:id/works", collectionsCtrl.GetWorks)
+		r.POST("/collections/:id/works", collectionsCtrl.AddWork)
+		r.PUT("/collections/:id/works", collectionsCtrl.Reorder)
+		r.DELETE("/collections/:id/works/:workId", collectionsCtrl.RemoveWork)
+	}
+}
+
+func TestGetCollections(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := &beans.PaginationBean{TotalItems: 1, Offset: 10, Items: []interface{}{&entities.Collection{ID: 1}}}
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().GetAll(ctx, "user01", 10, 100).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodGet, "/collections?user=user01&offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res, _ := json.Marshal(expect)
+		assert.Equal(t, res, w.Body.Bytes())
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w, _ := serve(ctx, collectionsRoutes(mocks.NewMockCollectionsService(ctrl)), http.MethodGet, "/collections?offset=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestFindCollectionByID(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1}, nil)
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodGet, "/collections/1", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, collectionsRoutes(mocks.NewMockCollectionsService(ctrl)), http.MethodGet, "/collections/abc", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestPostCollection(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().Create(ctx, &beans.CollectionFormBean{Title: "hoge", Visibility: 2}).
+			Return(&entities.Collection{ID: 1}, nil)
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodPost, "/collections", `{"title":"hoge","visibility":2}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Missing title", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, collectionsRoutes(mocks.NewMockCollectionsService(ctrl)), http.MethodPost, "/collections", `{"description":"hoge"}`)
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+}
+
+func TestPutCollection(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		var cover uint64 = 10
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().Update(ctx, uint64(1), &beans.CollectionFormBean{Title: "hoge", CoverWorkID: &cover}).
+			Return(&entities.Collection{ID: 1}, nil)
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodPut, "/collections/1", `{"title":"hoge","coverWorkId":10}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Not the owner", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().Update(ctx, uint64(1), gomock.Any()).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodPut, "/collections/1", `{"title":"hoge"}`)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
+
+func TestDeleteCollection(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockCollectionsService(ctrl)
+	service.EXPECT().DeleteByID(ctx, uint64(1))
+
+	w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodDelete, "/collections/1", "")
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestGetCollectionWorks(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockCollectionsService(ctrl)
+	service.EXPECT().GetWorks(ctx, uint64(1), 0, 20).Return(&beans.PaginationBean{Items: []interface{}{}}, nil)
+
+	w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodGet, "/collections/1/works?limit=20", "")
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAddCollectionWork(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().AddWork(ctx, uint64(1), uint64(10))
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodPost, "/collections/1/works", `{"workId":10}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Missing work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, collectionsRoutes(mocks.NewMockCollectionsService(ctrl)), http.MethodPost, "/collections/1/works", `{}`)
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+}
+
+func TestReorderCollectionWorks(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockCollectionsService(ctrl)
+	service.EXPECT().Reorder(ctx, uint64(1), []uint64{12, 10})
+
+	w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodPut, "/collections/1/works", `{"workIds":[12,10]}`)
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRemoveCollectionWork(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCollectionsService(ctrl)
+		service.EXPECT().RemoveWork(ctx, uint64(1), uint64(10))
+
+		w, ginCtx := serve(ctx, collectionsRoutes(service), http.MethodDelete, "/collections/1/works/10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("invalid work id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, collectionsRoutes(mocks.NewMockCollectionsService(ctrl)), http.MethodDelete, "/collections/1/works/abc", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const CommentsIDKey = "id"
+
+// CommentsController は、作品へのコメントの閲覧、投稿、編集と削除を受け付ける
+type CommentsController struct {
+	service services.CommentsService
+}
+
+//NewCommentsController add /works/:id/comments and /comments
+func NewCommentsController(service services.CommentsService) *CommentsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &CommentsController{
+		service: service,
+	}
+}
+
+// Get は、作品へのコメントのうち、返信でないものを返す
+func (ctrl *CommentsController) Get(c *gin.Context) {
+	workID, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetAll(c.Request.Context(), workID, offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Post は、作品にコメントを投稿する。parentIdを指定した場合は、そのコメントへの返信にする。
+func (ctrl *CommentsController) Post(c *gin.Context) {
+	workID, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.CommentFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Create(c.Request.Context(), workID, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// GetReplies は、コメントへの返信を返す
+func (ctrl *CommentsController) GetReplies(c *gin.Context) {
+	id, err := extractCommentID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetReplies(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Put は、コメントの本文を更新する
+func (ctrl *CommentsController) Put(c *gin.Context) {
+	id, err := extractCommentID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.CommentFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Update(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Delete は、コメントとその返信を削除する
+func (ctrl *CommentsController) Delete(c *gin.Context) {
+	id, err := extractCommentID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.DeleteByID(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func extractCommentID(c *gin.Context) (uint64, error) {
+	return strconv.ParseUint(c.Param(CommentsIDKey), 10, 64)
+}
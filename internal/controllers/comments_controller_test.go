@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommentsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCommentsService(ctrl)
+		commentsCtrl := NewCommentsController(service)
+
+		assert.Same(t, service, commentsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCommentsController(nil)
+		})
+	})
+}
+
+func commentsRoutes(service *mocks.MockCommentsService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		commentsCtrl := NewCommentsController(service)
+		r.GET("/works/:id/comments", commentsCtrl.Get)
+		r.POST("/works/:id/comments", commentsCtrl.Post)
+		r.PUT("/comments/:id", commentsCtrl.Put)
+		r.DELETE("/comments/:id", commentsCtrl.Delete)
+		r.GET("/comments/:id/replies", commentsCtrl.GetReplies)
+	}
+}
+
+func TestGetComments(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := &beans.PaginationBean{TotalItems: 1, Offset: 10, Items: []interface{}{&entities.Comment{ID: 1}}}
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().GetAll(ctx, uint64(1), 10, 100).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, commentsRoutes(service), http.MethodGet, "/works/1/comments?offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res, _ := json.Marshal(expect)
+		assert.Equal(t, res, w.Body.Bytes())
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, commentsRoutes(mocks.NewMockCommentsService(ctrl)), http.MethodGet, "/works/abc/comments", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestPostComment(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		var parentID uint64 = 5
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().Create(ctx, uint64(1), &beans.CommentFormBean{Body: "hoge", ParentID: &parentID}).
+			Return(&entities.Comment{ID: 10}, nil)
+
+		w, ginCtx := serve(ctx, commentsRoutes(service), http.MethodPost, "/works/1/comments", `{"body":"hoge","parentId":5}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE09))
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().Create(ctx, uint64(1), gomock.Any()).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, commentsRoutes(service), http.MethodPost, "/works/1/comments", `{"body":"<b>hoge</b>"}`)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, commentsRoutes(mocks.NewMockCommentsService(ctrl)), http.MethodPost, "/works/1/comments", `{"body":`)
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+}
+
+func TestGetCommentReplies(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockCommentsService(ctrl)
+	service.EXPECT().GetReplies(ctx, uint64(1), 0, 20).Return(&beans.PaginationBean{Items: []interface{}{}}, nil)
+
+	w, ginCtx := serve(ctx, commentsRoutes(service), http.MethodGet, "/comments/1/replies?limit=20", "")
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPutComment(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().Update(ctx, uint64(1), &beans.CommentFormBean{Body: "fuga"}).
+			Return(&entities.Comment{ID: 1}, nil)
+
+		w, ginCtx := serve(ctx, commentsRoutes(service), http.MethodPut, "/comments/1", `{"body":"fuga"}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Not the author", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().Update(ctx, uint64(1), gomock.Any()).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, commentsRoutes(service), http.MethodPut, "/comments/1", `{"body":"fuga"}`)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockCommentsService(ctrl)
+		service.EXPECT().DeleteByID(ctx, uint64(1))
+
+		w, ginCtx := serve(ctx, commentsRoutes(service), http.MethodDelete, "/comments/1", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, commentsRoutes(mocks.NewMockCommentsService(ctrl)), http.MethodDelete, "/comments/abc", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
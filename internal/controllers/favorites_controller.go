@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// FavoritesController は、作品のお気に入りの登録、解除と閲覧を受け付ける
+type FavoritesController struct {
+	service services.FavoritesService
+}
+
+//NewFavoritesController add /works/:id/favorite and /users/:id/favorites
+func NewFavoritesController(service services.FavoritesService) *FavoritesController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &FavoritesController{
+		service: service,
+	}
+}
+
+// Get は、ユーザーがお気に入りに登録した作品を返す。ユーザーIDに me を指定した場合は、ログイン中のユーザーのものを返す。
+func (ctrl *FavoritesController) Get(c *gin.Context) {
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetAll(c.Request.Context(), c.Param(UsersIDKey), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Put は、作品をお気に入りに登録する。登録済みの場合も成功する。
+func (ctrl *FavoritesController) Put(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.Add(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Delete は、作品のお気に入りを解除する。登録していない場合も成功する。
+func (ctrl *FavoritesController) Delete(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.Remove(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFavoritesController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockFavoritesService(ctrl)
+		favoritesCtrl := NewFavoritesController(service)
+
+		assert.Same(t, service, favoritesCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewFavoritesController(nil)
+		})
+	})
+}
+
+func favoritesRoutes(service *mocks.MockFavoritesService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		favoritesCtrl := NewFavoritesController(service)
+		r.GET("/users/:id/favorites", favoritesCtrl.Get)
+		r.PUT("/works/:id/favorite", favoritesCtrl.Put)
+		r.DELETE("/works/:id/favorite", favoritesCtrl.Delete)
+	}
+}
+
+func TestGetFavorites(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockFavoritesService(ctrl)
+		service.EXPECT().GetAll(ctx, "me", 10, 100).Return(&beans.PaginationBean{TotalItems: 0, Offset: 10, Items: []interface{}{}}, nil)
+
+		w, ginCtx := serve(ctx, favoritesRoutes(service), http.MethodGet, "/users/me/favorites?offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w, _ := serve(ctx, favoritesRoutes(mocks.NewMockFavoritesService(ctrl)), http.MethodGet, "/users/user01/favorites?offset=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPutFavorite(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockFavoritesService(ctrl)
+		service.EXPECT().Add(ctx, uint64(1))
+
+		w, ginCtx := serve(ctx, favoritesRoutes(service), http.MethodPut, "/works/1/favorite", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, favoritesRoutes(mocks.NewMockFavoritesService(ctrl)), http.MethodPut, "/works/abc/favorite", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE01))
+		service := mocks.NewMockFavoritesService(ctrl)
+		service.EXPECT().Add(ctx, uint64(1)).Return(expect)
+
+		_, ginCtx := serve(ctx, favoritesRoutes(service), http.MethodPut, "/works/1/favorite", "")
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
+
+func TestDeleteFavorite(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockFavoritesService(ctrl)
+	service.EXPECT().Remove(ctx, uint64(1))
+
+	w, ginCtx := serve(ctx, favoritesRoutes(service), http.MethodDelete, "/works/1/favorite", "")
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	service services.HealthService
+}
+
+//NewHealthController add /healthz, /readyz
+func NewHealthController(service services.HealthService) *HealthController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &HealthController{
+		service: service,
+	}
+}
+
+func (ctrl *HealthController) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, ctrl.service.Live(c.Request.Context()))
+}
+
+func (ctrl *HealthController) Ready(c *gin.Context) {
+	res, ok := ctrl.service.Ready(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockHealthService(ctrl)
+		healthCtrl := NewHealthController(service)
+
+		assert.Same(t, service, healthCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHealthController(nil)
+		})
+	})
+}
+
+func TestLive(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	ginCtx, r := gin.CreateTestContext(w)
+
+	expect := &beans.HealthBean{Status: "ok"}
+	service := mocks.NewMockHealthService(ctrl)
+	service.EXPECT().Live(ctx).Return(expect)
+	healthCtrl := NewHealthController(service)
+	r.GET("/healthz", healthCtrl.Live)
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	ginCtx.Request = req.WithContext(ctx)
+	r.HandleContext(ginCtx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	res, _ := json.Marshal(expect)
+	assert.Equal(t, res, w.Body.Bytes())
+}
+
+func TestReady(t *testing.T) {
+	t.Run("is ready", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		expect := &beans.HealthBean{
+			Status: "ok",
+			Checks: map[string]string{"database": "ok"},
+		}
+		service := mocks.NewMockHealthService(ctrl)
+		service.EXPECT().Ready(ctx).Return(expect, true)
+		healthCtrl := NewHealthController(service)
+		r.GET("/readyz", healthCtrl.Ready)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		ginCtx.Request = req.WithContext(ctx)
+		r.HandleContext(ginCtx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		res, _ := json.Marshal(expect)
+		assert.Equal(t, res, w.Body.Bytes())
+	})
+
+	t.Run("is not ready", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		expect := &beans.HealthBean{
+			Status: "unavailable",
+			Checks: map[string]string{"database": "unavailable"},
+		}
+		service := mocks.NewMockHealthService(ctrl)
+		service.EXPECT().Ready(ctx).Return(expect, false)
+		healthCtrl := NewHealthController(service)
+		r.GET("/readyz", healthCtrl.Ready)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		ginCtx.Request = req.WithContext(ctx)
+		r.HandleContext(ginCtx)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		res, _ := json.Marshal(expect)
+		assert.Equal(t, res, w.Body.Bytes())
+	})
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const ModerationIDKey = "id"
+
+// ModerationController は、モデレーターによる通報の確認と、作品とユーザーに対する操作を受け付ける
+type ModerationController struct {
+	service services.ModerationService
+}
+
+//NewModerationController add /moderation
+func NewModerationController(service services.ModerationService) *ModerationController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &ModerationController{
+		service: service,
+	}
+}
+
+// GetReports は、未対応の通報を返す
+func (ctrl *ModerationController) GetReports(c *gin.Context) {
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetReports(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetActions は、モデレーターの操作の記録を返す
+func (ctrl *ModerationController) GetActions(c *gin.Context) {
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetActions(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ModerateWork は、作品に対する操作を行う
+func (ctrl *ModerationController) ModerateWork(c *gin.Context) {
+	workID, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.ModerationFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	if err := ctrl.service.ModerateWork(c.Request.Context(), workID, form); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ModerateUser は、ユーザーに対する操作を行う
+func (ctrl *ModerationController) ModerateUser(c *gin.Context) {
+	form := &beans.ModerationFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	if err := ctrl.service.ModerateUser(c.Request.Context(), c.Param(ModerationIDKey), form); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
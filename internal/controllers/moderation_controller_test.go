@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewModerationController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockModerationService(ctrl)
+		moderationCtrl := NewModerationController(service)
+
+		assert.Same(t, service, moderationCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewModerationController(nil)
+		})
+	})
+}
+
+func moderationRoutes(service *mocks.MockModerationService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		moderationCtrl := NewModerationController(service)
+		r.GET("/moderation/reports", moderationCtrl.GetReports)
+		r.GET("/moderation/actions", moderationCtrl.GetActions)
+		r.POST("/moderation/works/:id/actions", moderationCtrl.ModerateWork)
+		r.POST("/moderation/users/:id/actions", moderationCtrl.ModerateUser)
+	}
+}
+
+func TestGetModerationReports(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().GetReports(ctx, 10, 100).Return(&beans.PaginationBean{Offset: 10, Items: []interface{}{}}, nil)
+
+		w, ginCtx := serve(ctx, moderationRoutes(service), http.MethodGet, "/moderation/reports?offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().GetReports(ctx, 0, 100).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, moderationRoutes(service), http.MethodGet, "/moderation/reports", "")
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
+
+func TestGetModerationActions(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().GetActions(ctx, 0, 20).Return(&beans.PaginationBean{Items: []interface{}{}}, nil)
+
+		w, ginCtx := serve(ctx, moderationRoutes(service), http.MethodGet, "/moderation/actions?limit=20", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w, _ := serve(ctx, moderationRoutes(mocks.NewMockModerationService(ctrl)), http.MethodGet, "/moderation/actions?offset=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestModerateWork(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().ModerateWork(ctx, uint64(1), &beans.ModerationFormBean{Action: "hide", Note: "spam"}).Return(nil)
+
+		w, ginCtx := serve(ctx, moderationRoutes(service), http.MethodPost, "/moderation/works/1/actions", `{"action":"hide","note":"spam"}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Action is missing", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, moderationRoutes(mocks.NewMockModerationService(ctrl)), http.MethodPost, "/moderation/works/1/actions", `{"note":"spam"}`)
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, moderationRoutes(mocks.NewMockModerationService(ctrl)), http.MethodPost, "/moderation/works/abc/actions", `{"action":"hide"}`)
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestModerateUser(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().ModerateUser(ctx, "user01", &beans.ModerationFormBean{Action: "suspend"}).Return(nil)
+
+		w, ginCtx := serve(ctx, moderationRoutes(service), http.MethodPost, "/moderation/users/user01/actions", `{"action":"suspend"}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE11))
+		service := mocks.NewMockModerationService(ctrl)
+		service.EXPECT().ModerateUser(ctx, "user01", gomock.Any()).Return(expect)
+
+		_, ginCtx := serve(ctx, moderationRoutes(service), http.MethodPost, "/moderation/users/user01/actions", `{"action":"suspend"}`)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportsController は、作品の通報を受け付ける
+type ReportsController struct {
+	service services.ReportsService
+}
+
+//NewReportsController add /works/:id/reports
+func NewReportsController(service services.ReportsService) *ReportsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &ReportsController{
+		service: service,
+	}
+}
+
+// Post は、作品を通報する。通報済みの場合も成功とする。
+func (ctrl *ReportsController) Post(c *gin.Context) {
+	workID, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.ReportFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	if err := ctrl.service.Create(c.Request.Context(), workID, form); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
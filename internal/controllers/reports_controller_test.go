@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReportsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockReportsService(ctrl)
+		reportsCtrl := NewReportsController(service)
+
+		assert.Same(t, service, reportsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewReportsController(nil)
+		})
+	})
+}
+
+func reportsRoutes(service *mocks.MockReportsService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		reportsCtrl := NewReportsController(service)
+		r.POST("/works/:id/reports", reportsCtrl.Post)
+	}
+}
+
+func TestPostReport(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockReportsService(ctrl)
+		service.EXPECT().Create(ctx, uint64(1), &beans.ReportFormBean{Reason: constants.ReportCopyright, Note: "hoge"}).Return(nil)
+
+		w, ginCtx := serve(ctx, reportsRoutes(service), http.MethodPost, "/works/1/reports", `{"reason":4,"note":"hoge"}`)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Reason is invalid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, reportsRoutes(mocks.NewMockReportsService(ctrl)), http.MethodPost, "/works/1/reports", `{"reason":9}`)
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, reportsRoutes(mocks.NewMockReportsService(ctrl)), http.MethodPost, "/works/abc/reports", `{"reason":1}`)
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockReportsService(ctrl)
+		service.EXPECT().Create(ctx, uint64(1), gomock.Any()).Return(expect)
+
+		_, ginCtx := serve(ctx, reportsRoutes(service), http.MethodPost, "/works/1/reports", `{"reason":1}`)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const RevisionsVersionKey = "version"
+
+// RevisionsController は、作品の履歴の閲覧と復元を受け付ける
+type RevisionsController struct {
+	service services.RevisionsService
+}
+
+//NewRevisionsController add /works/:id/revisions
+func NewRevisionsController(service services.RevisionsService) *RevisionsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &RevisionsController{
+		service: service,
+	}
+}
+
+// Get は、作品の履歴を新しいバージョンから順に返す
+func (ctrl *RevisionsController) Get(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetAll(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// FindByVersion は、作品の指定したバージョンの履歴を返す
+func (ctrl *RevisionsController) FindByVersion(c *gin.Context) {
+	id, version, err := extractRevisionKey(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	res, err := ctrl.service.FindByVersion(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Diff は、クエリパラメータfromとtoのバージョンの差分を返す。toを省略した場合は、現在のバージョンと比較する。
+func (ctrl *RevisionsController) Diff(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	from, err := parseVersion(c.Query("from"))
+	if err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+	var to uint
+	if v := c.Query("to"); v != "" {
+		if to, err = parseVersion(v); err != nil {
+			c.Error(errors.NewBadRequestError(err.Error(), err))
+			return
+		}
+	}
+
+	res, err := ctrl.service.Diff(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Restore は、作品を指定したバージョンの内容に戻し、更新後の作品を返す
+func (ctrl *RevisionsController) Restore(c *gin.Context) {
+	id, version, err := extractRevisionKey(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	res, err := ctrl.service.Restore(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func extractRevisionKey(c *gin.Context) (uint64, uint, error) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		return 0, 0, err
+	}
+	version, err := parseVersion(c.Param(RevisionsVersionKey))
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, version, nil
+}
+
+// parseVersion は、1以上のバージョンを解析する
+func parseVersion(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 {
+		return 0, strconv.ErrRange
+	}
+	return uint(v), nil
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevisionsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockRevisionsService(ctrl)
+		revisionsCtrl := NewRevisionsController(service)
+
+		assert.Same(t, service, revisionsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewRevisionsController(nil)
+		})
+	})
+}
+
+func revisionsRoutes(service *mocks.MockRevisionsService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		revisionsCtrl := NewRevisionsController(service)
+		r.GET("/:id/revisions", revisionsCtrl.Get)
+		r.GET("/:id/revisions/:version", revisionsCtrl.FindByVersion)
+		r.POST("/:id/revisions/:version/restore", revisionsCtrl.Restore)
+		r.GET("/:id/diff", revisionsCtrl.Diff)
+	}
+}
+
+func TestGetRevisions(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().GetAll(ctx, uint64(1), 10, 100).Return(&beans.PaginationBean{TotalItems: 0, Offset: 10, Items: []interface{}{}}, nil)
+
+		w, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodGet, "/1/revisions?offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, revisionsRoutes(mocks.NewMockRevisionsService(ctrl)), http.MethodGet, "/abc/revisions", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestFindRevisionByVersion(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := &entities.WorkRevision{WorkID: 1, Version: 2, Title: "hoge"}
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().FindByVersion(ctx, uint64(1), uint(2)).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodGet, "/1/revisions/2", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res entities.WorkRevision
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		assert.Equal(t, *expect, res)
+	})
+
+	t.Run("Version 0", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, revisionsRoutes(mocks.NewMockRevisionsService(ctrl)), http.MethodGet, "/1/revisions/0", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestDiffRevisions(t *testing.T) {
+	t.Run("Compare with current version", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := &beans.RevisionDiffBean{
+			From:    1,
+			To:      3,
+			Changes: []*beans.FieldChangeBean{{Field: "title", From: "hoge", To: "fuga"}},
+		}
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().Diff(ctx, uint64(1), uint(1), uint(0)).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodGet, "/1/diff?from=1", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res beans.RevisionDiffBean
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		assert.Equal(t, *expect, res)
+	})
+
+	t.Run("Compare two versions", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().Diff(ctx, uint64(1), uint(1), uint(2)).Return(&beans.RevisionDiffBean{From: 1, To: 2}, nil)
+
+		w, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodGet, "/1/diff?from=1&to=2", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing from", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, revisionsRoutes(mocks.NewMockRevisionsService(ctrl)), http.MethodGet, "/1/diff?to=2", "")
+
+		err := ginCtx.Errors.Last()
+		var bre *myErr.BadRequestError
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.As(err.Err, &bre))
+		}
+	})
+}
+
+func TestRestoreRevision(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := &entities.Work{ID: 1, Title: "hoge", Version: 4}
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().Restore(ctx, uint64(1), uint(2)).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodPost, "/1/revisions/2/restore", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res entities.Work
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		assert.Equal(t, *expect, res)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockRevisionsService(ctrl)
+		service.EXPECT().Restore(ctx, uint64(1), uint(2)).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, revisionsRoutes(service), http.MethodPost, "/1/revisions/2/restore", "")
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve は、registerで登録したルートにリクエストを送信する。bodyが空でない場合は、JSONとして送信する。
+func serve(ctx context.Context, register func(*gin.Engine), method string, path string, body string) (*httptest.ResponseRecorder, *gin.Context) {
+	w := httptest.NewRecorder()
+	ginCtx, r := gin.CreateTestContext(w)
+	register(r)
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set(contentTypeKey, "application/json")
+	}
+	ginCtx.Request = req.WithContext(ctx)
+	r.HandleContext(ginCtx)
+	return w, ginCtx
+}
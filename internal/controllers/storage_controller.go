@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/gin-gonic/gin"
+)
+
+const StorageKeyKey = "key"
+
+// StorageController は、ローカルのストレージを使用する場合に、署名付きURLへ直接送信されたファイルを受信し、
+// 署名付きURLで非公開のファイルを配信する
+type StorageController struct {
+	receiver         lib.SignedUploadReceiver
+	downloadReceiver lib.SignedDownloadReceiver
+	storage          lib.StorageClient
+}
+
+func NewStorageController(receiver lib.SignedUploadReceiver, downloadReceiver lib.SignedDownloadReceiver, storage lib.StorageClient) *StorageController {
+	if receiver == nil {
+		panic("receiver can't be nil")
+	}
+	if downloadReceiver == nil {
+		panic("downloadReceiver can't be nil")
+	}
+	if storage == nil {
+		panic("storage can't be nil")
+	}
+
+	return &StorageController{
+		receiver:         receiver,
+		downloadReceiver: downloadReceiver,
+		storage:          storage,
+	}
+}
+
+// Get は、署名を検証し、非公開のファイルを配信する。署名が不正な場合は、ファイルの有無を区別しない。
+func (ctrl *StorageController) Get(c *gin.Context) {
+	path, err := ctrl.downloadReceiver.VerifyDownload(c.Param(StorageKeyKey), c.Request)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	// 署名付きURLは共有されうるため、期限切れの後もキャッシュされないようにする
+	c.Header("Cache-Control", "private, no-store")
+	c.File(path)
+}
+
+// Put は、署名を検証し、リクエストボディを一時領域に保存する
+func (ctrl *StorageController) Put(c *gin.Context) {
+	key := c.Param(StorageKeyKey)
+	object, err := ctrl.receiver.VerifyUpload(key, c.Request)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE02), errors.Cause(err)))
+		return
+	}
+
+	body := &countingReader{reader: io.LimitReader(c.Request.Body, object.Size)}
+	if err := ctrl.storage.Upload(key, body, "", object.ContentType); err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE99), errors.Cause(err)))
+		return
+	}
+
+	// 接続が途中で切れた場合は、送信済みの分を残さない
+	if body.count != object.Size {
+		ctrl.storage.Delete(key)
+		c.Error(errors.NewBadRequestError("request body is shorter than Content-Length", io.ErrUnexpectedEOF))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorageController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	receiver := mocks.NewMockSignedUploadReceiver(ctrl)
+	downloadReceiver := mocks.NewMockSignedDownloadReceiver(ctrl)
+	storage := mocks.NewMockStorageClient(ctrl)
+
+	storageCtrl := NewStorageController(receiver, downloadReceiver, storage)
+	assert.Same(t, receiver, storageCtrl.receiver)
+	assert.Same(t, downloadReceiver, storageCtrl.downloadReceiver)
+	assert.Same(t, storage, storageCtrl.storage)
+
+	assert.Panics(t, func() { NewStorageController(nil, downloadReceiver, storage) })
+	assert.Panics(t, func() { NewStorageController(receiver, nil, storage) })
+	assert.Panics(t, func() { NewStorageController(receiver, downloadReceiver, nil) })
+}
+
+func TestGetStorage(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		file, err := ioutil.TempFile("", "wu-private")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Remove(file.Name()) })
+		file.WriteString("1234")
+		file.Close()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest(http.MethodGet, "/abcde12345.zip?signature=abc", nil)
+		ginCtx.Request = req
+
+		downloadReceiver := mocks.NewMockSignedDownloadReceiver(ctrl)
+		downloadReceiver.EXPECT().VerifyDownload("abcde12345.zip", gomock.Any()).Return(file.Name(), nil)
+		storageCtrl := NewStorageController(mocks.NewMockSignedUploadReceiver(ctrl), downloadReceiver, mocks.NewMockStorageClient(ctrl))
+		r.GET("/:key", storageCtrl.Get)
+
+		r.HandleContext(ginCtx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1234", w.Body.String())
+		assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest(http.MethodGet, "/abcde12345.zip?signature=abc", nil)
+		ginCtx.Request = req
+
+		downloadReceiver := mocks.NewMockSignedDownloadReceiver(ctrl)
+		downloadReceiver.EXPECT().VerifyDownload("abcde12345.zip", gomock.Any()).Return("", errors.New("signature does not match"))
+		storageCtrl := NewStorageController(mocks.NewMockSignedUploadReceiver(ctrl), downloadReceiver, mocks.NewMockStorageClient(ctrl))
+		r.GET("/:key", storageCtrl.Get)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if errActual != nil && errors.As(errActual.Err, &appErr) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
+
+func TestPutStorage(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest(http.MethodPut, "/abcde12345.zip?signature=abc", strings.NewReader("1234"))
+		ginCtx.Request = req
+
+		receiver := mocks.NewMockSignedUploadReceiver(ctrl)
+		receiver.EXPECT().VerifyUpload("abcde12345.zip", gomock.Any()).Return(&lib.ObjectInfo{Size: 4, ContentType: "application/zip"}, nil)
+		storage := mocks.NewMockStorageClient(ctrl)
+		storage.
+			EXPECT().
+			Upload("abcde12345.zip", gomock.Any(), "", "application/zip").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("1234"), b)
+				return err
+			})
+		storageCtrl := NewStorageController(receiver, mocks.NewMockSignedDownloadReceiver(ctrl), storage)
+		r.PUT("/:key", storageCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest(http.MethodPut, "/abcde12345.zip?signature=abc", strings.NewReader("1234"))
+		ginCtx.Request = req
+
+		receiver := mocks.NewMockSignedUploadReceiver(ctrl)
+		receiver.EXPECT().VerifyUpload("abcde12345.zip", gomock.Any()).Return(nil, errors.New("signature does not match"))
+		storage := mocks.NewMockStorageClient(ctrl)
+		storageCtrl := NewStorageController(receiver, mocks.NewMockSignedDownloadReceiver(ctrl), storage)
+		r.PUT("/:key", storageCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if errActual != nil && errors.As(errActual.Err, &appErr) {
+			assert.Equal(t, myErr.WUE02, appErr.Code())
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Body is shorter than signed size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest(http.MethodPut, "/abcde12345.zip?signature=abc", strings.NewReader("12"))
+		ginCtx.Request = req
+
+		receiver := mocks.NewMockSignedUploadReceiver(ctrl)
+		receiver.EXPECT().VerifyUpload("abcde12345.zip", gomock.Any()).Return(&lib.ObjectInfo{Size: 4, ContentType: "application/zip"}, nil)
+		storage := mocks.NewMockStorageClient(ctrl)
+		storage.
+			EXPECT().
+			Upload("abcde12345.zip", gomock.Any(), "", "application/zip").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return err
+			})
+		storage.EXPECT().Delete("abcde12345.zip")
+		storageCtrl := NewStorageController(receiver, mocks.NewMockSignedDownloadReceiver(ctrl), storage)
+		r.PUT("/:key", storageCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
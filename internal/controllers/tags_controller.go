@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const TagsPrefixKey = "q"
+
+// defaultTagsLimit, maxTagsLimit は、タグの検索で返す件数の既定値と上限
+const defaultTagsLimit = 20
+const maxTagsLimit = 100
+
+// TagsController は、タグの検索を受け付ける
+type TagsController struct {
+	service services.TagsService
+}
+
+//NewTagsController add /tags
+func NewTagsController(service services.TagsService) *TagsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &TagsController{
+		service: service,
+	}
+}
+
+// Get は、クエリパラメータqで始まるタグを、付いた作品の多い順に返す。qを省略した場合は全てのタグを対象にする。
+func (ctrl *TagsController) Get(c *gin.Context) {
+	_, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = defaultTagsLimit
+	}
+	if limit > maxTagsLimit {
+		limit = maxTagsLimit
+	}
+
+	res, err := ctrl.service.Search(c.Request.Context(), c.Query(TagsPrefixKey), limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
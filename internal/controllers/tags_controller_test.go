@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTagsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTagsService(ctrl)
+		tagsCtrl := NewTagsController(service)
+
+		assert.Same(t, service, tagsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTagsController(nil)
+		})
+	})
+}
+
+func tagsRoutes(service *mocks.MockTagsService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		tagsCtrl := NewTagsController(service)
+		r.GET("/tags", tagsCtrl.Get)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := []*entities.TagCount{{Name: "game", Count: 3}, {Name: "garden", Count: 1}}
+		service := mocks.NewMockTagsService(ctrl)
+		service.EXPECT().Search(ctx, "ga", defaultTagsLimit).Return(expect, nil)
+
+		w, ginCtx := serve(ctx, tagsRoutes(service), http.MethodGet, "/tags?q=ga", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res, _ := json.Marshal(expect)
+		assert.Equal(t, res, w.Body.Bytes())
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTagsService(ctrl)
+		service.EXPECT().Search(ctx, "", maxTagsLimit).Return([]*entities.TagCount{}, nil)
+
+		w, _ := serve(ctx, tagsRoutes(service), http.MethodGet, "/tags?limit=1000", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w, _ := serve(ctx, tagsRoutes(mocks.NewMockTagsService(ctrl)), http.MethodGet, "/tags?limit=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("error")
+		service := mocks.NewMockTagsService(ctrl)
+		service.EXPECT().Search(ctx, "", defaultTagsLimit).Return(nil, expect)
+
+		_, ginCtx := serve(ctx, tagsRoutes(service), http.MethodGet, "/tags", "")
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashController は、削除した作品の閲覧、復元と物理削除を受け付ける
+type TrashController struct {
+	service services.TrashService
+}
+
+//NewTrashController add /users/me/trash, /works/:id/restore and /trash/:id
+func NewTrashController(service services.TrashService) *TrashController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &TrashController{
+		service: service,
+	}
+}
+
+// Get は、ログイン中のユーザーが削除した作品を返す
+func (ctrl *TrashController) Get(c *gin.Context) {
+	// ゴミ箱は本人のみが閲覧できるため、/users/me/trash 以外は存在しないものとする
+	if c.Param(UsersIDKey) != UsersMe {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01)))
+		return
+	}
+
+	offset, limit, err := common.ExtractOffsetAndLimit(c.Request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if limit == -1 {
+		limit = 100
+	}
+
+	res, err := ctrl.service.GetAll(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Restore は、削除した作品を復元し、復元後の作品を返す
+func (ctrl *TrashController) Restore(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	res, err := ctrl.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Purge は、削除した作品をすぐに物理削除する
+func (ctrl *TrashController) Purge(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	if err := ctrl.service.Purge(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
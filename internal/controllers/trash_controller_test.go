@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTrashController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTrashService(ctrl)
+		trashCtrl := NewTrashController(service)
+
+		assert.Same(t, service, trashCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTrashController(nil)
+		})
+	})
+}
+
+func trashRoutes(service *mocks.MockTrashService) func(*gin.Engine) {
+	return func(r *gin.Engine) {
+		trashCtrl := NewTrashController(service)
+		r.GET("/users/:id/trash", trashCtrl.Get)
+		r.POST("/works/:id/restore", trashCtrl.Restore)
+		r.DELETE("/trash/:id", trashCtrl.Purge)
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTrashService(ctrl)
+		service.EXPECT().GetAll(ctx, 10, 100).Return(&beans.PaginationBean{TotalItems: 0, Offset: 10, Items: []interface{}{}}, nil)
+
+		w, ginCtx := serve(ctx, trashRoutes(service), http.MethodGet, "/users/me/trash?offset=10", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid offset", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w, _ := serve(ctx, trashRoutes(mocks.NewMockTrashService(ctrl)), http.MethodGet, "/users/me/trash?offset=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Trash of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, trashRoutes(mocks.NewMockTrashService(ctrl)), http.MethodGet, "/users/user01/trash", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestRestoreFromTrash(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTrashService(ctrl)
+		service.EXPECT().Restore(ctx, uint64(1)).Return(&entities.Work{ID: 1}, nil)
+
+		w, ginCtx := serve(ctx, trashRoutes(service), http.MethodPost, "/works/1/restore", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		_, ginCtx := serve(ctx, trashRoutes(mocks.NewMockTrashService(ctrl)), http.MethodPost, "/works/abc/restore", "")
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+}
+
+func TestPurgeFromTrash(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockTrashService(ctrl)
+		service.EXPECT().Purge(ctx, uint64(1))
+
+		w, ginCtx := serve(ctx, trashRoutes(service), http.MethodDelete, "/trash/1", "")
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Not an admin", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+		service := mocks.NewMockTrashService(ctrl)
+		service.EXPECT().Purge(ctx, uint64(1)).Return(expect)
+
+		_, ginCtx := serve(ctx, trashRoutes(service), http.MethodDelete, "/trash/1", "")
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// UploadSessionsController は、クライアントがストレージへ直接ファイルを送信するためのアップロードを作成する
+type UploadSessionsController struct {
+	service services.UploadsService
+}
+
+func NewUploadSessionsController(service services.UploadsService) *UploadSessionsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &UploadSessionsController{service: service}
+}
+
+// Post は、アップロードを作成し、ファイルの送信先を返す
+func (ctrl *UploadSessionsController) Post(c *gin.Context) {
+	form := &beans.UploadSessionFormBean{}
+	if err := c.ShouldBindJSON(form); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.CreateSession(c.Request.Context(), form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// サーバー自身が受信する場合は、パスのみが返される
+	if strings.HasPrefix(res.URL, "/") {
+		res.URL = fmt.Sprintf("%s://%s%s", common.GetScheme(c.Request), c.Request.Host, res.URL)
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUploadSessionsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockUploadsService(ctrl)
+		sessionsCtrl := NewUploadSessionsController(service)
+
+		assert.Same(t, service, sessionsCtrl.service)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewUploadSessionsController(nil)
+		})
+	})
+}
+
+func TestPostUploadSessions(t *testing.T) {
+	const body = `{"filename":"content01.zip","contentType":"application/zip","length":10}`
+	form := &beans.UploadSessionFormBean{
+		Filename:    "content01.zip",
+		ContentType: "application/zip",
+		Length:      10,
+	}
+	expiresAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		url    string
+		expect string
+	}{
+		"Is valid":                  {"https://bucket.s3.amazonaws.com/pending/key?X-Amz-Signature=abc", "https://bucket.s3.amazonaws.com/pending/key?X-Amz-Signature=abc"},
+		"Received by local storage": {"/api/v1/storage/key?signature=abc", "http://example.com/api/v1/storage/key?signature=abc"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			defer ctrl.Finish()
+
+			w := httptest.NewRecorder()
+			ginCtx, r := gin.CreateTestContext(w)
+
+			service := mocks.NewMockUploadsService(ctrl)
+			service.EXPECT().CreateSession(ctx, form).Return(&beans.UploadSessionBean{
+				ID:        "upload01",
+				Method:    http.MethodPut,
+				URL:       tc.url,
+				Headers:   map[string]string{"Content-Type": "application/zip"},
+				ExpiresAt: expiresAt,
+			}, nil)
+			sessionsCtrl := NewUploadSessionsController(service)
+			r.POST("/", sessionsCtrl.Post)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Host = "example.com"
+			req.Header.Set(contentTypeKey, "application/json")
+			ginCtx.Request = req.WithContext(ctx)
+
+			r.HandleContext(ginCtx)
+
+			err := ginCtx.Errors.Last()
+			assert.Nil(t, err, "%T %v", err, err)
+			assert.Equal(t, http.StatusCreated, w.Code)
+			var res beans.UploadSessionBean
+			_ = json.Unmarshal(w.Body.Bytes(), &res)
+			assert.Equal(t, beans.UploadSessionBean{
+				ID:        "upload01",
+				Method:    http.MethodPut,
+				URL:       tc.expect,
+				Headers:   map[string]string{"Content-Type": "application/zip"},
+				ExpiresAt: expiresAt,
+			}, res)
+		})
+	}
+
+	t.Run("Missing filename", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		sessionsCtrl := NewUploadSessionsController(service)
+		r.POST("/", sessionsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"contentType":"application/zip","length":10}`))
+		req.Header.Set(contentTypeKey, "application/json")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Is fail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE03))
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().CreateSession(gomock.Any(), form).Return(nil, errExpect)
+		sessionsCtrl := NewUploadSessionsController(service)
+		r.POST("/", sessionsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(contentTypeKey, "application/json")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
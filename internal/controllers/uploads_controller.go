@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/edy4c7/works-uploader/internal/common"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const UploadsIDKey = "id"
+
+// tus 1.0.0 (https://tus.io/protocols/resumable-upload.html) のヘッダー
+const (
+	tusVersion          = "1.0.0"
+	tusExtensions       = "creation,termination,expiration"
+	headerTusResumable  = "Tus-Resumable"
+	headerTusVersion    = "Tus-Version"
+	headerTusExtension  = "Tus-Extension"
+	headerTusMaxSize    = "Tus-Max-Size"
+	headerUploadLength  = "Upload-Length"
+	headerUploadOffset  = "Upload-Offset"
+	headerUploadMeta    = "Upload-Metadata"
+	headerUploadExpires = "Upload-Expires"
+	contentTypeOffset   = "application/offset+octet-stream"
+	metadataFilename    = "filename"
+)
+
+// UploadsController は、tusプロトコルによる再開可能なアップロードを受け付ける
+type UploadsController struct {
+	service services.UploadsService
+	maxSize int64
+}
+
+//NewUploadsController は、受け付ける最大バイト数を指定し、UploadsControllerの新しいインスタンスを生成する
+func NewUploadsController(service services.UploadsService, maxSize int64) *UploadsController {
+	if service == nil {
+		panic("service can't be nil")
+	}
+
+	return &UploadsController{
+		service: service,
+		maxSize: maxSize,
+	}
+}
+
+// Options は、サーバーが対応するプロトコルのバージョンと拡張を返す
+func (ctrl *UploadsController) Options(c *gin.Context) {
+	c.Header(headerTusResumable, tusVersion)
+	c.Header(headerTusVersion, tusVersion)
+	c.Header(headerTusExtension, tusExtensions)
+	c.Header(headerTusMaxSize, strconv.FormatInt(ctrl.maxSize, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// Post は、アップロードを作成する
+func (ctrl *UploadsController) Post(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader(headerUploadLength), 10, 64)
+	if err != nil {
+		// Upload-Defer-Length には対応しない
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader(headerUploadMeta))
+	if err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Create(c.Request.Context(), length, metadata[metadataFilename])
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	scheme := common.GetScheme(c.Request)
+
+	c.Header("Location", fmt.Sprintf("%s://%s%s/%s", scheme, c.Request.Host, c.FullPath(), res.ID))
+	setUploadHeaders(c, res)
+	c.Status(http.StatusCreated)
+}
+
+// Head は、アップロード済みの位置を返す。クライアントは、この位置から再開する。
+func (ctrl *UploadsController) Head(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	res, err := ctrl.service.FindByID(c.Request.Context(), c.Param(UploadsIDKey))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header(headerUploadLength, strconv.FormatInt(res.Length, 10))
+	if res.Filename != "" {
+		c.Header(headerUploadMeta, fmt.Sprintf("%s %s", metadataFilename, base64.StdEncoding.EncodeToString([]byte(res.Filename))))
+	}
+	setUploadHeaders(c, res)
+	c.Status(http.StatusOK)
+}
+
+// Patch は、リクエストボディを指定された位置から追記する
+func (ctrl *UploadsController) Patch(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	if c.ContentType() != contentTypeOffset {
+		c.AbortWithStatus(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(headerUploadOffset), 10, 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError(err.Error(), err))
+		return
+	}
+
+	res, err := ctrl.service.Append(c.Request.Context(), c.Param(UploadsIDKey), offset, c.Request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setUploadHeaders(c, res)
+	c.Status(http.StatusNoContent)
+}
+
+// Delete は、アップロードを中止する
+func (ctrl *UploadsController) Delete(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	if err := ctrl.service.DeleteByID(c.Request.Context(), c.Param(UploadsIDKey)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkTusResumable は、クライアントのプロトコルのバージョンを確認する。対応しない場合は412を返す。
+func checkTusResumable(c *gin.Context) bool {
+	c.Header(headerTusResumable, tusVersion)
+
+	if c.GetHeader(headerTusResumable) != tusVersion {
+		c.Header(headerTusVersion, tusVersion)
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+
+	return true
+}
+
+func setUploadHeaders(c *gin.Context, upload *entities.Upload) {
+	c.Header(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	c.Header(headerUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// parseUploadMetadata は、"キー Base64の値" をカンマで区切った Upload-Metadata を解析する
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		kv := strings.Fields(pair)
+		switch len(kv) {
+		case 1:
+			metadata[kv[0]] = ""
+		case 2:
+			v, err := base64.StdEncoding.DecodeString(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", headerUploadMeta, err)
+			}
+			metadata[kv[0]] = string(v)
+		default:
+			return nil, fmt.Errorf("invalid %s: %q", headerUploadMeta, pair)
+		}
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUploadsController(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+
+		assert.Same(t, service, uploadsCtrl.service)
+		assert.Equal(t, int64(100), uploadsCtrl.maxSize)
+	})
+
+	t.Run("service is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewUploadsController(nil, 100)
+		})
+	})
+}
+
+func TestOptionsUploads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	ginCtx, r := gin.CreateTestContext(w)
+
+	service := mocks.NewMockUploadsService(ctrl)
+	uploadsCtrl := NewUploadsController(service, 100)
+	r.OPTIONS("/", uploadsCtrl.Options)
+
+	// OPTIONSは、Tus-Resumableを指定しなくても応答する
+	ginCtx.Request, _ = http.NewRequest(http.MethodOptions, "/", nil)
+
+	r.HandleContext(ginCtx)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "1.0.0", w.Header().Get("Tus-Resumable"))
+	assert.Equal(t, "1.0.0", w.Header().Get("Tus-Version"))
+	assert.Equal(t, "creation,termination,expiration", w.Header().Get("Tus-Extension"))
+	assert.Equal(t, "100", w.Header().Get("Tus-Max-Size"))
+}
+
+func TestPostUploads(t *testing.T) {
+	expiresAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().Create(ctx, int64(10), "content01.zip").Return(&entities.Upload{
+			ID:        "upload01",
+			Length:    10,
+			ExpiresAt: expiresAt,
+		}, nil)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.POST("/uploads", uploadsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", nil)
+		req.Host = "example.com"
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "10")
+		req.Header.Set("Upload-Metadata", "filename Y29udGVudDAxLnppcA==,is_confidential")
+		ginCtx.Request = req.WithContext(ctx)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "http://example.com/uploads/upload01", w.Header().Get("Location"))
+		assert.Equal(t, "0", w.Header().Get("Upload-Offset"))
+		assert.Equal(t, "Sat, 02 Jan 2021 03:04:05 GMT", w.Header().Get("Upload-Expires"))
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.POST("/uploads", uploadsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", nil)
+		req.Header.Set("Tus-Resumable", "0.2.2")
+		req.Header.Set("Upload-Length", "10")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, "1.0.0", w.Header().Get("Tus-Version"))
+	})
+
+	t.Run("Invalid length", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.POST("/uploads", uploadsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Defer-Length", "1")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Invalid metadata", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.POST("/uploads", uploadsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "10")
+		req.Header.Set("Upload-Metadata", "filename content01.zip")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Is fail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE03))
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().Create(gomock.Any(), int64(1000), "").Return(nil, errExpect)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.POST("/uploads", uploadsCtrl.Post)
+
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "1000")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
+
+func TestHeadUploads(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	ginCtx, r := gin.CreateTestContext(w)
+
+	service := mocks.NewMockUploadsService(ctrl)
+	service.EXPECT().FindByID(ctx, "upload01").Return(&entities.Upload{
+		ID:        "upload01",
+		Filename:  "content01.zip",
+		Length:    10,
+		Offset:    4,
+		ExpiresAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil)
+	uploadsCtrl := NewUploadsController(service, 100)
+	r.HEAD("/:id", uploadsCtrl.Head)
+
+	req, _ := http.NewRequest(http.MethodHead, "/upload01", nil)
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	ginCtx.Request = req.WithContext(ctx)
+
+	r.HandleContext(ginCtx)
+
+	err := ginCtx.Errors.Last()
+	assert.Nil(t, err, "%T %v", err, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "10", w.Header().Get("Upload-Length"))
+	assert.Equal(t, "4", w.Header().Get("Upload-Offset"))
+	assert.Equal(t, "filename Y29udGVudDAxLnppcA==", w.Header().Get("Upload-Metadata"))
+}
+
+func TestPatchUploads(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		service.
+			EXPECT().
+			Append(ctx, "upload01", int64(4), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id string, offset int64, body io.Reader) (*entities.Upload, error) {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("5678"), b)
+				return &entities.Upload{ID: id, Length: 10, Offset: 8}, err
+			})
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.PATCH("/:id", uploadsCtrl.Patch)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/upload01", strings.NewReader("5678"))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Offset", "4")
+		req.Header.Set(contentTypeKey, "application/offset+octet-stream")
+		ginCtx.Request = req.WithContext(ctx)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "8", w.Header().Get("Upload-Offset"))
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.PATCH("/:id", uploadsCtrl.Patch)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/upload01", strings.NewReader("5678"))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Offset", "4")
+		req.Header.Set(contentTypeKey, "application/octet-stream")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Invalid offset", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.PATCH("/:id", uploadsCtrl.Patch)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/upload01", strings.NewReader("5678"))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set(contentTypeKey, "application/offset+octet-stream")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Offset mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE05))
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().Append(gomock.Any(), "upload01", int64(0), gomock.Any()).Return(nil, errExpect)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.PATCH("/:id", uploadsCtrl.Patch)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/upload01", strings.NewReader("5678"))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Offset", "0")
+		req.Header.Set(contentTypeKey, "application/offset+octet-stream")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
+
+func TestDeleteUploads(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().DeleteByID(ctx, "upload01").Return(nil)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.DELETE("/:id", uploadsCtrl.Delete)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/upload01", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		ginCtx.Request = req.WithContext(ctx)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE04))
+		service := mocks.NewMockUploadsService(ctrl)
+		service.EXPECT().DeleteByID(gomock.Any(), "upload01").Return(errExpect)
+		uploadsCtrl := NewUploadsController(service, 100)
+		r.DELETE("/:id", uploadsCtrl.Delete)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/upload01", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
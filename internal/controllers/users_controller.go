@@ -8,6 +8,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const UsersIDKey = "id"
+
+// UsersMe は、ユーザーIDの代わりに指定する、ログイン中のユーザーを表す値
+const UsersMe = "me"
+
 type UsersController struct {
 	service services.UsersService
 }
@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	goErrors "errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/edy4c7/works-uploader/internal/beans"
@@ -10,9 +14,15 @@ import (
 	"github.com/edy4c7/works-uploader/internal/errors"
 	"github.com/edy4c7/works-uploader/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 const WorksIDKey = "id"
+const WorksTagKey = "tag"
+const WorksSortKey = "sort"
+
+// maxFormValueSize は、フォームのファイル以外の項目1つあたりの最大バイト数
+const maxFormValueSize = 64 << 10
 
 type WorksController struct {
 	service services.WorksService
@@ -40,7 +50,8 @@ func (ctrl *WorksController) Get(c *gin.Context) {
 		limit = 100
 	}
 
-	res, err := ctrl.service.GetAll(c.Request.Context(), offset, limit)
+	// タグは、クエリパラメータtagを繰り返して複数指定できる
+	res, err := ctrl.service.GetAll(c.Request.Context(), c.QueryArray(WorksTagKey), c.Query(WorksSortKey), offset, limit)
 	if err != nil {
 		c.Error(err)
 		return
@@ -67,8 +78,9 @@ func (ctrl *WorksController) FindByID(c *gin.Context) {
 
 func (ctrl *WorksController) Post(c *gin.Context) {
 	form := &beans.WorksFormBean{}
-	if err := c.Bind(form); err != nil {
-		c.Error(errors.NewBadRequestError(err.Error(), err))
+	if err := ctrl.bindWorksForm(c, form, &form.Thumbnail, &form.Content); err != nil {
+		ctrl.service.Discard(c.Request.Context(), form.Thumbnail, form.Content)
+		c.Error(err)
 		return
 	}
 
@@ -84,6 +96,29 @@ func (ctrl *WorksController) Post(c *gin.Context) {
 	c.JSON(http.StatusCreated, res)
 }
 
+func (ctrl *WorksController) Put(c *gin.Context) {
+	id, err := extractWorksID(c)
+	if err != nil {
+		c.Error(errors.NewApplicationError(errors.Code(errors.WUE01), errors.Cause(err)))
+		return
+	}
+
+	form := &beans.WorkUpdateFormBean{}
+	if err := ctrl.bindWorksForm(c, form, &form.Thumbnail, &form.Content); err != nil {
+		ctrl.service.Discard(c.Request.Context(), form.Thumbnail, form.Content)
+		c.Error(err)
+		return
+	}
+
+	res, err := ctrl.service.Update(c.Request.Context(), id, form)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
 func (ctrl *WorksController) Delete(c *gin.Context) {
 	id, err := extractWorksID(c)
 	if err != nil {
@@ -99,6 +134,109 @@ func (ctrl *WorksController) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// bindWorksForm は、multipart/form-dataのパートを先頭から順に読み、ファイルは一時ファイルやメモリに
+// 溜めずにサービスへ流し込む。ファイル以外の項目は全て読み終えた後でまとめて検証する。
+// 受信したファイルは、thumbnail、contentに設定する。
+func (ctrl *WorksController) bindWorksForm(c *gin.Context, form interface{}, thumbnail, content **beans.StagedFileBean) error {
+	values, err := ctrl.readWorksForm(c, thumbnail, content)
+	if err != nil {
+		return err
+	}
+
+	// ファイルの代わりに、再開可能なアップロードで受信済みのファイルを指定できる
+	for _, ref := range []struct {
+		field string
+		param string
+		dest  **beans.StagedFileBean
+	}{
+		{services.FieldThumbnail, "thumbnailUploadId", thumbnail},
+		{services.FieldContent, "contentUploadId", content},
+	} {
+		uploadID := values.Get(ref.param)
+		if uploadID == "" {
+			continue
+		}
+		if *ref.dest != nil {
+			msg := fmt.Sprintf("%s and %s can't be specified together", ref.field, ref.param)
+			return errors.NewBadRequestError(msg, goErrors.New(msg))
+		}
+
+		staged, err := ctrl.service.StageUpload(c.Request.Context(), ref.field, uploadID)
+		if err != nil {
+			return err
+		}
+		*ref.dest = staged
+	}
+
+	if err := binding.FormPost.Bind(&http.Request{PostForm: values}, form); err != nil {
+		return errors.NewBadRequestError(err.Error(), err)
+	}
+
+	return nil
+}
+
+// readWorksForm は、ファイルをサービスに渡しながらフォームを読み込み、ファイル以外の項目を返す
+func (ctrl *WorksController) readWorksForm(c *gin.Context, thumbnail, content **beans.StagedFileBean) (url.Values, error) {
+	reader, err := c.Request.MultipartReader()
+	if err == http.ErrNotMultipart {
+		// ファイルを含まない場合は、通常のフォームでも受け付ける
+		if err := c.Request.ParseForm(); err != nil {
+			return nil, errors.NewBadRequestError(err.Error(), err)
+		}
+		return c.Request.PostForm, nil
+	}
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error(), err)
+	}
+
+	values := make(url.Values)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewBadRequestError(err.Error(), err)
+		}
+
+		if part.FileName() == "" {
+			b, err := ioutil.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+			if err != nil {
+				return nil, errors.NewBadRequestError(err.Error(), err)
+			}
+			if len(b) > maxFormValueSize {
+				msg := fmt.Sprintf("%s is too large", part.FormName())
+				return nil, errors.NewBadRequestError(msg, goErrors.New(msg))
+			}
+			values.Add(part.FormName(), string(b))
+			continue
+		}
+
+		var dest **beans.StagedFileBean
+		switch part.FormName() {
+		case services.FieldThumbnail:
+			dest = thumbnail
+		case services.FieldContent:
+			dest = content
+		default:
+			// 未知のファイル項目は読み捨てる
+			continue
+		}
+		if *dest != nil {
+			msg := fmt.Sprintf("%s is specified more than once", part.FormName())
+			return nil, errors.NewBadRequestError(msg, goErrors.New(msg))
+		}
+
+		staged, err := ctrl.service.Stage(c.Request.Context(), part.FormName(), part.FileName(), part)
+		if err != nil {
+			return nil, err
+		}
+		*dest = staged
+	}
+
+	return values, nil
+}
+
 func extractWorksID(c *gin.Context) (uint64, error) {
 	return strconv.ParseUint(c.Param(WorksIDKey), 10, 64)
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"mime/multipart"
 	"net/http"
@@ -87,7 +88,7 @@ func TestGetWorks(t *testing.T) {
 		}
 
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().GetAll(ctx, offset, limit).Return(pagination, nil)
+		service.EXPECT().GetAll(ctx, []string{}, "", offset, limit).Return(pagination, nil)
 		workCtrl := NewWorksController(service)
 		r.GET("/", workCtrl.Get)
 
@@ -103,6 +104,66 @@ func TestGetWorks(t *testing.T) {
 		assert.Equal(t, res, w.Body.Bytes())
 	})
 
+	t.Run("Is valid and specify tags", func(t *testing.T) {
+		const endpoint = "/?tag=go&tag=art"
+
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		pagination := &beans.PaginationBean{
+			TotalItems: 0,
+			Offset:     0,
+			Items:      []interface{}{},
+		}
+
+		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().GetAll(ctx, []string{"go", "art"}, "", 0, 100).Return(pagination, nil)
+		workCtrl := NewWorksController(service)
+		r.GET("/", workCtrl.Get)
+
+		req, _ := http.NewRequest(http.MethodGet, endpoint, nil)
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Is valid and specify sort", func(t *testing.T) {
+		const endpoint = "/?sort=favorites"
+
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		pagination := &beans.PaginationBean{
+			TotalItems: 0,
+			Offset:     0,
+			Items:      []interface{}{},
+		}
+
+		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().GetAll(ctx, []string{}, "favorites", 0, 100).Return(pagination, nil)
+		workCtrl := NewWorksController(service)
+		r.GET("/", workCtrl.Get)
+
+		req, _ := http.NewRequest(http.MethodGet, endpoint, nil)
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("Is valid and specify only offset", func(t *testing.T) {
 		const endpoint = "/?offset=%d"
 
@@ -123,7 +184,7 @@ func TestGetWorks(t *testing.T) {
 		}
 
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().GetAll(ctx, offset, limit).Return(pagination, nil)
+		service.EXPECT().GetAll(ctx, []string{}, "", offset, limit).Return(pagination, nil)
 		workCtrl := NewWorksController(service)
 		r.GET("/", workCtrl.Get)
 
@@ -159,7 +220,7 @@ func TestGetWorks(t *testing.T) {
 		}
 
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().GetAll(ctx, offset, limit).Return(pagination, nil)
+		service.EXPECT().GetAll(ctx, []string{}, "", offset, limit).Return(pagination, nil)
 		workCtrl := NewWorksController(service)
 		r.GET("/", workCtrl.Get)
 
@@ -195,7 +256,7 @@ func TestGetWorks(t *testing.T) {
 		}
 
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().GetAll(ctx, offset, limit).Return(pagination, nil)
+		service.EXPECT().GetAll(ctx, []string{}, "", offset, limit).Return(pagination, nil)
 		workCtrl := NewWorksController(service)
 		r.GET("/", workCtrl.Get)
 
@@ -222,7 +283,7 @@ func TestGetWorks(t *testing.T) {
 
 		errExpect := errors.New("ERROR")
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().GetAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errExpect)
+		service.EXPECT().GetAll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errExpect)
 		workCtrl := NewWorksController(service)
 		r.GET("/", workCtrl.Get)
 
@@ -347,9 +408,11 @@ func TestPostWorksWithURL(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		req = req.WithContext(ctx)
 		ginCtx.Request = req
-		var form beans.WorksFormBean
-		if err := ginCtx.ShouldBind(&form); err != nil {
-			assert.FailNow(t, err.Error())
+		form := beans.WorksFormBean{
+			Type:        contentType,
+			Title:       title,
+			Description: description,
+			ContentURL:  url,
 		}
 		service := mocks.NewMockWorksService(ctrl)
 		expect := &entities.Work{
@@ -388,6 +451,37 @@ func TestPostWorksWithURL(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Discard(gomock.Any(), nil, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Unknown content type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, 3, title, description, url, nil, nil, 0)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Discard(gomock.Any(), nil, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -447,6 +541,7 @@ func TestPostWorksWithURL(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Discard(gomock.Any(), nil, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -507,6 +602,7 @@ func TestPostWorksWithURL(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Discard(gomock.Any(), nil, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -521,8 +617,8 @@ func TestPostWorksWithURL(t *testing.T) {
 		}
 	})
 
-	t.Run("Missing to Title", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
+	t.Run("Title omitted", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 
 		w := httptest.NewRecorder()
@@ -534,20 +630,24 @@ func TestPostWorksWithURL(t *testing.T) {
 		mw.Close()
 		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
 		ginCtx.Request = req
+		// リンク先のプレビューから補うため、省略したまま登録する
+		form := beans.WorksFormBean{
+			Type:        contentType,
+			Description: description,
+			ContentURL:  url,
+		}
 		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Create(ctx, &form).Return(&entities.Work{ID: 12345}, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
 		r.HandleContext(ginCtx)
 
-		errActual := ginCtx.Errors.Last()
-		if errActual != nil {
-			var bre *myErr.BadRequestError
-			assert.True(t, errors.As(errActual.Err, &bre))
-		} else {
-			assert.Fail(t, "%v", errActual)
-		}
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
 	})
 
 	t.Run("title over than 40 characters", func(t *testing.T) {
@@ -566,6 +666,7 @@ func TestPostWorksWithURL(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		service.EXPECT().Discard(gomock.Any(), nil, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -605,11 +706,15 @@ func TestPostWorksWithFile(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		req = req.WithContext(ctx)
 		ginCtx.Request = req
-		var form beans.WorksFormBean
-		if err := ginCtx.ShouldBind(&form); err != nil {
-			assert.FailNow(t, err.Error())
-		}
 		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, contentFile := expectStage(service, ctx, thumbnail, content)
+		form := beans.WorksFormBean{
+			Type:        contentType,
+			Title:       title,
+			Description: description,
+			Thumbnail:   thumbnailFile,
+			Content:     contentFile,
+		}
 		expect := &entities.Work{
 			ID:           12345,
 			Title:        form.Title,
@@ -647,6 +752,8 @@ func TestPostWorksWithFile(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, contentFile := expectStage(service, gomock.Any(), thumbnail, content)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, contentFile)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -676,6 +783,7 @@ func TestPostWorksWithFile(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		expectStage(service, gomock.Any(), thumbnail, content)
 		errExpect := errors.New("ERROR")
 		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errExpect)
 		workCtrl := NewWorksController(service)
@@ -706,6 +814,8 @@ func TestPostWorksWithFile(t *testing.T) {
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, _ := expectStage(service, gomock.Any(), thumbnail, nil)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -720,8 +830,8 @@ func TestPostWorksWithFile(t *testing.T) {
 		}
 	})
 
-	t.Run("Missing to Thumbnail", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
+	t.Run("Thumbnail omitted", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 
 		w := httptest.NewRecorder()
@@ -733,8 +843,82 @@ func TestPostWorksWithFile(t *testing.T) {
 		mw.Close()
 		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		_, contentFile := expectStage(service, ctx, nil, content)
+		// 作品の画像からサムネイルを生成できるかは、サービスで判断する
+		form := beans.WorksFormBean{
+			Type:        contentType,
+			Title:       title,
+			Description: description,
+			Content:     contentFile,
+		}
+		expect := &entities.Work{
+			ID:           12345,
+			Title:        form.Title,
+			Description:  form.Description,
+			ThumbnailURL: "https://example.com/thumbnail_320.jpg",
+			Thumbnails:   entities.ImageVariants{"320": "https://example.com/thumbnail_320.jpg"},
+			ContentURL:   "https://example.com/contenturl",
+		}
+		service.EXPECT().Create(ctx, &form).Return(expect, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var res entities.Work
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		assert.Equal(t, *expect, res)
+	})
+
+	t.Run("Missing to Description", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, contentType, title, "", "", thumbnail, content, 0)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		expectStage(service, gomock.Any(), thumbnail, content)
+		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.Work{}, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+	})
+
+	t.Run("Missing to Title", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, contentType, "", description, "", thumbnail, content, 0)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, contentFile := expectStage(service, gomock.Any(), thumbnail, content)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, contentFile)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -749,7 +933,7 @@ func TestPostWorksWithFile(t *testing.T) {
 		}
 	})
 
-	t.Run("Missing to Description", func(t *testing.T) {
+	t.Run("File too large", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -758,13 +942,56 @@ func TestPostWorksWithFile(t *testing.T) {
 
 		buff := new(bytes.Buffer)
 		mw := multipart.NewWriter(buff)
-		createWorksFormRequestBody(mw, contentType, title, "", "", thumbnail, content, 0)
+		createWorksFormRequestBody(mw, contentType, title, description, "", thumbnail, content, 0)
 		mw.Close()
 		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
-		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.Work{}, nil)
+		thumbnailFile := &beans.StagedFileBean{Key: "thumbnail.png", Filename: "thumbnail.png", Size: 2}
+		service.EXPECT().Stage(gomock.Any(), "thumbnail", "thumbnail.png", gomock.Any()).Return(thumbnailFile, nil)
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE03))
+		service.EXPECT().Stage(gomock.Any(), "content", "content.png", gomock.Any()).Return(nil, errExpect)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("Files are streamed in order", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		// ファイルを先に送信しても、後から受信した項目と合わせて検証される
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, -1, "", "", "", thumbnail, content, 0)
+		mw.WriteField("type", fmt.Sprint(contentType))
+		mw.WriteField("title", title)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, contentFile := expectStage(service, ctx, thumbnail, content)
+		service.EXPECT().Create(ctx, &beans.WorksFormBean{
+			Type:      contentType,
+			Title:     title,
+			Thumbnail: thumbnailFile,
+			Content:   contentFile,
+		}).Return(&entities.Work{ID: 1}, nil)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -772,9 +999,48 @@ func TestPostWorksWithFile(t *testing.T) {
 
 		err := ginCtx.Errors.Last()
 		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
 	})
 
-	t.Run("Missing to Title", func(t *testing.T) {
+	t.Run("Upload IDs", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		// 再開可能なアップロードで送信済みのファイルをIDで指定する
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, contentType, title, "", "", thumbnail, nil, 0)
+		mw.WriteField("contentUploadId", "upload01")
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, _ := expectStage(service, ctx, thumbnail, nil)
+		contentFile := &beans.StagedFileBean{Key: "content.zip", Filename: "content.zip", Size: 2, UploadID: "upload01"}
+		service.EXPECT().StageUpload(ctx, "content", "upload01").Return(contentFile, nil)
+		service.EXPECT().Create(ctx, &beans.WorksFormBean{
+			Type:            contentType,
+			Title:           title,
+			ContentUploadID: "upload01",
+			Thumbnail:       thumbnailFile,
+			Content:         contentFile,
+		}).Return(&entities.Work{ID: 1}, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Both file and upload ID", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -783,12 +1049,15 @@ func TestPostWorksWithFile(t *testing.T) {
 
 		buff := new(bytes.Buffer)
 		mw := multipart.NewWriter(buff)
-		createWorksFormRequestBody(mw, contentType, "", description, "", thumbnail, content, 0)
+		createWorksFormRequestBody(mw, contentType, title, "", "", thumbnail, content, 0)
+		mw.WriteField("contentUploadId", "upload01")
 		mw.Close()
 		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
 		req.Header.Set(contentTypeKey, mw.FormDataContentType())
 		ginCtx.Request = req
 		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, contentFile := expectStage(service, gomock.Any(), thumbnail, content)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, contentFile)
 		workCtrl := NewWorksController(service)
 		r.POST("/", workCtrl.Post)
 
@@ -802,6 +1071,177 @@ func TestPostWorksWithFile(t *testing.T) {
 			assert.Fail(t, "%v", errActual)
 		}
 	})
+
+	t.Run("Upload not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, contentType, title, "", "", thumbnail, nil, 0)
+		mw.WriteField("contentUploadId", "upload01")
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, endpoint, buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		thumbnailFile, _ := expectStage(service, gomock.Any(), thumbnail, nil)
+		errExpect := myErr.NewApplicationError(myErr.Code(myErr.WUE04))
+		service.EXPECT().StageUpload(gomock.Any(), "content", "upload01").Return(nil, errExpect)
+		service.EXPECT().Discard(gomock.Any(), thumbnailFile, nil)
+		workCtrl := NewWorksController(service)
+		r.POST("/", workCtrl.Post)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			assert.Same(t, errExpect, errActual.Err)
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+}
+
+func TestPutWorks(t *testing.T) {
+	const endpoint = "/%v"
+
+	title := "foo"
+	description := "aaaaaaaaaaaaaa"
+	content := []byte{0xab, 0xcd}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, -1, title, description, "", nil, content, 2)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf(endpoint, 1234), buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		_, contentFile := expectStage(service, ctx, nil, content)
+		form := beans.WorkUpdateFormBean{
+			Version:     2,
+			Title:       title,
+			Description: description,
+			Content:     contentFile,
+		}
+		expect := &entities.Work{
+			ID:          1234,
+			Title:       form.Title,
+			Description: form.Description,
+			ContentURL:  "https://example.com/contenturl",
+			Version:     3,
+		}
+		service.EXPECT().Update(ctx, uint64(1234), &form).Return(expect, nil)
+		workCtrl := NewWorksController(service)
+		r.PUT("/:id", workCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		assert.Nil(t, err, "%T %v", err, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res entities.Work
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		assert.Equal(t, *expect, res)
+	})
+
+	t.Run("Missing version", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, -1, title, description, "", nil, content, 0)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf(endpoint, 1234), buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		_, contentFile := expectStage(service, ctx, nil, content)
+		// 検証に失敗した場合は、受信したファイルを破棄する
+		service.EXPECT().Discard(ctx, nil, contentFile)
+		workCtrl := NewWorksController(service)
+		r.PUT("/:id", workCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		errActual := ginCtx.Errors.Last()
+		if errActual != nil {
+			var bre *myErr.BadRequestError
+			assert.True(t, errors.As(errActual.Err, &bre))
+		} else {
+			assert.Fail(t, "%v", errActual)
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		service := mocks.NewMockWorksService(ctrl)
+		workCtrl := NewWorksController(service)
+		r.PUT("/:id", workCtrl.Put)
+
+		req, _ := http.NewRequest(http.MethodPut, "/abc", nil)
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		var appErr *myErr.ApplicationError
+		if assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, myErr.WUE01, appErr.Code())
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := httptest.NewRecorder()
+		ginCtx, r := gin.CreateTestContext(w)
+
+		buff := new(bytes.Buffer)
+		mw := multipart.NewWriter(buff)
+		createWorksFormRequestBody(mw, -1, title, description, "", nil, nil, 1)
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf(endpoint, 1234), buff)
+		req.Header.Set(contentTypeKey, mw.FormDataContentType())
+		req = req.WithContext(ctx)
+		ginCtx.Request = req
+		service := mocks.NewMockWorksService(ctrl)
+		expect := myErr.NewApplicationError(myErr.Code(myErr.WUE07))
+		service.EXPECT().Update(ctx, uint64(1234), gomock.Any()).Return(nil, expect)
+		workCtrl := NewWorksController(service)
+		r.PUT("/:id", workCtrl.Put)
+
+		r.HandleContext(ginCtx)
+
+		err := ginCtx.Errors.Last()
+		if assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
 }
 
 func TestDeleteWorks(t *testing.T) {
@@ -938,3 +1378,29 @@ func createWorksFormRequestBody(w *multipart.Writer, contentType constants.WorkT
 
 	return nil
 }
+
+// expectStage は、送信したファイルの内容がそのままStageに渡されることを期待し、一時領域のファイルを返す
+func expectStage(service *mocks.MockWorksService, ctx interface{}, thumbnail []byte, content []byte) (*beans.StagedFileBean, *beans.StagedFileBean) {
+	stage := func(field string, filename string, data []byte) *beans.StagedFileBean {
+		staged := &beans.StagedFileBean{Key: filename, Filename: filename, Size: int64(len(data))}
+		service.EXPECT().
+			Stage(ctx, field, filename, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, field string, filename string, body io.Reader) (*beans.StagedFileBean, error) {
+				b, err := ioutil.ReadAll(body)
+				if err != nil || !bytes.Equal(data, b) {
+					return nil, fmt.Errorf("unexpected body: %v %v", b, err)
+				}
+				return staged, nil
+			})
+		return staged
+	}
+
+	var thumbnailFile, contentFile *beans.StagedFileBean
+	if thumbnail != nil {
+		thumbnailFile = stage("thumbnail", "thumbnail.png", thumbnail)
+	}
+	if content != nil {
+		contentFile = stage("content", "content.png", content)
+	}
+	return thumbnailFile, contentFile
+}
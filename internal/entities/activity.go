@@ -14,4 +14,10 @@ type Activity struct {
 	WorkID    uint64 `json:"-"`
 	Work      *Work
 	CreatedAt time.Time
+	// CollectionID, Collection は、作品をコレクションに追加したアクティビティで、追加先のコレクション
+	CollectionID *uint64 `json:"-"`
+	Collection   *Collection
+	// CommentID, Comment は、コメントを投稿したアクティビティで、投稿したコメント
+	CommentID *uint64 `json:"-"`
+	Comment   *Comment
 }
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// Blob は、公開済みのファイルとその内容のSHA-256を表す。
+// 重複排除する場合は同じ内容のファイルを複数の作品で共有するため、参照している作品の数を持つ。
+type Blob struct {
+	Key       string `gorm:"primaryKey"`
+	SHA256    string `gorm:"column:sha256"`
+	Size      int64
+	RefCount  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WorkBlob は、作品が参照している公開済みのファイルを表す。
+// 作品ごとに1度だけ参照数を数えるため、作品とファイルの組を記録する。
+type WorkBlob struct {
+	WorkID uint64 `gorm:"primaryKey"`
+	Key    string `gorm:"primaryKey"`
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+)
+
+// Collection は、作者が関連する作品をまとめたコレクション (シリーズやアルバム) を表す。
+// 含まれる作品は、所有者の作品のみ。
+type Collection struct {
+	ID          uint64
+	OwnerID     string `json:"-"`
+	Owner       *User  `gorm:"foreignKey:OwnerID"`
+	Title       string `size:"40"`
+	Description string `size:"200"`
+	Visibility  constants.Visibility
+	// CoverWorkID, Cover は、表紙にする作品。Coverは、閲覧しているユーザーが閲覧できない場合はnil。
+	CoverWorkID *uint64
+	Cover       *Work `gorm:"foreignKey:CoverWorkID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CollectionWork は、コレクションに含まれる作品と、コレクション内の並び順を表す
+type CollectionWork struct {
+	CollectionID uint64 `gorm:"primaryKey"`
+	WorkID       uint64 `gorm:"primaryKey"`
+	// Position は、コレクション内の並び順。昇順に並べる。
+	Position  int
+	CreatedAt time.Time
+}
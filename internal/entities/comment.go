@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Comment は、作品へのコメントを表す。
+// 返信は1階層までで、ParentIDには返信先のコメントのIDを持つ。返信でないコメントではnil。
+type Comment struct {
+	ID       uint64
+	WorkID   uint64
+	AuthorID string `json:"-"`
+	Author   *User  `gorm:"foreignKey:AuthorID"`
+	ParentID *uint64
+	// Body は、本文。Markdownのうち、強調、コード、リンク、引用、リストのみを使用できる。
+	Body string
+	// ReplyCount は、返信の数。返信には常に0。
+	ReplyCount int64 `gorm:"->;-:migration"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+// Favorite は、ユーザーが作品をお気に入りに登録したことを表す
+type Favorite struct {
+	UserID    string `gorm:"primaryKey"`
+	WorkID    uint64 `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ImageVariants は、縮小した画像の幅 (文字列) と、そのURLの対応を表す。DBにはJSONとして保存する。
+type ImageVariants map[string]string
+
+// GormDataType は、カラムの型を返す
+func (ImageVariants) GormDataType() string {
+	return "text"
+}
+
+func (r ImageVariants) Value() (driver.Value, error) {
+	if r == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(r))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (r *ImageVariants) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*r = ImageVariants{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("can't scan %T into ImageVariants", value)
+	}
+
+	variants := ImageVariants{}
+	if err := json.Unmarshal(b, &variants); err != nil {
+		return err
+	}
+	*r = variants
+	return nil
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+)
+
+// Report は、ユーザーによる作品の通報を表す。同じユーザーは同じ作品を1回のみ通報できる。
+type Report struct {
+	ID         uint64
+	WorkID     uint64
+	Work       *Work
+	ReporterID string `json:"-"`
+	Reporter   *User  `gorm:"foreignKey:ReporterID"`
+	Reason     constants.ReportReason
+	// Note は、通報したユーザーが記入した補足
+	Note   string
+	Status constants.ReportStatus
+	// ResolvedAt は、モデレーターが対応した日時。対応していない場合はnil。
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// ModerationAction は、モデレーターが行った操作の記録 (監査証跡) を表す。記録は変更・削除しない。
+// 作品やユーザーを物理削除しても記録を残すため、対象は外部キーで参照しない。
+type ModerationAction struct {
+	ID   uint64
+	Type constants.ModerationActionType
+	// ModeratorID, Moderator は、操作したモデレーター。自動で非表示にした場合はnil。
+	ModeratorID *string `json:"-"`
+	Moderator   *User   `gorm:"foreignKey:ModeratorID"`
+	// WorkID, Work は、作品に対する操作で、対象の作品。削除した作品も読み込む。
+	WorkID *uint64
+	Work   *Work
+	// UserID, User は、ユーザーに対する操作で、対象のユーザー
+	UserID *string `json:"-"`
+	User   *User   `gorm:"foreignKey:UserID"`
+	// Note は、操作の理由
+	Note      string
+	CreatedAt time.Time
+}
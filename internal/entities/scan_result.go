@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// ScanResult は、作品のファイルをマルウェアの検査で検査した結果を表す
+type ScanResult struct {
+	ID     uint64
+	WorkID uint64
+	// Field は、検査したファイルのフォームの項目名
+	Field string
+	// Key は、検査した一時領域のキー。検出したファイルは同じキーで隔離領域に移動する。
+	Key     string
+	Scanner string
+	// Signature は、検出したマルウェアの名前。検出しなかった場合は空。
+	Signature string
+	CreatedAt time.Time
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Tag は、作品を話題で分類するタグを表す。Nameは正規化した名前で、大文字と小文字を区別しない。
+type Tag struct {
+	ID        uint64 `json:"-"`
+	Name      string
+	CreatedAt time.Time `json:"-"`
+}
+
+// TagCount は、タグとそれが付いた作品の数を表す
+type TagCount struct {
+	Name  string
+	Count int64 `gorm:"column:work_count"`
+}
+
+// WorkTag は、作品に付いたタグを表す
+type WorkTag struct {
+	WorkID uint64 `gorm:"primaryKey"`
+	TagID  uint64 `gorm:"primaryKey"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+// Upload は、アップロードの状態を表す。
+// tusプロトコルで受信した内容はStorageKeyを元にしたチャンク毎のオブジェクトとして保存し、全て受信した時点で1つにまとめる。
+// クライアントがストレージへ直接送信する場合は、StorageKeyに直接保存される。
+type Upload struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string
+	Filename   string
+	StorageKey string
+	// ContentType は、直接送信する場合にクライアントが申告したContent-Type
+	ContentType string
+	Length      int64
+	Offset      int64
+	Chunks      int
+	// CompletedAt は、全ての内容を受信し終えた日時。受信中の場合はnil。
+	CompletedAt *time.Time
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// UploadChunk は、Uploadが受信したチャンクを表す。
+// 同じ位置への要求が同時に行われても互いの内容を上書きしないよう、チャンクは要求毎に異なるキーに保存する。
+type UploadChunk struct {
+	UploadID string `gorm:"primaryKey"`
+	// Number は、何番目に受信したチャンクかを表す。0から始まる。
+	Number     int `gorm:"primaryKey;autoIncrement:false"`
+	StorageKey string
+	CreatedAt  time.Time
+}
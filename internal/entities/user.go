@@ -9,4 +9,6 @@ type User struct {
 	Picture   string
 	CreatedAt time.Time `json:"-"`
 	UpdatedAt time.Time `json:"-"`
+	// SuspendedAt は、モデレーターがアカウントを停止した日時。停止したユーザーは、閲覧以外の操作ができない。
+	SuspendedAt *time.Time `json:"-"`
 }
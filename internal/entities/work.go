@@ -10,14 +10,40 @@ import (
 type Work struct {
 	ID           uint64
 	Type         constants.WorkType
+	Visibility   constants.Visibility
+	Status       constants.WorkStatus
+	PublishAt    *time.Time
 	Title        string `size:"40"`
 	AuthorID     string `json:"-"`
 	Author       *User  `gorm:"foreignKey:AuthorID"`
 	Description  string `size:"200"`
+	Tags         []*Tag `gorm:"many2many:work_tags"`
 	ThumbnailURL string
+	Thumbnails   ImageVariants
 	ContentURL   string
-	Version      uint
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt
+	ContentType  string
+	// Collections は、作品を含むコレクションのうち、閲覧しているユーザーが一覧で閲覧できるもの。
+	// 作品の個別取得でのみ読み込む。
+	Collections []*Collection `gorm:"-" json:",omitempty"`
+	// ContentSHA256, ContentSize は、ファイルの作品で、保存した作品本体のSHA-256とバイト数
+	ContentSHA256 string `gorm:"column:content_sha256"`
+	ContentSize   int64
+	ScanStatus    constants.ScanStatus `json:"-"`
+	// LinkStatus, LinkCheckedAt は、URLの作品で、リンク先を最後に確認した時の応答のステータスコードと日時。
+	// 接続できなかった場合のステータスコードは0。
+	LinkStatus    int
+	LinkCheckedAt *time.Time
+	// LinkFailures は、リンク先の確認に連続して失敗した回数。LinkDownSinceは、その最初の失敗の日時。
+	// LinkBroken は、一定期間失敗し続けていることを表す。
+	LinkFailures  int
+	LinkDownSince *time.Time `json:"-"`
+	LinkBroken    bool
+	// FavoriteCount は、作品をお気に入りに登録したユーザーの数。お気に入りの登録・解除と同じトランザクションで更新する。
+	FavoriteCount int64
+	Version       uint
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt
+	// HiddenAt は、モデレーターが作品を非表示にした日時。非表示の作品は、作者以外には存在しないものとして扱う。
+	HiddenAt *time.Time
 }
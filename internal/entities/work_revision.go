@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+)
+
+// WorkRevision は、作品のあるバージョンの内容を表す。作品を登録・更新する毎に記録し、変更しない。
+type WorkRevision struct {
+	ID            uint64 `json:"-"`
+	WorkID        uint64
+	Version       uint
+	Type          constants.WorkType
+	Title         string
+	Description   string
+	ThumbnailURL  string
+	Thumbnails    ImageVariants
+	ContentURL    string
+	ContentType   string
+	ContentSHA256 string `gorm:"column:content_sha256"`
+	ContentSize   int64
+	// EditorID は、このバージョンを作成したユーザーのID
+	EditorID  string
+	CreatedAt time.Time
+}
+
+// NewWorkRevision は、作品の現在の内容を、editorが作成したバージョンとして記録する履歴を生成する
+func NewWorkRevision(w *Work, editor string) *WorkRevision {
+	return &WorkRevision{
+		WorkID:        w.ID,
+		Version:       w.Version,
+		Type:          w.Type,
+		Title:         w.Title,
+		Description:   w.Description,
+		ThumbnailURL:  w.ThumbnailURL,
+		Thumbnails:    w.Thumbnails,
+		ContentURL:    w.ContentURL,
+		ContentType:   w.ContentType,
+		ContentSHA256: w.ContentSHA256,
+		ContentSize:   w.ContentSize,
+		EditorID:      editor,
+	}
+}
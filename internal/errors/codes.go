@@ -7,6 +7,24 @@ const (
 	WUE01 string = "WUE01"
 	// WUE02 他のユーザーによって更新されました。お手数ですが最初からやり直して下さい。
 	WUE02 string = "WUE02"
+	// WUE03 {0}のサイズは{1}バイト以内にして下さい。
+	WUE03 string = "WUE03"
+	// WUE04 指定されたアップロードは見つかりません。
+	WUE04 string = "WUE04"
+	// WUE05 アップロード済みの位置が一致しません。最新の位置を確認してやり直して下さい。
+	WUE05 string = "WUE05"
+	// WUE06 {0}からマルウェアが検出されたため、登録できません。
+	WUE06 string = "WUE06"
+	// WUE07 作品は他の操作によって更新されています。最新の内容を取得してやり直して下さい。
+	WUE07 string = "WUE07"
+	// WUE08 {0}は{1}字以内で入力して下さい。
+	WUE08 string = "WUE08"
+	// WUE09 {0}に使用できない記法が含まれています。
+	WUE09 string = "WUE09"
+	// WUE10 アカウントが停止されているため、この操作は行えません。
+	WUE10 string = "WUE10"
+	// WUE11 指定されたユーザーは見つかりません。
+	WUE11 string = "WUE11"
 	// WUE99 システムエラーが発生しました。お手数ですが管理者にお問い合わせ下さい。
 	WUE99 string = "WUE99"
 )
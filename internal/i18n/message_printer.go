@@ -19,15 +19,33 @@ func NewPrinter() *PrinterImpl {
 	builder := catalog.NewBuilder(catalog.Fallback(language.English))
 
 	// English
-	builder.SetString(language.English, errors.WUE00, "An error has occurred")
+	builder.SetString(language.English, errors.WUE00, "%v is invalid.")
 	builder.SetString(language.English, errors.WUE01, "Works is not found.")
 	builder.SetString(language.English, errors.WUE02, "An error has occurred")
+	builder.SetString(language.English, errors.WUE03, "%v must be %v bytes or smaller.")
+	builder.SetString(language.English, errors.WUE04, "Upload is not found.")
+	builder.SetString(language.English, errors.WUE05, "Upload offset does not match. Please check the current offset and retry.")
+	builder.SetString(language.English, errors.WUE06, "Malware was detected in %v. It can't be registered.")
+	builder.SetString(language.English, errors.WUE07, "The work has been updated by another operation. Please get the latest one and retry.")
+	builder.SetString(language.English, errors.WUE08, "%v must be %v characters or fewer.")
+	builder.SetString(language.English, errors.WUE09, "%v contains unsupported markup.")
+	builder.SetString(language.English, errors.WUE10, "Your account is suspended. This operation is not allowed.")
+	builder.SetString(language.English, errors.WUE11, "User is not found.")
 	builder.SetString(language.English, errors.WUE99, "A system error has occurred")
 
 	// Japanese
 	builder.SetString(language.Japanese, errors.WUE00, "%vの形式が不正です。")
 	builder.SetString(language.Japanese, errors.WUE01, "指定された作品は見つかりません")
 	builder.SetString(language.Japanese, errors.WUE02, "操作を行う権限がありません。")
+	builder.SetString(language.Japanese, errors.WUE03, "%vのサイズは%vバイト以内にして下さい。")
+	builder.SetString(language.Japanese, errors.WUE04, "指定されたアップロードは見つかりません。")
+	builder.SetString(language.Japanese, errors.WUE05, "アップロード済みの位置が一致しません。最新の位置を確認してやり直して下さい。")
+	builder.SetString(language.Japanese, errors.WUE06, "%vからマルウェアが検出されたため、登録できません。")
+	builder.SetString(language.Japanese, errors.WUE07, "作品は他の操作によって更新されています。最新の内容を取得してやり直して下さい。")
+	builder.SetString(language.Japanese, errors.WUE08, "%vは%v字以内で入力して下さい。")
+	builder.SetString(language.Japanese, errors.WUE09, "%vに使用できない記法が含まれています。")
+	builder.SetString(language.Japanese, errors.WUE10, "アカウントが停止されているため、この操作は行えません。")
+	builder.SetString(language.Japanese, errors.WUE11, "指定されたユーザーは見つかりません。")
 	builder.SetString(language.Japanese, errors.WUE99, "システムエラーが発生しました。お手数ですが管理者にお問い合わせ下さい。 ")
 
 	return &PrinterImpl{
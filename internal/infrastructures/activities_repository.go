@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/edy4c7/works-uploader/internal/common/constants"
 	"github.com/edy4c7/works-uploader/internal/entities"
 	"gorm.io/gorm"
 )
@@ -18,18 +19,30 @@ func NewActivitiesRepositoryImpl(db *gorm.DB) *ActivitiesRepositoryImpl {
 	}
 }
 
-func (r *ActivitiesRepositoryImpl) GetAll(ctx context.Context, limit int) ([]*entities.Activity, error) {
+func (r *ActivitiesRepositoryImpl) GetAll(ctx context.Context, viewer string, limit int) ([]*entities.Activity, error) {
 	acts := make([]*entities.Activity, 0)
-	err := r.db.WithContext(ctx).Preload("User").Preload("Work").Limit(limit).Order("created_at desc").Find(&acts).Error
+	err := r.visible(ctx, viewer).Preload("User").Preload("Work").Preload("Collection").Preload("Comment").Limit(limit).Order("created_at desc").Find(&acts).Error
 	return acts, err
 }
 
-func (r *ActivitiesRepositoryImpl) FindByUserID(ctx context.Context, userID string, limit int) ([]*entities.Activity, error) {
+func (r *ActivitiesRepositoryImpl) FindByUserID(ctx context.Context, viewer string, userID string, limit int) ([]*entities.Activity, error) {
 	acts := make([]*entities.Activity, 0)
-	err := r.db.WithContext(ctx).Preload("User").Preload("Work").Limit(limit).Where("activities.user_id = ?", userID).Order("created_at desc").Find(&acts).Error
+	err := r.visible(ctx, viewer).Preload("User").Preload("Work").Preload("Collection").Preload("Comment").Limit(limit).Where("activities.user_id = ?", userID).Order("created_at desc").Find(&acts).Error
 	return acts, err
 }
 
+// visible は、viewerが閲覧できるアクティビティのみを対象にする。
+// 公開でない作品や公開済みでない作品、非表示の作品のものと、作者への通知 (リンク切れ) は、作者のみが閲覧できる。
+// コレクションに追加したものは、コレクションが公開でない場合も所有者のみが閲覧できる。
+func (r *ActivitiesRepositoryImpl) visible(ctx context.Context, viewer string) *gorm.DB {
+	return getDB(ctx, r.db).
+		Where("activities.work_id IN (SELECT id FROM works WHERE (visibility = ? AND status = ? AND hidden_at IS NULL) OR author_id = ?)",
+			constants.VisibilityPublic, constants.WorkPublished, viewer).
+		Where("activities.type <> ? OR activities.user_id = ?", constants.ActivityLinkBroken, viewer).
+		Where("activities.collection_id IS NULL OR activities.collection_id IN (SELECT id FROM collections WHERE visibility = ? OR owner_id = ?)",
+			constants.VisibilityPublic, viewer)
+}
+
 func (r *ActivitiesRepositoryImpl) Create(ctx context.Context, act *entities.Activity) error {
 	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
 		return tx.WithContext(ctx).Create(act).Error
@@ -37,3 +50,13 @@ func (r *ActivitiesRepositoryImpl) Create(ctx context.Context, act *entities.Act
 
 	return errors.New(notInTransactionMessage)
 }
+
+func (r *ActivitiesRepositoryImpl) DeleteByType(ctx context.Context, actType constants.ActivityType, userID string, workID uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).
+			Where("type = ? AND user_id = ? AND work_id = ?", actType, userID, workID).
+			Delete(&entities.Activity{}).Error
+	}
+
+	return errors.New(notInTransactionMessage)
+}
@@ -0,0 +1,115 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BlobsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBlobsRepositoryImpl(db *gorm.DB) *BlobsRepositoryImpl {
+	return &BlobsRepositoryImpl{db: db}
+}
+
+func (r *BlobsRepositoryImpl) Acquire(ctx context.Context, workID uint64, blob *entities.Blob) (int, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		// 作品が既に参照している場合は、参照数を増やさない
+		result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.WorkBlob{WorkID: workID, Key: blob.Key})
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected == 0 {
+			var saved entities.Blob
+			if err := tx.WithContext(ctx).First(&saved, "key = ?", blob.Key).Error; err != nil {
+				return 0, err
+			}
+			blob.RefCount = saved.RefCount
+			return saved.RefCount, nil
+		}
+
+		// 同じ内容のファイルが同時に登録されても、参照数を取りこぼさないよう1文で登録する
+		blob.RefCount = 1
+		err := tx.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"ref_count":  gorm.Expr("blobs.ref_count + 1"),
+				"updated_at": time.Now(),
+			}),
+		}).Create(blob).Error
+		if err != nil {
+			return 0, err
+		}
+
+		var saved entities.Blob
+		if err := tx.WithContext(ctx).First(&saved, "key = ?", blob.Key).Error; err != nil {
+			return 0, err
+		}
+		blob.RefCount = saved.RefCount
+		return saved.RefCount, nil
+	}
+	return 0, errors.New(notInTransactionMessage)
+}
+
+func (r *BlobsRepositoryImpl) Release(ctx context.Context, workID uint64, key string) (int, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.WorkBlob{}, "work_id = ? AND key = ?", workID, key)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected == 0 {
+			var saved entities.Blob
+			err := tx.WithContext(ctx).First(&saved, "key = ?", key).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, nil
+			}
+			return saved.RefCount, err
+		}
+
+		result = tx.WithContext(ctx).Model(&entities.Blob{}).
+			Where("key = ?", key).
+			Updates(map[string]interface{}{
+				"ref_count":  gorm.Expr("ref_count - 1"),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return 0, wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+
+		var saved entities.Blob
+		if err := tx.WithContext(ctx).First(&saved, "key = ?", key).Error; err != nil {
+			return 0, err
+		}
+		if saved.RefCount > 0 {
+			return saved.RefCount, nil
+		}
+		if err := tx.WithContext(ctx).Delete(&entities.Blob{}, "key = ?", key).Error; err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	return 0, errors.New(notInTransactionMessage)
+}
+
+func (r *BlobsRepositoryImpl) FindKeysByWorkID(ctx context.Context, workID uint64) ([]string, error) {
+	keys := make([]string, 0)
+	err := getDB(ctx, r.db).Model(&entities.WorkBlob{}).Where("work_id = ?", workID).Order("key").Pluck("key", &keys).Error
+	return keys, err
+}
+
+func (r *BlobsRepositoryImpl) FindAfter(ctx context.Context, after string, limit int) ([]*entities.Blob, error) {
+	blobs := make([]*entities.Blob, 0)
+	err := getDB(ctx, r.db).Where("key > ?", after).Order("key").Limit(limit).Find(&blobs).Error
+	return blobs, err
+}
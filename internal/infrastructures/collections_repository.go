@@ -0,0 +1,218 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectionsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCollectionsRepositoryImpl(db *gorm.DB) *CollectionsRepositoryImpl {
+	return &CollectionsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *CollectionsRepositoryImpl) GetAll(ctx context.Context, viewer string, ownerID string, offset int, limit int) ([]*entities.Collection, error) {
+	collections := make([]*entities.Collection, 0)
+	err := r.owned(r.listed(ctx, viewer), ownerID).Preload("Owner").Preload("Cover", cleanWorks).
+		Order("collections.updated_at DESC, collections.id DESC").Offset(offset).Limit(limit).Find(&collections).Error
+	return collections, err
+}
+
+func (r *CollectionsRepositoryImpl) CountAll(ctx context.Context, viewer string, ownerID string) (int64, error) {
+	var count int64
+	err := r.owned(r.listed(ctx, viewer), ownerID).Model(&entities.Collection{}).Count(&count).Error
+	return count, err
+}
+
+func (r *CollectionsRepositoryImpl) FindByID(ctx context.Context, id uint64) (*entities.Collection, error) {
+	var collection entities.Collection
+	err := getDB(ctx, r.db).Preload("Owner").Preload("Cover", cleanWorks).First(&collection, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &collection, err
+}
+
+func (r *CollectionsRepositoryImpl) FindByWorkID(ctx context.Context, viewer string, workID uint64) ([]*entities.Collection, error) {
+	collections := make([]*entities.Collection, 0)
+	err := r.listed(ctx, viewer).
+		Joins("JOIN collection_works ON collection_works.collection_id = collections.id").
+		Where("collection_works.work_id = ?", workID).
+		Order("collections.id").Find(&collections).Error
+	return collections, err
+}
+
+func (r *CollectionsRepositoryImpl) Create(ctx context.Context, collection *entities.Collection) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Omit(clause.Associations).Create(collection).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *CollectionsRepositoryImpl) Update(ctx context.Context, collection *entities.Collection) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(&entities.Collection{}).
+			Where("id = ?", collection.ID).
+			Updates(map[string]interface{}{
+				"title":         collection.Title,
+				"description":   collection.Description,
+				"visibility":    collection.Visibility,
+				"cover_work_id": collection.CoverWorkID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// DeleteByID は、コレクションを物理削除する。含まれる作品の記録とアクティビティは外部キーによって削除される。
+func (r *CollectionsRepositoryImpl) DeleteByID(ctx context.Context, id uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.Collection{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *CollectionsRepositoryImpl) FindWorks(ctx context.Context, viewer string, id uint64, offset int, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := r.works(ctx, viewer, id).Preload("Author").Preload("Tags", orderedTags).
+		Order("collection_works.position, collection_works.created_at, works.id").
+		Offset(offset).Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *CollectionsRepositoryImpl) CountWorks(ctx context.Context, viewer string, id uint64) (int64, error) {
+	var count int64
+	err := r.works(ctx, viewer, id).Model(&entities.Work{}).Count(&count).Error
+	return count, err
+}
+
+func (r *CollectionsRepositoryImpl) ContainsWork(ctx context.Context, id uint64, workID uint64) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&entities.CollectionWork{}).
+		Where("collection_id = ? AND work_id = ?", id, workID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *CollectionsRepositoryImpl) AddWork(ctx context.Context, id uint64, workID uint64) (bool, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		var last int
+		err := tx.WithContext(ctx).Model(&entities.CollectionWork{}).
+			Select("COALESCE(MAX(position), 0)").Where("collection_id = ?", id).Scan(&last).Error
+		if err != nil {
+			return false, err
+		}
+
+		result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.CollectionWork{CollectionID: id, WorkID: workID, Position: last + 1})
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+	return false, errors.New(notInTransactionMessage)
+}
+
+func (r *CollectionsRepositoryImpl) RemoveWork(ctx context.Context, id uint64, workID uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.CollectionWork{}, "collection_id = ? AND work_id = ?", id, workID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *CollectionsRepositoryImpl) Reorder(ctx context.Context, id uint64, workIDs []uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		var current []uint64
+		err := tx.WithContext(ctx).Model(&entities.CollectionWork{}).Where("collection_id = ?", id).
+			Order("position, created_at, work_id").Pluck("work_id", &current).Error
+		if err != nil {
+			return err
+		}
+
+		members := make(map[uint64]bool, len(current))
+		for _, v := range current {
+			members[v] = true
+		}
+		order := make([]uint64, 0, len(current))
+		for _, v := range workIDs {
+			if !members[v] {
+				return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+			}
+			members[v] = false
+			order = append(order, v)
+		}
+		for _, v := range current {
+			if members[v] {
+				order = append(order, v)
+			}
+		}
+
+		for i, v := range order {
+			err := tx.WithContext(ctx).Model(&entities.CollectionWork{}).
+				Where("collection_id = ? AND work_id = ?", id, v).UpdateColumn("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// listed は、viewerの一覧に表示するコレクションのみを対象にする。限定公開と非公開のコレクションは、所有者の一覧にのみ表示する。
+func (r *CollectionsRepositoryImpl) listed(ctx context.Context, viewer string) *gorm.DB {
+	return getDB(ctx, r.db).Where("collections.visibility = ? OR collections.owner_id = ?",
+		constants.VisibilityPublic, viewer)
+}
+
+// owned は、ownerIDが所有するコレクションのみを対象にする。ownerIDが空の場合は絞り込まない。
+func (r *CollectionsRepositoryImpl) owned(db *gorm.DB, ownerID string) *gorm.DB {
+	if ownerID == "" {
+		return db
+	}
+	return db.Where("collections.owner_id = ?", ownerID)
+}
+
+// works は、コレクションに含まれる作品のうち、viewerが一覧で閲覧できるもののみを対象にする。
+// 作品の一覧と同様に、公開済みかつ公開の作品 (非表示のものを除く) と、viewerが作者の作品を含む。
+func (r *CollectionsRepositoryImpl) works(ctx context.Context, viewer string, id uint64) *gorm.DB {
+	return getDB(ctx, r.db).
+		Joins("JOIN collection_works ON collection_works.work_id = works.id").
+		Where("collection_works.collection_id = ? AND works.scan_status = ?", id, constants.ScanClean).
+		Where("(works.visibility = ? AND works.status = ? AND works.hidden_at IS NULL) OR works.author_id = ?",
+			constants.VisibilityPublic, constants.WorkPublished, viewer)
+}
+
+// cleanWorks は、マルウェアの検査を通過した作品のみを読み込む
+func cleanWorks(db *gorm.DB) *gorm.DB {
+	return db.Where("works.scan_status = ?", constants.ScanClean)
+}
@@ -0,0 +1,102 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// replyCountColumn は、コメントへの返信の数を読み込む列
+const replyCountColumn = "(SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id) AS reply_count"
+
+type CommentsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCommentsRepositoryImpl(db *gorm.DB) *CommentsRepositoryImpl {
+	return &CommentsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *CommentsRepositoryImpl) FindByWorkID(ctx context.Context, workID uint64, offset int, limit int) ([]*entities.Comment, error) {
+	comments := make([]*entities.Comment, 0)
+	err := r.topLevel(ctx, workID).Select("comments.*, " + replyCountColumn).Preload("Author").
+		Order("comments.created_at, comments.id").Offset(offset).Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+func (r *CommentsRepositoryImpl) CountByWorkID(ctx context.Context, workID uint64) (int64, error) {
+	var count int64
+	err := r.topLevel(ctx, workID).Model(&entities.Comment{}).Count(&count).Error
+	return count, err
+}
+
+func (r *CommentsRepositoryImpl) FindReplies(ctx context.Context, parentID uint64, offset int, limit int) ([]*entities.Comment, error) {
+	comments := make([]*entities.Comment, 0)
+	err := getDB(ctx, r.db).Where("comments.parent_id = ?", parentID).Preload("Author").
+		Order("comments.created_at, comments.id").Offset(offset).Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+func (r *CommentsRepositoryImpl) CountReplies(ctx context.Context, parentID uint64) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&entities.Comment{}).Where("comments.parent_id = ?", parentID).Count(&count).Error
+	return count, err
+}
+
+func (r *CommentsRepositoryImpl) FindByID(ctx context.Context, id uint64) (*entities.Comment, error) {
+	var comment entities.Comment
+	err := getDB(ctx, r.db).Select("comments.*, "+replyCountColumn).Preload("Author").
+		First(&comment, "comments.id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &comment, err
+}
+
+func (r *CommentsRepositoryImpl) Create(ctx context.Context, comment *entities.Comment) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Omit(clause.Associations).Create(comment).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *CommentsRepositoryImpl) Update(ctx context.Context, comment *entities.Comment) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(&entities.Comment{}).
+			Where("id = ?", comment.ID).Update("body", comment.Body)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// DeleteByID は、コメントを物理削除する。返信とアクティビティは外部キーによって削除される。
+func (r *CommentsRepositoryImpl) DeleteByID(ctx context.Context, id uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.Comment{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// topLevel は、作品へのコメントのうち、返信でないもののみを対象にする
+func (r *CommentsRepositoryImpl) topLevel(ctx context.Context, workID uint64) *gorm.DB {
+	return getDB(ctx, r.db).Where("comments.work_id = ? AND comments.parent_id IS NULL", workID)
+}
@@ -0,0 +1,23 @@
+package infrastructures
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type DBHealthCheckerImpl struct {
+	db *gorm.DB
+}
+
+func NewDBHealthCheckerImpl(db *gorm.DB) *DBHealthCheckerImpl {
+	return &DBHealthCheckerImpl{db: db}
+}
+
+func (r *DBHealthCheckerImpl) Check(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,67 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoritesRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewFavoritesRepositoryImpl(db *gorm.DB) *FavoritesRepositoryImpl {
+	return &FavoritesRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *FavoritesRepositoryImpl) Add(ctx context.Context, userID string, workID uint64) (bool, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.Favorite{UserID: userID, WorkID: workID})
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+	return false, errors.New(notInTransactionMessage)
+}
+
+func (r *FavoritesRepositoryImpl) Remove(ctx context.Context, userID string, workID uint64) (bool, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.Favorite{}, "user_id = ? AND work_id = ?", userID, workID)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+	return false, errors.New(notInTransactionMessage)
+}
+
+func (r *FavoritesRepositoryImpl) FindWorks(ctx context.Context, viewer string, userID string, offset int, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := r.works(ctx, viewer, userID).Preload("Author").Preload("Tags", orderedTags).
+		Order("favorites.created_at DESC, works.id DESC").Offset(offset).Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *FavoritesRepositoryImpl) CountWorks(ctx context.Context, viewer string, userID string) (int64, error) {
+	var count int64
+	err := r.works(ctx, viewer, userID).Model(&entities.Work{}).Count(&count).Error
+	return count, err
+}
+
+// works は、ユーザーがお気に入りに登録した作品のうち、viewerが一覧で閲覧できるもののみを対象にする。
+// 作品の一覧と同様に、公開済みかつ公開の作品 (非表示のものを除く) と、viewerが作者の作品を含む。
+func (r *FavoritesRepositoryImpl) works(ctx context.Context, viewer string, userID string) *gorm.DB {
+	return getDB(ctx, r.db).
+		Joins("JOIN favorites ON favorites.work_id = works.id").
+		Where("favorites.user_id = ? AND works.scan_status = ?", userID, constants.ScanClean).
+		Where("(works.visibility = ? AND works.status = ? AND works.hidden_at IS NULL) OR works.author_id = ?",
+			constants.VisibilityPublic, constants.WorkPublished, viewer)
+}
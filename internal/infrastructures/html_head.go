@@ -0,0 +1,211 @@
+package infrastructures
+
+import (
+	"html"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+// htmlHead は、HTMLのheadから読み込んだ、プレビューに使用する要素を表す
+type htmlHead struct {
+	// titleText は、title要素の内容
+	titleText string
+	// meta は、meta要素のpropertyまたはnameを小文字にしたものと、contentの組。同じものは最初の値を使用する。
+	meta      map[string]string
+	charset   string
+	oEmbedURL string
+}
+
+// title は、OpenGraph、Twitterカードのタイトルを返す
+func (r *htmlHead) title() string {
+	return firstNonEmpty(r.meta["og:title"], r.meta["twitter:title"])
+}
+
+// imageURL は、OpenGraph、Twitterカードの画像のURLを返す
+func (r *htmlHead) imageURL() string {
+	return firstNonEmpty(
+		r.meta["og:image:secure_url"], r.meta["og:image"], r.meta["og:image:url"],
+		r.meta["twitter:image"], r.meta["twitter:image:src"],
+	)
+}
+
+// preview は、OpenGraph、Twitterカード、oEmbed、HTMLの要素の順に優先してプレビューを作成する。
+// 画像のURLは、baseを基準にした絶対URLにする。
+func (r *htmlHead) preview(base *url.URL, embed *oEmbedResponse) *lib.LinkPreview {
+	title := r.title()
+	imageURL := r.imageURL()
+	if embed != nil {
+		title = firstNonEmpty(title, strings.TrimSpace(embed.Title))
+		imageURL = firstNonEmpty(imageURL, strings.TrimSpace(embed.ThumbnailURL))
+	}
+
+	return &lib.LinkPreview{
+		Title:       firstNonEmpty(title, r.titleText),
+		Description: firstNonEmpty(r.meta["og:description"], r.meta["twitter:description"], r.meta["description"]),
+		ImageURL:    resolveLinkURL(base, imageURL),
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseHTMLHead は、HTMLの先頭からbodyが始まるまでのtitle、meta、link要素を読み込む。
+// 不完全なHTMLは、読み込めた範囲の要素を返す。
+func parseHTMLHead(doc string) *htmlHead {
+	head := &htmlHead{meta: map[string]string{}}
+
+	for i := 0; i < len(doc); {
+		start := strings.IndexByte(doc[i:], '<')
+		if start < 0 {
+			break
+		}
+		i += start
+		if strings.HasPrefix(doc[i:], "<!--") {
+			end := strings.Index(doc[i:], "-->")
+			if end < 0 {
+				break
+			}
+			i += end + len("-->")
+			continue
+		}
+
+		name, attrs, next := readHTMLTag(doc, i)
+		i = next
+		switch name {
+		case "/head", "body":
+			return head
+		case "title":
+			text, next := readRawText(doc, i, name)
+			if head.titleText == "" {
+				head.titleText = strings.TrimSpace(html.UnescapeString(text))
+			}
+			i = next
+		case "script", "style":
+			// 内容にタグと紛らわしい文字列があっても読み飛ばす
+			_, i = readRawText(doc, i, name)
+		case "meta":
+			head.addMeta(attrs)
+		case "link":
+			rel := " " + strings.ToLower(attrs["rel"]) + " "
+			if head.oEmbedURL == "" && strings.Contains(rel, " alternate ") &&
+				strings.EqualFold(attrs["type"], "application/json+oembed") {
+				head.oEmbedURL = attrs["href"]
+			}
+		}
+	}
+
+	return head
+}
+
+func (r *htmlHead) addMeta(attrs map[string]string) {
+	if charset, ok := attrs["charset"]; ok && r.charset == "" {
+		r.charset = charset
+	}
+	content, ok := attrs["content"]
+	if !ok {
+		return
+	}
+	if strings.EqualFold(attrs["http-equiv"], "content-type") && r.charset == "" {
+		if _, params, err := mime.ParseMediaType(content); err == nil {
+			r.charset = params["charset"]
+		}
+		return
+	}
+
+	key := strings.ToLower(firstNonEmpty(attrs["property"], attrs["name"]))
+	if _, exists := r.meta[key]; key != "" && !exists {
+		r.meta[key] = strings.TrimSpace(content)
+	}
+}
+
+// readHTMLTag は、docのiから始まるタグを読み込み、小文字にしたタグ名、属性、タグの次の位置を返す。
+// 終了タグの名前は "/" から始まる。属性値の文字参照は展開する。
+func readHTMLTag(doc string, i int) (string, map[string]string, int) {
+	j := i + 1
+	for j < len(doc) && !isHTMLSpace(doc[j]) && doc[j] != '>' && (doc[j] != '/' || j == i+1) {
+		j++
+	}
+	name := strings.ToLower(doc[i+1 : j])
+
+	attrs := map[string]string{}
+	for j < len(doc) {
+		for j < len(doc) && (isHTMLSpace(doc[j]) || doc[j] == '/') {
+			j++
+		}
+		if j >= len(doc) {
+			break
+		}
+		if doc[j] == '>' {
+			return name, attrs, j + 1
+		}
+
+		k := j
+		for k < len(doc) && !isHTMLSpace(doc[k]) && doc[k] != '=' && doc[k] != '>' && doc[k] != '/' {
+			k++
+		}
+		key := strings.ToLower(doc[j:k])
+		for k < len(doc) && isHTMLSpace(doc[k]) {
+			k++
+		}
+
+		value := ""
+		if k < len(doc) && doc[k] == '=' {
+			k++
+			for k < len(doc) && isHTMLSpace(doc[k]) {
+				k++
+			}
+			if k < len(doc) && (doc[k] == '"' || doc[k] == '\'') {
+				end := strings.IndexByte(doc[k+1:], doc[k])
+				if end < 0 {
+					return name, attrs, len(doc)
+				}
+				value = doc[k+1 : k+1+end]
+				k += end + 2
+			} else {
+				v := k
+				for k < len(doc) && !isHTMLSpace(doc[k]) && doc[k] != '>' {
+					k++
+				}
+				value = doc[v:k]
+			}
+		}
+
+		if _, exists := attrs[key]; key != "" && !exists {
+			attrs[key] = html.UnescapeString(value)
+		}
+		if k == j {
+			k++
+		}
+		j = k
+	}
+
+	return name, attrs, len(doc)
+}
+
+// readRawText は、docのiから、nameの終了タグまでの内容と、終了タグの次の位置を返す
+func readRawText(doc string, i int, name string) (string, int) {
+	closing := "</" + name
+	for j := i; j+len(closing) <= len(doc); j++ {
+		if doc[j] == '<' && strings.EqualFold(doc[j:j+len(closing)], closing) {
+			end := strings.IndexByte(doc[j:], '>')
+			if end < 0 {
+				return doc[i:j], len(doc)
+			}
+			return doc[i:j], j + end + 1
+		}
+	}
+	return doc[i:], len(doc)
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,477 @@
+package infrastructures
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+const orientedJPEGQuality = 90
+
+// maxExifSize は、向きを読み取るために保持するEXIFの上限。超える場合は向きを読み取らずに取り除く。
+const maxExifSize = 1 << 20
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader   = []byte("Exif\x00\x00")
+	iccHeader    = []byte("ICC_PROFILE\x00")
+	adobeHeader  = []byte("Adobe")
+)
+
+// pngMetadataChunks は、PNGから取り除くチャンク
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// strippedReader は、メタデータを取り除く処理の出力を読み込む。
+// 閉じた時点で処理が元のReaderを読み込んでいないよう、処理の終了を待つ。
+type strippedReader struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+func (r *strippedReader) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+	return err
+}
+
+func (r *ImageProcessorImpl) StripMetadata(body io.Reader, contentType string) (io.ReadCloser, bool) {
+	if !r.stripMetadata {
+		return nil, false
+	}
+
+	var strip func(io.Writer, io.Reader) error
+	switch contentType {
+	case "image/jpeg":
+		strip = r.stripJPEG
+	case "image/png":
+		strip = r.stripPNG
+	case "image/webp":
+		strip = stripWebP
+	default:
+		return nil, false
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(strip(pw, body))
+	}()
+
+	return &strippedReader{PipeReader: pr, done: done}, true
+}
+
+// stripJPEG は、画像データの前にあるセグメントのうち、表示に必要なもの以外を取り除く。
+// EXIFの向きが指定されている場合は、画素を回転して再度エンコードする。
+func (r *ImageProcessorImpl) stripJPEG(w io.Writer, src io.Reader) error {
+	br := bufio.NewReader(src)
+	header := &bytes.Buffer{}
+
+	var soi [2]byte
+	if _, err := io.ReadFull(br, soi[:]); err != nil {
+		return malformedImage(err)
+	}
+	if soi != [2]byte{0xFF, 0xD8} {
+		return fmt.Errorf("%w: missing SOI", lib.ErrUnsupportedImage)
+	}
+	header.Write(soi[:])
+
+	orientation := 1
+	for {
+		marker, err := readJPEGMarker(br)
+		if err != nil {
+			return malformedImage(err)
+		}
+
+		switch {
+		case marker == 0xDA:
+			// SOS以降は画像データのため、そのまま書き込む
+			header.Write([]byte{0xFF, marker})
+			if orientation == 1 {
+				_, err := io.Copy(w, io.MultiReader(header, br))
+				return err
+			}
+			// 大きさを読み取るには、SOSのセグメントまで必要になる
+			sos, err := peekJPEGSegment(br)
+			if err != nil {
+				return malformedImage(err)
+			}
+			if !r.orientable(append(append([]byte{}, header.Bytes()...), sos...)) {
+				return writeAll(w, bytes.NewReader(soi[:]), bytes.NewReader(jpegOrientationSegment(orientation)),
+					bytes.NewReader(header.Bytes()[len(soi):]), br)
+			}
+			return r.reorient(w, io.MultiReader(header, br), orientation, func(w io.Writer, img image.Image) error {
+				return jpeg.Encode(w, img, &jpeg.Options{Quality: orientedJPEGQuality})
+			})
+		case marker == 0xD9:
+			return fmt.Errorf("%w: no image data", lib.ErrUnsupportedImage)
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// 長さを持たないマーカー
+			header.Write([]byte{0xFF, marker})
+			continue
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(br, length[:]); err != nil {
+			return malformedImage(err)
+		}
+		size := int(binary.BigEndian.Uint16(length[:]))
+		if size < 2 {
+			return fmt.Errorf("%w: invalid segment length", lib.ErrUnsupportedImage)
+		}
+		payload := make([]byte, size-2)
+		if _, err := io.ReadFull(br, payload); err != nil {
+			return malformedImage(err)
+		}
+
+		if marker == 0xE1 && bytes.HasPrefix(payload, exifHeader) {
+			orientation = exifOrientation(payload[len(exifHeader):])
+		}
+		if keepJPEGSegment(marker, payload) {
+			header.Write([]byte{0xFF, marker})
+			header.Write(length[:])
+			header.Write(payload)
+		}
+	}
+}
+
+// stripPNG は、テキストやEXIFのチャンクを取り除く。
+// 画像データより前のEXIFで向きが指定されている場合は、画素を回転して再度エンコードする。
+func (r *ImageProcessorImpl) stripPNG(w io.Writer, src io.Reader) error {
+	br := bufio.NewReader(src)
+
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(br, signature); err != nil {
+		return malformedImage(err)
+	}
+	if !bytes.Equal(signature, pngSignature) {
+		return fmt.Errorf("%w: missing PNG signature", lib.ErrUnsupportedImage)
+	}
+
+	// 向きを確認できるまでは、画像データより前のチャンクを保持する
+	header := bytes.NewBuffer(signature)
+	var dst io.Writer = header
+	orientation := 1
+	for {
+		var h [8]byte
+		if _, err := io.ReadFull(br, h[:]); err != nil {
+			return malformedImage(err)
+		}
+		length := binary.BigEndian.Uint32(h[:4])
+		if length > 1<<31-1 {
+			return fmt.Errorf("%w: invalid chunk length", lib.ErrUnsupportedImage)
+		}
+		typ := string(h[4:])
+
+		if typ == "IDAT" && dst == header {
+			if orientation != 1 && r.orientable(header.Bytes()) {
+				return r.reorient(w, io.MultiReader(header, bytes.NewReader(h[:]), br), orientation, png.Encode)
+			}
+			if orientation != 1 {
+				header.Write(pngOrientationChunk(orientation))
+			}
+			if _, err := io.Copy(w, header); err != nil {
+				return err
+			}
+			dst = w
+		}
+
+		if pngMetadataChunks[typ] {
+			if typ == "eXIf" && dst == header && length <= maxExifSize {
+				payload := make([]byte, length)
+				if _, err := io.ReadFull(br, payload); err != nil {
+					return malformedImage(err)
+				}
+				orientation = exifOrientation(payload)
+				length = 0
+			}
+			// 内容とCRCを読み飛ばす
+			if _, err := io.CopyN(io.Discard, br, int64(length)+4); err != nil {
+				return malformedImage(err)
+			}
+			continue
+		}
+
+		if _, err := dst.Write(h[:]); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(dst, br, int64(length)+4); err != nil {
+			return malformedImage(err)
+		}
+		if typ == "IEND" {
+			return nil
+		}
+	}
+}
+
+// stripWebP は、EXIFとXMPのチャンクを内容を消去した不明なチャンクに置き換える。
+// RIFFの大きさを変えずに逐次書き込むため、チャンクを取り除かずに置き換える。向きは回転しない。
+func stripWebP(w io.Writer, src io.Reader) error {
+	br := bufio.NewReader(src)
+
+	var h [12]byte
+	if _, err := io.ReadFull(br, h[:]); err != nil {
+		return malformedImage(err)
+	}
+	if string(h[:4]) != "RIFF" || string(h[8:]) != "WEBP" {
+		return fmt.Errorf("%w: missing WebP header", lib.ErrUnsupportedImage)
+	}
+	if _, err := w.Write(h[:]); err != nil {
+		return err
+	}
+
+	for {
+		var ch [8]byte
+		if _, err := io.ReadFull(br, ch[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return malformedImage(err)
+		}
+		size := binary.LittleEndian.Uint32(ch[4:])
+		padded := int64(size) + int64(size&1)
+
+		switch string(ch[:4]) {
+		case "VP8X":
+			if padded > maxExifSize {
+				return fmt.Errorf("%w: invalid VP8X chunk", lib.ErrUnsupportedImage)
+			}
+			payload := make([]byte, padded)
+			if _, err := io.ReadFull(br, payload); err != nil {
+				return malformedImage(err)
+			}
+			if len(payload) > 0 {
+				// EXIFとXMPを含むことを表すフラグを消す
+				payload[0] &^= 0x08 | 0x04
+			}
+			if err := writeAll(w, bytes.NewReader(ch[:]), bytes.NewReader(payload)); err != nil {
+				return err
+			}
+		case "EXIF", "XMP ":
+			copy(ch[:4], "JUNK")
+			if _, err := w.Write(ch[:]); err != nil {
+				return err
+			}
+			if _, err := io.CopyN(io.Discard, br, padded); err != nil {
+				return malformedImage(err)
+			}
+			if _, err := io.CopyN(w, zeroReader{}, padded); err != nil {
+				return err
+			}
+		default:
+			if _, err := w.Write(ch[:]); err != nil {
+				return err
+			}
+			if _, err := io.CopyN(w, br, padded); err != nil {
+				return malformedImage(err)
+			}
+		}
+	}
+}
+
+// orientable は、画像データより前の部分から大きさを読み取り、回転できる画素数かを判定する
+func (r *ImageProcessorImpl) orientable(header []byte) bool {
+	config, _, err := image.DecodeConfig(bytes.NewReader(header))
+	if err != nil {
+		return false
+	}
+	return int64(config.Width)*int64(config.Height) <= r.maxPixels
+}
+
+// reorient は、画像を展開し、EXIFの向きに従って回転したものをエンコードする
+func (r *ImageProcessorImpl) reorient(w io.Writer, src io.Reader, orientation int, encode func(io.Writer, image.Image) error) error {
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return fmt.Errorf("%w: %v", lib.ErrUnsupportedImage, err)
+	}
+	return encode(w, orient(img, orientation))
+}
+
+// orient は、EXIFの向き(1〜8)に従って画素を並べ替える
+func orient(src image.Image, orientation int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dw, dh := width, height
+	if orientation >= 5 {
+		dw, dh = height, width
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := x, y
+			switch orientation {
+			case 2:
+				sx, sy = width-1-x, y
+			case 3:
+				sx, sy = width-1-x, height-1-y
+			case 4:
+				sx, sy = x, height-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, height-1-x
+			case 7:
+				sx, sy = width-1-y, height-1-x
+			case 8:
+				sx, sy = width-1-y, x
+			}
+			dst.Set(x, y, src.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// exifOrientation は、TIFF形式のEXIFのIFD0から向きを読み取る。読み取れない場合は1を返す。
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := uint64(order.Uint32(tiff[4:8]))
+	if offset+2 > uint64(len(tiff)) {
+		return 1
+	}
+	count := uint64(order.Uint16(tiff[offset:]))
+	for i := uint64(0); i < count; i++ {
+		start := offset + 2 + i*12
+		if start+12 > uint64(len(tiff)) {
+			break
+		}
+		entry := tiff[start : start+12]
+		// Orientation(0x0112)はSHORT型
+		if order.Uint16(entry[0:2]) == 0x0112 && order.Uint16(entry[2:4]) == 3 {
+			if o := int(order.Uint16(entry[8:10])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientationTIFF は、向きのみを含むTIFF形式のEXIFを生成する。
+// 回転できない大きさの画像で、向きだけを残すために使用する。
+func orientationTIFF(orientation int) []byte {
+	tiff := make([]byte, 26)
+	copy(tiff, "MM\x00\x2A")
+	binary.BigEndian.PutUint32(tiff[4:], 8)
+	binary.BigEndian.PutUint16(tiff[8:], 1)
+	binary.BigEndian.PutUint16(tiff[10:], 0x0112)
+	binary.BigEndian.PutUint16(tiff[12:], 3)
+	binary.BigEndian.PutUint32(tiff[14:], 1)
+	binary.BigEndian.PutUint16(tiff[18:], uint16(orientation))
+	return tiff
+}
+
+// jpegOrientationSegment は、向きのみを含むAPP1セグメントを生成する
+func jpegOrientationSegment(orientation int) []byte {
+	payload := append(append([]byte{}, exifHeader...), orientationTIFF(orientation)...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// pngOrientationChunk は、向きのみを含むeXIfチャンクを生成する
+func pngOrientationChunk(orientation int) []byte {
+	data := append([]byte("eXIf"), orientationTIFF(orientation)...)
+	chunk := make([]byte, 4, len(data)+8)
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)-4))
+	chunk = append(chunk, data...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(data))
+	return append(chunk, crc...)
+}
+
+// keepJPEGSegment は、JPEGのセグメントを残すかを判定する。
+// 表示に影響するJFIF、ICCプロファイル、Adobeのセグメント以外のアプリケーションセグメントとコメントは取り除く。
+func keepJPEGSegment(marker byte, payload []byte) bool {
+	switch {
+	case marker == 0xE0:
+		return true
+	case marker == 0xE2:
+		return bytes.HasPrefix(payload, iccHeader)
+	case marker == 0xEE:
+		return bytes.HasPrefix(payload, adobeHeader)
+	case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+		return false
+	}
+	return true
+}
+
+// peekJPEGSegment は、マーカーに続くセグメントを読み進めずに取得する
+func peekJPEGSegment(r *bufio.Reader) ([]byte, error) {
+	length, err := r.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	return r.Peek(int(binary.BigEndian.Uint16(length)))
+}
+
+// readJPEGMarker は、次のマーカーを読み込む。マーカーの前の詰め物の0xFFは読み飛ばす。
+func readJPEGMarker(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != 0xFF {
+		return 0, fmt.Errorf("%w: invalid marker", lib.ErrUnsupportedImage)
+	}
+	for {
+		b, err = r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != 0xFF {
+			return b, nil
+		}
+	}
+}
+
+// malformedImage は、画像の途中で終わっている場合にErrUnsupportedImageとする。
+// 読み込み元のエラーは、呼び出し元で判別できるようそのまま返す。
+func malformedImage(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: unexpected end of image", lib.ErrUnsupportedImage)
+	}
+	return err
+}
+
+func writeAll(w io.Writer, readers ...io.Reader) error {
+	_, err := io.Copy(w, io.MultiReader(readers...))
+	return err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
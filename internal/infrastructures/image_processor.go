@@ -0,0 +1,108 @@
+package infrastructures
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"sort"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const variantJPEGQuality = 85
+
+// ImageProcessorImpl は、Goで実装されたデコーダーのみを使用して画像を加工する。
+// JPEG, PNG, GIF, WebPを読み込み、JPEGで出力する。
+// メタデータはJPEG, PNG, WebPから取り除く。
+type ImageProcessorImpl struct {
+	maxPixels     int64
+	stripMetadata bool
+}
+
+// NewImageProcessorImpl は、ImageProcessorImplの新しいインスタンスを生成する。
+// maxPixelsを超える画素数の画像は、展開時のメモリを抑えるため読み込まない。
+// stripMetadataがfalseの場合、メタデータを取り除かない。
+func NewImageProcessorImpl(maxPixels int64, stripMetadata bool) *ImageProcessorImpl {
+	if maxPixels <= 0 {
+		panic("maxPixels must be greater than 0")
+	}
+
+	return &ImageProcessorImpl{
+		maxPixels:     maxPixels,
+		stripMetadata: stripMetadata,
+	}
+}
+
+func (r *ImageProcessorImpl) Resize(body io.Reader, widths []int) ([]*lib.ImageVariant, error) {
+	// 先にヘッダーだけを読んで大きさを確認し、読み込んだ分は展開時に再度使用する
+	header := &bytes.Buffer{}
+	config, _, err := image.DecodeConfig(io.TeeReader(body, header))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", lib.ErrUnsupportedImage, err)
+	}
+	if int64(config.Width)*int64(config.Height) > r.maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", lib.ErrUnsupportedImage, config.Width, config.Height, r.maxPixels)
+	}
+
+	src, _, err := image.Decode(io.MultiReader(header, body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", lib.ErrUnsupportedImage, err)
+	}
+
+	sorted := append([]int{}, widths...)
+	sort.Ints(sorted)
+
+	variants := make([]*lib.ImageVariant, 0, len(sorted))
+	bounds := src.Bounds()
+	for _, width := range sorted {
+		if width <= 0 {
+			continue
+		}
+		actual := width
+		if actual >= bounds.Dx() {
+			actual = bounds.Dx()
+		}
+
+		b, err := encodeVariant(src, actual)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, &lib.ImageVariant{
+			Width:       width,
+			ContentType: "image/jpeg",
+			Body:        b,
+		})
+
+		if actual == bounds.Dx() {
+			break
+		}
+	}
+
+	return variants, nil
+}
+
+// encodeVariant は、縦横比を保って幅をwidthに縮小し、JPEGにする。透過部分は白で塗りつぶす。
+func encodeVariant(src image.Image, width int) ([]byte, error) {
+	bounds := src.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, dst, &jpeg.Options{Quality: variantJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
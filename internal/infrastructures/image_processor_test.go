@@ -0,0 +1,344 @@
+package infrastructures
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPNG(t *testing.T, width int, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 0xff, A: 0x80})
+	}
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewImageProcessorImpl(t *testing.T) {
+	assert.Panics(t, func() {
+		NewImageProcessorImpl(0, true)
+	})
+}
+
+func TestImageProcessorResize(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		processor := NewImageProcessorImpl(1<<20, true)
+
+		variants, err := processor.Resize(bytes.NewReader(newPNG(t, 1000, 500)), []int{1280, 320, 640, 2000})
+
+		assert.Nil(t, err)
+		// 元の画像以上の幅は、元の大きさのものを1つだけ生成する
+		if assert.Len(t, variants, 3) {
+			for i, expect := range []struct{ width, actualWidth, actualHeight int }{
+				{320, 320, 160},
+				{640, 640, 320},
+				{1280, 1000, 500},
+			} {
+				assert.Equal(t, expect.width, variants[i].Width)
+				assert.Equal(t, "image/jpeg", variants[i].ContentType)
+				img, err := jpeg.Decode(bytes.NewReader(variants[i].Body))
+				if assert.Nil(t, err) {
+					assert.Equal(t, image.Rect(0, 0, expect.actualWidth, expect.actualHeight), img.Bounds())
+				}
+			}
+		}
+	})
+
+	t.Run("Too many pixels", func(t *testing.T) {
+		processor := NewImageProcessorImpl(100, true)
+
+		_, err := processor.Resize(bytes.NewReader(newPNG(t, 20, 10)), []int{320})
+
+		assert.True(t, errors.Is(err, lib.ErrUnsupportedImage), "%v", err)
+	})
+
+	t.Run("Not an image", func(t *testing.T) {
+		processor := NewImageProcessorImpl(100, true)
+
+		_, err := processor.Resize(strings.NewReader("PK\x03\x04"), []int{320})
+
+		assert.True(t, errors.Is(err, lib.ErrUnsupportedImage), "%v", err)
+	})
+}
+
+// secret は、取り除かれるべきメタデータに含める文字列
+const secret = "GPS 35.6812N 139.7671E SERIAL 0123456789"
+
+// newTIFF は、IFD0に向きと任意のASCIIの項目を持つTIFF形式のEXIFを生成する
+func newTIFF(orientation int) []byte {
+	tiff := []byte("II\x2A\x00\x08\x00\x00\x00")
+	tiff = append(tiff, 2, 0)
+	entry := func(tag, typ uint16, count, value uint32) {
+		b := make([]byte, 12)
+		binary.LittleEndian.PutUint16(b[0:], tag)
+		binary.LittleEndian.PutUint16(b[2:], typ)
+		binary.LittleEndian.PutUint32(b[4:], count)
+		binary.LittleEndian.PutUint32(b[8:], value)
+		tiff = append(tiff, b...)
+	}
+	// Orientation, BodySerialNumber(値はIFDの後ろに置く)
+	entry(0x0112, 3, 1, uint32(orientation))
+	entry(0xA431, 2, uint32(len(secret)+1), 8+2+24+4)
+	tiff = append(tiff, 0, 0, 0, 0)
+	return append(append(tiff, secret...), 0)
+}
+
+// newSplitImage は、左半分が赤、右半分が青の画像を生成する
+func newSplitImage(width int, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 0xff, A: 0xff}
+			if x >= width/2 {
+				c = color.NRGBA{B: 0xff, A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// newJPEGFixture は、EXIF、IPTC、コメントを含むJPEGを生成する
+func newJPEGFixture(t *testing.T, width int, height int, orientation int) []byte {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, newSplitImage(width, height), &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	segment := func(marker byte, payload []byte) []byte {
+		b := []byte{0xFF, marker, 0, 0}
+		binary.BigEndian.PutUint16(b[2:], uint16(len(payload)+2))
+		return append(b, payload...)
+	}
+	fixture := append([]byte{}, encoded[:2]...)
+	fixture = append(fixture, segment(0xE1, append([]byte("Exif\x00\x00"), newTIFF(orientation)...))...)
+	fixture = append(fixture, segment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00"+secret))...)
+	fixture = append(fixture, segment(0xED, []byte("Photoshop 3.0\x00"+secret))...)
+	fixture = append(fixture, segment(0xFE, []byte(secret))...)
+	return append(fixture, encoded[2:]...)
+}
+
+// newPNGFixture は、画像データの前にEXIFとテキスト、後ろにテキストを含むPNGを生成する
+func newPNGFixture(t *testing.T, width int, height int, orientation int) []byte {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, newSplitImage(width, height)); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	chunk := func(typ string, data []byte) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(len(data)))
+		body := append([]byte(typ), data...)
+		crc := make([]byte, 4)
+		binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(body))
+		return append(append(b, body...), crc...)
+	}
+	// シグネチャとIHDRの後ろに挿入する
+	ihdrEnd := 8 + 8 + 13 + 4
+	iendStart := len(encoded) - 12
+	fixture := append([]byte{}, encoded[:ihdrEnd]...)
+	fixture = append(fixture, chunk("eXIf", newTIFF(orientation))...)
+	fixture = append(fixture, chunk("tEXt", []byte("Comment\x00"+secret))...)
+	fixture = append(fixture, encoded[ihdrEnd:iendStart]...)
+	fixture = append(fixture, chunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00"+secret))...)
+	return append(fixture, encoded[iendStart:]...)
+}
+
+// newWebPFixture は、EXIFとXMPを含む拡張形式のWebPのチャンク構成を生成する。画像データの内容は検証しない。
+func newWebPFixture() []byte {
+	chunk := func(fourcc string, data []byte) []byte {
+		b := append([]byte(fourcc), 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+		b = append(b, data...)
+		if len(data)%2 == 1 {
+			b = append(b, 0)
+		}
+		return b
+	}
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", []byte{0x0C | 0x10, 0, 0, 0, 1, 0, 0, 1, 0, 0})...)
+	body = append(body, chunk("VP8L", []byte("image data"))...)
+	body = append(body, chunk("EXIF", newTIFF(1))...)
+	body = append(body, chunk("XMP ", []byte(secret+"!"))...)
+	header := []byte("RIFF\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(body)))
+	return append(header, body...)
+}
+
+func strip(t *testing.T, processor *ImageProcessorImpl, body []byte, contentType string) ([]byte, error) {
+	stripped, ok := processor.StripMetadata(bytes.NewReader(body), contentType)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	defer stripped.Close()
+	return ioutil.ReadAll(stripped)
+}
+
+func assertColor(t *testing.T, expect color.Color, img image.Image, x int, y int) {
+	er, eg, eb, _ := expect.RGBA()
+	ar, ag, ab, _ := img.At(x, y).RGBA()
+	near := func(e, a uint32) bool { return e>>8 == 0 && a>>8 < 0x30 || e>>8 == 0xff && a>>8 > 0xd0 }
+	assert.True(t, near(er, ar) && near(eg, ag) && near(eb, ab), "(%d, %d) = %v", x, y, img.At(x, y))
+}
+
+func TestImageProcessorStripMetadata(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	blue := color.NRGBA{B: 0xff, A: 0xff}
+
+	t.Run("JPEG", func(t *testing.T) {
+		fixture := newJPEGFixture(t, 16, 8, 1)
+
+		actual, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture, "image/jpeg")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		assert.False(t, bytes.Contains(actual, []byte("Exif")))
+		// 向きを変えない場合は、画像データを再度エンコードしない
+		assert.Equal(t, fixture[len(fixture)-100:], actual[len(actual)-100:])
+		img, err := jpeg.Decode(bytes.NewReader(actual))
+		if assert.Nil(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 16, 8), img.Bounds())
+		}
+	})
+
+	t.Run("JPEG with orientation", func(t *testing.T) {
+		fixture := newJPEGFixture(t, 16, 8, 6)
+
+		actual, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture, "image/jpeg")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		assert.False(t, bytes.Contains(actual, []byte("Exif")))
+		// 時計回りに90度回転し、左半分が上になる
+		img, err := jpeg.Decode(bytes.NewReader(actual))
+		if assert.Nil(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 8, 16), img.Bounds())
+			assertColor(t, red, img, 4, 3)
+			assertColor(t, blue, img, 4, 12)
+		}
+	})
+
+	t.Run("JPEG too large to rotate", func(t *testing.T) {
+		fixture := newJPEGFixture(t, 16, 8, 6)
+
+		actual, err := strip(t, NewImageProcessorImpl(100, true), fixture, "image/jpeg")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		// 向きのみを残す
+		i := bytes.Index(actual, []byte("Exif\x00\x00"))
+		if assert.True(t, i >= 0) {
+			assert.Equal(t, 6, exifOrientation(actual[i+6:]))
+		}
+		img, err := jpeg.Decode(bytes.NewReader(actual))
+		if assert.Nil(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 16, 8), img.Bounds())
+		}
+	})
+
+	t.Run("PNG", func(t *testing.T) {
+		fixture := newPNGFixture(t, 16, 8, 1)
+
+		actual, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture, "image/png")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		assert.False(t, bytes.Contains(actual, []byte("eXIf")))
+		img, err := png.Decode(bytes.NewReader(actual))
+		if assert.Nil(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 16, 8), img.Bounds())
+			assertColor(t, red, img, 0, 0)
+		}
+	})
+
+	t.Run("PNG with orientation", func(t *testing.T) {
+		fixture := newPNGFixture(t, 16, 8, 3)
+
+		actual, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture, "image/png")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		assert.False(t, bytes.Contains(actual, []byte("eXIf")))
+		// 180度回転し、左右が入れ替わる
+		img, err := png.Decode(bytes.NewReader(actual))
+		if assert.Nil(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 16, 8), img.Bounds())
+			assertColor(t, blue, img, 0, 0)
+			assertColor(t, red, img, 15, 7)
+		}
+	})
+
+	t.Run("PNG too large to rotate", func(t *testing.T) {
+		fixture := newPNGFixture(t, 16, 8, 3)
+
+		actual, err := strip(t, NewImageProcessorImpl(100, true), fixture, "image/png")
+
+		assert.Nil(t, err)
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		i := bytes.Index(actual, []byte("eXIf"))
+		if assert.True(t, i >= 0) {
+			assert.Equal(t, 3, exifOrientation(actual[i+4:]))
+		}
+		_, err = png.Decode(bytes.NewReader(actual))
+		assert.Nil(t, err)
+	})
+
+	t.Run("WebP", func(t *testing.T) {
+		fixture := newWebPFixture()
+
+		actual, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture, "image/webp")
+
+		assert.Nil(t, err)
+		// RIFFの大きさを変えずに、EXIFとXMPの内容を消去する
+		assert.Len(t, actual, len(fixture))
+		assert.Equal(t, fixture[:12], actual[:12])
+		assert.False(t, bytes.Contains(actual, []byte(secret)))
+		assert.False(t, bytes.Contains(actual, []byte("EXIF")))
+		assert.False(t, bytes.Contains(actual, []byte("XMP ")))
+		assert.Equal(t, byte(0x10), actual[20])
+		assert.True(t, bytes.Contains(actual, []byte("VP8L\x0a\x00\x00\x00image data")))
+	})
+
+	t.Run("Broken image", func(t *testing.T) {
+		fixture := newJPEGFixture(t, 16, 8, 1)
+
+		_, err := strip(t, NewImageProcessorImpl(1<<20, true), fixture[:40], "image/jpeg")
+
+		assert.True(t, errors.Is(err, lib.ErrUnsupportedImage), "%v", err)
+	})
+
+	t.Run("Not an image", func(t *testing.T) {
+		_, err := strip(t, NewImageProcessorImpl(1<<20, true), []byte("PK\x03\x04"), "image/png")
+
+		assert.True(t, errors.Is(err, lib.ErrUnsupportedImage), "%v", err)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		_, ok := NewImageProcessorImpl(1<<20, true).StripMetadata(strings.NewReader("GIF89a"), "image/gif")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, ok := NewImageProcessorImpl(1<<20, false).StripMetadata(bytes.NewReader(newJPEGFixture(t, 16, 8, 6)), "image/jpeg")
+
+		assert.False(t, ok)
+	})
+}
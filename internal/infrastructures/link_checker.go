@@ -0,0 +1,70 @@
+package infrastructures
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const linkCheckerUserAgent = "works-uploader-link-checker/1.0"
+
+// HTTPLinkCheckerImpl は、リンク先にHEADリクエストを送信して到達できるかを確認する。
+// HTTPLinkUnfurlerImplと同様に、内部のアドレスには接続しない。
+type HTTPLinkCheckerImpl struct {
+	client *http.Client
+}
+
+// NewHTTPLinkCheckerImpl は、1回の確認の制限時間と、追跡するリダイレクトの最大回数を指定し、
+// HTTPLinkCheckerImplの新しいインスタンスを生成する
+func NewHTTPLinkCheckerImpl(timeout time.Duration, maxRedirects int) *HTTPLinkCheckerImpl {
+	return newHTTPLinkChecker(timeout, maxRedirects, isPublicAddress)
+}
+
+// newHTTPLinkChecker は、接続できるアドレスの判定を指定してHTTPLinkCheckerImplを生成する
+func newHTTPLinkChecker(timeout time.Duration, maxRedirects int, allow func(net.IP) bool) *HTTPLinkCheckerImpl {
+	if timeout <= 0 {
+		panic("timeout must be greater than 0")
+	}
+	if maxRedirects < 0 {
+		panic("maxRedirects must not be negative")
+	}
+
+	return &HTTPLinkCheckerImpl{
+		client: newLinkClient(timeout, maxRedirects, allow),
+	}
+}
+
+func (r *HTTPLinkCheckerImpl) Check(ctx context.Context, rawURL string) (int, error) {
+	status, err := r.request(ctx, http.MethodHead, rawURL)
+	if err != nil || status < http.StatusBadRequest {
+		return status, err
+	}
+	// HEADに対応しない、またはGETと異なる応答を返すサーバーがあるため、GETで確認し直す
+	return r.request(ctx, http.MethodGet, rawURL)
+}
+
+// request は、rawURLにリクエストを送信し、本文は読み込まずに応答のステータスコードを返す
+func (r *HTTPLinkCheckerImpl) request(ctx context.Context, method string, rawURL string) (int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkLinkScheme(u); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", linkCheckerUserAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
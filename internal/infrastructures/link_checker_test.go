@@ -0,0 +1,108 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLinkChecker は、テスト用のサーバーのループバックアドレスにも接続するHTTPLinkCheckerImplを生成する
+func newTestLinkChecker(maxRedirects int) *HTTPLinkCheckerImpl {
+	return newHTTPLinkChecker(time.Second, maxRedirects, func(net.IP) bool { return true })
+}
+
+func TestNewHTTPLinkCheckerImpl(t *testing.T) {
+	t.Run("Timeout is zero", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHTTPLinkCheckerImpl(0, 0)
+		})
+	})
+
+	t.Run("Max redirects is negative", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHTTPLinkCheckerImpl(time.Second, -1)
+		})
+	})
+}
+
+func TestHTTPLinkChecker(t *testing.T) {
+	t.Run("HEAD", func(t *testing.T) {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			methods = append(methods, req.Method)
+			assert.Equal(t, linkCheckerUserAgent, req.UserAgent())
+		}))
+		t.Cleanup(server.Close)
+
+		status, err := newTestLinkChecker(0).Check(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{http.MethodHead}, methods)
+	})
+
+	t.Run("Falls back to GET", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		status, err := newTestLinkChecker(0).Check(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		status, err := newTestLinkChecker(0).Check(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Follows redirects", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		mux.Handle("/a", http.RedirectHandler("/b", http.StatusMovedPermanently))
+		mux.Handle("/b", http.RedirectHandler("/gone", http.StatusFound))
+		mux.HandleFunc("/gone", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		})
+
+		status, err := newTestLinkChecker(2).Check(context.Background(), server.URL+"/a")
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusGone, status)
+
+		_, err = newTestLinkChecker(1).Check(context.Background(), server.URL+"/a")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Private address", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		t.Cleanup(server.Close)
+
+		_, err := NewHTTPLinkCheckerImpl(time.Second, 0).Check(context.Background(), server.URL)
+
+		assert.True(t, errors.Is(err, errForbiddenAddress), "%v", err)
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		_, err := newTestLinkChecker(0).Check(context.Background(), "ftp://example.com/work.zip")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,274 @@
+package infrastructures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+const (
+	linkUnfurlerUserAgent = "works-uploader-link-preview/1.0"
+	// linkUnfurlerMaxHeaderBytes は、リンク先の応答のヘッダーとして読み込む最大バイト数
+	linkUnfurlerMaxHeaderBytes = 64 << 10
+)
+
+// errForbiddenAddress は、接続先が内部のアドレスであるため接続しなかったことを表す
+var errForbiddenAddress = errors.New("address is not allowed")
+
+// deniedNetworks は、リンク先として接続しないアドレスの範囲。
+// ループバック、プライベート、リンクローカル (クラウドのメタデータサービスを含む)、マルチキャストなど。
+var deniedNetworks = mustParseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+	"192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "64:ff9b::/96", "fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isPublicAddress は、リンク先として接続できるアドレスかを返す。IPv4射影アドレスはIPv4として判定する。
+func isPublicAddress(ip net.IP) bool {
+	for _, network := range deniedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NopLinkUnfurlerImpl は、プレビューを取得しないLinkUnfurlerを表す
+type NopLinkUnfurlerImpl struct{}
+
+func (r *NopLinkUnfurlerImpl) Unfurl(ctx context.Context, rawURL string) (*lib.LinkPreview, error) {
+	return nil, nil
+}
+
+func (r *NopLinkUnfurlerImpl) Fetch(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	return nil, errors.New("link previews are disabled")
+}
+
+// HTTPLinkUnfurlerImpl は、リンク先のHTMLのheadからプレビューを取得する。
+// 内部のアドレスには、リダイレクトやDNSの応答で誘導された場合も含めて接続しない。
+type HTTPLinkUnfurlerImpl struct {
+	client  *http.Client
+	maxSize int64
+}
+
+// NewHTTPLinkUnfurlerImpl は、HTTPLinkUnfurlerImplの新しいインスタンスを生成する。
+// timeoutは1回の取得全体の制限時間、maxSizeはHTMLとoEmbedの応答として読み込む最大バイト数、
+// maxRedirectsは追跡するリダイレクトの最大回数。
+func NewHTTPLinkUnfurlerImpl(timeout time.Duration, maxSize int64, maxRedirects int) *HTTPLinkUnfurlerImpl {
+	return newHTTPLinkUnfurler(timeout, maxSize, maxRedirects, isPublicAddress)
+}
+
+// newHTTPLinkUnfurler は、接続できるアドレスの判定を指定してHTTPLinkUnfurlerImplを生成する
+func newHTTPLinkUnfurler(timeout time.Duration, maxSize int64, maxRedirects int, allow func(net.IP) bool) *HTTPLinkUnfurlerImpl {
+	if timeout <= 0 {
+		panic("timeout must be greater than 0")
+	}
+	if maxSize <= 0 {
+		panic("maxSize must be greater than 0")
+	}
+	if maxRedirects < 0 {
+		panic("maxRedirects must not be negative")
+	}
+
+	return &HTTPLinkUnfurlerImpl{
+		client:  newLinkClient(timeout, maxRedirects, allow),
+		maxSize: maxSize,
+	}
+}
+
+// newLinkClient は、allowが許可するアドレスにのみ接続し、http, httpsのリダイレクトをmaxRedirects回まで追跡するクライアントを生成する
+func newLinkClient(timeout time.Duration, maxRedirects int, allow func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// 名前解決した後の、実際に接続するアドレスを検査する
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			// プロキシを経由すると接続先を検査できないため、環境変数の設定も使用しない
+			Proxy:                  nil,
+			DialContext:            dialer.DialContext,
+			TLSHandshakeTimeout:    timeout,
+			ResponseHeaderTimeout:  timeout,
+			MaxResponseHeaderBytes: linkUnfurlerMaxHeaderBytes,
+		},
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return checkLinkScheme(req.URL)
+		},
+	}
+}
+
+func (r *HTTPLinkUnfurlerImpl) Unfurl(ctx context.Context, rawURL string) (*lib.LinkPreview, error) {
+	resp, err := r.get(ctx, rawURL, "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("unfurl %s: unsupported content type %q", rawURL, mediaType)
+	}
+
+	// headは先頭にあるため、上限を超える部分は読み込まない
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, r.maxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	head := parseHTMLHead(string(body))
+	charset := params["charset"]
+	if charset == "" {
+		charset = head.charset
+	}
+	if decoded, ok := decodeCharset(body, charset); ok {
+		head = parseHTMLHead(decoded)
+	}
+
+	// リダイレクトされた場合は、最後に取得したページを基準にする
+	base := resp.Request.URL
+	var embed *oEmbedResponse
+	if head.oEmbedURL != "" && (head.title() == "" || head.imageURL() == "") {
+		if embed, err = r.oEmbed(ctx, resolveLinkURL(base, head.oEmbedURL)); err != nil {
+			log.Printf("skipped oEmbed of %s: %v", rawURL, err)
+		}
+	}
+
+	return head.preview(base, embed), nil
+}
+
+func (r *HTTPLinkUnfurlerImpl) Fetch(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	resp, err := r.get(ctx, rawURL, "image/*")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// oEmbedResponse は、oEmbedの応答のうちプレビューに使用する項目を表す
+type oEmbedResponse struct {
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func (r *HTTPLinkUnfurlerImpl) oEmbed(ctx context.Context, rawURL string) (*oEmbedResponse, error) {
+	if rawURL == "" {
+		return nil, errors.New("invalid oEmbed URL")
+	}
+	resp, err := r.get(ctx, rawURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	embed := &oEmbedResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, r.maxSize)).Decode(embed); err != nil {
+		return nil, err
+	}
+	return embed, nil
+}
+
+// get は、http, httpsのURLを取得する。成功以外の応答はエラーにする。
+func (r *HTTPLinkUnfurlerImpl) get(ctx context.Context, rawURL string, accept string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLinkScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", linkUnfurlerUserAgent)
+	req.Header.Set("Accept", accept)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+func checkLinkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme: %q", u.Scheme)
+	}
+	return nil
+}
+
+// resolveLinkURL は、baseを基準にrefを絶対URLにする。http, https以外は空にする。
+func resolveLinkURL(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || checkLinkScheme(u) != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// decodeCharset は、UTF-8以外の文字コードのHTMLをUTF-8に変換する。変換しなかった場合はfalseを返す。
+func decodeCharset(body []byte, charset string) (string, bool) {
+	if charset == "" {
+		return "", false
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return "", false
+	}
+	if name, _ := htmlindex.Name(encoding); name == "utf-8" {
+		return "", false
+	}
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
@@ -0,0 +1,262 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// newTestLinkUnfurler は、テスト用のサーバーのループバックアドレスにも接続するHTTPLinkUnfurlerImplを生成する
+func newTestLinkUnfurler(maxSize int64, maxRedirects int) *HTTPLinkUnfurlerImpl {
+	return newHTTPLinkUnfurler(time.Second, maxSize, maxRedirects, func(net.IP) bool { return true })
+}
+
+func servePage(t *testing.T, contentType string, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	for address, expected := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"0.0.0.0":          false,
+		"10.1.2.3":         false,
+		"100.64.0.1":       false,
+		"127.0.0.1":        false,
+		"169.254.169.254":  false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"224.0.0.1":        false,
+		"::":               false,
+		"::1":              false,
+		"::ffff:127.0.0.1": false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+	} {
+		assert.Equal(t, expected, isPublicAddress(net.ParseIP(address)), address)
+	}
+}
+
+func TestNopLinkUnfurler(t *testing.T) {
+	preview, err := (&NopLinkUnfurlerImpl{}).Unfurl(context.Background(), "https://example.com")
+
+	assert.Nil(t, err)
+	assert.Nil(t, preview)
+}
+
+func TestNewHTTPLinkUnfurlerImpl(t *testing.T) {
+	t.Run("Timeout is zero", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHTTPLinkUnfurlerImpl(0, 1, 0)
+		})
+	})
+
+	t.Run("Max size is zero", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHTTPLinkUnfurlerImpl(time.Second, 0, 0)
+		})
+	})
+
+	t.Run("Max redirects is negative", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHTTPLinkUnfurlerImpl(time.Second, 1, -1)
+		})
+	})
+}
+
+func TestHTTPLinkUnfurler(t *testing.T) {
+	t.Run("OpenGraph", func(t *testing.T) {
+		server := servePage(t, "text/html; charset=utf-8", `<!DOCTYPE html>
+<html><head>
+<title>Page title</title>
+<meta property="og:title" content="Work title">
+<meta property="og:description" content="Work description">
+<meta property="og:image" content="/images/preview.png">
+</head><body></body></html>`)
+
+		preview, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL+"/works/1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.LinkPreview{
+			Title:       "Work title",
+			Description: "Work description",
+			ImageURL:    server.URL + "/images/preview.png",
+		}, preview)
+	})
+
+	t.Run("Twitter card and title element", func(t *testing.T) {
+		server := servePage(t, "text/html", `<html><head>
+<TITLE> Tom &amp; Jerry </TITLE>
+<meta name="twitter:description" content="Cat &amp; mouse">
+<meta name="twitter:image" content="https://cdn.example.com/a.jpg">
+</head></html>`)
+
+		preview, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.LinkPreview{
+			Title:       "Tom & Jerry",
+			Description: "Cat & mouse",
+			ImageURL:    "https://cdn.example.com/a.jpg",
+		}, preview)
+	})
+
+	t.Run("oEmbed", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, `<html><head><title>Page title</title>
+<link rel="alternate" type="application/json+oembed" href="/oembed?url=%s"></head></html>`, url.QueryEscape(server.URL))
+		})
+		mux.HandleFunc("/oembed", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type":"video","version":"1.0","title":"Embedded title","thumbnail_url":"/thumb.jpg"}`)
+		})
+
+		// oEmbedのタイトルは、title要素より優先する
+		preview, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.LinkPreview{
+			Title:    "Embedded title",
+			ImageURL: server.URL + "/thumb.jpg",
+		}, preview)
+	})
+
+	t.Run("Charset", func(t *testing.T) {
+		page := `<html><head><meta charset="Shift_JIS"><meta property="og:title" content="作品の題名"></head></html>`
+		encoded, err := japanese.ShiftJIS.NewEncoder().String(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := servePage(t, "text/html", encoded)
+
+		preview, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "作品の題名", preview.Title)
+	})
+
+	t.Run("Reads up to max size", func(t *testing.T) {
+		server := servePage(t, "text/html", `<html><head><meta property="og:title" content="Work title">`+
+			strings.Repeat(" ", 256)+`<meta property="og:description" content="Too far"></head></html>`)
+
+		preview, err := newTestLinkUnfurler(256, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Work title", preview.Title)
+		assert.Empty(t, preview.Description)
+	})
+
+	t.Run("Follows redirects", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		mux.Handle("/a", http.RedirectHandler("/b", http.StatusFound))
+		mux.Handle("/b", http.RedirectHandler("/works/", http.StatusMovedPermanently))
+		mux.HandleFunc("/works/", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<meta property="og:image" content="preview.png">`)
+		})
+
+		// 相対URLは、リダイレクト先のページを基準にする
+		preview, err := newTestLinkUnfurler(1<<20, 2).Unfurl(context.Background(), server.URL+"/a")
+
+		assert.Nil(t, err)
+		assert.Equal(t, server.URL+"/works/preview.png", preview.ImageURL)
+
+		_, err = newTestLinkUnfurler(1<<20, 1).Unfurl(context.Background(), server.URL+"/a")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Private address", func(t *testing.T) {
+		server := servePage(t, "text/html", `<title>Internal</title>`)
+
+		_, err := NewHTTPLinkUnfurlerImpl(time.Second, 1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.True(t, errors.Is(err, errForbiddenAddress), "%v", err)
+	})
+
+	t.Run("Not HTML", func(t *testing.T) {
+		server := servePage(t, "application/pdf", `%PDF-1.4`)
+
+		_, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		_, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), server.URL)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		_, err := newTestLinkUnfurler(1<<20, 0).Unfurl(context.Background(), "file:///etc/passwd")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Fetch", func(t *testing.T) {
+		server := servePage(t, "image/png", "\x89PNG")
+
+		body, err := newTestLinkUnfurler(1<<20, 0).Fetch(context.Background(), server.URL+"/preview.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer body.Close()
+		b, err := ioutil.ReadAll(body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("\x89PNG"), b)
+	})
+
+	t.Run("Fail to fetch", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		body, err := newTestLinkUnfurler(1<<20, 0).Fetch(context.Background(), server.URL+"/preview.png")
+
+		assert.Nil(t, body)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseHTMLHead(t *testing.T) {
+	head := parseHTMLHead(`<!DOCTYPE html><html><HEAD>
+<!-- <meta property="og:title" content="Commented out"> -->
+<script>document.write('<meta property="og:title" content="Written">')</script>
+<meta property=og:title content=Unquoted>
+<meta property="og:title" content="Duplicated">
+<META NAME='Description' CONTENT='Single &quot;quoted&quot;'/>
+<link rel="alternate nofollow" type="application/json+oembed" href="/oembed">
+</head><body><meta property="og:image" content="/in-body.png"></body></html>`)
+
+	assert.Equal(t, "Unquoted", head.title())
+	assert.Equal(t, `Single "quoted"`, head.meta["description"])
+	assert.Equal(t, "/oembed", head.oEmbedURL)
+	assert.Empty(t, head.imageURL())
+}
@@ -0,0 +1,327 @@
+package infrastructures
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+const (
+	localPendingDir    = "pending"
+	localPublicDir     = "public"
+	localQuarantineDir = "quarantine"
+	localPrivateDir    = "private"
+
+	signedURLExpires   = "expires"
+	signedURLSignature = "signature"
+)
+
+// LocalStorageClientImpl は、ファイルをローカルのディレクトリに保存する。
+// 単一ノードでの運用や開発用で、公開済みのファイルはサーバー自身が配信する。
+// 非公開のファイルは、uploadPathへの署名付きのGETリクエストで配信する。
+type LocalStorageClientImpl struct {
+	dir        string
+	baseURL    string
+	uploadPath string
+	signingKey []byte
+}
+
+// NewLocalStorageClientImpl は、LocalStorageClientImplの新しいインスタンスを生成する。
+// baseURLは公開済みのファイルを配信するURL、uploadPathは署名付きのアップロードを受信するパス。
+func NewLocalStorageClientImpl(dir string, baseURL string, uploadPath string, signingKey string) *LocalStorageClientImpl {
+	if signingKey == "" {
+		panic("signingKey can't be empty")
+	}
+
+	return &LocalStorageClientImpl{
+		dir:        dir,
+		baseURL:    baseURL,
+		uploadPath: uploadPath,
+		signingKey: []byte(signingKey),
+	}
+}
+
+// PublicDir は、公開済みのファイルを置くディレクトリを返す
+func (r *LocalStorageClientImpl) PublicDir() string {
+	return filepath.Join(r.dir, localPublicDir)
+}
+
+// Upload は、一時ファイルに書き込んだ後で名前を変更し、書き込み途中のファイルを読まれないようにする。
+// Content-Typeは保持せず、配信時は拡張子から判定される。
+func (r *LocalStorageClientImpl) Upload(fileName string, body io.Reader, downloadName string, contentType string) error {
+	path, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), fileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// PresignUpload は、サーバー自身が受信する署名付きURLを生成する。
+// Content-Type, Content-Length は署名に含め、受信時にVerifyUploadで検証する。
+func (r *LocalStorageClientImpl) PresignUpload(fileName string, downloadName string, object *lib.ObjectInfo, expires time.Duration) (*lib.PresignedRequest, error) {
+	if _, err := r.path(localPendingDir, fileName); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(expires)
+	query := url.Values{}
+	query.Set(signedURLExpires, strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set(signedURLSignature, r.sign(fileName, object, expiresAt.Unix()))
+
+	headers := http.Header{}
+	headers.Set("Content-Type", object.ContentType)
+
+	return &lib.PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       fmt.Sprintf("%s/%s?%s", r.uploadPath, url.PathEscape(fileName), query.Encode()),
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyUpload は、PresignUploadで生成したURLへのリクエストであることを検証し、受信するファイルの属性を返す
+func (r *LocalStorageClientImpl) VerifyUpload(fileName string, req *http.Request) (*lib.ObjectInfo, error) {
+	if _, err := r.path(localPendingDir, fileName); err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	expires, err := strconv.ParseInt(query.Get(signedURLExpires), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", signedURLExpires, err)
+	}
+	if time.Now().Unix() > expires {
+		return nil, errors.New("signed URL has expired")
+	}
+
+	object := &lib.ObjectInfo{
+		Size:        req.ContentLength,
+		ContentType: req.Header.Get("Content-Type"),
+	}
+	expected := r.sign(fileName, object, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get(signedURLSignature))) {
+		return nil, errors.New("signature does not match")
+	}
+
+	return object, nil
+}
+
+// Stat は、ファイルのサイズを返す。Content-Typeは保持しないため、受信時にVerifyUploadで検証する。
+func (r *LocalStorageClientImpl) Stat(fileName string) (*lib.ObjectInfo, error) {
+	path, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, lib.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return &lib.ObjectInfo{Size: info.Size()}, nil
+}
+
+func (r *LocalStorageClientImpl) Open(fileName string) (io.ReadCloser, error) {
+	path, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (r *LocalStorageClientImpl) Promote(fileName string, publicName string) error {
+	pending, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return err
+	}
+	public, err := r.path(localPublicDir, publicName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(public), 0755); err != nil {
+		return err
+	}
+
+	return os.Rename(pending, public)
+}
+
+// PromotePrivate は、公開済みのファイルとして配信しないディレクトリへ移動する
+func (r *LocalStorageClientImpl) PromotePrivate(fileName string, privateName string) error {
+	pending, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return err
+	}
+	private, err := r.path(localPrivateDir, privateName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(private), 0700); err != nil {
+		return err
+	}
+
+	return os.Rename(pending, private)
+}
+
+func (r *LocalStorageClientImpl) Quarantine(fileName string) error {
+	pending, err := r.path(localPendingDir, fileName)
+	if err != nil {
+		return err
+	}
+	quarantined, err := r.path(localQuarantineDir, fileName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(quarantined), 0700); err != nil {
+		return err
+	}
+
+	return os.Rename(pending, quarantined)
+}
+
+func (r *LocalStorageClientImpl) OpenPublished(fileName string) (io.ReadCloser, error) {
+	for _, dir := range []string{localPublicDir, localPrivateDir} {
+		path, err := r.path(dir, fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := os.Open(path)
+		if err == nil {
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, lib.ErrObjectNotFound
+}
+
+func (r *LocalStorageClientImpl) Delete(fileName string) error {
+	for _, dir := range []string{localPendingDir, localPublicDir, localPrivateDir} {
+		path, err := r.path(dir, fileName)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *LocalStorageClientImpl) URL(fileName string) string {
+	return fmt.Sprintf("%s/%s", r.baseURL, fileName)
+}
+
+// SignURL は、非公開のファイルをサーバー自身が配信する署名付きURLを生成する
+func (r *LocalStorageClientImpl) SignURL(fileURL string, expires time.Duration) (string, error) {
+	fileName := strings.TrimPrefix(fileURL, r.baseURL+"/")
+	if fileName == fileURL {
+		return "", fmt.Errorf("not a URL of the storage: %q", fileURL)
+	}
+	if _, err := r.path(localPrivateDir, fileName); err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set(signedURLExpires, strconv.FormatInt(expiresAt, 10))
+	query.Set(signedURLSignature, r.signDownload(fileName, expiresAt))
+
+	return fmt.Sprintf("%s/%s?%s", r.uploadPath, url.PathEscape(fileName), query.Encode()), nil
+}
+
+// VerifyDownload は、SignURLで生成したURLへのリクエストであることを検証し、非公開のファイルのパスを返す
+func (r *LocalStorageClientImpl) VerifyDownload(fileName string, req *http.Request) (string, error) {
+	path, err := r.path(localPrivateDir, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	query := req.URL.Query()
+	expires, err := strconv.ParseInt(query.Get(signedURLExpires), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", signedURLExpires, err)
+	}
+	if time.Now().Unix() > expires {
+		return "", errors.New("signed URL has expired")
+	}
+
+	expected := r.signDownload(fileName, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get(signedURLSignature))) {
+		return "", errors.New("signature does not match")
+	}
+
+	return path, nil
+}
+
+// Check は、保存先のディレクトリにアクセスできるかを確認する
+func (r *LocalStorageClientImpl) Check(ctx context.Context) error {
+	info, err := os.Stat(r.dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", r.dir)
+	}
+	return nil
+}
+
+// path は、キーに対応するファイルのパスを返す。ディレクトリの外を指すキーはエラーにする。
+func (r *LocalStorageClientImpl) path(dir string, fileName string) (string, error) {
+	if fileName == "" || fileName != filepath.Base(fileName) || fileName == "." || fileName == ".." {
+		return "", fmt.Errorf("invalid key: %q", fileName)
+	}
+
+	return filepath.Join(r.dir, dir, fileName), nil
+}
+
+func (r *LocalStorageClientImpl) sign(fileName string, object *lib.ObjectInfo, expires int64) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", http.MethodPut, fileName, object.ContentType, object.Size, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signDownload は、アップロードの署名と区別するため、メソッドをGETとして署名する
+func (r *LocalStorageClientImpl) signDownload(fileName string, expires int64) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d", http.MethodGet, fileName, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,201 @@
+package infrastructures
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLocalStorage(t *testing.T) *LocalStorageClientImpl {
+	dir, err := ioutil.TempDir("", "wu-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return NewLocalStorageClientImpl(dir, "/files", "/api/v1/storage", "secret")
+}
+
+func TestLocalStorageClient(t *testing.T) {
+	t.Run("Upload and promote", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		assert.Nil(t, storage.Upload("key.txt", strings.NewReader("1234"), "a.txt", "text/plain"))
+
+		info, err := storage.Stat("key.txt")
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.ObjectInfo{Size: 4}, info)
+
+		assert.Nil(t, storage.Promote("key.txt", "key.txt"))
+
+		_, err = storage.Stat("key.txt")
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+		b, err := ioutil.ReadFile(filepath.Join(storage.PublicDir(), "key.txt"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("1234"), b)
+		assert.Equal(t, "/files/key.txt", storage.URL("key.txt"))
+
+		r, err := storage.OpenPublished("key.txt")
+		if assert.Nil(t, err) {
+			b, _ = ioutil.ReadAll(r)
+			r.Close()
+			assert.Equal(t, []byte("1234"), b)
+		}
+
+		assert.Nil(t, storage.Delete("key.txt"))
+		assert.Nil(t, storage.Delete("key.txt"))
+		_, err = os.Stat(filepath.Join(storage.PublicDir(), "key.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Promote to another key", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		assert.Nil(t, storage.Upload("key.txt", strings.NewReader("1234"), "a.txt", "text/plain"))
+		assert.Nil(t, storage.Upload("other.txt", strings.NewReader("5678"), "b.txt", "text/plain"))
+
+		// 公開後のキーにあるファイルは置き換える
+		assert.Nil(t, storage.Promote("key.txt", "public.txt"))
+		assert.Nil(t, storage.Promote("other.txt", "public.txt"))
+
+		r, err := storage.OpenPublished("public.txt")
+		if assert.Nil(t, err) {
+			b, _ := ioutil.ReadAll(r)
+			r.Close()
+			assert.Equal(t, []byte("5678"), b)
+		}
+		_, err = storage.OpenPublished("key.txt")
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+	})
+
+	t.Run("Quarantine", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		assert.Nil(t, storage.Upload("key.txt", strings.NewReader("1234"), "a.txt", "text/plain"))
+		assert.Nil(t, storage.Quarantine("key.txt"))
+
+		// 一時領域から取り除き、公開もしない
+		_, err := storage.Stat("key.txt")
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+		_, err = storage.OpenPublished("key.txt")
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+		b, err := ioutil.ReadFile(filepath.Join(storage.dir, localQuarantineDir, "key.txt"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("1234"), b)
+	})
+
+	t.Run("Promote private", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		assert.Nil(t, storage.Upload("key.txt", strings.NewReader("1234"), "a.txt", "text/plain"))
+		assert.Nil(t, storage.PromotePrivate("key.txt", "key.txt"))
+
+		// 公開済みのファイルとして配信しない
+		_, err := os.Stat(filepath.Join(storage.PublicDir(), "key.txt"))
+		assert.True(t, os.IsNotExist(err))
+		r, err := storage.OpenPublished("key.txt")
+		if assert.Nil(t, err) {
+			b, _ := ioutil.ReadAll(r)
+			r.Close()
+			assert.Equal(t, []byte("1234"), b)
+		}
+
+		assert.Nil(t, storage.Delete("key.txt"))
+		_, err = storage.OpenPublished("key.txt")
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+	})
+
+	t.Run("Signed download", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		signed, err := storage.SignURL(storage.URL("key.txt"), time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(signed, "/api/v1/storage/key.txt?"))
+
+		req, _ := http.NewRequest(http.MethodGet, signed, nil)
+		path, err := storage.VerifyDownload("key.txt", req)
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(storage.dir, localPrivateDir, "key.txt"), path)
+
+		// 他のファイルや、アップロードには使用できない
+		_, err = storage.VerifyDownload("other.txt", req)
+		assert.Error(t, err)
+		req, _ = http.NewRequest(http.MethodPut, signed, strings.NewReader(""))
+		_, err = storage.VerifyUpload("key.txt", req)
+		assert.Error(t, err)
+
+		_, err = storage.SignURL("https://example.com/key.txt", time.Minute)
+		assert.Error(t, err)
+	})
+
+	t.Run("Signed download has expired", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		signed, err := storage.SignURL(storage.URL("key.txt"), -time.Minute)
+		assert.Nil(t, err)
+
+		req, _ := http.NewRequest(http.MethodGet, signed, nil)
+		_, err = storage.VerifyDownload("key.txt", req)
+		assert.Error(t, err)
+	})
+
+	t.Run("Key outside the directory", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		assert.Error(t, storage.Upload("../key.txt", strings.NewReader("1234"), "", ""))
+		assert.Error(t, storage.Delete(".."))
+		_, err := storage.Open("pending/key.txt")
+		assert.Error(t, err)
+		assert.Error(t, storage.Promote("key.txt", "../key.txt"))
+		assert.Error(t, storage.Quarantine("../key.txt"))
+		assert.Error(t, storage.PromotePrivate("key.txt", "../key.txt"))
+		_, err = storage.SignURL(storage.URL("../key.txt"), time.Minute)
+		assert.Error(t, err)
+	})
+
+	t.Run("Presigned upload", func(t *testing.T) {
+		storage := newLocalStorage(t)
+		object := &lib.ObjectInfo{Size: 4, ContentType: "text/plain"}
+
+		presigned, err := storage.PresignUpload("key.txt", "a.txt", object, time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, http.MethodPut, presigned.Method)
+		assert.True(t, strings.HasPrefix(presigned.URL, "/api/v1/storage/key.txt?"))
+
+		newRequest := func(body string, contentType string) *http.Request {
+			req, _ := http.NewRequest(presigned.Method, presigned.URL, strings.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			return req
+		}
+
+		info, err := storage.VerifyUpload("key.txt", newRequest("1234", "text/plain"))
+		assert.Nil(t, err)
+		assert.Equal(t, object, info)
+
+		// 署名した内容と異なるリクエストは拒否する
+		_, err = storage.VerifyUpload("other.txt", newRequest("1234", "text/plain"))
+		assert.Error(t, err)
+		_, err = storage.VerifyUpload("key.txt", newRequest("12345", "text/plain"))
+		assert.Error(t, err)
+		_, err = storage.VerifyUpload("key.txt", newRequest("1234", "text/html"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Presigned upload has expired", func(t *testing.T) {
+		storage := newLocalStorage(t)
+
+		presigned, err := storage.PresignUpload("key.txt", "a.txt", &lib.ObjectInfo{Size: 4}, -time.Minute)
+		assert.Nil(t, err)
+
+		req, _ := http.NewRequest(presigned.Method, presigned.URL, strings.NewReader("1234"))
+		_, err = storage.VerifyUpload("key.txt", req)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,41 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ModerationActionsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewModerationActionsRepositoryImpl(db *gorm.DB) *ModerationActionsRepositoryImpl {
+	return &ModerationActionsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ModerationActionsRepositoryImpl) GetAll(ctx context.Context, offset int, limit int) ([]*entities.ModerationAction, error) {
+	actions := make([]*entities.ModerationAction, 0)
+	err := getDB(ctx, r.db).Preload("Moderator").Preload("User").Preload("Work", withTrashed).
+		Order("moderation_actions.created_at DESC, moderation_actions.id DESC").
+		Offset(offset).Limit(limit).Find(&actions).Error
+	return actions, err
+}
+
+func (r *ModerationActionsRepositoryImpl) CountAll(ctx context.Context) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&entities.ModerationAction{}).Count(&count).Error
+	return count, err
+}
+
+func (r *ModerationActionsRepositoryImpl) Create(ctx context.Context, action *entities.ModerationAction) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Omit(clause.Associations).Create(action).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
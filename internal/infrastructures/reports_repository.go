@@ -0,0 +1,75 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReportsRepositoryImpl(db *gorm.DB) *ReportsRepositoryImpl {
+	return &ReportsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ReportsRepositoryImpl) Create(ctx context.Context, report *entities.Report) (bool, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+	return false, errors.New(notInTransactionMessage)
+}
+
+func (r *ReportsRepositoryImpl) CountOpenByWorkID(ctx context.Context, workID uint64) (int64, error) {
+	var count int64
+	err := r.open(ctx).Model(&entities.Report{}).Where("reports.work_id = ?", workID).Count(&count).Error
+	return count, err
+}
+
+func (r *ReportsRepositoryImpl) FindOpen(ctx context.Context, offset int, limit int) ([]*entities.Report, error) {
+	reports := make([]*entities.Report, 0)
+	err := r.open(ctx).Preload("Reporter").Preload("Work", withTrashed).Preload("Work.Author").
+		Order("reports.created_at, reports.id").Offset(offset).Limit(limit).Find(&reports).Error
+	return reports, err
+}
+
+func (r *ReportsRepositoryImpl) CountOpen(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.open(ctx).Model(&entities.Report{}).Count(&count).Error
+	return count, err
+}
+
+func (r *ReportsRepositoryImpl) ResolveByWorkID(ctx context.Context, workID uint64, status constants.ReportStatus) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Model(&entities.Report{}).
+			Where("work_id = ? AND status = ?", workID, constants.ReportOpen).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"resolved_at": time.Now(),
+			}).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// open は、未対応の通報のみを対象にする
+func (r *ReportsRepositoryImpl) open(ctx context.Context) *gorm.DB {
+	return getDB(ctx, r.db).Where("reports.status = ?", constants.ReportOpen)
+}
+
+// withTrashed は、論理削除された作品も読み込む
+func withTrashed(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
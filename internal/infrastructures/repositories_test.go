@@ -0,0 +1,112 @@
+package infrastructures
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/migrations"
+	"github.com/edy4c7/works-uploader/internal/repositories/repositorytest"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// postgresDSNEnv に接続文字列を設定すると、PostgreSQLに対しても契約テストを実行する。
+// 例: TEST_POSTGRES_DSN="host=localhost user=works_uploader password=xxx dbname=works_uploader port=5432 sslmode=disable"
+const postgresDSNEnv = "TEST_POSTGRES_DSN"
+
+func TestRepositoriesWithSQLite(t *testing.T) {
+	repositorytest.RunAll(t, setupSQLite)
+}
+
+func TestRepositoriesWithPostgres(t *testing.T) {
+	if os.Getenv(postgresDSNEnv) == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	repositorytest.RunAll(t, setupPostgres)
+}
+
+func setupSQLite(t *testing.T) *repositorytest.Harness {
+	db := openTestDB(t, sqlite.Open(":memory:?_pragma=foreign_keys(1)"))
+
+	// ":memory:"はコネクション毎に別のDBになるため、1つに限定する
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	return newHarness(t, db, migrations.SQLite)
+}
+
+func setupPostgres(t *testing.T) *repositorytest.Harness {
+	dsn := os.Getenv(postgresDSNEnv)
+
+	// テスト毎にスキーマを作成し、互いに干渉しないようにする
+	schema := fmt.Sprintf("wu_test_%s", uuid.New().String()[:8])
+	admin := openTestDB(t, postgres.Open(dsn))
+	if err := admin.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+	})
+
+	db := openTestDB(t, postgres.Open(fmt.Sprintf("%s search_path=%s", dsn, schema)))
+
+	return newHarness(t, db, migrations.Postgres)
+}
+
+func openTestDB(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func newHarness(t *testing.T, db *gorm.DB, dialect string) *repositorytest.Harness {
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := migrations.NewMigrator(sqlDB, dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return &repositorytest.Harness{
+		TransactionRunner: NewTransactionRunnerImpl(db),
+		Works:             NewWorksRepositoryImpl(db),
+		Activities:        NewActivitiesRepositoryImpl(db),
+		Users:             NewUserRepositoryImpl(db),
+		Uploads:           NewUploadsRepositoryImpl(db),
+		Blobs:             NewBlobsRepositoryImpl(db),
+		ScanResults:       NewScanResultsRepositoryImpl(db),
+		WorkRevisions:     NewWorkRevisionsRepositoryImpl(db),
+		Tags:              NewTagsRepositoryImpl(db),
+		Collections:       NewCollectionsRepositoryImpl(db),
+		Favorites:         NewFavoritesRepositoryImpl(db),
+		Comments:          NewCommentsRepositoryImpl(db),
+		Reports:           NewReportsRepositoryImpl(db),
+		ModerationActions: NewModerationActionsRepositoryImpl(db),
+	}
+}
@@ -0,0 +1,32 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"gorm.io/gorm"
+)
+
+type ScanResultsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewScanResultsRepositoryImpl(db *gorm.DB) *ScanResultsRepositoryImpl {
+	return &ScanResultsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ScanResultsRepositoryImpl) Create(ctx context.Context, result *entities.ScanResult) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Create(result).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *ScanResultsRepositoryImpl) FindByWorkID(ctx context.Context, workID uint64) ([]*entities.ScanResult, error) {
+	results := make([]*entities.ScanResult, 0)
+	err := getDB(ctx, r.db).Where("work_id = ?", workID).Order("id").Find(&results).Error
+	return results, err
+}
@@ -0,0 +1,142 @@
+package infrastructures
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+const (
+	clamdScannerName = "clamd"
+	// clamdChunkSize は、INSTREAMで1回に送信する最大バイト数
+	clamdChunkSize = 64 << 10
+	// clamdMaxReply は、clamdの応答として読み込む最大バイト数
+	clamdMaxReply = 4 << 10
+)
+
+// NopScannerImpl は、検査しないScannerを表す
+type NopScannerImpl struct{}
+
+func (r *NopScannerImpl) Scan(body io.Reader) (*lib.ScanVerdict, error) {
+	return nil, nil
+}
+
+// ClamdScannerImpl は、ClamAVのclamdにINSTREAMコマンドで内容を送信して検査する
+type ClamdScannerImpl struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScannerImpl は、ClamdScannerImplの新しいインスタンスを生成する。
+// networkは "tcp" または "unix"。timeoutは接続と、送信・受信のそれぞれが進まない場合の待ち時間。
+func NewClamdScannerImpl(network string, address string, timeout time.Duration) *ClamdScannerImpl {
+	if network != "tcp" && network != "unix" {
+		panic(fmt.Sprintf("unsupported network: %q", network))
+	}
+	if timeout <= 0 {
+		panic("timeout must be greater than 0")
+	}
+
+	return &ClamdScannerImpl{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan は、内容を長さ付きのチャンクに分けて送信し、clamdの応答を解釈する。
+// clamdのStreamMaxLengthを超える内容は、clamdが接続を閉じるためエラーになる。
+func (r *ClamdScannerImpl) Scan(body io.Reader) (*lib.ScanVerdict, error) {
+	conn, err := net.DialTimeout(r.network, r.address, r.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := r.send(conn, body); err != nil {
+		// clamdが上限を超えた時点で応答を返して切断した場合は、その内容をエラーにする
+		if reply, rErr := r.receive(conn); rErr == nil && reply != "" {
+			return nil, fmt.Errorf("clamd: %s", reply)
+		}
+		return nil, err
+	}
+
+	reply, err := r.receive(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseClamdReply(reply)
+}
+
+// send は、INSTREAMコマンドと内容を送信する。内容の終わりは長さ0のチャンクで表す。
+func (r *ClamdScannerImpl) send(conn net.Conn, body io.Reader) error {
+	if err := r.write(conn, []byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := body.Read(chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if wErr := r.write(conn, chunk[:4+n]); wErr != nil {
+				return wErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.write(conn, []byte{0, 0, 0, 0})
+}
+
+func (r *ClamdScannerImpl) write(conn net.Conn, b []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(r.timeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(b)
+	return err
+}
+
+// receive は、NULL文字で終わるclamdの応答を読み込む
+func (r *ClamdScannerImpl) receive(conn net.Conn) (string, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(r.timeout)); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(io.LimitReader(conn, clamdMaxReply)).ReadString(0)
+	if err != nil && reply == "" {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimSuffix(reply, "\x00")), nil
+}
+
+// parseClamdReply は、"stream: OK" や "stream: Eicar-Signature FOUND" の形式の応答を解釈する
+func parseClamdReply(reply string) (*lib.ScanVerdict, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return &lib.ScanVerdict{Scanner: clamdScannerName}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &lib.ScanVerdict{
+			Scanner:   clamdScannerName,
+			Signature: strings.TrimSuffix(result, " FOUND"),
+		}, nil
+	case reply == "":
+		return nil, errors.New("clamd: empty reply")
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
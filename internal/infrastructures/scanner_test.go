@@ -0,0 +1,193 @@
+package infrastructures
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd は、INSTREAMコマンドのみに応答するclamdを表す。
+// 受信した内容にEICARのテスト用の文字列があれば検出し、maxLengthを超えた場合はエラーを返す。
+type fakeClamd struct {
+	listener  net.Listener
+	maxLength int
+	received  chan []byte
+}
+
+func newFakeClamd(t *testing.T, network string, address string) *fakeClamd {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	clamd := &fakeClamd{listener: listener, maxLength: 1 << 20, received: make(chan []byte, 1)}
+	go clamd.serve()
+	return clamd
+}
+
+func (r *fakeClamd) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.handle(conn)
+	}
+}
+
+func (r *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+
+	command := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	body := &bytes.Buffer{}
+	for {
+		var size uint32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if body.Len()+int(size) > r.maxLength {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+		if _, err := io.CopyN(body, conn, int64(size)); err != nil {
+			return
+		}
+	}
+	r.received <- body.Bytes()
+
+	if strings.Contains(body.String(), eicar) {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestNopScanner(t *testing.T) {
+	scanner := &NopScannerImpl{}
+
+	verdict, err := scanner.Scan(strings.NewReader(eicar))
+
+	assert.Nil(t, err)
+	assert.Nil(t, verdict)
+}
+
+func TestNewClamdScannerImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		scanner := NewClamdScannerImpl("tcp", "127.0.0.1:3310", time.Second)
+
+		assert.Equal(t, "tcp", scanner.network)
+		assert.Equal(t, "127.0.0.1:3310", scanner.address)
+		assert.Equal(t, time.Second, scanner.timeout)
+	})
+
+	t.Run("Unsupported network", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewClamdScannerImpl("udp", "127.0.0.1:3310", time.Second)
+		})
+	})
+
+	t.Run("Timeout is zero", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewClamdScannerImpl("tcp", "127.0.0.1:3310", 0)
+		})
+	})
+}
+
+func TestClamdScanner(t *testing.T) {
+	t.Run("Clean", func(t *testing.T) {
+		clamd := newFakeClamd(t, "tcp", "127.0.0.1:0")
+		scanner := NewClamdScannerImpl("tcp", clamd.listener.Addr().String(), time.Second)
+
+		// チャンクの大きさを超える内容も、分割して全て送信する
+		body := bytes.Repeat([]byte("a"), clamdChunkSize*2+1)
+		verdict, err := scanner.Scan(bytes.NewReader(body))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.ScanVerdict{Scanner: "clamd"}, verdict)
+		assert.False(t, verdict.Infected())
+		assert.Equal(t, body, <-clamd.received)
+	})
+
+	t.Run("Infected", func(t *testing.T) {
+		clamd := newFakeClamd(t, "tcp", "127.0.0.1:0")
+		scanner := NewClamdScannerImpl("tcp", clamd.listener.Addr().String(), time.Second)
+
+		verdict, err := scanner.Scan(strings.NewReader(eicar))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &lib.ScanVerdict{Scanner: "clamd", Signature: "Eicar-Test-Signature"}, verdict)
+		assert.True(t, verdict.Infected())
+	})
+
+	t.Run("Unix socket", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "wu-clamd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		path := filepath.Join(dir, "clamd.sock")
+		newFakeClamd(t, "unix", path)
+		scanner := NewClamdScannerImpl("unix", path, time.Second)
+
+		verdict, err := scanner.Scan(strings.NewReader(eicar))
+
+		assert.Nil(t, err)
+		assert.True(t, verdict.Infected())
+	})
+
+	t.Run("Size limit exceeded", func(t *testing.T) {
+		clamd := newFakeClamd(t, "tcp", "127.0.0.1:0")
+		clamd.maxLength = 10
+		scanner := NewClamdScannerImpl("tcp", clamd.listener.Addr().String(), time.Second)
+
+		verdict, err := scanner.Scan(strings.NewReader("01234567890"))
+
+		assert.Nil(t, verdict)
+		assert.EqualError(t, err, "clamd: INSTREAM size limit exceeded. ERROR")
+	})
+
+	t.Run("Connection refused", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address := listener.Addr().String()
+		listener.Close()
+		scanner := NewClamdScannerImpl("tcp", address, time.Second)
+
+		verdict, err := scanner.Scan(strings.NewReader("1234"))
+
+		assert.Nil(t, verdict)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseClamdReply(t *testing.T) {
+	for _, reply := range []string{"", "UNKNOWN COMMAND", "stream: Can't allocate memory ERROR"} {
+		verdict, err := parseClamdReply(reply)
+
+		assert.Nil(t, verdict)
+		assert.Error(t, err, reply)
+	}
+}
@@ -1,50 +1,253 @@
 package infrastructures
 
 import (
+	"context"
 	"fmt"
-	"mime/multipart"
-	"os"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/edy4c7/works-uploader/internal/lib"
 )
 
+// pendingPrefix は、公開前のファイルを置くキーのプレフィックス。
+// 異常終了で残ったファイルは、バケットのライフサイクルルールで期限切れにすること。
+const pendingPrefix = "pending/"
+
+// quarantinePrefix は、マルウェアを検出したファイルを隔離するキーのプレフィックス。公開しない。
+const quarantinePrefix = "quarantine/"
+
 type StorageClientImpl struct {
+	client     *s3.S3
 	uploader   *s3manager.Uploader
 	bucketName string
+	baseURL    string
 }
 
-func NewStorageClientImpl() *StorageClientImpl {
+// NewStorageClientImpl は、StorageClientImplの新しいインスタンスを生成する。
+// endpointには、MinIOなどS3互換のストレージを使用する場合にそのURLを指定する。
+func NewStorageClientImpl(bucketName string, cdnDomain string, endpoint string) *StorageClientImpl {
+	awsConf := aws.NewConfig()
+	if endpoint != "" {
+		// S3互換のストレージは、バケット名をホスト名に含める形式に対応しないことが多い
+		awsConf = awsConf.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+
+	var baseURL string
+	switch {
+	case cdnDomain != "":
+		baseURL = fmt.Sprintf("https://%s", cdnDomain)
+	case endpoint != "":
+		baseURL = fmt.Sprintf("%s/%s", strings.TrimSuffix(endpoint, "/"), bucketName)
+	default:
+		baseURL = fmt.Sprintf("https://%s.s3.amazonaws.com", bucketName)
+	}
+
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		Config:            *awsConf,
 		SharedConfigState: session.SharedConfigEnable,
 	}))
 	return &StorageClientImpl{
+		client:     s3.New(sess),
 		uploader:   s3manager.NewUploader(sess),
-		bucketName: os.Getenv("S3_BUCKET"),
+		bucketName: bucketName,
+		baseURL:    baseURL,
 	}
 }
 
-func (r *StorageClientImpl) Upload(fileName string, fh *multipart.FileHeader) (string, error) {
-	body, err := fh.Open()
-	if err != nil {
-		return "", err
+// Upload は、bodyをパート毎にS3のマルチパートアップロードへ流し込む。
+// 失敗した場合、アップロード済みのパートは破棄される。
+func (r *StorageClientImpl) Upload(fileName string, body io.Reader, downloadName string, contentType string) error {
+	input := &s3manager.UploadInput{
+		Bucket:             aws.String(r.bucketName),
+		Key:                aws.String(pendingPrefix + fileName),
+		ContentDisposition: aws.String(contentDisposition(downloadName)),
+		Body:               body,
 	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	_, err := r.uploader.Upload(input)
+
+	return err
+}
 
-	_, err = r.uploader.Upload(&s3manager.UploadInput{
-		ACL:                aws.String(s3.BucketCannedACLPublicRead),
+// PresignUpload は、一時領域へのPutObjectの署名付きURLを生成する。
+// ファイルの属性は署名に含めるため、クライアントが異なる値を送信した場合はS3が拒否する。
+func (r *StorageClientImpl) PresignUpload(fileName string, downloadName string, object *lib.ObjectInfo, expires time.Duration) (*lib.PresignedRequest, error) {
+	req, _ := r.client.PutObjectRequest(&s3.PutObjectInput{
 		Bucket:             aws.String(r.bucketName),
-		Key:                aws.String(fileName),
-		ContentDisposition: aws.String(fmt.Sprintf("attachment;filename=\"%s\"", fh.Filename)),
-		Body:               body,
+		Key:                aws.String(pendingPrefix + fileName),
+		ContentDisposition: aws.String(contentDisposition(downloadName)),
+		ContentLength:      aws.Int64(object.Size),
+		ContentType:        aws.String(object.ContentType),
+	})
+
+	url, headers, err := req.PresignRequest(expires)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lib.PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       url,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expires),
+	}, nil
+}
+
+func (r *StorageClientImpl) Stat(fileName string) (*lib.ObjectInfo, error) {
+	out, err := r.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(pendingPrefix + fileName),
+	})
+	if err != nil {
+		// HeadObjectはボディを返さないため、エラーコードではなくステータスで判定する
+		if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusNotFound {
+			return nil, lib.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return &lib.ObjectInfo{
+		Size:        aws.Int64Value(out.ContentLength),
+		ContentType: aws.StringValue(out.ContentType),
+	}, nil
+}
+
+func (r *StorageClientImpl) Open(fileName string) (io.ReadCloser, error) {
+	out, err := r.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(pendingPrefix + fileName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+func (r *StorageClientImpl) Promote(fileName string, publicName string) error {
+	_, err := r.client.CopyObject(&s3.CopyObjectInput{
+		ACL:        aws.String(s3.BucketCannedACLPublicRead),
+		Bucket:     aws.String(r.bucketName),
+		CopySource: aws.String(fmt.Sprintf("%s/%s%s", r.bucketName, pendingPrefix, fileName)),
+		Key:        aws.String(publicName),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(pendingPrefix + fileName),
+	})
+
+	return err
+}
+
+// PromotePrivate は、公開するファイルと同じキーに、バケットの所有者のみが読み込めるACLで保存する
+func (r *StorageClientImpl) PromotePrivate(fileName string, privateName string) error {
+	_, err := r.client.CopyObject(&s3.CopyObjectInput{
+		ACL:        aws.String(s3.ObjectCannedACLPrivate),
+		Bucket:     aws.String(r.bucketName),
+		CopySource: aws.String(fmt.Sprintf("%s/%s%s", r.bucketName, pendingPrefix, fileName)),
+		Key:        aws.String(privateName),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(pendingPrefix + fileName),
 	})
 
+	return err
+}
+
+func (r *StorageClientImpl) Quarantine(fileName string) error {
+	_, err := r.client.CopyObject(&s3.CopyObjectInput{
+		ACL:        aws.String(s3.ObjectCannedACLPrivate),
+		Bucket:     aws.String(r.bucketName),
+		CopySource: aws.String(fmt.Sprintf("%s/%s%s", r.bucketName, pendingPrefix, fileName)),
+		Key:        aws.String(quarantinePrefix + fileName),
+	})
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	cdn := os.Getenv("CDN_DOMAIN")
+	_, err = r.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(pendingPrefix + fileName),
+	})
+
+	return err
+}
+
+func (r *StorageClientImpl) OpenPublished(fileName string) (io.ReadCloser, error) {
+	out, err := r.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(fileName),
+	})
+	if err != nil {
+		if aErr, ok := err.(awserr.Error); ok && aErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, lib.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+func (r *StorageClientImpl) Delete(fileName string) error {
+	_, err := r.client.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String(r.bucketName),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String(pendingPrefix + fileName)},
+				{Key: aws.String(fileName)},
+			},
+			Quiet: aws.Bool(true),
+		},
+	})
+
+	return err
+}
+
+func (r *StorageClientImpl) URL(fileName string) string {
+	return fmt.Sprintf("%s/%s", r.baseURL, fileName)
+}
+
+// SignURL は、GetObjectの署名付きURLを生成する。CDNを経由せず、S3から直接配信する。
+func (r *StorageClientImpl) SignURL(fileURL string, expires time.Duration) (string, error) {
+	fileName := strings.TrimPrefix(fileURL, r.baseURL+"/")
+	if fileName == fileURL || fileName == "" {
+		return "", fmt.Errorf("not a URL of the bucket: %q", fileURL)
+	}
+
+	req, _ := r.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(fileName),
+	})
+	return req.Presign(expires)
+}
+
+// Check は、バケットにアクセスできるかを確認する
+func (r *StorageClientImpl) Check(ctx context.Context) error {
+	_, err := r.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucketName),
+	})
+	return err
+}
 
-	return fmt.Sprintf("https://%s/%s", cdn, fileName), nil
+// contentDisposition は、ダウンロード時にdownloadNameで保存させるContent-Dispositionを返す
+func contentDisposition(downloadName string) string {
+	return fmt.Sprintf("attachment;filename=\"%s\"", downloadName)
 }
@@ -0,0 +1,122 @@
+package infrastructures
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// s3EndpointEnv にMinIOなどS3互換のストレージのURLを設定すると、S3のクライアントをテストする。
+// バケットは s3BucketEnv で指定し、認証情報とリージョンは AWS_ACCESS_KEY_ID などの環境変数で指定する。
+// 例: docker run -p 9000:9000 minio/minio server /data
+const (
+	s3EndpointEnv = "TEST_S3_ENDPOINT"
+	s3BucketEnv   = "TEST_S3_BUCKET"
+)
+
+func TestStorageClientWithS3(t *testing.T) {
+	endpoint := os.Getenv(s3EndpointEnv)
+	if endpoint == "" {
+		t.Skipf("%s is not set", s3EndpointEnv)
+	}
+
+	storage := NewStorageClientImpl(os.Getenv(s3BucketEnv), "", endpoint)
+	key := uuid.New().String() + ".txt"
+	t.Cleanup(func() { storage.Delete(key) })
+
+	t.Run("Presigned upload", func(t *testing.T) {
+		object := &lib.ObjectInfo{Size: 4, ContentType: "text/plain"}
+		presigned, err := storage.PresignUpload(key, "a.txt", object, time.Minute)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		req, _ := http.NewRequest(presigned.Method, presigned.URL, strings.NewReader("1234"))
+		req.Header = presigned.Headers.Clone()
+		res, err := http.DefaultClient.Do(req)
+		if !assert.Nil(t, err) {
+			return
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		info, err := storage.Stat(key)
+		assert.Nil(t, err)
+		assert.Equal(t, object, info)
+	})
+
+	t.Run("Promote and delete", func(t *testing.T) {
+		r, err := storage.Open(key)
+		if !assert.Nil(t, err) {
+			return
+		}
+		b, _ := ioutil.ReadAll(r)
+		r.Close()
+		assert.Equal(t, []byte("1234"), b)
+
+		assert.Nil(t, storage.Promote(key, key))
+		_, err = storage.Stat(key)
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+
+		r, err = storage.OpenPublished(key)
+		if assert.Nil(t, err) {
+			b, _ = ioutil.ReadAll(r)
+			r.Close()
+			assert.Equal(t, []byte("1234"), b)
+		}
+		_, err = storage.OpenPublished("nothing-" + key)
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+
+		assert.Nil(t, storage.Delete(key))
+	})
+	t.Run("Quarantine", func(t *testing.T) {
+		quarantined := "quarantined-" + key
+		t.Cleanup(func() {
+			storage.client.DeleteObject(&s3.DeleteObjectInput{
+				Bucket: aws.String(storage.bucketName),
+				Key:    aws.String(quarantinePrefix + quarantined),
+			})
+		})
+		if !assert.Nil(t, storage.Upload(quarantined, strings.NewReader("1234"), "a.txt", "text/plain")) {
+			return
+		}
+
+		assert.Nil(t, storage.Quarantine(quarantined))
+
+		_, err := storage.Stat(quarantined)
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+		_, err = storage.OpenPublished(quarantined)
+		assert.Equal(t, lib.ErrObjectNotFound, err)
+	})
+	t.Run("Promote private", func(t *testing.T) {
+		private := "private-" + key
+		t.Cleanup(func() { storage.Delete(private) })
+		if !assert.Nil(t, storage.Upload(private, strings.NewReader("1234"), "a.txt", "text/plain")) {
+			return
+		}
+
+		assert.Nil(t, storage.PromotePrivate(private, private))
+
+		signed, err := storage.SignURL(storage.URL(private), time.Minute)
+		if !assert.Nil(t, err) {
+			return
+		}
+		res, err := http.Get(signed)
+		if !assert.Nil(t, err) {
+			return
+		}
+		b, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []byte("1234"), b)
+	})
+}
@@ -0,0 +1,73 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper は、LIKEのパターンで特別な意味を持つ文字をエスケープする
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type TagsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTagsRepositoryImpl(db *gorm.DB) *TagsRepositoryImpl {
+	return &TagsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *TagsRepositoryImpl) Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error) {
+	counts := make([]*entities.TagCount, 0)
+	err := getDB(ctx, r.db).Model(&entities.Tag{}).
+		Select("tags.name, COUNT(works.id) AS work_count").
+		Joins("JOIN work_tags ON work_tags.tag_id = tags.id").
+		Joins("JOIN works ON works.id = work_tags.work_id").
+		Where("works.deleted_at IS NULL AND works.hidden_at IS NULL AND works.scan_status = ? AND works.visibility = ? AND works.status = ?",
+			constants.ScanClean, constants.VisibilityPublic, constants.WorkPublished).
+		Where(`tags.name LIKE ? ESCAPE '\'`, likeEscaper.Replace(prefix)+"%").
+		Group("tags.name").Order("work_count DESC, tags.name").Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *TagsRepositoryImpl) ReplaceWorkTags(ctx context.Context, workID uint64, names []string) ([]*entities.Tag, error) {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		tags := make([]*entities.Tag, 0, len(names))
+		for _, name := range names {
+			// 同じタグが同時に登録されても失敗しないよう、登録済みの場合は読み込み直す
+			tag := &entities.Tag{Name: name}
+			result := tx.WithContext(ctx).Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "name"}},
+				DoNothing: true,
+			}).Create(tag)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if result.RowsAffected == 0 {
+				if err := tx.WithContext(ctx).First(tag, "name = ?", name).Error; err != nil {
+					return nil, err
+				}
+			}
+			tags = append(tags, tag)
+		}
+
+		if err := tx.WithContext(ctx).Delete(&entities.WorkTag{}, "work_id = ?", workID).Error; err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if err := tx.WithContext(ctx).Create(&entities.WorkTag{WorkID: workID, TagID: tag.ID}).Error; err != nil {
+				return nil, err
+			}
+		}
+		return tags, nil
+	}
+	return nil, errors.New(notInTransactionMessage)
+}
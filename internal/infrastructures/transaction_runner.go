@@ -26,3 +26,11 @@ func (r *TransactionRunnerImpl) Run(ctx context.Context, tranFunc repositories.T
 		return tranFunc(context.WithValue(ctx, transactionKey, tx))
 	})
 }
+
+// getDB は、トランザクション中であればそのトランザクションを、そうでなければdbを返す
+func getDB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
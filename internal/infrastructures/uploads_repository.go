@@ -0,0 +1,85 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"gorm.io/gorm"
+)
+
+type UploadsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewUploadsRepositoryImpl(db *gorm.DB) *UploadsRepositoryImpl {
+	return &UploadsRepositoryImpl{db: db}
+}
+
+func (r *UploadsRepositoryImpl) FindByID(ctx context.Context, id string) (*entities.Upload, error) {
+	var upload entities.Upload
+	err := getDB(ctx, r.db).First(&upload, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &upload, err
+}
+
+func (r *UploadsRepositoryImpl) FindExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Upload, error) {
+	uploads := make([]*entities.Upload, 0)
+	err := getDB(ctx, r.db).Where("expires_at <= ?", now).Order("expires_at").Limit(limit).Find(&uploads).Error
+	return uploads, err
+}
+
+func (r *UploadsRepositoryImpl) Create(ctx context.Context, upload *entities.Upload) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Create(upload).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *UploadsRepositoryImpl) UpdateProgress(ctx context.Context, upload *entities.Upload, expectedChunks int) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(upload).
+			Where("chunks = ?", expectedChunks).
+			Select("offset", "chunks", "completed_at", "updated_at").
+			Updates(upload)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *UploadsRepositoryImpl) FindChunks(ctx context.Context, uploadID string) ([]*entities.UploadChunk, error) {
+	chunks := make([]*entities.UploadChunk, 0)
+	err := getDB(ctx, r.db).Where("upload_id = ?", uploadID).Order("number").Find(&chunks).Error
+	return chunks, err
+}
+
+func (r *UploadsRepositoryImpl) CreateChunk(ctx context.Context, chunk *entities.UploadChunk) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Create(chunk).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *UploadsRepositoryImpl) DeleteByID(ctx context.Context, id string) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Delete(&entities.Upload{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
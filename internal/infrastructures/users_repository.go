@@ -2,8 +2,11 @@ package infrastructures
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -17,8 +20,32 @@ func NewUserRepositoryImpl(db *gorm.DB) *UsersRepositoryImpl {
 }
 
 func (r *UsersRepositoryImpl) Save(ctx context.Context, user *entities.User) error {
-	return r.db.Clauses(clause.OnConflict{
+	return getDB(ctx, r.db).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"name", "nickname", "picture", "updated_at"}),
 	}).Create(user).Error
 }
+
+func (r *UsersRepositoryImpl) FindByID(ctx context.Context, id string) (*entities.User, error) {
+	var user entities.User
+	err := getDB(ctx, r.db).First(&user, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &user, err
+}
+
+func (r *UsersRepositoryImpl) UpdateSuspended(ctx context.Context, id string, suspendedAt *time.Time) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).
+			UpdateColumn("suspended_at", suspendedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
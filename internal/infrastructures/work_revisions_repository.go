@@ -0,0 +1,49 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"gorm.io/gorm"
+)
+
+type WorkRevisionsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWorkRevisionsRepositoryImpl(db *gorm.DB) *WorkRevisionsRepositoryImpl {
+	return &WorkRevisionsRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *WorkRevisionsRepositoryImpl) Create(ctx context.Context, revision *entities.WorkRevision) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Create(revision).Error
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorkRevisionsRepositoryImpl) FindByWorkID(ctx context.Context, workID uint64, offset int, limit int) ([]*entities.WorkRevision, error) {
+	revisions := make([]*entities.WorkRevision, 0)
+	err := getDB(ctx, r.db).Where("work_id = ?", workID).
+		Order("version DESC").Offset(offset).Limit(limit).Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *WorkRevisionsRepositoryImpl) CountByWorkID(ctx context.Context, workID uint64) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&entities.WorkRevision{}).Where("work_id = ?", workID).Count(&count).Error
+	return count, err
+}
+
+func (r *WorkRevisionsRepositoryImpl) FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error) {
+	var revision entities.WorkRevision
+	err := getDB(ctx, r.db).Where("work_id = ? AND version = ?", workID, version).First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &revision, err
+}
@@ -3,7 +3,9 @@ package infrastructures
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/edy4c7/works-uploader/internal/common/constants"
 	"github.com/edy4c7/works-uploader/internal/entities"
 	wuErr "github.com/edy4c7/works-uploader/internal/errors"
 	"gorm.io/gorm"
@@ -21,22 +23,22 @@ func NewWorksRepositoryImpl(db *gorm.DB) *WorksRepositoryImpl {
 	}
 }
 
-func (r *WorksRepositoryImpl) GetAll(ctx context.Context, offset int, limit int) ([]*entities.Work, error) {
+func (r *WorksRepositoryImpl) GetAll(ctx context.Context, viewer string, tags []string, order constants.WorkOrder, offset int, limit int) ([]*entities.Work, error) {
 	works := make([]*entities.Work, 0)
-	err := r.db.WithContext(ctx).Preload("Author").Offset(offset).Limit(limit).Find(&works).Error
+	err := ordered(r.tagged(r.listed(ctx, viewer), tags), order).Preload("Author").Preload("Tags", orderedTags).
+		Offset(offset).Limit(limit).Find(&works).Error
 	return works, err
 }
 
-func (r *WorksRepositoryImpl) CountAll(ctx context.Context) (int64, error) {
-	works := make([]*entities.Work, 0)
+func (r *WorksRepositoryImpl) CountAll(ctx context.Context, viewer string, tags []string) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Find(&works).Count(&count).Error
+	err := r.tagged(r.listed(ctx, viewer), tags).Model(&entities.Work{}).Count(&count).Error
 	return count, err
 }
 
 func (r *WorksRepositoryImpl) FindByID(ctx context.Context, id uint64) (*entities.Work, error) {
 	var work entities.Work
-	err := r.db.WithContext(ctx).Preload("Author").First(&work, id).Error
+	err := r.published(ctx).Preload("Author").Preload("Tags", orderedTags).First(&work, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
 	}
@@ -50,9 +52,255 @@ func (r *WorksRepositoryImpl) Create(ctx context.Context, work *entities.Work) e
 	return errors.New(notInTransactionMessage)
 }
 
+func (r *WorksRepositoryImpl) Update(ctx context.Context, work *entities.Work) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		// 他の更新と競合しないよう、読み込んだ時のバージョンの場合のみ更新する
+		query := tx.WithContext(ctx).Model(&entities.Work{}).
+			Where("id = ? AND version = ?", work.ID, work.Version)
+		if work.Status != constants.WorkPublished {
+			// 読み込んだ後に公開された作品は、公開前の状態に戻さない
+			query = query.Where("status <> ?", constants.WorkPublished)
+		}
+		result := query.
+			Updates(map[string]interface{}{
+				"status":         work.Status,
+				"publish_at":     work.PublishAt,
+				"title":          work.Title,
+				"description":    work.Description,
+				"thumbnail_url":  work.ThumbnailURL,
+				"thumbnails":     work.Thumbnails,
+				"content_url":    work.ContentURL,
+				"content_type":   work.ContentType,
+				"content_sha256": work.ContentSHA256,
+				"content_size":   work.ContentSize,
+				"version":        work.Version + 1,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		work.Version++
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorksRepositoryImpl) UpdateScanStatus(ctx context.Context, id uint64, status constants.ScanStatus) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(&entities.Work{}).Where("id = ?", id).Update("scan_status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorksRepositoryImpl) UpdateFavoriteCount(ctx context.Context, id uint64, delta int) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		// 同時に登録されても数え漏れないよう、読み込んだ値ではなく現在の値に加える
+		result := tx.WithContext(ctx).Unscoped().Model(&entities.Work{}).Where("id = ?", id).
+			UpdateColumn("favorite_count", gorm.Expr("favorite_count + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorksRepositoryImpl) UpdateHidden(ctx context.Context, id uint64, hiddenAt *time.Time) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Unscoped().Model(&entities.Work{}).Where("id = ?", id).
+			UpdateColumn("hidden_at", hiddenAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorksRepositoryImpl) FindLinksToCheck(ctx context.Context, checkedBefore time.Time, after uint64, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := r.published(ctx).
+		Where("works.type = ? AND works.id > ?", constants.ContentTypeURL, after).
+		Where("works.link_checked_at IS NULL OR works.link_checked_at < ?", checkedBefore).
+		Order("works.id").Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *WorksRepositoryImpl) UpdateLinkStatus(ctx context.Context, work *entities.Work) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Model(&entities.Work{}).Where("id = ?", work.ID).UpdateColumns(map[string]interface{}{
+			"link_status":     work.LinkStatus,
+			"link_checked_at": work.LinkCheckedAt,
+			"link_failures":   work.LinkFailures,
+			"link_down_since": work.LinkDownSince,
+			"link_broken":     work.LinkBroken,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+func (r *WorksRepositoryImpl) FindDueToPublish(ctx context.Context, now time.Time, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := r.published(ctx).
+		Where("works.status = ? AND works.publish_at <= ?", constants.WorkScheduled, now).
+		Order("works.publish_at, works.id").Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *WorksRepositoryImpl) Publish(ctx context.Context, id uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		// 複数のサーバーや作者が同時に公開しても、アクティビティを1度だけ登録できるよう、公開前の場合のみ更新する
+		result := tx.WithContext(ctx).Model(&entities.Work{}).
+			Where("id = ? AND status <> ?", id, constants.WorkPublished).
+			UpdateColumn("status", constants.WorkPublished)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
 func (r *WorksRepositoryImpl) DeleteByID(ctx context.Context, id uint64) error {
 	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
-		return tx.WithContext(ctx).Delete(&entities.Work{}, id).Error
+		result := tx.WithContext(ctx).Delete(&entities.Work{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// PurgeByID は、論理削除せずに作品を物理削除する。関連するアクティビティも外部キーによって削除される。
+func (r *WorksRepositoryImpl) PurgeByID(ctx context.Context, id uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Unscoped().Delete(&entities.Work{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
 	}
 	return errors.New(notInTransactionMessage)
 }
+
+func (r *WorksRepositoryImpl) FindTrashed(ctx context.Context, authorID string, offset int, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := r.trashed(ctx).Preload("Author").Preload("Tags", orderedTags).Where("works.author_id = ?", authorID).
+		Order("works.deleted_at DESC, works.id DESC").Offset(offset).Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *WorksRepositoryImpl) CountTrashed(ctx context.Context, authorID string) (int64, error) {
+	var count int64
+	err := r.trashed(ctx).Model(&entities.Work{}).Where("works.author_id = ?", authorID).Count(&count).Error
+	return count, err
+}
+
+func (r *WorksRepositoryImpl) FindTrashedByID(ctx context.Context, id uint64) (*entities.Work, error) {
+	var work entities.Work
+	err := r.trashed(ctx).Preload("Author").Preload("Tags", orderedTags).First(&work, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, wuErr.NewRecordNotFoundError(err.Error(), err)
+	}
+	return &work, err
+}
+
+func (r *WorksRepositoryImpl) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Work, error) {
+	works := make([]*entities.Work, 0)
+	err := getDB(ctx, r.db).Unscoped().Where("works.deleted_at < ?", before).
+		Order("works.deleted_at, works.id").Limit(limit).Find(&works).Error
+	return works, err
+}
+
+func (r *WorksRepositoryImpl) Restore(ctx context.Context, id uint64) error {
+	if tx, ok := ctx.Value(transactionKey).(*gorm.DB); ok {
+		result := tx.WithContext(ctx).Unscoped().Model(&entities.Work{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return wuErr.NewRecordNotFoundError(gorm.ErrRecordNotFound.Error(), gorm.ErrRecordNotFound)
+		}
+		return nil
+	}
+	return errors.New(notInTransactionMessage)
+}
+
+// published は、マルウェアの検査を通過した作品のみを対象にする
+func (r *WorksRepositoryImpl) published(ctx context.Context) *gorm.DB {
+	return getDB(ctx, r.db).Where("works.scan_status = ?", constants.ScanClean)
+}
+
+// listed は、公開された作品のうち、viewerの一覧に表示するもののみを対象にする。
+// 下書きと公開予定の作品と、モデレーターが非表示にした作品は、作者の一覧にのみ表示する。
+func (r *WorksRepositoryImpl) listed(ctx context.Context, viewer string) *gorm.DB {
+	return r.published(ctx).Where("(works.visibility = ? AND works.status = ? AND works.hidden_at IS NULL) OR works.author_id = ?",
+		constants.VisibilityPublic, constants.WorkPublished, viewer)
+}
+
+// trashed は、論理削除された作品のうち、マルウェアの検査を通過したもののみを対象にする
+func (r *WorksRepositoryImpl) trashed(ctx context.Context) *gorm.DB {
+	return getDB(ctx, r.db).Unscoped().
+		Where("works.scan_status = ? AND works.deleted_at IS NOT NULL", constants.ScanClean)
+}
+
+// tagged は、tagsの全てのタグが付いた作品のみを対象にする。tagsが空の場合は絞り込まない。
+func (r *WorksRepositoryImpl) tagged(db *gorm.DB, tags []string) *gorm.DB {
+	if len(tags) == 0 {
+		return db
+	}
+	workIDs := r.db.Model(&entities.WorkTag{}).Select("work_tags.work_id").
+		Joins("JOIN tags ON tags.id = work_tags.tag_id").
+		Where("tags.name IN ?", tags).
+		Group("work_tags.work_id").Having("COUNT(*) = ?", len(tags))
+	return db.Where("works.id IN (?)", workIDs)
+}
+
+// ordered は、作品をorderの順に並べる。既定の並び順では並べ替えない。
+func ordered(db *gorm.DB, order constants.WorkOrder) *gorm.DB {
+	switch order {
+	case constants.OrderMostFavorited:
+		return db.Order("works.favorite_count DESC, works.id DESC")
+	default:
+		return db
+	}
+}
+
+// orderedTags は、作品のタグを名前の順に読み込む
+func orderedTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
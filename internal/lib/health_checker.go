@@ -0,0 +1,8 @@
+package lib
+
+import "context"
+
+// HealthChecker は、依存先(DB、ストレージなど)が利用可能かを確認する
+type HealthChecker interface {
+	Check(context.Context) error
+}
@@ -0,0 +1,28 @@
+package lib
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrUnsupportedImage は、画像として読み込めない、または処理できる大きさを超えていることを表す
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// ImageVariant は、縮小した画像を表す
+type ImageVariant struct {
+	// Width は、要求した幅。元の画像の方が小さい場合は、実際の幅はこれより小さい。
+	Width       int
+	ContentType string
+	Body        []byte
+}
+
+// ImageProcessor は、アップロードされた画像の加工を表す
+type ImageProcessor interface {
+	// Resize は、画像を各幅に縮小する。元の画像以上の幅は拡大せず、元の大きさのものを最初の1つだけ生成する。
+	// 画像として読み込めない場合はErrUnsupportedImageを返す。
+	Resize(io.Reader, []int) ([]*ImageVariant, error)
+	// StripMetadata は、EXIF、XMP、IPTCなどのメタデータを取り除いた画像を読み込むReaderを返す。
+	// 取り除く対象の形式でない場合や、取り除かない設定の場合はfalseを返す。
+	// 画像として読み込めない場合は、ReaderがErrUnsupportedImageを返す。返したReaderは必ず閉じること。
+	StripMetadata(io.Reader, string) (io.ReadCloser, bool)
+}
@@ -1,11 +1,14 @@
 package lib
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/edy4c7/works-uploader/internal/common"
 	"github.com/form3tech-oss/jwt-go"
@@ -22,39 +25,107 @@ type jsonWebKeys struct {
 	} `json:"keys"`
 }
 
-func GetPemCertOfJWK(token *jwt.Token, url string) (string, error) {
-	cert := ""
-	resp, err := http.Get(url)
+// JWKS は、JWKのエンドポイントから取得した鍵をキャッシュする
+type JWKS struct {
+	url  string
+	mu   sync.RWMutex
+	keys *jsonWebKeys
+}
+
+func NewJWKS(url string) *JWKS {
+	return &JWKS{url: url}
+}
+
+// Load は、JWKのエンドポイントから鍵を取得し直す
+func (r *JWKS) Load(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
 
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return cert, err
+		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: %s", resp.Status)
+	}
+
 	var jwks = jsonWebKeys{}
-	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return cert, err
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = &jwks
+
+	return nil
+}
+
+// Check は、鍵が読み込まれているかを確認する。未読込の場合は読み込みを試みる。
+func (r *JWKS) Check(ctx context.Context) error {
+	r.mu.RLock()
+	loaded := r.keys != nil && len(r.keys.Keys) > 0
+	r.mu.RUnlock()
+
+	if loaded {
+		return nil
 	}
 
-	for k := range jwks.Keys {
-		if token.Header["kid"] == jwks.Keys[k].Kid {
-			cert = "-----BEGIN CERTIFICATE-----\n" + jwks.Keys[k].X5c[0] + "\n-----END CERTIFICATE-----"
+	if err := r.Load(ctx); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.keys.Keys) == 0 {
+		return errors.New("no keys in JWKS")
+	}
+
+	return nil
+}
+
+func (r *JWKS) findCert(kid interface{}) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.keys == nil {
+		return ""
+	}
+
+	for _, k := range r.keys.Keys {
+		if kid == k.Kid && len(k.X5c) > 0 {
+			return "-----BEGIN CERTIFICATE-----\n" + k.X5c[0] + "\n-----END CERTIFICATE-----"
 		}
 	}
 
-	if cert == "" {
-		err := errors.New("unable to find appropriate key")
-		return cert, err
+	return ""
+}
+
+// PemCert は、トークンのkidに対応する証明書を返す。
+// キャッシュに見つからない場合は、鍵のローテーションに備えて一度だけ取得し直す。
+func (r *JWKS) PemCert(token *jwt.Token) (string, error) {
+	if cert := r.findCert(token.Header["kid"]); cert != "" {
+		return cert, nil
+	}
+
+	if err := r.Load(context.Background()); err != nil {
+		return "", err
+	}
+
+	if cert := r.findCert(token.Header["kid"]); cert != "" {
+		return cert, nil
 	}
 
-	return cert, nil
+	return "", errors.New("unable to find appropriate key")
 }
 
-func CheckJWTScope(jwk string, scope string, tokenString string) bool {
+func CheckJWTScope(jwks *JWKS, scope string, tokenString string) bool {
 	token, err := jwt.ParseWithClaims(tokenString, make(jwt.MapClaims), func(token *jwt.Token) (interface{}, error) {
-		cert, err := GetPemCertOfJWK(token, jwk)
+		cert, err := jwks.PemCert(token)
 		if err != nil {
 			return nil, err
 		}
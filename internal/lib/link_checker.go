@@ -0,0 +1,10 @@
+package lib
+
+import "context"
+
+// LinkChecker は、URLの作品のリンク先に到達できるかの確認を表す
+type LinkChecker interface {
+	// Check は、リンク先の応答のステータスコードを返す。リダイレクトは追跡し、最後の応答のものを返す。
+	// 接続できなかった場合はエラーを返す。
+	Check(context.Context, string) (int, error)
+}
@@ -0,0 +1,23 @@
+package lib
+
+import (
+	"context"
+	"io"
+)
+
+// LinkPreview は、リンク先のページから取得したプレビューを表す。取得できなかった項目は空になる。
+type LinkPreview struct {
+	Title       string
+	Description string
+	// ImageURL は、プレビューの画像の絶対URL
+	ImageURL string
+}
+
+// LinkUnfurler は、URLの作品のリンク先からのプレビューの取得を表す
+type LinkUnfurler interface {
+	// Unfurl は、リンク先のOpenGraph、Twitterカード、oEmbedからプレビューを取得する。
+	// 取得しない設定の場合はnilを返す。
+	Unfurl(context.Context, string) (*LinkPreview, error)
+	// Fetch は、プレビューの画像などのリンク先のリソースを開く。返したReadCloserは必ず閉じること。
+	Fetch(context.Context, string) (io.ReadCloser, error)
+}
@@ -0,0 +1,23 @@
+package lib
+
+import "io"
+
+// ScanVerdict は、Scannerによる検査の結果を表す
+type ScanVerdict struct {
+	// Scanner は、検査したスキャナーの名前
+	Scanner string
+	// Signature は、検出したマルウェアの名前。検出しなかった場合は空になる。
+	Signature string
+}
+
+// Infected は、マルウェアを検出したかを返す
+func (r *ScanVerdict) Infected() bool {
+	return r.Signature != ""
+}
+
+// Scanner は、アップロードされたファイルのマルウェアの検査を表す
+type Scanner interface {
+	// Scan は、内容を全て読み込んで検査する。検査しない設定の場合は、読み込まずにnilを返す。
+	// 検査を完了できなかった場合はエラーを返す。
+	Scan(io.Reader) (*ScanVerdict, error)
+}
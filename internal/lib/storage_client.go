@@ -1,7 +1,71 @@
 package lib
 
-import "mime/multipart"
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
 
+// ErrObjectNotFound は、指定したキーのファイルがストレージに存在しないことを表す
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo は、ストレージに保存されたファイルの属性を表す
+type ObjectInfo struct {
+	Size int64
+	// ContentType は、ストレージが保持していない場合は空になる
+	ContentType string
+}
+
+// PresignedRequest は、クライアントがサーバーを経由せずにファイルを送信するための署名付きリクエストを表す。
+// クライアントは Headers を全て付けて URL へ送信する。
+type PresignedRequest struct {
+	Method    string
+	URL       string
+	Headers   http.Header
+	ExpiresAt time.Time
+}
+
+// StorageClient は、作品のファイルを保存するストレージを表す。
+// ファイルは公開前の一時領域にアップロードし、DBへの登録が確定した後にPromoteで公開する。
 type StorageClient interface {
-	Upload(string, *multipart.FileHeader) (string, error)
+	// Upload は、読み込んだ内容をバッファリングせずに公開前の一時領域へアップロードする。
+	// 引数は キー、内容、ダウンロード時のファイル名、Content-Type の順。Content-Typeは空にできる。
+	Upload(string, io.Reader, string, string) error
+	// PresignUpload は、一時領域へ直接アップロードするための署名付きリクエストを生成する。
+	// 引数は キー、ダウンロード時のファイル名、送信させるファイルの属性、有効期間 の順。
+	PresignUpload(string, string, *ObjectInfo, time.Duration) (*PresignedRequest, error)
+	// Stat は、一時領域にあるファイルの属性を取得する。存在しない場合はErrObjectNotFoundを返す。
+	Stat(string) (*ObjectInfo, error)
+	// Open は、一時領域にあるファイルを読み込む
+	Open(string) (io.ReadCloser, error)
+	// Promote は、一時領域にあるファイルを公開する。引数は 一時領域のキー、公開後のキー の順。
+	// 公開後のキーにファイルがある場合は置き換える。
+	Promote(string, string) error
+	// PromotePrivate は、一時領域にあるファイルを、URLだけでは閲覧できない非公開のファイルとして保存する。
+	// 引数は Promote と同じ。非公開のファイルは SignURL で署名したURLから閲覧する。
+	PromotePrivate(string, string) error
+	// OpenPublished は、公開済み・非公開のファイルを読み込む。存在しない場合はErrObjectNotFoundを返す。
+	OpenPublished(string) (io.ReadCloser, error)
+	// Quarantine は、一時領域にあるファイルを、公開されない隔離領域へ移動する
+	Quarantine(string) error
+	// Delete は、一時領域・公開済み・非公開のいずれにあるファイルも削除する。存在しない場合もエラーにしない。
+	Delete(string) error
+	// URL は、公開後のファイルのURLを返す
+	URL(string) string
+	// SignURL は、URLが返したURLを、非公開のファイルを有効期間の間だけ閲覧できる署名付きURLに変換する
+	SignURL(string, time.Duration) (string, error)
+}
+
+// SignedUploadReceiver は、PresignUploadで生成したリクエストをサーバー自身で受信するストレージを表す。
+// S3のように、ストレージが直接受信する場合は実装しない。
+type SignedUploadReceiver interface {
+	// VerifyUpload は、受信したリクエストの署名と、ヘッダーが署名した内容と一致することを検証する
+	VerifyUpload(key string, r *http.Request) (*ObjectInfo, error)
+}
+
+// SignedDownloadReceiver は、SignURLで生成したURLへのリクエストをサーバー自身で受信するストレージを表す
+type SignedDownloadReceiver interface {
+	// VerifyDownload は、受信したリクエストの署名を検証し、配信する非公開のファイルのパスを返す
+	VerifyDownload(key string, r *http.Request) (string, error)
 }
@@ -11,8 +11,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func NewJWTMiddleware(aud string, iss string, jwk string) *jwtmiddleware.JWTMiddleware {
-	return jwtmiddleware.New(jwtmiddleware.Options{
+func NewJWTMiddleware(aud string, iss string, jwks *lib.JWKS) *jwtmiddleware.JWTMiddleware {
+	return jwtmiddleware.New(jwtOptions(aud, iss, jwks))
+}
+
+// NewOptionalJWTMiddleware は、トークンがない場合は匿名のリクエストとして通し、ある場合は検証するミドルウェアを生成する
+func NewOptionalJWTMiddleware(aud string, iss string, jwks *lib.JWKS) *jwtmiddleware.JWTMiddleware {
+	opts := jwtOptions(aud, iss, jwks)
+	opts.CredentialsOptional = true
+	return jwtmiddleware.New(opts)
+}
+
+func jwtOptions(aud string, iss string, jwks *lib.JWKS) jwtmiddleware.Options {
+	return jwtmiddleware.Options{
 		ValidationKeyGetter: func(token *jwt.Token) (interface{}, error) {
 			// Verify 'aud' claim
 			checkAud := token.Claims.(jwt.MapClaims).VerifyAudience(aud, false)
@@ -25,16 +36,16 @@ func NewJWTMiddleware(aud string, iss string, jwk string) *jwtmiddleware.JWTMidd
 				return token, errors.New("invalid issuer")
 			}
 
-			cert, err := lib.GetPemCertOfJWK(token, jwk)
+			cert, err := jwks.PemCert(token)
 			if err != nil {
-				panic(err.Error())
+				return token, err
 			}
 
 			result, _ := jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
 			return result, nil
 		},
 		SigningMethod: jwt.SigningMethodRS256,
-	})
+	}
 }
 
 type policyFunc func(*http.Request) bool
@@ -44,7 +55,10 @@ type JWTMiddleware interface {
 }
 
 type authorizationConfig struct {
-	skipped policyFunc
+	skipped  policyFunc
+	optional policyFunc
+	// optionalJWT は、optionalに一致するリクエストを検証する
+	optionalJWT JWTMiddleware
 }
 
 type authorizationConfigrator func(*authorizationConfig)
@@ -55,6 +69,15 @@ func SkipAuthorization(filter policyFunc) authorizationConfigrator {
 	}
 }
 
+// OptionalAuthorization は、filterに一致するリクエストを、トークンを省略できるjwtMiddlewareで検証する。
+// トークンを送信したリクエストは、匿名のリクエストと区別できる。
+func OptionalAuthorization(jwtMiddleware JWTMiddleware, filter policyFunc) authorizationConfigrator {
+	return func(c *authorizationConfig) {
+		c.optional = filter
+		c.optionalJWT = jwtMiddleware
+	}
+}
+
 func NewAuthorizationMiddleware(jwtMiddleware JWTMiddleware, configrators ...authorizationConfigrator) gin.HandlerFunc {
 	conf := &authorizationConfig{}
 	for _, c := range configrators {
@@ -67,7 +90,11 @@ func NewAuthorizationMiddleware(jwtMiddleware JWTMiddleware, configrators ...aut
 			return
 		}
 
-		if err := jwtMiddleware.CheckJWT(c.Writer, c.Request); err != nil {
+		checker := jwtMiddleware
+		if conf.optional != nil && conf.optional(c.Request) {
+			checker = conf.optionalJWT
+		}
+		if err := checker.CheckJWT(c.Writer, c.Request); err != nil {
 			c.Error(err)
 			c.Abort()
 			return
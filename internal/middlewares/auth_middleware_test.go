@@ -37,6 +37,32 @@ func TestAuthentication(t *testing.T) {
 		assert.True(t, called)
 	})
 
+	t.Run("Authorization is optional", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJWTMiddleware := mocks.NewMockJWTMiddleware(ctrl)
+		mockOptionalJWTMiddleware := mocks.NewMockJWTMiddleware(ctrl)
+		middleware := NewAuthorizationMiddleware(
+			mockJWTMiddleware,
+			OptionalAuthorization(mockOptionalJWTMiddleware, func(r *http.Request) bool {
+				return r.Method == http.MethodGet
+			}),
+		)
+
+		w := httptest.NewRecorder()
+		c, r := gin.CreateTestContext(w)
+		called := false
+		r.GET("/", middleware, func(c *gin.Context) {
+			called = true
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		mockOptionalJWTMiddleware.EXPECT().CheckJWT(gomock.Any(), req).Return(nil)
+		c.Request = req
+		r.HandleContext(c)
+
+		assert.True(t, called)
+	})
+
 	t.Run("Authorization succeeded", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mockJWTMiddleware := mocks.NewMockJWTMiddleware(ctrl)
@@ -11,8 +11,18 @@ import (
 )
 
 var mapStatusCode = map[string]int{
+	wuErr.WUE00: http.StatusBadRequest,
 	wuErr.WUE01: http.StatusNotFound,
 	wuErr.WUE02: http.StatusForbidden,
+	wuErr.WUE03: http.StatusRequestEntityTooLarge,
+	wuErr.WUE04: http.StatusNotFound,
+	wuErr.WUE05: http.StatusConflict,
+	wuErr.WUE06: http.StatusUnprocessableEntity,
+	wuErr.WUE07: http.StatusConflict,
+	wuErr.WUE08: http.StatusBadRequest,
+	wuErr.WUE09: http.StatusBadRequest,
+	wuErr.WUE10: http.StatusForbidden,
+	wuErr.WUE11: http.StatusNotFound,
 	wuErr.WUE99: http.StatusInternalServerError,
 }
 
@@ -39,7 +49,7 @@ func NewErrorMiddleware(messagePrinter i18n.Printer) gin.HandlerFunc {
 			lang := c.Request.Header.Get("Accept-Language")
 			errBean := &beans.ErrorBean{
 				Code:    appErr.Code(),
-				Message: messagePrinter.Print(lang, appErr.Code(), appErr.MessageParams()),
+				Message: messagePrinter.Print(lang, appErr.Code(), appErr.MessageParams()...),
 			}
 			if sc, ok := mapStatusCode[appErr.Code()]; ok {
 				c.AbortWithStatusJSON(sc, errBean)
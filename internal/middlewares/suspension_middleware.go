@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// SuspensionChecker は、ログイン中のユーザーのアカウントが停止されているかの確認を表す
+type SuspensionChecker interface {
+	IsSuspended(context.Context) (bool, error)
+}
+
+// NewSuspensionMiddleware は、アカウントが停止されたユーザーによる閲覧以外の操作を、WUE10で拒否するミドルウェアを生成する。
+// skippedに一致するリクエストは、停止中でも受け付ける。認証のミドルウェアの後に適用する。
+func NewSuspensionMiddleware(checker SuspensionChecker, skipped policyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if skipped != nil && skipped(c.Request) {
+			return
+		}
+
+		suspended, err := checker.IsSuspended(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if suspended {
+			c.Error(wuErr.NewApplicationError(wuErr.Code(wuErr.WUE10)))
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveSuspension は、停止の確認のミドルウェアを適用したハンドラーにリクエストを送信し、ハンドラーが呼ばれたかを返す
+func serveSuspension(checker SuspensionChecker, skipped policyFunc, method string, path string) (bool, *gin.Context) {
+	w := httptest.NewRecorder()
+	c, r := gin.CreateTestContext(w)
+	called := false
+	r.Handle(method, path, NewSuspensionMiddleware(checker, skipped), func(c *gin.Context) {
+		called = true
+	})
+
+	req, _ := http.NewRequest(method, path, nil)
+	c.Request = req
+	r.HandleContext(c)
+	return called, c
+}
+
+func TestSuspension(t *testing.T) {
+	t.Run("Is not suspended", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		checker := mocks.NewMockUsersService(ctrl)
+		checker.EXPECT().IsSuspended(gomock.Any()).Return(false, nil)
+
+		called, c := serveSuspension(checker, nil, http.MethodPost, "/works")
+
+		assert.True(t, called)
+		assert.Nil(t, c.Errors.Last())
+	})
+
+	t.Run("Is suspended", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		checker := mocks.NewMockUsersService(ctrl)
+		checker.EXPECT().IsSuspended(gomock.Any()).Return(true, nil)
+
+		called, c := serveSuspension(checker, nil, http.MethodPost, "/works")
+
+		assert.False(t, called)
+		var appErr *wuErr.ApplicationError
+		if err := c.Errors.Last(); assert.NotNil(t, err) && assert.True(t, errors.As(err.Err, &appErr)) {
+			assert.Equal(t, wuErr.WUE10, appErr.Code())
+		}
+	})
+
+	t.Run("Viewing is allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		checker := mocks.NewMockUsersService(ctrl)
+
+		called, _ := serveSuspension(checker, nil, http.MethodGet, "/works")
+
+		assert.True(t, called)
+	})
+
+	t.Run("Is skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		checker := mocks.NewMockUsersService(ctrl)
+
+		called, _ := serveSuspension(checker, func(r *http.Request) bool {
+			return r.URL.Path == "/users"
+		}, http.MethodPut, "/users")
+
+		assert.True(t, called)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		expect := errors.New("error")
+		checker := mocks.NewMockUsersService(ctrl)
+		checker.EXPECT().IsSuspended(gomock.Any()).Return(false, expect)
+
+		called, c := serveSuspension(checker, nil, http.MethodDelete, "/works/1")
+
+		assert.False(t, called)
+		if err := c.Errors.Last(); assert.NotNil(t, err) {
+			assert.True(t, errors.Is(err.Err, expect))
+		}
+	})
+}
@@ -0,0 +1,314 @@
+// Package migrations は、バイナリに埋め込んだSQLファイルによるスキーマのバージョン管理を行う
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+const (
+	// Postgres は、PostgreSQL用のマイグレーションを表すdialect名
+	Postgres = "postgres"
+	// SQLite は、SQLite用のマイグレーションを表すdialect名
+	SQLite = "sqlite"
+)
+
+// advisoryLockKey は、マイグレーションの同時実行を防ぐためのロックキー ("wu_migra")
+const advisoryLockKey int64 = 0x77755f6d69677261
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration は、1つのバージョンのスキーマ変更を表す
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status は、マイグレーションの適用状況を表す。未適用の場合AppliedAtはnil。
+type Status struct {
+	*Migration
+	AppliedAt *time.Time
+}
+
+type dialect struct {
+	dir                string
+	createVersionTable string
+	insertVersion      string
+	deleteVersion      string
+	lock               func(context.Context, *sql.Conn) error
+	unlock             func(context.Context, *sql.Conn) error
+}
+
+var dialects = map[string]*dialect{
+	Postgres: {
+		dir: "postgres",
+		createVersionTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    bigint PRIMARY KEY,
+			name       text NOT NULL,
+			applied_at timestamptz NOT NULL
+		)`,
+		insertVersion: "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+		deleteVersion: "DELETE FROM schema_migrations WHERE version = $1",
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey)
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+			return err
+		},
+	},
+	// SQLiteは書き込みをファイルロックで直列化し、各マイグレーションはトランザクション内で
+	// バージョンの登録まで行うため、同時に実行されても二重に適用されることは無い
+	SQLite: {
+		dir: "sqlite",
+		createVersionTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    integer PRIMARY KEY,
+			name       text NOT NULL,
+			applied_at datetime NOT NULL
+		)`,
+		insertVersion: "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		deleteVersion: "DELETE FROM schema_migrations WHERE version = ?",
+		lock:          func(context.Context, *sql.Conn) error { return nil },
+		unlock:        func(context.Context, *sql.Conn) error { return nil },
+	},
+}
+
+// Migrator は、マイグレーションの適用・取り消しを行う
+type Migrator struct {
+	db         *sql.DB
+	dialect    *dialect
+	migrations []*Migration
+}
+
+// NewMigrator は、DBとdialect名を指定し、Migratorの新しいインスタンスを生成する
+func NewMigrator(db *sql.DB, dialectName string) (*Migrator, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialect: %s", dialectName)
+	}
+
+	migrations, err := load(files, d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    d,
+		migrations: migrations,
+	}, nil
+}
+
+// load は、ディレクトリ内の "<version>_<name>.(up|down).sql" を読み込み、バージョン順に並べて返す
+func load(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		m := fileNamePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", e.Name())
+		}
+
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("conflicting names for migration %d: %s, %s", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(b)
+		} else {
+			mig.Down = string(b)
+		}
+	}
+
+	result := make([]*Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", mig.Version, mig.Name)
+		}
+		result = append(result, mig)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// Up は、未適用のマイグレーションを全て適用し、適用したものを返す
+func (r *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var applied []*Migration
+
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := r.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			if _, ok := versions[m.Version]; ok {
+				continue
+			}
+
+			if err := r.apply(ctx, conn, m, m.Up, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, r.dialect.insertVersion, m.Version, m.Name, time.Now())
+				return err
+			}); err != nil {
+				return err
+			}
+			applied = append(applied, m)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down は、最後に適用したマイグレーションを1つ取り消し、取り消したものを返す。
+// 適用済みのものが無い場合はnilを返す。
+func (r *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var reverted *Migration
+
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := r.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0; i-- {
+			m := r.migrations[i]
+			if _, ok := versions[m.Version]; !ok {
+				continue
+			}
+
+			if err := r.apply(ctx, conn, m, m.Down, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, r.dialect.deleteVersion, m.Version)
+				return err
+			}); err != nil {
+				return err
+			}
+			reverted = m
+			return nil
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status は、全てのマイグレーションの適用状況をバージョン順に返す
+func (r *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	var result []*Status
+
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := r.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			s := &Status{Migration: m}
+			if t, ok := versions[m.Version]; ok {
+				appliedAt := t
+				s.AppliedAt = &appliedAt
+			}
+			result = append(result, s)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (r *Migrator) apply(ctx context.Context, conn *sql.Conn, m *Migration, query string, record func(*sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+	}
+
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint64]time.Time, error) {
+	if _, err := conn.ExecContext(ctx, r.dialect.createVersionTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[uint64]time.Time)
+	for rows.Next() {
+		var v uint64
+		var t time.Time
+		if err := rows.Scan(&v, &t); err != nil {
+			return nil, err
+		}
+		versions[v] = t
+	}
+
+	return versions, rows.Err()
+}
+
+// withLock は、ロックを取得した1つのコネクション上でfを実行する
+func (r *Migrator) withLock(ctx context.Context, f func(*sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := r.dialect.lock(ctx, conn); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer r.dialect.unlock(context.Background(), conn)
+
+	return f(conn)
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"db/0002_second.up.sql":   {Data: []byte("up2")},
+			"db/0002_second.down.sql": {Data: []byte("down2")},
+			"db/0001_first.up.sql":    {Data: []byte("up1")},
+			"db/0001_first.down.sql":  {Data: []byte("down1")},
+		}
+
+		result, err := load(fsys, "db")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*Migration{
+			{Version: 1, Name: "first", Up: "up1", Down: "down1"},
+			{Version: 2, Name: "second", Up: "up2", Down: "down2"},
+		}, result)
+	})
+
+	t.Run("Down file is missing", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"db/0001_first.up.sql": {Data: []byte("up1")},
+		}
+
+		_, err := load(fsys, "db")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid file name", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"db/first.sql": {Data: []byte("up1")},
+		}
+
+		_, err := load(fsys, "db")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Conflicting names", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"db/0001_first.up.sql":     {Data: []byte("up1")},
+			"db/0001_another.down.sql": {Data: []byte("down1")},
+		}
+
+		_, err := load(fsys, "db")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	names := make(map[string][]string)
+
+	for name, d := range dialects {
+		t.Run(name, func(t *testing.T) {
+			result, err := load(files, d.dir)
+
+			assert.Nil(t, err)
+			assert.NotEmpty(t, result)
+			for i, m := range result {
+				assert.Equal(t, uint64(i+1), m.Version, "versions must be sequential")
+				names[name] = append(names[name], m.Name)
+			}
+		})
+	}
+
+	// 全てのdialectで同じバージョンを持つこと
+	assert.Equal(t, names[Postgres], names[SQLite])
+}
@@ -0,0 +1,33 @@
+-- AutoMigrateで作成された既存のテーブルをそのまま引き継げるよう、IF NOT EXISTSで作成する
+CREATE TABLE IF NOT EXISTS users (
+    id         text PRIMARY KEY,
+    name       text,
+    nickname   text,
+    picture    text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS works (
+    id            bigserial PRIMARY KEY,
+    type          bigint,
+    title         text,
+    author_id     text,
+    description   text,
+    thumbnail_url text,
+    content_url   text,
+    version       bigint,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_works_deleted_at ON works (deleted_at);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id         bigserial PRIMARY KEY,
+    type       bigint,
+    user_id    text,
+    work_id    bigint,
+    created_at timestamptz
+);
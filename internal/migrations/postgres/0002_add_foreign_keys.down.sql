@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_activities_created_at;
+DROP INDEX IF EXISTS idx_activities_work_id;
+DROP INDEX IF EXISTS idx_activities_user_id;
+DROP INDEX IF EXISTS idx_works_author_id;
+
+ALTER TABLE activities
+    DROP CONSTRAINT IF EXISTS fk_activities_work,
+    DROP CONSTRAINT IF EXISTS fk_activities_user;
+
+ALTER TABLE works
+    DROP CONSTRAINT IF EXISTS fk_works_author;
@@ -0,0 +1,11 @@
+ALTER TABLE works
+    ADD CONSTRAINT fk_works_author FOREIGN KEY (author_id) REFERENCES users (id);
+
+ALTER TABLE activities
+    ADD CONSTRAINT fk_activities_user FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT fk_activities_work FOREIGN KEY (work_id) REFERENCES works (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_works_author_id ON works (author_id);
+CREATE INDEX idx_activities_user_id ON activities (user_id);
+CREATE INDEX idx_activities_work_id ON activities (work_id);
+CREATE INDEX idx_activities_created_at ON activities (created_at);
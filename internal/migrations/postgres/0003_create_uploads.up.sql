@@ -0,0 +1,15 @@
+CREATE TABLE uploads (
+    id           text PRIMARY KEY,
+    user_id      text NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    filename     text NOT NULL DEFAULT '',
+    storage_key  text NOT NULL,
+    length       bigint NOT NULL,
+    "offset"     bigint NOT NULL DEFAULT 0,
+    chunks       integer NOT NULL DEFAULT 0,
+    completed_at timestamptz,
+    expires_at   timestamptz NOT NULL,
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+
+CREATE INDEX idx_uploads_expires_at ON uploads (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE works DROP COLUMN thumbnails;
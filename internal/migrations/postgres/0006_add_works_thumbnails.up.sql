@@ -0,0 +1 @@
+ALTER TABLE works ADD COLUMN thumbnails text NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+ALTER TABLE works DROP COLUMN content_size;
+ALTER TABLE works DROP COLUMN content_sha256;
+
+DROP TABLE blobs;
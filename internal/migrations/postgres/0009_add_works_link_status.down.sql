@@ -0,0 +1,5 @@
+ALTER TABLE works DROP COLUMN link_broken;
+ALTER TABLE works DROP COLUMN link_down_since;
+ALTER TABLE works DROP COLUMN link_failures;
+ALTER TABLE works DROP COLUMN link_checked_at;
+ALTER TABLE works DROP COLUMN link_status;
@@ -0,0 +1,25 @@
+CREATE TABLE work_revisions (
+    id             bigserial PRIMARY KEY,
+    work_id        bigint NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    version        bigint NOT NULL,
+    type           bigint NOT NULL,
+    title          text NOT NULL DEFAULT '',
+    description    text NOT NULL DEFAULT '',
+    thumbnail_url  text NOT NULL DEFAULT '',
+    thumbnails     text NOT NULL DEFAULT '{}',
+    content_url    text NOT NULL DEFAULT '',
+    content_type   text NOT NULL DEFAULT '',
+    content_sha256 text NOT NULL DEFAULT '',
+    content_size   bigint NOT NULL DEFAULT 0,
+    editor_id      text NOT NULL,
+    created_at     timestamptz
+);
+
+CREATE UNIQUE INDEX idx_work_revisions_work_id_version ON work_revisions (work_id, version);
+
+-- 既存の作品は、現在の内容を作者による最初の履歴として記録する
+INSERT INTO work_revisions (work_id, version, type, title, description, thumbnail_url, thumbnails,
+    content_url, content_type, content_sha256, content_size, editor_id, created_at)
+SELECT id, COALESCE(version, 1), COALESCE(type, 0), COALESCE(title, ''), COALESCE(description, ''), COALESCE(thumbnail_url, ''), thumbnails,
+    COALESCE(content_url, ''), content_type, content_sha256, content_size, COALESCE(author_id, ''), updated_at
+FROM works;
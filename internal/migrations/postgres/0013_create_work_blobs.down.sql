@@ -0,0 +1 @@
+DROP TABLE work_blobs;
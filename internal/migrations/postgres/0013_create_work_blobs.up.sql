@@ -0,0 +1,20 @@
+-- 作品が参照している公開済みのファイル。blobs.ref_count は、参照している作品の数になる。
+CREATE TABLE work_blobs (
+    work_id bigint NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    key     text NOT NULL,
+    PRIMARY KEY (work_id, key)
+);
+
+CREATE INDEX idx_work_blobs_key ON work_blobs (key);
+
+-- 既存の作品と履歴のファイルは、URLの末尾のキーから登録済みのファイルを探す
+INSERT INTO work_blobs (work_id, key)
+SELECT DISTINCT refs.work_id, blobs.key
+FROM (
+    SELECT id AS work_id, thumbnail_url, thumbnails, content_url FROM works
+    UNION ALL
+    SELECT work_id, thumbnail_url, thumbnails, content_url FROM work_revisions
+) refs
+JOIN blobs ON refs.thumbnail_url LIKE '%/' || blobs.key
+    OR refs.content_url LIKE '%/' || blobs.key
+    OR refs.thumbnails LIKE '%/' || blobs.key || '"%';
@@ -0,0 +1,14 @@
+-- 作品のタグ。name は正規化 (NFKC、小文字) した名前で、大文字と小文字を区別せずに一意になる。
+CREATE TABLE tags (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL UNIQUE,
+    created_at timestamptz
+);
+
+CREATE TABLE work_tags (
+    work_id bigint NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    tag_id  bigint NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (work_id, tag_id)
+);
+
+CREATE INDEX idx_work_tags_tag_id ON work_tags (tag_id);
@@ -0,0 +1,29 @@
+-- 作者が作品をまとめたコレクション。cover_work_id は表紙にする作品で、作品を物理削除すると表紙なしになる。
+CREATE TABLE collections (
+    id            bigserial PRIMARY KEY,
+    owner_id      text NOT NULL REFERENCES users (id),
+    title         text NOT NULL,
+    description   text NOT NULL DEFAULT '',
+    visibility    integer NOT NULL DEFAULT 1,
+    cover_work_id bigint REFERENCES works (id) ON DELETE SET NULL,
+    created_at    timestamptz,
+    updated_at    timestamptz
+);
+
+CREATE INDEX idx_collections_owner_id ON collections (owner_id);
+
+-- コレクションに含まれる作品。position の昇順に並べる。
+CREATE TABLE collection_works (
+    collection_id bigint NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    work_id       bigint NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    position      integer NOT NULL,
+    created_at    timestamptz,
+    PRIMARY KEY (collection_id, work_id)
+);
+
+CREATE INDEX idx_collection_works_work_id ON collection_works (work_id);
+
+-- 作品をコレクションに追加したアクティビティは、追加先のコレクションを記録する
+ALTER TABLE activities ADD COLUMN collection_id bigint REFERENCES collections (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_activities_collection_id ON activities (collection_id);
@@ -0,0 +1,14 @@
+-- ユーザーがお気に入りに登録した作品。作品を物理削除すると削除する。
+CREATE TABLE favorites (
+    user_id    text NOT NULL REFERENCES users (id),
+    work_id    bigint NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    created_at timestamptz,
+    PRIMARY KEY (user_id, work_id)
+);
+
+CREATE INDEX idx_favorites_work_id ON favorites (work_id);
+
+-- 作品をお気に入りに登録したユーザーの数。お気に入りの多い順に並べるため、お気に入りの登録・解除と同じトランザクションで更新する。
+ALTER TABLE works ADD COLUMN favorite_count bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_works_favorite_count ON works (favorite_count);
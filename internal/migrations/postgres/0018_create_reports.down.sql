@@ -0,0 +1,12 @@
+DROP INDEX idx_moderation_actions_user_id;
+DROP INDEX idx_moderation_actions_work_id;
+
+DROP TABLE moderation_actions;
+
+DROP INDEX idx_reports_status;
+
+DROP TABLE reports;
+
+ALTER TABLE users DROP COLUMN suspended_at;
+
+ALTER TABLE works DROP COLUMN hidden_at;
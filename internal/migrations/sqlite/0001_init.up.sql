@@ -0,0 +1,33 @@
+-- SQLiteはALTER TABLEで外部キーを追加できないため、作成時に制約を定義する
+CREATE TABLE users (
+    id         text PRIMARY KEY,
+    name       text,
+    nickname   text,
+    picture    text,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE works (
+    id            integer PRIMARY KEY AUTOINCREMENT,
+    type          integer,
+    title         text,
+    author_id     text REFERENCES users (id),
+    description   text,
+    thumbnail_url text,
+    content_url   text,
+    version       integer,
+    created_at    datetime,
+    updated_at    datetime,
+    deleted_at    datetime
+);
+
+CREATE INDEX idx_works_deleted_at ON works (deleted_at);
+
+CREATE TABLE activities (
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    type       integer,
+    user_id    text REFERENCES users (id),
+    work_id    integer REFERENCES works (id) ON DELETE CASCADE,
+    created_at datetime
+);
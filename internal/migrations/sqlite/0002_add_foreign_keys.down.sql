@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_activities_created_at;
+DROP INDEX IF EXISTS idx_activities_work_id;
+DROP INDEX IF EXISTS idx_activities_user_id;
+DROP INDEX IF EXISTS idx_works_author_id;
@@ -0,0 +1,5 @@
+-- 外部キー制約は0001で作成済みのため、インデックスのみ追加する
+CREATE INDEX idx_works_author_id ON works (author_id);
+CREATE INDEX idx_activities_user_id ON activities (user_id);
+CREATE INDEX idx_activities_work_id ON activities (work_id);
+CREATE INDEX idx_activities_created_at ON activities (created_at);
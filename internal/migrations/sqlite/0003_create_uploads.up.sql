@@ -0,0 +1,15 @@
+CREATE TABLE uploads (
+    id           text PRIMARY KEY,
+    user_id      text NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    filename     text NOT NULL DEFAULT '',
+    storage_key  text NOT NULL,
+    length       integer NOT NULL,
+    "offset"     integer NOT NULL DEFAULT 0,
+    chunks       integer NOT NULL DEFAULT 0,
+    completed_at datetime,
+    expires_at   datetime NOT NULL,
+    created_at   datetime,
+    updated_at   datetime
+);
+
+CREATE INDEX idx_uploads_expires_at ON uploads (expires_at);
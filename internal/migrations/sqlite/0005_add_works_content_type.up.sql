@@ -0,0 +1 @@
+ALTER TABLE works ADD COLUMN content_type text NOT NULL DEFAULT '';
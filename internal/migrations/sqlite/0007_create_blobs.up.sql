@@ -0,0 +1,11 @@
+CREATE TABLE blobs (
+    key        text PRIMARY KEY,
+    sha256     text NOT NULL,
+    size       integer NOT NULL,
+    ref_count  integer NOT NULL DEFAULT 1,
+    created_at datetime,
+    updated_at datetime
+);
+
+ALTER TABLE works ADD COLUMN content_sha256 text NOT NULL DEFAULT '';
+ALTER TABLE works ADD COLUMN content_size integer NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE scan_results;
+
+ALTER TABLE works DROP COLUMN scan_status;
@@ -0,0 +1,14 @@
+-- 既存の作品は検査済み (constants.ScanClean) として扱う
+ALTER TABLE works ADD COLUMN scan_status integer NOT NULL DEFAULT 2;
+
+CREATE TABLE scan_results (
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    work_id    integer NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    field      text NOT NULL,
+    key        text NOT NULL,
+    scanner    text NOT NULL,
+    signature  text NOT NULL DEFAULT '',
+    created_at datetime
+);
+
+CREATE INDEX idx_scan_results_work_id ON scan_results (work_id);
@@ -0,0 +1,5 @@
+ALTER TABLE works ADD COLUMN link_status integer NOT NULL DEFAULT 0;
+ALTER TABLE works ADD COLUMN link_checked_at datetime;
+ALTER TABLE works ADD COLUMN link_failures integer NOT NULL DEFAULT 0;
+ALTER TABLE works ADD COLUMN link_down_since datetime;
+ALTER TABLE works ADD COLUMN link_broken boolean NOT NULL DEFAULT false;
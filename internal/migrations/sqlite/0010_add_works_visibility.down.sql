@@ -0,0 +1,3 @@
+DROP INDEX idx_works_visibility;
+
+ALTER TABLE works DROP COLUMN visibility;
@@ -0,0 +1,4 @@
+-- 既存の作品は公開 (constants.VisibilityPublic) として扱う
+ALTER TABLE works ADD COLUMN visibility integer NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_works_visibility ON works (visibility);
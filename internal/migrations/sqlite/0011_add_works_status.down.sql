@@ -0,0 +1,4 @@
+DROP INDEX idx_works_status_publish_at;
+
+ALTER TABLE works DROP COLUMN publish_at;
+ALTER TABLE works DROP COLUMN status;
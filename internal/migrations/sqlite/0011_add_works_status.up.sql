@@ -0,0 +1,5 @@
+-- 既存の作品は公開済み (constants.WorkPublished) として扱う
+ALTER TABLE works ADD COLUMN status integer NOT NULL DEFAULT 1;
+ALTER TABLE works ADD COLUMN publish_at datetime;
+
+CREATE INDEX idx_works_status_publish_at ON works (status, publish_at);
@@ -0,0 +1,3 @@
+DROP TABLE work_tags;
+
+DROP TABLE tags;
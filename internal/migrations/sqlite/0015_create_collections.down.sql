@@ -0,0 +1,7 @@
+DROP INDEX idx_activities_collection_id;
+
+ALTER TABLE activities DROP COLUMN collection_id;
+
+DROP TABLE collection_works;
+
+DROP TABLE collections;
@@ -0,0 +1,5 @@
+DROP INDEX idx_works_favorite_count;
+
+ALTER TABLE works DROP COLUMN favorite_count;
+
+DROP TABLE favorites;
@@ -0,0 +1,5 @@
+DROP INDEX idx_activities_comment_id;
+
+ALTER TABLE activities DROP COLUMN comment_id;
+
+DROP TABLE comments;
@@ -0,0 +1,18 @@
+-- 作品へのコメント。parent_id は返信先のコメントで、返信は1階層まで。作品や返信先のコメントを物理削除すると削除する。
+CREATE TABLE comments (
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    work_id    integer NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    author_id  text NOT NULL REFERENCES users (id),
+    parent_id  integer REFERENCES comments (id) ON DELETE CASCADE,
+    body       text NOT NULL,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE INDEX idx_comments_work_id ON comments (work_id);
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
+
+-- コメントを投稿したアクティビティは、投稿したコメントを記録する
+ALTER TABLE activities ADD COLUMN comment_id integer REFERENCES comments (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_activities_comment_id ON activities (comment_id);
@@ -0,0 +1,33 @@
+-- モデレーターが作品を非表示にした日時と、ユーザーのアカウントを停止した日時
+ALTER TABLE works ADD COLUMN hidden_at datetime;
+
+ALTER TABLE users ADD COLUMN suspended_at datetime;
+
+-- ユーザーによる作品の通報。同じユーザーは同じ作品を1回のみ通報でき、作品を物理削除すると削除する。
+CREATE TABLE reports (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    work_id     integer NOT NULL REFERENCES works (id) ON DELETE CASCADE,
+    reporter_id text NOT NULL REFERENCES users (id),
+    reason      integer NOT NULL,
+    note        text NOT NULL DEFAULT '',
+    status      integer NOT NULL DEFAULT 1,
+    resolved_at datetime,
+    created_at  datetime,
+    UNIQUE (work_id, reporter_id)
+);
+
+CREATE INDEX idx_reports_status ON reports (status);
+
+-- モデレーターの操作の記録。作品やユーザーを物理削除しても残すため、対象は外部キーで参照しない。
+CREATE TABLE moderation_actions (
+    id           integer PRIMARY KEY AUTOINCREMENT,
+    type         integer NOT NULL,
+    moderator_id text,
+    work_id      integer,
+    user_id      text,
+    note         text NOT NULL DEFAULT '',
+    created_at   datetime
+);
+
+CREATE INDEX idx_moderation_actions_work_id ON moderation_actions (work_id);
+CREATE INDEX idx_moderation_actions_user_id ON moderation_actions (user_id);
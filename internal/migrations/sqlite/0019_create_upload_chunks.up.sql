@@ -0,0 +1,18 @@
+-- tusで受信したチャンク。同じ位置への要求が同時に行われても上書きしないよう、要求毎に異なるキーに保存し、
+-- 受信位置を更新できた要求のキーだけを記録する。アップロードを削除すると削除する。
+CREATE TABLE upload_chunks (
+    upload_id   text NOT NULL REFERENCES uploads (id) ON DELETE CASCADE,
+    number      integer NOT NULL,
+    storage_key text NOT NULL,
+    created_at  datetime,
+    PRIMARY KEY (upload_id, number)
+);
+
+-- 受信中のアップロードのチャンクは、番号だけのキーに保存されている
+INSERT INTO upload_chunks (upload_id, number, storage_key)
+WITH RECURSIVE parts (upload_id, number, storage_key, total) AS (
+    SELECT id, 0, storage_key, chunks FROM uploads WHERE chunks > 0 AND completed_at IS NULL
+    UNION ALL
+    SELECT upload_id, number + 1, storage_key, total FROM parts WHERE number + 1 < total
+)
+SELECT upload_id, number, storage_key || '.' || number || '.part' FROM parts;
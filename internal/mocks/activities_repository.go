@@ -6,6 +6,7 @@ package mocks
 
 import (
 	context "context"
+	constants "github.com/edy4c7/works-uploader/internal/common/constants"
 	entities "github.com/edy4c7/works-uploader/internal/entities"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -35,33 +36,33 @@ func (m *MockActivitiesRepository) EXPECT() *MockActivitiesRepositoryMockRecorde
 }
 
 // GetAll mocks base method
-func (m *MockActivitiesRepository) GetAll(arg0 context.Context, arg1 int) ([]*entities.Activity, error) {
+func (m *MockActivitiesRepository) GetAll(ctx context.Context, viewer string, limit int) ([]*entities.Activity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAll", ctx, viewer, limit)
 	ret0, _ := ret[0].([]*entities.Activity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockActivitiesRepositoryMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockActivitiesRepositoryMockRecorder) GetAll(ctx, viewer, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockActivitiesRepository)(nil).GetAll), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockActivitiesRepository)(nil).GetAll), ctx, viewer, limit)
 }
 
 // FindByUserID mocks base method
-func (m *MockActivitiesRepository) FindByUserID(arg0 context.Context, arg1 string, arg2 int) ([]*entities.Activity, error) {
+func (m *MockActivitiesRepository) FindByUserID(ctx context.Context, viewer, userID string, limit int) ([]*entities.Activity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUserID", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, viewer, userID, limit)
 	ret0, _ := ret[0].([]*entities.Activity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByUserID indicates an expected call of FindByUserID
-func (mr *MockActivitiesRepositoryMockRecorder) FindByUserID(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockActivitiesRepositoryMockRecorder) FindByUserID(ctx, viewer, userID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockActivitiesRepository)(nil).FindByUserID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockActivitiesRepository)(nil).FindByUserID), ctx, viewer, userID, limit)
 }
 
 // Create mocks base method
@@ -77,3 +78,17 @@ func (mr *MockActivitiesRepositoryMockRecorder) Create(arg0, arg1 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockActivitiesRepository)(nil).Create), arg0, arg1)
 }
+
+// DeleteByType mocks base method
+func (m *MockActivitiesRepository) DeleteByType(ctx context.Context, actType constants.ActivityType, userID string, workID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByType", ctx, actType, userID, workID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByType indicates an expected call of DeleteByType
+func (mr *MockActivitiesRepositoryMockRecorder) DeleteByType(ctx, actType, userID, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByType", reflect.TypeOf((*MockActivitiesRepository)(nil).DeleteByType), ctx, actType, userID, workID)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/blobs_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBlobsRepository is a mock of BlobsRepository interface
+type MockBlobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobsRepositoryMockRecorder
+}
+
+// MockBlobsRepositoryMockRecorder is the mock recorder for MockBlobsRepository
+type MockBlobsRepositoryMockRecorder struct {
+	mock *MockBlobsRepository
+}
+
+// NewMockBlobsRepository creates a new mock instance
+func NewMockBlobsRepository(ctrl *gomock.Controller) *MockBlobsRepository {
+	mock := &MockBlobsRepository{ctrl: ctrl}
+	mock.recorder = &MockBlobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBlobsRepository) EXPECT() *MockBlobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method
+func (m *MockBlobsRepository) Acquire(ctx context.Context, workID uint64, blob *entities.Blob) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, workID, blob)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire
+func (mr *MockBlobsRepositoryMockRecorder) Acquire(ctx, workID, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockBlobsRepository)(nil).Acquire), ctx, workID, blob)
+}
+
+// Release mocks base method
+func (m *MockBlobsRepository) Release(ctx context.Context, workID uint64, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, workID, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Release indicates an expected call of Release
+func (mr *MockBlobsRepositoryMockRecorder) Release(ctx, workID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockBlobsRepository)(nil).Release), ctx, workID, key)
+}
+
+// FindKeysByWorkID mocks base method
+func (m *MockBlobsRepository) FindKeysByWorkID(ctx context.Context, workID uint64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindKeysByWorkID", ctx, workID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKeysByWorkID indicates an expected call of FindKeysByWorkID
+func (mr *MockBlobsRepositoryMockRecorder) FindKeysByWorkID(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKeysByWorkID", reflect.TypeOf((*MockBlobsRepository)(nil).FindKeysByWorkID), ctx, workID)
+}
+
+// FindAfter mocks base method
+func (m *MockBlobsRepository) FindAfter(ctx context.Context, after string, limit int) ([]*entities.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAfter", ctx, after, limit)
+	ret0, _ := ret[0].([]*entities.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAfter indicates an expected call of FindAfter
+func (mr *MockBlobsRepositoryMockRecorder) FindAfter(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAfter", reflect.TypeOf((*MockBlobsRepository)(nil).FindAfter), ctx, after, limit)
+}
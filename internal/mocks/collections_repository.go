@@ -0,0 +1,225 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/collections_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCollectionsRepository is a mock of CollectionsRepository interface
+type MockCollectionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionsRepositoryMockRecorder
+}
+
+// MockCollectionsRepositoryMockRecorder is the mock recorder for MockCollectionsRepository
+type MockCollectionsRepositoryMockRecorder struct {
+	mock *MockCollectionsRepository
+}
+
+// NewMockCollectionsRepository creates a new mock instance
+func NewMockCollectionsRepository(ctrl *gomock.Controller) *MockCollectionsRepository {
+	mock := &MockCollectionsRepository{ctrl: ctrl}
+	mock.recorder = &MockCollectionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCollectionsRepository) EXPECT() *MockCollectionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockCollectionsRepository) GetAll(ctx context.Context, viewer, ownerID string, offset, limit int) ([]*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, viewer, ownerID, offset, limit)
+	ret0, _ := ret[0].([]*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockCollectionsRepositoryMockRecorder) GetAll(ctx, viewer, ownerID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCollectionsRepository)(nil).GetAll), ctx, viewer, ownerID, offset, limit)
+}
+
+// CountAll mocks base method
+func (m *MockCollectionsRepository) CountAll(ctx context.Context, viewer, ownerID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAll", ctx, viewer, ownerID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAll indicates an expected call of CountAll
+func (mr *MockCollectionsRepositoryMockRecorder) CountAll(ctx, viewer, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAll", reflect.TypeOf((*MockCollectionsRepository)(nil).CountAll), ctx, viewer, ownerID)
+}
+
+// FindByID mocks base method
+func (m *MockCollectionsRepository) FindByID(arg0 context.Context, arg1 uint64) (*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockCollectionsRepositoryMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCollectionsRepository)(nil).FindByID), arg0, arg1)
+}
+
+// FindByWorkID mocks base method
+func (m *MockCollectionsRepository) FindByWorkID(ctx context.Context, viewer string, workID uint64) ([]*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByWorkID", ctx, viewer, workID)
+	ret0, _ := ret[0].([]*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByWorkID indicates an expected call of FindByWorkID
+func (mr *MockCollectionsRepositoryMockRecorder) FindByWorkID(ctx, viewer, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWorkID", reflect.TypeOf((*MockCollectionsRepository)(nil).FindByWorkID), ctx, viewer, workID)
+}
+
+// Create mocks base method
+func (m *MockCollectionsRepository) Create(arg0 context.Context, arg1 *entities.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCollectionsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCollectionsRepository)(nil).Create), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockCollectionsRepository) Update(arg0 context.Context, arg1 *entities.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCollectionsRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCollectionsRepository)(nil).Update), arg0, arg1)
+}
+
+// DeleteByID mocks base method
+func (m *MockCollectionsRepository) DeleteByID(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockCollectionsRepositoryMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCollectionsRepository)(nil).DeleteByID), arg0, arg1)
+}
+
+// FindWorks mocks base method
+func (m *MockCollectionsRepository) FindWorks(ctx context.Context, viewer string, id uint64, offset, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWorks", ctx, viewer, id, offset, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWorks indicates an expected call of FindWorks
+func (mr *MockCollectionsRepositoryMockRecorder) FindWorks(ctx, viewer, id, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWorks", reflect.TypeOf((*MockCollectionsRepository)(nil).FindWorks), ctx, viewer, id, offset, limit)
+}
+
+// CountWorks mocks base method
+func (m *MockCollectionsRepository) CountWorks(ctx context.Context, viewer string, id uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorks", ctx, viewer, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorks indicates an expected call of CountWorks
+func (mr *MockCollectionsRepositoryMockRecorder) CountWorks(ctx, viewer, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorks", reflect.TypeOf((*MockCollectionsRepository)(nil).CountWorks), ctx, viewer, id)
+}
+
+// ContainsWork mocks base method
+func (m *MockCollectionsRepository) ContainsWork(ctx context.Context, id, workID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainsWork", ctx, id, workID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainsWork indicates an expected call of ContainsWork
+func (mr *MockCollectionsRepositoryMockRecorder) ContainsWork(ctx, id, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsWork", reflect.TypeOf((*MockCollectionsRepository)(nil).ContainsWork), ctx, id, workID)
+}
+
+// AddWork mocks base method
+func (m *MockCollectionsRepository) AddWork(ctx context.Context, id, workID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWork", ctx, id, workID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWork indicates an expected call of AddWork
+func (mr *MockCollectionsRepositoryMockRecorder) AddWork(ctx, id, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWork", reflect.TypeOf((*MockCollectionsRepository)(nil).AddWork), ctx, id, workID)
+}
+
+// RemoveWork mocks base method
+func (m *MockCollectionsRepository) RemoveWork(ctx context.Context, id, workID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWork", ctx, id, workID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWork indicates an expected call of RemoveWork
+func (mr *MockCollectionsRepositoryMockRecorder) RemoveWork(ctx, id, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWork", reflect.TypeOf((*MockCollectionsRepository)(nil).RemoveWork), ctx, id, workID)
+}
+
+// Reorder mocks base method
+func (m *MockCollectionsRepository) Reorder(ctx context.Context, id uint64, workIDs []uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, id, workIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder
+func (mr *MockCollectionsRepositoryMockRecorder) Reorder(ctx, id, workIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCollectionsRepository)(nil).Reorder), ctx, id, workIDs)
+}
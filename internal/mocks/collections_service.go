@@ -0,0 +1,167 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/collections_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCollectionsService is a mock of CollectionsService interface
+type MockCollectionsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionsServiceMockRecorder
+}
+
+// MockCollectionsServiceMockRecorder is the mock recorder for MockCollectionsService
+type MockCollectionsServiceMockRecorder struct {
+	mock *MockCollectionsService
+}
+
+// NewMockCollectionsService creates a new mock instance
+func NewMockCollectionsService(ctrl *gomock.Controller) *MockCollectionsService {
+	mock := &MockCollectionsService{ctrl: ctrl}
+	mock.recorder = &MockCollectionsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCollectionsService) EXPECT() *MockCollectionsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockCollectionsService) GetAll(ctx context.Context, ownerID string, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, ownerID, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockCollectionsServiceMockRecorder) GetAll(ctx, ownerID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCollectionsService)(nil).GetAll), ctx, ownerID, offset, limit)
+}
+
+// FindByID mocks base method
+func (m *MockCollectionsService) FindByID(arg0 context.Context, arg1 uint64) (*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockCollectionsServiceMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCollectionsService)(nil).FindByID), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *MockCollectionsService) Create(arg0 context.Context, arg1 *beans.CollectionFormBean) (*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCollectionsServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCollectionsService)(nil).Create), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockCollectionsService) Update(arg0 context.Context, arg1 uint64, arg2 *beans.CollectionFormBean) (*entities.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entities.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCollectionsServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCollectionsService)(nil).Update), arg0, arg1, arg2)
+}
+
+// DeleteByID mocks base method
+func (m *MockCollectionsService) DeleteByID(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockCollectionsServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCollectionsService)(nil).DeleteByID), arg0, arg1)
+}
+
+// GetWorks mocks base method
+func (m *MockCollectionsService) GetWorks(ctx context.Context, id uint64, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorks", ctx, id, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorks indicates an expected call of GetWorks
+func (mr *MockCollectionsServiceMockRecorder) GetWorks(ctx, id, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorks", reflect.TypeOf((*MockCollectionsService)(nil).GetWorks), ctx, id, offset, limit)
+}
+
+// AddWork mocks base method
+func (m *MockCollectionsService) AddWork(ctx context.Context, id, workID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWork", ctx, id, workID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWork indicates an expected call of AddWork
+func (mr *MockCollectionsServiceMockRecorder) AddWork(ctx, id, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWork", reflect.TypeOf((*MockCollectionsService)(nil).AddWork), ctx, id, workID)
+}
+
+// RemoveWork mocks base method
+func (m *MockCollectionsService) RemoveWork(ctx context.Context, id, workID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWork", ctx, id, workID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWork indicates an expected call of RemoveWork
+func (mr *MockCollectionsServiceMockRecorder) RemoveWork(ctx, id, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWork", reflect.TypeOf((*MockCollectionsService)(nil).RemoveWork), ctx, id, workID)
+}
+
+// Reorder mocks base method
+func (m *MockCollectionsService) Reorder(ctx context.Context, id uint64, workIDs []uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, id, workIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder
+func (mr *MockCollectionsServiceMockRecorder) Reorder(ctx, id, workIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCollectionsService)(nil).Reorder), ctx, id, workIDs)
+}
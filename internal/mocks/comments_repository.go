@@ -0,0 +1,152 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/comments_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCommentsRepository is a mock of CommentsRepository interface
+type MockCommentsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentsRepositoryMockRecorder
+}
+
+// MockCommentsRepositoryMockRecorder is the mock recorder for MockCommentsRepository
+type MockCommentsRepositoryMockRecorder struct {
+	mock *MockCommentsRepository
+}
+
+// NewMockCommentsRepository creates a new mock instance
+func NewMockCommentsRepository(ctrl *gomock.Controller) *MockCommentsRepository {
+	mock := &MockCommentsRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommentsRepository) EXPECT() *MockCommentsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByWorkID mocks base method
+func (m *MockCommentsRepository) FindByWorkID(ctx context.Context, workID uint64, offset, limit int) ([]*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByWorkID", ctx, workID, offset, limit)
+	ret0, _ := ret[0].([]*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByWorkID indicates an expected call of FindByWorkID
+func (mr *MockCommentsRepositoryMockRecorder) FindByWorkID(ctx, workID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWorkID", reflect.TypeOf((*MockCommentsRepository)(nil).FindByWorkID), ctx, workID, offset, limit)
+}
+
+// CountByWorkID mocks base method
+func (m *MockCommentsRepository) CountByWorkID(ctx context.Context, workID uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByWorkID", ctx, workID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByWorkID indicates an expected call of CountByWorkID
+func (mr *MockCommentsRepositoryMockRecorder) CountByWorkID(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByWorkID", reflect.TypeOf((*MockCommentsRepository)(nil).CountByWorkID), ctx, workID)
+}
+
+// FindReplies mocks base method
+func (m *MockCommentsRepository) FindReplies(ctx context.Context, parentID uint64, offset, limit int) ([]*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReplies", ctx, parentID, offset, limit)
+	ret0, _ := ret[0].([]*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReplies indicates an expected call of FindReplies
+func (mr *MockCommentsRepositoryMockRecorder) FindReplies(ctx, parentID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReplies", reflect.TypeOf((*MockCommentsRepository)(nil).FindReplies), ctx, parentID, offset, limit)
+}
+
+// CountReplies mocks base method
+func (m *MockCommentsRepository) CountReplies(ctx context.Context, parentID uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReplies", ctx, parentID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReplies indicates an expected call of CountReplies
+func (mr *MockCommentsRepositoryMockRecorder) CountReplies(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReplies", reflect.TypeOf((*MockCommentsRepository)(nil).CountReplies), ctx, parentID)
+}
+
+// FindByID mocks base method
+func (m *MockCommentsRepository) FindByID(arg0 context.Context, arg1 uint64) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockCommentsRepositoryMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCommentsRepository)(nil).FindByID), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *MockCommentsRepository) Create(arg0 context.Context, arg1 *entities.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCommentsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentsRepository)(nil).Create), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockCommentsRepository) Update(arg0 context.Context, arg1 *entities.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCommentsRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentsRepository)(nil).Update), arg0, arg1)
+}
+
+// DeleteByID mocks base method
+func (m *MockCommentsRepository) DeleteByID(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockCommentsRepositoryMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentsRepository)(nil).DeleteByID), arg0, arg1)
+}
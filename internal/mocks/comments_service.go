@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/comments_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCommentsService is a mock of CommentsService interface
+type MockCommentsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentsServiceMockRecorder
+}
+
+// MockCommentsServiceMockRecorder is the mock recorder for MockCommentsService
+type MockCommentsServiceMockRecorder struct {
+	mock *MockCommentsService
+}
+
+// NewMockCommentsService creates a new mock instance
+func NewMockCommentsService(ctrl *gomock.Controller) *MockCommentsService {
+	mock := &MockCommentsService{ctrl: ctrl}
+	mock.recorder = &MockCommentsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommentsService) EXPECT() *MockCommentsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockCommentsService) GetAll(ctx context.Context, workID uint64, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, workID, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockCommentsServiceMockRecorder) GetAll(ctx, workID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCommentsService)(nil).GetAll), ctx, workID, offset, limit)
+}
+
+// GetReplies mocks base method
+func (m *MockCommentsService) GetReplies(ctx context.Context, id uint64, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplies", ctx, id, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplies indicates an expected call of GetReplies
+func (mr *MockCommentsServiceMockRecorder) GetReplies(ctx, id, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplies", reflect.TypeOf((*MockCommentsService)(nil).GetReplies), ctx, id, offset, limit)
+}
+
+// Create mocks base method
+func (m *MockCommentsService) Create(ctx context.Context, workID uint64, bean *beans.CommentFormBean) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, workID, bean)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCommentsServiceMockRecorder) Create(ctx, workID, bean interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentsService)(nil).Create), ctx, workID, bean)
+}
+
+// Update mocks base method
+func (m *MockCommentsService) Update(ctx context.Context, id uint64, bean *beans.CommentFormBean) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, bean)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCommentsServiceMockRecorder) Update(ctx, id, bean interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentsService)(nil).Update), ctx, id, bean)
+}
+
+// DeleteByID mocks base method
+func (m *MockCommentsService) DeleteByID(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockCommentsServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentsService)(nil).DeleteByID), arg0, arg1)
+}
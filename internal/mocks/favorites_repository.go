@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/favorites_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockFavoritesRepository is a mock of FavoritesRepository interface
+type MockFavoritesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFavoritesRepositoryMockRecorder
+}
+
+// MockFavoritesRepositoryMockRecorder is the mock recorder for MockFavoritesRepository
+type MockFavoritesRepositoryMockRecorder struct {
+	mock *MockFavoritesRepository
+}
+
+// NewMockFavoritesRepository creates a new mock instance
+func NewMockFavoritesRepository(ctrl *gomock.Controller) *MockFavoritesRepository {
+	mock := &MockFavoritesRepository{ctrl: ctrl}
+	mock.recorder = &MockFavoritesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFavoritesRepository) EXPECT() *MockFavoritesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method
+func (m *MockFavoritesRepository) Add(ctx context.Context, userID string, workID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, userID, workID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add
+func (mr *MockFavoritesRepositoryMockRecorder) Add(ctx, userID, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFavoritesRepository)(nil).Add), ctx, userID, workID)
+}
+
+// Remove mocks base method
+func (m *MockFavoritesRepository) Remove(ctx context.Context, userID string, workID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, userID, workID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove
+func (mr *MockFavoritesRepositoryMockRecorder) Remove(ctx, userID, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFavoritesRepository)(nil).Remove), ctx, userID, workID)
+}
+
+// FindWorks mocks base method
+func (m *MockFavoritesRepository) FindWorks(ctx context.Context, viewer, userID string, offset, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWorks", ctx, viewer, userID, offset, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWorks indicates an expected call of FindWorks
+func (mr *MockFavoritesRepositoryMockRecorder) FindWorks(ctx, viewer, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWorks", reflect.TypeOf((*MockFavoritesRepository)(nil).FindWorks), ctx, viewer, userID, offset, limit)
+}
+
+// CountWorks mocks base method
+func (m *MockFavoritesRepository) CountWorks(ctx context.Context, viewer, userID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorks", ctx, viewer, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorks indicates an expected call of CountWorks
+func (mr *MockFavoritesRepositoryMockRecorder) CountWorks(ctx, viewer, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorks", reflect.TypeOf((*MockFavoritesRepository)(nil).CountWorks), ctx, viewer, userID)
+}
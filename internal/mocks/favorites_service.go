@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/favorites_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockFavoritesService is a mock of FavoritesService interface
+type MockFavoritesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFavoritesServiceMockRecorder
+}
+
+// MockFavoritesServiceMockRecorder is the mock recorder for MockFavoritesService
+type MockFavoritesServiceMockRecorder struct {
+	mock *MockFavoritesService
+}
+
+// NewMockFavoritesService creates a new mock instance
+func NewMockFavoritesService(ctrl *gomock.Controller) *MockFavoritesService {
+	mock := &MockFavoritesService{ctrl: ctrl}
+	mock.recorder = &MockFavoritesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFavoritesService) EXPECT() *MockFavoritesServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockFavoritesService) GetAll(ctx context.Context, userID string, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockFavoritesServiceMockRecorder) GetAll(ctx, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFavoritesService)(nil).GetAll), ctx, userID, offset, limit)
+}
+
+// Add mocks base method
+func (m *MockFavoritesService) Add(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add
+func (mr *MockFavoritesServiceMockRecorder) Add(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFavoritesService)(nil).Add), arg0, arg1)
+}
+
+// Remove mocks base method
+func (m *MockFavoritesService) Remove(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove
+func (mr *MockFavoritesServiceMockRecorder) Remove(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFavoritesService)(nil).Remove), arg0, arg1)
+}
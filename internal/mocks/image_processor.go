@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/lib/image_processor.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	lib "github.com/edy4c7/works-uploader/internal/lib"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// MockImageProcessor is a mock of ImageProcessor interface
+type MockImageProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageProcessorMockRecorder
+}
+
+// MockImageProcessorMockRecorder is the mock recorder for MockImageProcessor
+type MockImageProcessorMockRecorder struct {
+	mock *MockImageProcessor
+}
+
+// NewMockImageProcessor creates a new mock instance
+func NewMockImageProcessor(ctrl *gomock.Controller) *MockImageProcessor {
+	mock := &MockImageProcessor{ctrl: ctrl}
+	mock.recorder = &MockImageProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockImageProcessor) EXPECT() *MockImageProcessorMockRecorder {
+	return m.recorder
+}
+
+// Resize mocks base method
+func (m *MockImageProcessor) Resize(arg0 io.Reader, arg1 []int) ([]*lib.ImageVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resize", arg0, arg1)
+	ret0, _ := ret[0].([]*lib.ImageVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resize indicates an expected call of Resize
+func (mr *MockImageProcessorMockRecorder) Resize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockImageProcessor)(nil).Resize), arg0, arg1)
+}
+
+// StripMetadata mocks base method
+func (m *MockImageProcessor) StripMetadata(arg0 io.Reader, arg1 string) (io.ReadCloser, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StripMetadata", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// StripMetadata indicates an expected call of StripMetadata
+func (mr *MockImageProcessorMockRecorder) StripMetadata(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StripMetadata", reflect.TypeOf((*MockImageProcessor)(nil).StripMetadata), arg0, arg1)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/integrity_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockIntegrityService is a mock of IntegrityService interface
+type MockIntegrityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIntegrityServiceMockRecorder
+}
+
+// MockIntegrityServiceMockRecorder is the mock recorder for MockIntegrityService
+type MockIntegrityServiceMockRecorder struct {
+	mock *MockIntegrityService
+}
+
+// NewMockIntegrityService creates a new mock instance
+func NewMockIntegrityService(ctrl *gomock.Controller) *MockIntegrityService {
+	mock := &MockIntegrityService{ctrl: ctrl}
+	mock.recorder = &MockIntegrityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIntegrityService) EXPECT() *MockIntegrityServiceMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method
+func (m *MockIntegrityService) Verify(ctx context.Context, report func(*beans.VerificationFailureBean)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, report)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockIntegrityServiceMockRecorder) Verify(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockIntegrityService)(nil).Verify), ctx, report)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/link_check_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockLinkCheckService is a mock of LinkCheckService interface
+type MockLinkCheckService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkCheckServiceMockRecorder
+}
+
+// MockLinkCheckServiceMockRecorder is the mock recorder for MockLinkCheckService
+type MockLinkCheckServiceMockRecorder struct {
+	mock *MockLinkCheckService
+}
+
+// NewMockLinkCheckService creates a new mock instance
+func NewMockLinkCheckService(ctrl *gomock.Controller) *MockLinkCheckService {
+	mock := &MockLinkCheckService{ctrl: ctrl}
+	mock.recorder = &MockLinkCheckServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkCheckService) EXPECT() *MockLinkCheckServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckLinks mocks base method
+func (m *MockLinkCheckService) CheckLinks(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLinks", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLinks indicates an expected call of CheckLinks
+func (mr *MockLinkCheckServiceMockRecorder) CheckLinks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLinks", reflect.TypeOf((*MockLinkCheckService)(nil).CheckLinks), arg0)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/lib/link_checker.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockLinkChecker is a mock of LinkChecker interface
+type MockLinkChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkCheckerMockRecorder
+}
+
+// MockLinkCheckerMockRecorder is the mock recorder for MockLinkChecker
+type MockLinkCheckerMockRecorder struct {
+	mock *MockLinkChecker
+}
+
+// NewMockLinkChecker creates a new mock instance
+func NewMockLinkChecker(ctrl *gomock.Controller) *MockLinkChecker {
+	mock := &MockLinkChecker{ctrl: ctrl}
+	mock.recorder = &MockLinkCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkChecker) EXPECT() *MockLinkCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method
+func (m *MockLinkChecker) Check(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check
+func (mr *MockLinkCheckerMockRecorder) Check(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLinkChecker)(nil).Check), arg0, arg1)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/lib/link_unfurler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	lib "github.com/edy4c7/works-uploader/internal/lib"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// MockLinkUnfurler is a mock of LinkUnfurler interface
+type MockLinkUnfurler struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkUnfurlerMockRecorder
+}
+
+// MockLinkUnfurlerMockRecorder is the mock recorder for MockLinkUnfurler
+type MockLinkUnfurlerMockRecorder struct {
+	mock *MockLinkUnfurler
+}
+
+// NewMockLinkUnfurler creates a new mock instance
+func NewMockLinkUnfurler(ctrl *gomock.Controller) *MockLinkUnfurler {
+	mock := &MockLinkUnfurler{ctrl: ctrl}
+	mock.recorder = &MockLinkUnfurlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkUnfurler) EXPECT() *MockLinkUnfurlerMockRecorder {
+	return m.recorder
+}
+
+// Unfurl mocks base method
+func (m *MockLinkUnfurler) Unfurl(arg0 context.Context, arg1 string) (*lib.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfurl", arg0, arg1)
+	ret0, _ := ret[0].(*lib.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfurl indicates an expected call of Unfurl
+func (mr *MockLinkUnfurlerMockRecorder) Unfurl(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfurl", reflect.TypeOf((*MockLinkUnfurler)(nil).Unfurl), arg0, arg1)
+}
+
+// Fetch mocks base method
+func (m *MockLinkUnfurler) Fetch(arg0 context.Context, arg1 string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch
+func (mr *MockLinkUnfurlerMockRecorder) Fetch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockLinkUnfurler)(nil).Fetch), arg0, arg1)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/moderation_actions_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockModerationActionsRepository is a mock of ModerationActionsRepository interface
+type MockModerationActionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationActionsRepositoryMockRecorder
+}
+
+// MockModerationActionsRepositoryMockRecorder is the mock recorder for MockModerationActionsRepository
+type MockModerationActionsRepositoryMockRecorder struct {
+	mock *MockModerationActionsRepository
+}
+
+// NewMockModerationActionsRepository creates a new mock instance
+func NewMockModerationActionsRepository(ctrl *gomock.Controller) *MockModerationActionsRepository {
+	mock := &MockModerationActionsRepository{ctrl: ctrl}
+	mock.recorder = &MockModerationActionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockModerationActionsRepository) EXPECT() *MockModerationActionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockModerationActionsRepository) GetAll(ctx context.Context, offset, limit int) ([]*entities.ModerationAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, offset, limit)
+	ret0, _ := ret[0].([]*entities.ModerationAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockModerationActionsRepositoryMockRecorder) GetAll(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockModerationActionsRepository)(nil).GetAll), ctx, offset, limit)
+}
+
+// CountAll mocks base method
+func (m *MockModerationActionsRepository) CountAll(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAll", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAll indicates an expected call of CountAll
+func (mr *MockModerationActionsRepositoryMockRecorder) CountAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAll", reflect.TypeOf((*MockModerationActionsRepository)(nil).CountAll), arg0)
+}
+
+// Create mocks base method
+func (m *MockModerationActionsRepository) Create(arg0 context.Context, arg1 *entities.ModerationAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockModerationActionsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockModerationActionsRepository)(nil).Create), arg0, arg1)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/moderation_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockModerationService is a mock of ModerationService interface
+type MockModerationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationServiceMockRecorder
+}
+
+// MockModerationServiceMockRecorder is the mock recorder for MockModerationService
+type MockModerationServiceMockRecorder struct {
+	mock *MockModerationService
+}
+
+// NewMockModerationService creates a new mock instance
+func NewMockModerationService(ctrl *gomock.Controller) *MockModerationService {
+	mock := &MockModerationService{ctrl: ctrl}
+	mock.recorder = &MockModerationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockModerationService) EXPECT() *MockModerationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReports mocks base method
+func (m *MockModerationService) GetReports(ctx context.Context, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", ctx, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports
+func (mr *MockModerationServiceMockRecorder) GetReports(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockModerationService)(nil).GetReports), ctx, offset, limit)
+}
+
+// GetActions mocks base method
+func (m *MockModerationService) GetActions(ctx context.Context, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActions", ctx, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActions indicates an expected call of GetActions
+func (mr *MockModerationServiceMockRecorder) GetActions(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActions", reflect.TypeOf((*MockModerationService)(nil).GetActions), ctx, offset, limit)
+}
+
+// ModerateWork mocks base method
+func (m *MockModerationService) ModerateWork(ctx context.Context, workID uint64, bean *beans.ModerationFormBean) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateWork", ctx, workID, bean)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateWork indicates an expected call of ModerateWork
+func (mr *MockModerationServiceMockRecorder) ModerateWork(ctx, workID, bean interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateWork", reflect.TypeOf((*MockModerationService)(nil).ModerateWork), ctx, workID, bean)
+}
+
+// ModerateUser mocks base method
+func (m *MockModerationService) ModerateUser(ctx context.Context, userID string, bean *beans.ModerationFormBean) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateUser", ctx, userID, bean)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateUser indicates an expected call of ModerateUser
+func (mr *MockModerationServiceMockRecorder) ModerateUser(ctx, userID, bean interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateUser", reflect.TypeOf((*MockModerationService)(nil).ModerateUser), ctx, userID, bean)
+}
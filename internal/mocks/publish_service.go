@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/publish_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockPublishService is a mock of PublishService interface
+type MockPublishService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublishServiceMockRecorder
+}
+
+// MockPublishServiceMockRecorder is the mock recorder for MockPublishService
+type MockPublishServiceMockRecorder struct {
+	mock *MockPublishService
+}
+
+// NewMockPublishService creates a new mock instance
+func NewMockPublishService(ctrl *gomock.Controller) *MockPublishService {
+	mock := &MockPublishService{ctrl: ctrl}
+	mock.recorder = &MockPublishServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPublishService) EXPECT() *MockPublishServiceMockRecorder {
+	return m.recorder
+}
+
+// PublishDue mocks base method
+func (m *MockPublishService) PublishDue(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue
+func (mr *MockPublishServiceMockRecorder) PublishDue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockPublishService)(nil).PublishDue), arg0)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/reports_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	constants "github.com/edy4c7/works-uploader/internal/common/constants"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockReportsRepository is a mock of ReportsRepository interface
+type MockReportsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportsRepositoryMockRecorder
+}
+
+// MockReportsRepositoryMockRecorder is the mock recorder for MockReportsRepository
+type MockReportsRepositoryMockRecorder struct {
+	mock *MockReportsRepository
+}
+
+// NewMockReportsRepository creates a new mock instance
+func NewMockReportsRepository(ctrl *gomock.Controller) *MockReportsRepository {
+	mock := &MockReportsRepository{ctrl: ctrl}
+	mock.recorder = &MockReportsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReportsRepository) EXPECT() *MockReportsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockReportsRepository) Create(arg0 context.Context, arg1 *entities.Report) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockReportsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReportsRepository)(nil).Create), arg0, arg1)
+}
+
+// CountOpenByWorkID mocks base method
+func (m *MockReportsRepository) CountOpenByWorkID(arg0 context.Context, arg1 uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenByWorkID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenByWorkID indicates an expected call of CountOpenByWorkID
+func (mr *MockReportsRepositoryMockRecorder) CountOpenByWorkID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenByWorkID", reflect.TypeOf((*MockReportsRepository)(nil).CountOpenByWorkID), arg0, arg1)
+}
+
+// FindOpen mocks base method
+func (m *MockReportsRepository) FindOpen(ctx context.Context, offset, limit int) ([]*entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOpen", ctx, offset, limit)
+	ret0, _ := ret[0].([]*entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOpen indicates an expected call of FindOpen
+func (mr *MockReportsRepositoryMockRecorder) FindOpen(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOpen", reflect.TypeOf((*MockReportsRepository)(nil).FindOpen), ctx, offset, limit)
+}
+
+// CountOpen mocks base method
+func (m *MockReportsRepository) CountOpen(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpen", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpen indicates an expected call of CountOpen
+func (mr *MockReportsRepositoryMockRecorder) CountOpen(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpen", reflect.TypeOf((*MockReportsRepository)(nil).CountOpen), arg0)
+}
+
+// ResolveByWorkID mocks base method
+func (m *MockReportsRepository) ResolveByWorkID(ctx context.Context, workID uint64, status constants.ReportStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveByWorkID", ctx, workID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveByWorkID indicates an expected call of ResolveByWorkID
+func (mr *MockReportsRepositoryMockRecorder) ResolveByWorkID(ctx, workID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveByWorkID", reflect.TypeOf((*MockReportsRepository)(nil).ResolveByWorkID), ctx, workID, status)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/reports_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockReportsService is a mock of ReportsService interface
+type MockReportsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportsServiceMockRecorder
+}
+
+// MockReportsServiceMockRecorder is the mock recorder for MockReportsService
+type MockReportsServiceMockRecorder struct {
+	mock *MockReportsService
+}
+
+// NewMockReportsService creates a new mock instance
+func NewMockReportsService(ctrl *gomock.Controller) *MockReportsService {
+	mock := &MockReportsService{ctrl: ctrl}
+	mock.recorder = &MockReportsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReportsService) EXPECT() *MockReportsServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockReportsService) Create(ctx context.Context, workID uint64, bean *beans.ReportFormBean) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, workID, bean)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockReportsServiceMockRecorder) Create(ctx, workID, bean interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReportsService)(nil).Create), ctx, workID, bean)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/revisions_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockRevisionsService is a mock of RevisionsService interface
+type MockRevisionsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionsServiceMockRecorder
+}
+
+// MockRevisionsServiceMockRecorder is the mock recorder for MockRevisionsService
+type MockRevisionsServiceMockRecorder struct {
+	mock *MockRevisionsService
+}
+
+// NewMockRevisionsService creates a new mock instance
+func NewMockRevisionsService(ctrl *gomock.Controller) *MockRevisionsService {
+	mock := &MockRevisionsService{ctrl: ctrl}
+	mock.recorder = &MockRevisionsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRevisionsService) EXPECT() *MockRevisionsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockRevisionsService) GetAll(ctx context.Context, workID uint64, offset, limit int) (*beans.PaginationBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, workID, offset, limit)
+	ret0, _ := ret[0].(*beans.PaginationBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockRevisionsServiceMockRecorder) GetAll(ctx, workID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRevisionsService)(nil).GetAll), ctx, workID, offset, limit)
+}
+
+// FindByVersion mocks base method
+func (m *MockRevisionsService) FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, workID, version)
+	ret0, _ := ret[0].(*entities.WorkRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion
+func (mr *MockRevisionsServiceMockRecorder) FindByVersion(ctx, workID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockRevisionsService)(nil).FindByVersion), ctx, workID, version)
+}
+
+// Diff mocks base method
+func (m *MockRevisionsService) Diff(ctx context.Context, workID uint64, from, to uint) (*beans.RevisionDiffBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, workID, from, to)
+	ret0, _ := ret[0].(*beans.RevisionDiffBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff
+func (mr *MockRevisionsServiceMockRecorder) Diff(ctx, workID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockRevisionsService)(nil).Diff), ctx, workID, from, to)
+}
+
+// Restore mocks base method
+func (m *MockRevisionsService) Restore(ctx context.Context, workID uint64, version uint) (*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, workID, version)
+	ret0, _ := ret[0].(*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockRevisionsServiceMockRecorder) Restore(ctx, workID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRevisionsService)(nil).Restore), ctx, workID, version)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/scan_results_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockScanResultsRepository is a mock of ScanResultsRepository interface
+type MockScanResultsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScanResultsRepositoryMockRecorder
+}
+
+// MockScanResultsRepositoryMockRecorder is the mock recorder for MockScanResultsRepository
+type MockScanResultsRepositoryMockRecorder struct {
+	mock *MockScanResultsRepository
+}
+
+// NewMockScanResultsRepository creates a new mock instance
+func NewMockScanResultsRepository(ctrl *gomock.Controller) *MockScanResultsRepository {
+	mock := &MockScanResultsRepository{ctrl: ctrl}
+	mock.recorder = &MockScanResultsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScanResultsRepository) EXPECT() *MockScanResultsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockScanResultsRepository) Create(arg0 context.Context, arg1 *entities.ScanResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockScanResultsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScanResultsRepository)(nil).Create), arg0, arg1)
+}
+
+// FindByWorkID mocks base method
+func (m *MockScanResultsRepository) FindByWorkID(arg0 context.Context, arg1 uint64) ([]*entities.ScanResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByWorkID", arg0, arg1)
+	ret0, _ := ret[0].([]*entities.ScanResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByWorkID indicates an expected call of FindByWorkID
+func (mr *MockScanResultsRepositoryMockRecorder) FindByWorkID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWorkID", reflect.TypeOf((*MockScanResultsRepository)(nil).FindByWorkID), arg0, arg1)
+}
@@ -5,9 +5,12 @@
 package mocks
 
 import (
+	lib "github.com/edy4c7/works-uploader/internal/lib"
 	gomock "github.com/golang/mock/gomock"
-	multipart "mime/multipart"
+	io "io"
+	http "net/http"
 	reflect "reflect"
+	time "time"
 )
 
 // MockStorageClient is a mock of StorageClient interface
@@ -34,16 +37,236 @@ func (m *MockStorageClient) EXPECT() *MockStorageClientMockRecorder {
 }
 
 // Upload mocks base method
-func (m *MockStorageClient) Upload(arg0 string, arg1 *multipart.FileHeader) (string, error) {
+func (m *MockStorageClient) Upload(arg0 string, arg1 io.Reader, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", arg0, arg1)
+	ret := m.ctrl.Call(m, "Upload", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upload indicates an expected call of Upload
+func (mr *MockStorageClientMockRecorder) Upload(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStorageClient)(nil).Upload), arg0, arg1, arg2, arg3)
+}
+
+// PresignUpload mocks base method
+func (m *MockStorageClient) PresignUpload(arg0, arg1 string, arg2 *lib.ObjectInfo, arg3 time.Duration) (*lib.PresignedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignUpload", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*lib.PresignedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignUpload indicates an expected call of PresignUpload
+func (mr *MockStorageClientMockRecorder) PresignUpload(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignUpload", reflect.TypeOf((*MockStorageClient)(nil).PresignUpload), arg0, arg1, arg2, arg3)
+}
+
+// Stat mocks base method
+func (m *MockStorageClient) Stat(arg0 string) (*lib.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0)
+	ret0, _ := ret[0].(*lib.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat
+func (mr *MockStorageClientMockRecorder) Stat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockStorageClient)(nil).Stat), arg0)
+}
+
+// Open mocks base method
+func (m *MockStorageClient) Open(arg0 string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", arg0)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open
+func (mr *MockStorageClientMockRecorder) Open(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockStorageClient)(nil).Open), arg0)
+}
+
+// Promote mocks base method
+func (m *MockStorageClient) Promote(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Promote", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Promote indicates an expected call of Promote
+func (mr *MockStorageClientMockRecorder) Promote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockStorageClient)(nil).Promote), arg0, arg1)
+}
+
+// PromotePrivate mocks base method
+func (m *MockStorageClient) PromotePrivate(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromotePrivate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PromotePrivate indicates an expected call of PromotePrivate
+func (mr *MockStorageClientMockRecorder) PromotePrivate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromotePrivate", reflect.TypeOf((*MockStorageClient)(nil).PromotePrivate), arg0, arg1)
+}
+
+// OpenPublished mocks base method
+func (m *MockStorageClient) OpenPublished(arg0 string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenPublished", arg0)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenPublished indicates an expected call of OpenPublished
+func (mr *MockStorageClientMockRecorder) OpenPublished(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenPublished", reflect.TypeOf((*MockStorageClient)(nil).OpenPublished), arg0)
+}
+
+// Quarantine mocks base method
+func (m *MockStorageClient) Quarantine(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quarantine", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Quarantine indicates an expected call of Quarantine
+func (mr *MockStorageClientMockRecorder) Quarantine(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quarantine", reflect.TypeOf((*MockStorageClient)(nil).Quarantine), arg0)
+}
+
+// Delete mocks base method
+func (m *MockStorageClient) Delete(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockStorageClientMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorageClient)(nil).Delete), arg0)
+}
+
+// URL mocks base method
+func (m *MockStorageClient) URL(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL
+func (mr *MockStorageClientMockRecorder) URL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockStorageClient)(nil).URL), arg0)
+}
+
+// SignURL mocks base method
+func (m *MockStorageClient) SignURL(arg0 string, arg1 time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignURL", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Upload indicates an expected call of Upload
-func (mr *MockStorageClientMockRecorder) Upload(arg0, arg1 interface{}) *gomock.Call {
+// SignURL indicates an expected call of SignURL
+func (mr *MockStorageClientMockRecorder) SignURL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignURL", reflect.TypeOf((*MockStorageClient)(nil).SignURL), arg0, arg1)
+}
+
+// MockSignedUploadReceiver is a mock of SignedUploadReceiver interface
+type MockSignedUploadReceiver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignedUploadReceiverMockRecorder
+}
+
+// MockSignedUploadReceiverMockRecorder is the mock recorder for MockSignedUploadReceiver
+type MockSignedUploadReceiverMockRecorder struct {
+	mock *MockSignedUploadReceiver
+}
+
+// NewMockSignedUploadReceiver creates a new mock instance
+func NewMockSignedUploadReceiver(ctrl *gomock.Controller) *MockSignedUploadReceiver {
+	mock := &MockSignedUploadReceiver{ctrl: ctrl}
+	mock.recorder = &MockSignedUploadReceiverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSignedUploadReceiver) EXPECT() *MockSignedUploadReceiverMockRecorder {
+	return m.recorder
+}
+
+// VerifyUpload mocks base method
+func (m *MockSignedUploadReceiver) VerifyUpload(key string, r *http.Request) (*lib.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUpload", key, r)
+	ret0, _ := ret[0].(*lib.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUpload indicates an expected call of VerifyUpload
+func (mr *MockSignedUploadReceiverMockRecorder) VerifyUpload(key, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUpload", reflect.TypeOf((*MockSignedUploadReceiver)(nil).VerifyUpload), key, r)
+}
+
+// MockSignedDownloadReceiver is a mock of SignedDownloadReceiver interface
+type MockSignedDownloadReceiver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignedDownloadReceiverMockRecorder
+}
+
+// MockSignedDownloadReceiverMockRecorder is the mock recorder for MockSignedDownloadReceiver
+type MockSignedDownloadReceiverMockRecorder struct {
+	mock *MockSignedDownloadReceiver
+}
+
+// NewMockSignedDownloadReceiver creates a new mock instance
+func NewMockSignedDownloadReceiver(ctrl *gomock.Controller) *MockSignedDownloadReceiver {
+	mock := &MockSignedDownloadReceiver{ctrl: ctrl}
+	mock.recorder = &MockSignedDownloadReceiverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSignedDownloadReceiver) EXPECT() *MockSignedDownloadReceiverMockRecorder {
+	return m.recorder
+}
+
+// VerifyDownload mocks base method
+func (m *MockSignedDownloadReceiver) VerifyDownload(key string, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyDownload", key, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyDownload indicates an expected call of VerifyDownload
+func (mr *MockSignedDownloadReceiverMockRecorder) VerifyDownload(key, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStorageClient)(nil).Upload), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyDownload", reflect.TypeOf((*MockSignedDownloadReceiver)(nil).VerifyDownload), key, r)
 }
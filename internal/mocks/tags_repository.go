@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/tags_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTagsRepository is a mock of TagsRepository interface
+type MockTagsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsRepositoryMockRecorder
+}
+
+// MockTagsRepositoryMockRecorder is the mock recorder for MockTagsRepository
+type MockTagsRepositoryMockRecorder struct {
+	mock *MockTagsRepository
+}
+
+// NewMockTagsRepository creates a new mock instance
+func NewMockTagsRepository(ctrl *gomock.Controller) *MockTagsRepository {
+	mock := &MockTagsRepository{ctrl: ctrl}
+	mock.recorder = &MockTagsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTagsRepository) EXPECT() *MockTagsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method
+func (m *MockTagsRepository) Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, prefix, limit)
+	ret0, _ := ret[0].([]*entities.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockTagsRepositoryMockRecorder) Search(ctx, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTagsRepository)(nil).Search), ctx, prefix, limit)
+}
+
+// ReplaceWorkTags mocks base method
+func (m *MockTagsRepository) ReplaceWorkTags(ctx context.Context, workID uint64, names []string) ([]*entities.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceWorkTags", ctx, workID, names)
+	ret0, _ := ret[0].([]*entities.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceWorkTags indicates an expected call of ReplaceWorkTags
+func (mr *MockTagsRepositoryMockRecorder) ReplaceWorkTags(ctx, workID, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceWorkTags", reflect.TypeOf((*MockTagsRepository)(nil).ReplaceWorkTags), ctx, workID, names)
+}
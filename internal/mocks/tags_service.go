@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/tags_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTagsService is a mock of TagsService interface
+type MockTagsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsServiceMockRecorder
+}
+
+// MockTagsServiceMockRecorder is the mock recorder for MockTagsService
+type MockTagsServiceMockRecorder struct {
+	mock *MockTagsService
+}
+
+// NewMockTagsService creates a new mock instance
+func NewMockTagsService(ctrl *gomock.Controller) *MockTagsService {
+	mock := &MockTagsService{ctrl: ctrl}
+	mock.recorder = &MockTagsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTagsService) EXPECT() *MockTagsServiceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method
+func (m *MockTagsService) Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, prefix, limit)
+	ret0, _ := ret[0].([]*entities.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockTagsServiceMockRecorder) Search(ctx, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTagsService)(nil).Search), ctx, prefix, limit)
+}
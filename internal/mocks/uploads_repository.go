@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/uploads_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockUploadsRepository is a mock of UploadsRepository interface
+type MockUploadsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadsRepositoryMockRecorder
+}
+
+// MockUploadsRepositoryMockRecorder is the mock recorder for MockUploadsRepository
+type MockUploadsRepositoryMockRecorder struct {
+	mock *MockUploadsRepository
+}
+
+// NewMockUploadsRepository creates a new mock instance
+func NewMockUploadsRepository(ctrl *gomock.Controller) *MockUploadsRepository {
+	mock := &MockUploadsRepository{ctrl: ctrl}
+	mock.recorder = &MockUploadsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUploadsRepository) EXPECT() *MockUploadsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByID mocks base method
+func (m *MockUploadsRepository) FindByID(arg0 context.Context, arg1 string) (*entities.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockUploadsRepositoryMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUploadsRepository)(nil).FindByID), arg0, arg1)
+}
+
+// FindExpired mocks base method
+func (m *MockUploadsRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]*entities.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired
+func (mr *MockUploadsRepositoryMockRecorder) FindExpired(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockUploadsRepository)(nil).FindExpired), ctx, now, limit)
+}
+
+// Create mocks base method
+func (m *MockUploadsRepository) Create(arg0 context.Context, arg1 *entities.Upload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockUploadsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUploadsRepository)(nil).Create), arg0, arg1)
+}
+
+// UpdateProgress mocks base method
+func (m *MockUploadsRepository) UpdateProgress(ctx context.Context, upload *entities.Upload, expectedChunks int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", ctx, upload, expectedChunks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress
+func (mr *MockUploadsRepositoryMockRecorder) UpdateProgress(ctx, upload, expectedChunks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockUploadsRepository)(nil).UpdateProgress), ctx, upload, expectedChunks)
+}
+
+// FindChunks mocks base method
+func (m *MockUploadsRepository) FindChunks(ctx context.Context, uploadID string) ([]*entities.UploadChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChunks", ctx, uploadID)
+	ret0, _ := ret[0].([]*entities.UploadChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChunks indicates an expected call of FindChunks
+func (mr *MockUploadsRepositoryMockRecorder) FindChunks(ctx, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChunks", reflect.TypeOf((*MockUploadsRepository)(nil).FindChunks), ctx, uploadID)
+}
+
+// CreateChunk mocks base method
+func (m *MockUploadsRepository) CreateChunk(arg0 context.Context, arg1 *entities.UploadChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChunk", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChunk indicates an expected call of CreateChunk
+func (mr *MockUploadsRepositoryMockRecorder) CreateChunk(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChunk", reflect.TypeOf((*MockUploadsRepository)(nil).CreateChunk), arg0, arg1)
+}
+
+// DeleteByID mocks base method
+func (m *MockUploadsRepository) DeleteByID(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockUploadsRepositoryMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockUploadsRepository)(nil).DeleteByID), arg0, arg1)
+}
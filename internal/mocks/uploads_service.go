@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/uploads_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	beans "github.com/edy4c7/works-uploader/internal/beans"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// MockUploadsService is a mock of UploadsService interface
+type MockUploadsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadsServiceMockRecorder
+}
+
+// MockUploadsServiceMockRecorder is the mock recorder for MockUploadsService
+type MockUploadsServiceMockRecorder struct {
+	mock *MockUploadsService
+}
+
+// NewMockUploadsService creates a new mock instance
+func NewMockUploadsService(ctrl *gomock.Controller) *MockUploadsService {
+	mock := &MockUploadsService{ctrl: ctrl}
+	mock.recorder = &MockUploadsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUploadsService) EXPECT() *MockUploadsServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockUploadsService) Create(ctx context.Context, length int64, filename string) (*entities.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, length, filename)
+	ret0, _ := ret[0].(*entities.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockUploadsServiceMockRecorder) Create(ctx, length, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUploadsService)(nil).Create), ctx, length, filename)
+}
+
+// CreateSession mocks base method
+func (m *MockUploadsService) CreateSession(arg0 context.Context, arg1 *beans.UploadSessionFormBean) (*beans.UploadSessionBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(*beans.UploadSessionBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession
+func (mr *MockUploadsServiceMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockUploadsService)(nil).CreateSession), arg0, arg1)
+}
+
+// FindByID mocks base method
+func (m *MockUploadsService) FindByID(arg0 context.Context, arg1 string) (*entities.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockUploadsServiceMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUploadsService)(nil).FindByID), arg0, arg1)
+}
+
+// Append mocks base method
+func (m *MockUploadsService) Append(ctx context.Context, id string, offset int64, body io.Reader) (*entities.Upload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, id, offset, body)
+	ret0, _ := ret[0].(*entities.Upload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append
+func (mr *MockUploadsServiceMockRecorder) Append(ctx, id, offset, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockUploadsService)(nil).Append), ctx, id, offset, body)
+}
+
+// DeleteByID mocks base method
+func (m *MockUploadsService) DeleteByID(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID
+func (mr *MockUploadsServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockUploadsService)(nil).DeleteByID), arg0, arg1)
+}
+
+// PurgeExpired mocks base method
+func (m *MockUploadsService) PurgeExpired(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired
+func (mr *MockUploadsServiceMockRecorder) PurgeExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockUploadsService)(nil).PurgeExpired), arg0)
+}
@@ -9,6 +9,7 @@ import (
 	entities "github.com/edy4c7/works-uploader/internal/entities"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockUsersRepository is a mock of UsersRepository interface
@@ -47,3 +48,32 @@ func (mr *MockUsersRepositoryMockRecorder) Save(arg0, arg1 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUsersRepository)(nil).Save), arg0, arg1)
 }
+
+// FindByID mocks base method
+func (m *MockUsersRepository) FindByID(arg0 context.Context, arg1 string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockUsersRepositoryMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUsersRepository)(nil).FindByID), arg0, arg1)
+}
+
+// UpdateSuspended mocks base method
+func (m *MockUsersRepository) UpdateSuspended(ctx context.Context, id string, suspendedAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSuspended", ctx, id, suspendedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSuspended indicates an expected call of UpdateSuspended
+func (mr *MockUsersRepositoryMockRecorder) UpdateSuspended(ctx, id, suspendedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSuspended", reflect.TypeOf((*MockUsersRepository)(nil).UpdateSuspended), ctx, id, suspendedAt)
+}
@@ -47,3 +47,18 @@ func (mr *MockUsersServiceMockRecorder) Save(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUsersService)(nil).Save), arg0, arg1)
 }
+
+// IsSuspended mocks base method
+func (m *MockUsersService) IsSuspended(arg0 context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSuspended", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSuspended indicates an expected call of IsSuspended
+func (mr *MockUsersServiceMockRecorder) IsSuspended(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSuspended", reflect.TypeOf((*MockUsersService)(nil).IsSuspended), arg0)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/work_revisions_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	entities "github.com/edy4c7/works-uploader/internal/entities"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockWorkRevisionsRepository is a mock of WorkRevisionsRepository interface
+type MockWorkRevisionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkRevisionsRepositoryMockRecorder
+}
+
+// MockWorkRevisionsRepositoryMockRecorder is the mock recorder for MockWorkRevisionsRepository
+type MockWorkRevisionsRepositoryMockRecorder struct {
+	mock *MockWorkRevisionsRepository
+}
+
+// NewMockWorkRevisionsRepository creates a new mock instance
+func NewMockWorkRevisionsRepository(ctrl *gomock.Controller) *MockWorkRevisionsRepository {
+	mock := &MockWorkRevisionsRepository{ctrl: ctrl}
+	mock.recorder = &MockWorkRevisionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWorkRevisionsRepository) EXPECT() *MockWorkRevisionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockWorkRevisionsRepository) Create(arg0 context.Context, arg1 *entities.WorkRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockWorkRevisionsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorkRevisionsRepository)(nil).Create), arg0, arg1)
+}
+
+// FindByWorkID mocks base method
+func (m *MockWorkRevisionsRepository) FindByWorkID(ctx context.Context, workID uint64, offset, limit int) ([]*entities.WorkRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByWorkID", ctx, workID, offset, limit)
+	ret0, _ := ret[0].([]*entities.WorkRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByWorkID indicates an expected call of FindByWorkID
+func (mr *MockWorkRevisionsRepositoryMockRecorder) FindByWorkID(ctx, workID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWorkID", reflect.TypeOf((*MockWorkRevisionsRepository)(nil).FindByWorkID), ctx, workID, offset, limit)
+}
+
+// CountByWorkID mocks base method
+func (m *MockWorkRevisionsRepository) CountByWorkID(ctx context.Context, workID uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByWorkID", ctx, workID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByWorkID indicates an expected call of CountByWorkID
+func (mr *MockWorkRevisionsRepositoryMockRecorder) CountByWorkID(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByWorkID", reflect.TypeOf((*MockWorkRevisionsRepository)(nil).CountByWorkID), ctx, workID)
+}
+
+// FindByVersion mocks base method
+func (m *MockWorkRevisionsRepository) FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, workID, version)
+	ret0, _ := ret[0].(*entities.WorkRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion
+func (mr *MockWorkRevisionsRepositoryMockRecorder) FindByVersion(ctx, workID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockWorkRevisionsRepository)(nil).FindByVersion), ctx, workID, version)
+}
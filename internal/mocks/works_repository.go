@@ -6,9 +6,11 @@ package mocks
 
 import (
 	context "context"
+	constants "github.com/edy4c7/works-uploader/internal/common/constants"
 	entities "github.com/edy4c7/works-uploader/internal/entities"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockWorksRepository is a mock of WorksRepository interface
@@ -35,33 +37,33 @@ func (m *MockWorksRepository) EXPECT() *MockWorksRepositoryMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockWorksRepository) GetAll(ctx context.Context, offset, limit int) ([]*entities.Work, error) {
+func (m *MockWorksRepository) GetAll(ctx context.Context, viewer string, tags []string, order constants.WorkOrder, offset, limit int) ([]*entities.Work, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "GetAll", ctx, viewer, tags, order, offset, limit)
 	ret0, _ := ret[0].([]*entities.Work)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockWorksRepositoryMockRecorder) GetAll(ctx, offset, limit interface{}) *gomock.Call {
+func (mr *MockWorksRepositoryMockRecorder) GetAll(ctx, viewer, tags, order, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorksRepository)(nil).GetAll), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorksRepository)(nil).GetAll), ctx, viewer, tags, order, offset, limit)
 }
 
 // CountAll mocks base method
-func (m *MockWorksRepository) CountAll(arg0 context.Context) (int64, error) {
+func (m *MockWorksRepository) CountAll(ctx context.Context, viewer string, tags []string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountAll", arg0)
+	ret := m.ctrl.Call(m, "CountAll", ctx, viewer, tags)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountAll indicates an expected call of CountAll
-func (mr *MockWorksRepositoryMockRecorder) CountAll(arg0 interface{}) *gomock.Call {
+func (mr *MockWorksRepositoryMockRecorder) CountAll(ctx, viewer, tags interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAll", reflect.TypeOf((*MockWorksRepository)(nil).CountAll), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAll", reflect.TypeOf((*MockWorksRepository)(nil).CountAll), ctx, viewer, tags)
 }
 
 // FindByID mocks base method
@@ -93,6 +95,120 @@ func (mr *MockWorksRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorksRepository)(nil).Create), arg0, arg1)
 }
 
+// Update mocks base method
+func (m *MockWorksRepository) Update(ctx context.Context, work *entities.Work) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, work)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockWorksRepositoryMockRecorder) Update(ctx, work interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorksRepository)(nil).Update), ctx, work)
+}
+
+// UpdateScanStatus mocks base method
+func (m *MockWorksRepository) UpdateScanStatus(arg0 context.Context, arg1 uint64, arg2 constants.ScanStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScanStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScanStatus indicates an expected call of UpdateScanStatus
+func (mr *MockWorksRepositoryMockRecorder) UpdateScanStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScanStatus", reflect.TypeOf((*MockWorksRepository)(nil).UpdateScanStatus), arg0, arg1, arg2)
+}
+
+// UpdateFavoriteCount mocks base method
+func (m *MockWorksRepository) UpdateFavoriteCount(ctx context.Context, id uint64, delta int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFavoriteCount", ctx, id, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFavoriteCount indicates an expected call of UpdateFavoriteCount
+func (mr *MockWorksRepositoryMockRecorder) UpdateFavoriteCount(ctx, id, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFavoriteCount", reflect.TypeOf((*MockWorksRepository)(nil).UpdateFavoriteCount), ctx, id, delta)
+}
+
+// UpdateHidden mocks base method
+func (m *MockWorksRepository) UpdateHidden(ctx context.Context, id uint64, hiddenAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHidden", ctx, id, hiddenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHidden indicates an expected call of UpdateHidden
+func (mr *MockWorksRepositoryMockRecorder) UpdateHidden(ctx, id, hiddenAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHidden", reflect.TypeOf((*MockWorksRepository)(nil).UpdateHidden), ctx, id, hiddenAt)
+}
+
+// FindLinksToCheck mocks base method
+func (m *MockWorksRepository) FindLinksToCheck(ctx context.Context, checkedBefore time.Time, after uint64, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinksToCheck", ctx, checkedBefore, after, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinksToCheck indicates an expected call of FindLinksToCheck
+func (mr *MockWorksRepositoryMockRecorder) FindLinksToCheck(ctx, checkedBefore, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinksToCheck", reflect.TypeOf((*MockWorksRepository)(nil).FindLinksToCheck), ctx, checkedBefore, after, limit)
+}
+
+// UpdateLinkStatus mocks base method
+func (m *MockWorksRepository) UpdateLinkStatus(arg0 context.Context, arg1 *entities.Work) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkStatus indicates an expected call of UpdateLinkStatus
+func (mr *MockWorksRepositoryMockRecorder) UpdateLinkStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkStatus", reflect.TypeOf((*MockWorksRepository)(nil).UpdateLinkStatus), arg0, arg1)
+}
+
+// FindDueToPublish mocks base method
+func (m *MockWorksRepository) FindDueToPublish(ctx context.Context, now time.Time, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueToPublish", ctx, now, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueToPublish indicates an expected call of FindDueToPublish
+func (mr *MockWorksRepositoryMockRecorder) FindDueToPublish(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueToPublish", reflect.TypeOf((*MockWorksRepository)(nil).FindDueToPublish), ctx, now, limit)
+}
+
+// Publish mocks base method
+func (m *MockWorksRepository) Publish(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockWorksRepositoryMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockWorksRepository)(nil).Publish), arg0, arg1)
+}
+
 // DeleteByID mocks base method
 func (m *MockWorksRepository) DeleteByID(arg0 context.Context, arg1 uint64) error {
 	m.ctrl.T.Helper()
@@ -106,3 +222,91 @@ func (mr *MockWorksRepositoryMockRecorder) DeleteByID(arg0, arg1 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockWorksRepository)(nil).DeleteByID), arg0, arg1)
 }
+
+// PurgeByID mocks base method
+func (m *MockWorksRepository) PurgeByID(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeByID indicates an expected call of PurgeByID
+func (mr *MockWorksRepositoryMockRecorder) PurgeByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeByID", reflect.TypeOf((*MockWorksRepository)(nil).PurgeByID), arg0, arg1)
+}
+
+// FindTrashed mocks base method
+func (m *MockWorksRepository) FindTrashed(ctx context.Context, authorID string, offset, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrashed", ctx, authorID, offset, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrashed indicates an expected call of FindTrashed
+func (mr *MockWorksRepositoryMockRecorder) FindTrashed(ctx, authorID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrashed", reflect.TypeOf((*MockWorksRepository)(nil).FindTrashed), ctx, authorID, offset, limit)
+}
+
+// CountTrashed mocks base method
+func (m *MockWorksRepository) CountTrashed(ctx context.Context, authorID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTrashed", ctx, authorID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTrashed indicates an expected call of CountTrashed
+func (mr *MockWorksRepositoryMockRecorder) CountTrashed(ctx, authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTrashed", reflect.TypeOf((*MockWorksRepository)(nil).CountTrashed), ctx, authorID)
+}
+
+// FindTrashedByID mocks base method
+func (m *MockWorksRepository) FindTrashedByID(arg0 context.Context, arg1 uint64) (*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrashedByID", arg0, arg1)
+	ret0, _ := ret[0].(*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrashedByID indicates an expected call of FindTrashedByID
+func (mr *MockWorksRepositoryMockRecorder) FindTrashedByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrashedByID", reflect.TypeOf((*MockWorksRepository)(nil).FindTrashedByID), arg0, arg1)
+}
+
+// FindDeletedBefore mocks base method
+func (m *MockWorksRepository) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedBefore", ctx, before, limit)
+	ret0, _ := ret[0].([]*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedBefore indicates an expected call of FindDeletedBefore
+func (mr *MockWorksRepositoryMockRecorder) FindDeletedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedBefore", reflect.TypeOf((*MockWorksRepository)(nil).FindDeletedBefore), ctx, before, limit)
+}
+
+// Restore mocks base method
+func (m *MockWorksRepository) Restore(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockWorksRepositoryMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockWorksRepository)(nil).Restore), arg0, arg1)
+}
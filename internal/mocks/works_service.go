@@ -9,6 +9,7 @@ import (
 	beans "github.com/edy4c7/works-uploader/internal/beans"
 	entities "github.com/edy4c7/works-uploader/internal/entities"
 	gomock "github.com/golang/mock/gomock"
+	io "io"
 	reflect "reflect"
 )
 
@@ -36,18 +37,18 @@ func (m *MockWorksService) EXPECT() *MockWorksServiceMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockWorksService) GetAll(ctx context.Context, offset, limit int) (*beans.PaginationBean, error) {
+func (m *MockWorksService) GetAll(ctx context.Context, tags []string, sort string, offset, limit int) (*beans.PaginationBean, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "GetAll", ctx, tags, sort, offset, limit)
 	ret0, _ := ret[0].(*beans.PaginationBean)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockWorksServiceMockRecorder) GetAll(ctx, offset, limit interface{}) *gomock.Call {
+func (mr *MockWorksServiceMockRecorder) GetAll(ctx, tags, sort, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorksService)(nil).GetAll), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorksService)(nil).GetAll), ctx, tags, sort, offset, limit)
 }
 
 // FindByID mocks base method
@@ -65,6 +66,53 @@ func (mr *MockWorksServiceMockRecorder) FindByID(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockWorksService)(nil).FindByID), arg0, arg1)
 }
 
+// Stage mocks base method
+func (m *MockWorksService) Stage(arg0 context.Context, arg1, arg2 string, arg3 io.Reader) (*beans.StagedFileBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stage", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*beans.StagedFileBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stage indicates an expected call of Stage
+func (mr *MockWorksServiceMockRecorder) Stage(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stage", reflect.TypeOf((*MockWorksService)(nil).Stage), arg0, arg1, arg2, arg3)
+}
+
+// StageUpload mocks base method
+func (m *MockWorksService) StageUpload(arg0 context.Context, arg1, arg2 string) (*beans.StagedFileBean, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StageUpload", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*beans.StagedFileBean)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StageUpload indicates an expected call of StageUpload
+func (mr *MockWorksServiceMockRecorder) StageUpload(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageUpload", reflect.TypeOf((*MockWorksService)(nil).StageUpload), arg0, arg1, arg2)
+}
+
+// Discard mocks base method
+func (m *MockWorksService) Discard(arg0 context.Context, arg1 ...*beans.StagedFileBean) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Discard", varargs...)
+}
+
+// Discard indicates an expected call of Discard
+func (mr *MockWorksServiceMockRecorder) Discard(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discard", reflect.TypeOf((*MockWorksService)(nil).Discard), varargs...)
+}
+
 // Create mocks base method
 func (m *MockWorksService) Create(arg0 context.Context, arg1 *beans.WorksFormBean) (*entities.Work, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +128,21 @@ func (mr *MockWorksServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorksService)(nil).Create), arg0, arg1)
 }
 
+// Update mocks base method
+func (m *MockWorksService) Update(arg0 context.Context, arg1 uint64, arg2 *beans.WorkUpdateFormBean) (*entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockWorksServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorksService)(nil).Update), arg0, arg1, arg2)
+}
+
 // DeleteByID mocks base method
 func (m *MockWorksService) DeleteByID(arg0 context.Context, arg1 uint64) error {
 	m.ctrl.T.Helper()
@@ -3,11 +3,16 @@ package repositories
 import (
 	"context"
 
+	"github.com/edy4c7/works-uploader/internal/common/constants"
 	"github.com/edy4c7/works-uploader/internal/entities"
 )
 
 type ActivitiesRepository interface {
-	GetAll(context.Context, int) ([]*entities.Activity, error)
-	FindByUserID(context.Context, string, int) ([]*entities.Activity, error)
+	// GetAll, FindByUserID は、公開済みかつ公開の作品と、viewerが作者の作品のアクティビティを新しい順に取得する。
+	// コレクションに作品を追加したアクティビティは、コレクションも公開かviewerが所有するもののみ取得する。
+	GetAll(ctx context.Context, viewer string, limit int) ([]*entities.Activity, error)
+	FindByUserID(ctx context.Context, viewer string, userID string, limit int) ([]*entities.Activity, error)
 	Create(context.Context, *entities.Activity) error
+	// DeleteByType は、ユーザーが作品について登録した、指定した種別のアクティビティを削除する。ない場合は何もしない。
+	DeleteByType(ctx context.Context, actType constants.ActivityType, userID string, workID uint64) error
 }
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+type BlobsRepository interface {
+	// Acquire は、作品が参照するファイルを登録する。同じキーが登録済みの場合は参照数を増やす。
+	// 作品が既に参照しているファイルの場合は参照数を変えない。戻り値は登録後の参照数。
+	Acquire(ctx context.Context, workID uint64, blob *entities.Blob) (int, error)
+	// Release は、作品の参照を外して参照数を減らし、0になった場合は登録を削除する。戻り値は残りの参照数。
+	// 作品が参照していないファイルの場合は、参照数を変えずに返す (登録されていない場合は0)。
+	Release(ctx context.Context, workID uint64, key string) (int, error)
+	// FindKeysByWorkID は、作品が参照しているファイルのキーを昇順で取得する
+	FindKeysByWorkID(ctx context.Context, workID uint64) ([]string, error)
+	// FindAfter は、キーの昇順でafterより後のファイルを最大limit件取得する
+	FindAfter(ctx context.Context, after string, limit int) ([]*entities.Blob, error)
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// CollectionsRepository は、作品のコレクションと、コレクションに含まれる作品の永続化を表す
+type CollectionsRepository interface {
+	// GetAll, CountAll は、viewerの一覧に表示するコレクションを、更新日時の新しい順に対象にする。
+	// 公開のコレクションと、viewerが所有するコレクションを含む。ownerIDを指定した場合は、その所有者のものに絞り込む。
+	GetAll(ctx context.Context, viewer string, ownerID string, offset int, limit int) ([]*entities.Collection, error)
+	CountAll(ctx context.Context, viewer string, ownerID string) (int64, error)
+	// FindByID は、公開範囲に関わらずコレクションを取得する。表紙は、マルウェアの検査を通過した削除されていない作品のみ読み込む。
+	// ない場合はRecordNotFoundErrorを返す。
+	FindByID(context.Context, uint64) (*entities.Collection, error)
+	// FindByWorkID は、作品を含むコレクションのうち、viewerの一覧に表示するものを取得する。表紙は読み込まない。
+	FindByWorkID(ctx context.Context, viewer string, workID uint64) ([]*entities.Collection, error)
+	Create(context.Context, *entities.Collection) error
+	// Update は、コレクションのタイトル、説明、公開範囲と表紙を更新する。ない場合はRecordNotFoundErrorを返す。
+	Update(context.Context, *entities.Collection) error
+	// DeleteByID は、コレクションを物理削除する。含まれる作品の記録と、作品を追加したアクティビティも削除する。
+	// 作品は削除しない。ない場合はRecordNotFoundErrorを返す。
+	DeleteByID(context.Context, uint64) error
+	// FindWorks, CountWorks は、コレクションに含まれる作品のうち、viewerが一覧で閲覧できるものを並び順に対象にする
+	FindWorks(ctx context.Context, viewer string, id uint64, offset int, limit int) ([]*entities.Work, error)
+	CountWorks(ctx context.Context, viewer string, id uint64) (int64, error)
+	// ContainsWork は、コレクションに作品が含まれるかを返す。削除された作品も含む。
+	ContainsWork(ctx context.Context, id uint64, workID uint64) (bool, error)
+	// AddWork は、作品をコレクションの末尾に追加する。追加済みの場合は何もせずにfalseを返す。
+	AddWork(ctx context.Context, id uint64, workID uint64) (bool, error)
+	// RemoveWork は、作品をコレクションから取り除く。含まれていない場合はRecordNotFoundErrorを返す。
+	RemoveWork(ctx context.Context, id uint64, workID uint64) error
+	// Reorder は、workIDsの作品をその順にコレクションの先頭に並べ、指定されなかった作品は現在の順でその後に並べる。
+	// コレクションに含まれない作品を指定した場合はRecordNotFoundErrorを返す。
+	Reorder(ctx context.Context, id uint64, workIDs []uint64) error
+}
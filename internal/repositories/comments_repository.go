@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// CommentsRepository は、作品へのコメントの永続化を表す。
+// 作品を閲覧できるかは、呼び出し側で確認する。
+type CommentsRepository interface {
+	// FindByWorkID, CountByWorkID は、作品へのコメントのうち返信でないものを、投稿した日時の古い順に対象にする。返信の数を読み込む。
+	FindByWorkID(ctx context.Context, workID uint64, offset int, limit int) ([]*entities.Comment, error)
+	CountByWorkID(ctx context.Context, workID uint64) (int64, error)
+	// FindReplies, CountReplies は、コメントへの返信を、投稿した日時の古い順に対象にする
+	FindReplies(ctx context.Context, parentID uint64, offset int, limit int) ([]*entities.Comment, error)
+	CountReplies(ctx context.Context, parentID uint64) (int64, error)
+	// FindByID は、コメントを返信の数とともに取得する。ない場合はRecordNotFoundErrorを返す。
+	FindByID(context.Context, uint64) (*entities.Comment, error)
+	Create(context.Context, *entities.Comment) error
+	// Update は、コメントの本文を更新する。ない場合はRecordNotFoundErrorを返す。
+	Update(context.Context, *entities.Comment) error
+	// DeleteByID は、コメントを物理削除する。返信と、投稿したアクティビティも削除する。ない場合はRecordNotFoundErrorを返す。
+	DeleteByID(context.Context, uint64) error
+}
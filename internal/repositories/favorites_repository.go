@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// FavoritesRepository は、ユーザーが作品をお気に入りに登録したことの永続化を表す。
+// 作品のお気に入りの数は、WorksRepositoryのUpdateFavoriteCountで同じトランザクション内で更新する。
+type FavoritesRepository interface {
+	// Add は、作品をお気に入りに登録する。登録済みの場合は何もせずにfalseを返す。
+	Add(ctx context.Context, userID string, workID uint64) (bool, error)
+	// Remove は、作品のお気に入りを解除する。登録していない場合は何もせずにfalseを返す。
+	Remove(ctx context.Context, userID string, workID uint64) (bool, error)
+	// FindWorks, CountWorks は、ユーザーがお気に入りに登録した作品のうち、viewerが一覧で閲覧できるものを、登録した日時の新しい順に対象にする
+	FindWorks(ctx context.Context, viewer string, userID string, offset int, limit int) ([]*entities.Work, error)
+	CountWorks(ctx context.Context, viewer string, userID string) (int64, error)
+}
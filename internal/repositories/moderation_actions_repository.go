@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// ModerationActionsRepository は、モデレーターの操作の記録の永続化を表す。記録は追加のみ行う。
+type ModerationActionsRepository interface {
+	// GetAll, CountAll は、操作の記録を、操作した日時の新しい順に対象にする。
+	// GetAll は、モデレーターと対象のユーザー、削除済みのものを含めて対象の作品を読み込む。
+	GetAll(ctx context.Context, offset int, limit int) ([]*entities.ModerationAction, error)
+	CountAll(context.Context) (int64, error)
+	Create(context.Context, *entities.ModerationAction) error
+}
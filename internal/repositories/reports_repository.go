@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// ReportsRepository は、作品の通報の永続化を表す
+type ReportsRepository interface {
+	// Create は、通報を登録する。同じユーザーが同じ作品を通報済みの場合は登録せず、falseを返す。
+	Create(context.Context, *entities.Report) (bool, error)
+	// CountOpenByWorkID は、作品への未対応の通報の数を取得する
+	CountOpenByWorkID(context.Context, uint64) (int64, error)
+	// FindOpen, CountOpen は、未対応の通報を、通報した日時の古い順に対象にする。
+	// FindOpen は、通報したユーザーと、削除済みのものを含めて作品を読み込む。
+	FindOpen(ctx context.Context, offset int, limit int) ([]*entities.Report, error)
+	CountOpen(context.Context) (int64, error)
+	// ResolveByWorkID は、作品への未対応の通報を、対応した日時とともにstatusにする
+	ResolveByWorkID(ctx context.Context, workID uint64, status constants.ReportStatus) error
+}
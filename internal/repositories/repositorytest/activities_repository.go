@@ -0,0 +1,200 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunActivitiesRepositoryTests は、ActivitiesRepositoryの契約テストを実行する
+func RunActivitiesRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("GetAll returns the latest activities", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		user := f.user("user")
+		work := f.work(user, "hoge")
+		base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		oldest := f.activity(user, work, base)
+		middle := f.activity(user, work, base.Add(time.Hour))
+		latest := f.activity(user, work, base.Add(2*time.Hour))
+
+		acts, err := h.Activities.GetAll(context.Background(), "", 2)
+
+		assert.Nil(t, err)
+		if assert.Len(t, acts, 2) {
+			assert.Equal(t, latest.ID, acts[0].ID)
+			assert.Equal(t, middle.ID, acts[1].ID)
+			assert.NotEqual(t, oldest.ID, acts[1].ID)
+			if assert.NotNil(t, acts[0].User) {
+				assert.Equal(t, user.ID, acts[0].User.ID)
+			}
+			if assert.NotNil(t, acts[0].Work) {
+				assert.Equal(t, work.ID, acts[0].Work.ID)
+				assert.Equal(t, work.Title, acts[0].Work.Title)
+			}
+		}
+	})
+
+	t.Run("FindByUserID filters by user", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		hoge := f.user("hoge")
+		fuga := f.user("fuga")
+		work := f.work(hoge, "hoge")
+		base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		expect := f.activity(hoge, work, base)
+		f.activity(fuga, work, base.Add(time.Hour))
+
+		acts, err := h.Activities.FindByUserID(context.Background(), "", hoge.ID, 10)
+
+		assert.Nil(t, err)
+		if assert.Len(t, acts, 1) {
+			assert.Equal(t, expect.ID, acts[0].ID)
+			assert.Equal(t, hoge.ID, acts[0].UserID)
+		}
+	})
+
+	t.Run("FindByUserID returns empty for unknown users", func(t *testing.T) {
+		h := setup(t)
+
+		acts, err := h.Activities.FindByUserID(context.Background(), "", "nobody", 10)
+
+		assert.Nil(t, err)
+		assert.Empty(t, acts)
+	})
+
+	t.Run("Activities of works not public are shown only to the author", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		public := f.work(author, "public")
+		private := &entities.Work{Title: "private", AuthorID: author.ID, Visibility: constants.VisibilityPrivate, ScanStatus: constants.ScanClean}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, private)
+		})
+		base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		shown := f.activity(author, public, base)
+		f.activity(author, private, base.Add(time.Hour))
+
+		acts, err := h.Activities.GetAll(context.Background(), "other", 10)
+		assert.Nil(t, err)
+		if assert.Len(t, acts, 1) {
+			assert.Equal(t, shown.ID, acts[0].ID)
+		}
+		acts, err = h.Activities.FindByUserID(context.Background(), "other", author.ID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, acts, 1)
+
+		acts, err = h.Activities.GetAll(context.Background(), author.ID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, acts, 2)
+	})
+
+	t.Run("Activities of hidden works are shown only to the author", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		hidden := f.work(author, "hidden")
+		hiddenAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.UpdateHidden(ctx, hidden.ID, &hiddenAt)
+		})
+		f.activity(author, hidden, hiddenAt)
+
+		acts, err := h.Activities.GetAll(context.Background(), "other", 10)
+		assert.Nil(t, err)
+		assert.Len(t, acts, 0)
+
+		acts, err = h.Activities.GetAll(context.Background(), author.ID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, acts, 1)
+	})
+
+	t.Run("Notifications are shown only to the recipient", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Activities.Create(ctx, &entities.Activity{
+				Type:   constants.ActivityLinkBroken,
+				UserID: author.ID,
+				Work:   work,
+			})
+		})
+
+		acts, err := h.Activities.GetAll(context.Background(), "other", 10)
+		assert.Nil(t, err)
+		assert.Empty(t, acts)
+
+		acts, err = h.Activities.GetAll(context.Background(), author.ID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, acts, 1)
+	})
+
+	t.Run("DeleteByType", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		user := f.user("user")
+		work := f.work(author, "hoge")
+		other := f.work(author, "fuga")
+		f.activity(user, work, time.Now())
+		for _, w := range []*entities.Work{work, other} {
+			w := w
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Activities.Create(ctx, &entities.Activity{
+					Type:   constants.ActivityFavorited,
+					UserID: user.ID,
+					Work:   w,
+				})
+			})
+		}
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Activities.DeleteByType(ctx, constants.ActivityFavorited, user.ID, work.ID)
+		})
+		assert.Nil(t, err)
+
+		acts, err := h.Activities.FindByUserID(context.Background(), "", user.ID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, acts, 2) {
+			for _, a := range acts {
+				assert.False(t, a.Type == constants.ActivityFavorited && a.WorkID == work.ID)
+			}
+		}
+
+		// ない場合は何もしない
+		err = h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Activities.DeleteByType(ctx, constants.ActivityFavorited, user.ID, work.ID)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("DeleteByType requires a transaction", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.Activities.DeleteByType(context.Background(), constants.ActivityFavorited, "user", 1)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		user := f.user("user")
+		work := f.work(user, "hoge")
+
+		err := h.Activities.Create(context.Background(), &entities.Activity{
+			Type:   constants.ActivityAdded,
+			UserID: user.ID,
+			Work:   work,
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunBlobsRepositoryTests は、BlobsRepositoryの契約テストを実行する
+func RunBlobsRepositoryTests(t *testing.T, setup SetupFunc) {
+	newBlob := func(key string) *entities.Blob {
+		return &entities.Blob{
+			Key:    key,
+			SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			Size:   1024,
+		}
+	}
+
+	t.Run("Acquire and FindAfter", func(t *testing.T) {
+		h := setup(t)
+		ctx := context.Background()
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+
+		var counts []int
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			for _, ref := range []struct {
+				workID uint64
+				key    string
+			}{{w1.ID, "b.png"}, {w1.ID, "a.png"}, {w2.ID, "b.png"}, {w1.ID, "b.png"}} {
+				n, err := h.Blobs.Acquire(ctx, ref.workID, newBlob(ref.key))
+				if err != nil {
+					return err
+				}
+				counts = append(counts, n)
+			}
+			return nil
+		})
+
+		assert.Nil(t, err)
+		// 別の作品からの参照は参照数を増やし、同じ作品からの参照は増やさない
+		assert.Equal(t, []int{1, 1, 2, 2}, counts)
+
+		actual, err := h.Blobs.FindAfter(ctx, "", 10)
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, "a.png", actual[0].Key)
+			assert.Equal(t, "b.png", actual[1].Key)
+			assert.Equal(t, newBlob("").SHA256, actual[1].SHA256)
+			assert.Equal(t, int64(1024), actual[1].Size)
+			assert.Equal(t, 2, actual[1].RefCount)
+		}
+
+		next, err := h.Blobs.FindAfter(ctx, "a.png", 1)
+		assert.Nil(t, err)
+		if assert.Len(t, next, 1) {
+			assert.Equal(t, "b.png", next[0].Key)
+		}
+
+		keys, err := h.Blobs.FindKeysByWorkID(ctx, w1.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a.png", "b.png"}, keys)
+	})
+
+	t.Run("Acquire requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "w")
+
+		_, err := h.Blobs.Acquire(context.Background(), w.ID, newBlob("a.png"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		h := setup(t)
+		ctx := context.Background()
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			if _, err := h.Blobs.Acquire(ctx, w1.ID, newBlob("a.png")); err != nil {
+				return err
+			}
+			_, err := h.Blobs.Acquire(ctx, w2.ID, newBlob("a.png"))
+			return err
+		})
+		assert.Nil(t, err)
+
+		var counts []int
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			for _, workID := range []uint64{w1.ID, w1.ID, w2.ID} {
+				n, err := h.Blobs.Release(ctx, workID, "a.png")
+				if err != nil {
+					return err
+				}
+				counts = append(counts, n)
+			}
+			return nil
+		})
+
+		assert.Nil(t, err)
+		// 参照を外した作品から再度外しても、参照数は変わらない
+		assert.Equal(t, []int{1, 1, 0}, counts)
+		// 参照されなくなったものは削除する
+		actual, err := h.Blobs.FindAfter(ctx, "", 10)
+		assert.Nil(t, err)
+		assert.Empty(t, actual)
+		keys, err := h.Blobs.FindKeysByWorkID(ctx, w1.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("Release returns 0 when not registered", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "w")
+
+		var n int
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			var err error
+			n, err = h.Blobs.Release(ctx, w.ID, "nothing")
+			return err
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("References are deleted with the work", func(t *testing.T) {
+		h := setup(t)
+		ctx := context.Background()
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "w")
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			if _, err := h.Blobs.Acquire(ctx, w.ID, newBlob("a.png")); err != nil {
+				return err
+			}
+			return h.Works.PurgeByID(ctx, w.ID)
+		})
+		assert.Nil(t, err)
+
+		keys, err := h.Blobs.FindKeysByWorkID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+	})
+}
@@ -0,0 +1,315 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunCollectionsRepositoryTests は、CollectionsRepositoryの契約テストを実行する
+func RunCollectionsRepositoryTests(t *testing.T, setup SetupFunc) {
+	workIDs := func(works []*entities.Work) []uint64 {
+		var result []uint64
+		for _, v := range works {
+			result = append(result, v.ID)
+		}
+		return result
+	}
+
+	t.Run("Create and FindByID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		cover := f.work(owner, "cover")
+		c := f.collection(owner, "series", constants.VisibilityUnlisted, cover)
+
+		assert.NotZero(t, c.ID)
+
+		c.Title = "renamed"
+		c.CoverWorkID = &cover.ID
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Collections.Update(ctx, c)
+		})
+
+		actual, err := h.Collections.FindByID(context.Background(), c.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "renamed", actual.Title)
+		assert.Equal(t, c.Description, actual.Description)
+		assert.Equal(t, constants.VisibilityUnlisted, actual.Visibility)
+		if assert.NotNil(t, actual.Owner) {
+			assert.Equal(t, owner.ID, actual.Owner.ID)
+		}
+		if assert.NotNil(t, actual.Cover) {
+			assert.Equal(t, cover.ID, actual.Cover.ID)
+		}
+
+		// 削除した作品は表紙として読み込まない
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, cover.ID)
+		})
+		actual, err = h.Collections.FindByID(context.Background(), c.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, actual.Cover)
+	})
+
+	t.Run("FindByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		_, err := h.Collections.FindByID(context.Background(), 1)
+
+		var notFound *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Writes require a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		c := f.collection(owner, "series", constants.VisibilityPublic)
+		ctx := context.Background()
+
+		assert.Error(t, h.Collections.Create(ctx, &entities.Collection{OwnerID: owner.ID, Title: "hoge"}))
+		assert.Error(t, h.Collections.Update(ctx, c))
+		assert.Error(t, h.Collections.DeleteByID(ctx, c.ID))
+		_, err := h.Collections.AddWork(ctx, c.ID, f.work(owner, "hoge").ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("GetAll lists collections visible to the viewer", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		other := f.user("other")
+		ctx := context.Background()
+		public := f.collection(owner, "public", constants.VisibilityPublic)
+		unlisted := f.collection(owner, "unlisted", constants.VisibilityUnlisted)
+		private := f.collection(owner, "private", constants.VisibilityPrivate)
+		others := f.collection(other, "others", constants.VisibilityPublic)
+
+		ids := func(collections []*entities.Collection) []uint64 {
+			var result []uint64
+			for _, v := range collections {
+				result = append(result, v.ID)
+			}
+			return result
+		}
+
+		anonymous, err := h.Collections.GetAll(ctx, "", "", 0, 10)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []uint64{public.ID, others.ID}, ids(anonymous))
+		count, err := h.Collections.CountAll(ctx, "", "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 所有者の一覧には、限定公開と非公開のコレクションも表示する
+		mine, err := h.Collections.GetAll(ctx, owner.ID, owner.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []uint64{public.ID, unlisted.ID, private.ID}, ids(mine))
+		count, err = h.Collections.CountAll(ctx, owner.ID, owner.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		byOwner, err := h.Collections.GetAll(ctx, other.ID, owner.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{public.ID}, ids(byOwner))
+	})
+
+	t.Run("Works are ordered and filtered for the viewer", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		ctx := context.Background()
+		w1 := f.work(owner, "w1")
+		w2 := f.work(owner, "w2")
+		draft := &entities.Work{Title: "draft", AuthorID: owner.ID, Visibility: constants.VisibilityPublic,
+			Status: constants.WorkDraft, ScanStatus: constants.ScanClean}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, draft)
+		})
+		w3 := f.work(owner, "w3")
+		c := f.collection(owner, "series", constants.VisibilityPublic, w1, w2, draft, w3)
+
+		var added bool
+		f.inTransaction(func(ctx context.Context) error {
+			var err error
+			added, err = h.Collections.AddWork(ctx, c.ID, w1.ID)
+			return err
+		})
+		// 追加済みの作品は、並び順を変えない
+		assert.False(t, added)
+
+		works, err := h.Collections.FindWorks(ctx, "", c.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{w1.ID, w2.ID, w3.ID}, workIDs(works))
+		count, err := h.Collections.CountWorks(ctx, "", c.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		works, err = h.Collections.FindWorks(ctx, owner.ID, c.ID, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{w2.ID, draft.ID}, workIDs(works))
+		count, err = h.Collections.CountWorks(ctx, owner.ID, c.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), count)
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Collections.Reorder(ctx, c.ID, []uint64{w3.ID, w1.ID})
+		})
+
+		works, err = h.Collections.FindWorks(ctx, owner.ID, c.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{w3.ID, w1.ID, w2.ID, draft.ID}, workIDs(works))
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Collections.RemoveWork(ctx, c.ID, w1.ID)
+		})
+		contains, err := h.Collections.ContainsWork(ctx, c.ID, w1.ID)
+		assert.Nil(t, err)
+		assert.False(t, contains)
+		contains, err = h.Collections.ContainsWork(ctx, c.ID, w2.ID)
+		assert.Nil(t, err)
+		assert.True(t, contains)
+
+		// 追加した作品は末尾に並べる
+		f.inTransaction(func(ctx context.Context) error {
+			_, err := h.Collections.AddWork(ctx, c.ID, w1.ID)
+			return err
+		})
+		works, err = h.Collections.FindWorks(ctx, owner.ID, c.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{w3.ID, w2.ID, draft.ID, w1.ID}, workIDs(works))
+	})
+
+	t.Run("Reorder and RemoveWork return RecordNotFoundError for works not in the collection", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		w1 := f.work(owner, "w1")
+		other := f.work(owner, "other")
+		c := f.collection(owner, "series", constants.VisibilityPublic, w1)
+
+		var notFound *myErr.RecordNotFoundError
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Collections.Reorder(ctx, c.ID, []uint64{other.ID, w1.ID})
+		})
+		assert.True(t, errors.As(err, &notFound))
+
+		err = h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Collections.RemoveWork(ctx, c.ID, other.ID)
+		})
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("FindByWorkID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		w := f.work(owner, "hoge")
+		public := f.collection(owner, "public", constants.VisibilityPublic, w)
+		private := f.collection(owner, "private", constants.VisibilityPrivate, w)
+		f.collection(owner, "without", constants.VisibilityPublic)
+
+		anonymous, err := h.Collections.FindByWorkID(context.Background(), "", w.ID)
+		assert.Nil(t, err)
+		if assert.Len(t, anonymous, 1) {
+			assert.Equal(t, public.ID, anonymous[0].ID)
+		}
+
+		mine, err := h.Collections.FindByWorkID(context.Background(), owner.ID, w.ID)
+		assert.Nil(t, err)
+		if assert.Len(t, mine, 2) {
+			assert.Equal(t, public.ID, mine[0].ID)
+			assert.Equal(t, private.ID, mine[1].ID)
+		}
+	})
+
+	t.Run("Deleting removes memberships but keeps works", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		ctx := context.Background()
+		w := f.work(owner, "hoge")
+		c := f.collection(owner, "series", constants.VisibilityPublic, w)
+		cid := c.ID
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Activities.Create(ctx, &entities.Activity{
+				Type: constants.ActivityAddedToCollection, UserID: owner.ID, WorkID: w.ID, CollectionID: &cid,
+			})
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Collections.DeleteByID(ctx, c.ID)
+		})
+
+		_, err := h.Collections.FindByID(ctx, c.ID)
+		var notFound *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		_, err = h.Works.FindByID(ctx, w.ID)
+		assert.Nil(t, err)
+		acts, err := h.Activities.GetAll(ctx, owner.ID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, acts)
+
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Collections.DeleteByID(ctx, c.ID)
+		})
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Purging a work removes it from collections and the cover", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		ctx := context.Background()
+		w := f.work(owner, "hoge")
+		c := f.collection(owner, "series", constants.VisibilityPublic, w)
+		c.CoverWorkID = &w.ID
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Collections.Update(ctx, c)
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, w.ID)
+		})
+
+		actual, err := h.Collections.FindByID(ctx, c.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, actual.CoverWorkID)
+		count, err := h.Collections.CountWorks(ctx, owner.ID, c.ID)
+		assert.Nil(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Activities of private collections are shown only to the owner", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		ctx := context.Background()
+		w := f.work(owner, "hoge")
+		public := f.collection(owner, "public", constants.VisibilityPublic, w)
+		private := f.collection(owner, "private", constants.VisibilityPrivate, w)
+		for _, c := range []*entities.Collection{public, private} {
+			id := c.ID
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Activities.Create(ctx, &entities.Activity{
+					Type: constants.ActivityAddedToCollection, UserID: owner.ID, WorkID: w.ID, CollectionID: &id,
+				})
+			})
+		}
+
+		anonymous, err := h.Activities.GetAll(ctx, "", 10)
+		assert.Nil(t, err)
+		if assert.Len(t, anonymous, 1) && assert.NotNil(t, anonymous[0].Collection) {
+			assert.Equal(t, public.ID, anonymous[0].Collection.ID)
+		}
+
+		mine, err := h.Activities.FindByUserID(ctx, owner.ID, owner.ID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, mine, 2)
+	})
+}
@@ -0,0 +1,183 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	wuErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunCommentsRepositoryTests は、CommentsRepositoryの契約テストを実行する
+func RunCommentsRepositoryTests(t *testing.T, setup SetupFunc) {
+	commentIDs := func(comments []*entities.Comment) []uint64 {
+		var result []uint64
+		for _, v := range comments {
+			result = append(result, v.ID)
+		}
+		return result
+	}
+
+	t.Run("FindByWorkID returns top level comments with reply counts", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		user := f.user("user")
+		work := f.work(author, "hoge")
+		other := f.work(author, "fuga")
+		c1 := f.comment(user, work, nil, "c1")
+		c2 := f.comment(author, work, nil, "c2")
+		f.comment(author, work, c1, "r1")
+		f.comment(user, work, c1, "r2")
+		f.comment(user, other, nil, "other")
+		ctx := context.Background()
+
+		count, err := h.Comments.CountByWorkID(ctx, work.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		comments, err := h.Comments.FindByWorkID(ctx, work.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{c1.ID, c2.ID}, commentIDs(comments))
+		if assert.Len(t, comments, 2) {
+			assert.Equal(t, int64(2), comments[0].ReplyCount)
+			assert.Equal(t, int64(0), comments[1].ReplyCount)
+			if assert.NotNil(t, comments[0].Author) {
+				assert.Equal(t, user.ID, comments[0].Author.ID)
+			}
+		}
+
+		page, err := h.Comments.FindByWorkID(ctx, work.ID, 1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{c2.ID}, commentIDs(page))
+	})
+
+	t.Run("FindReplies and CountReplies", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		c1 := f.comment(author, work, nil, "c1")
+		c2 := f.comment(author, work, nil, "c2")
+		r1 := f.comment(author, work, c1, "r1")
+		r2 := f.comment(author, work, c1, "r2")
+		f.comment(author, work, c2, "r3")
+		ctx := context.Background()
+
+		count, err := h.Comments.CountReplies(ctx, c1.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		replies, err := h.Comments.FindReplies(ctx, c1.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{r1.ID, r2.ID}, commentIDs(replies))
+	})
+
+	t.Run("FindByID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		c := f.comment(author, work, nil, "c1")
+		f.comment(author, work, c, "r1")
+		ctx := context.Background()
+
+		actual, err := h.Comments.FindByID(ctx, c.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "c1", actual.Body)
+		assert.Equal(t, work.ID, actual.WorkID)
+		assert.Nil(t, actual.ParentID)
+		assert.Equal(t, int64(1), actual.ReplyCount)
+
+		_, err = h.Comments.FindByID(ctx, c.ID+100)
+		var notFound *wuErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		c := f.comment(author, f.work(author, "hoge"), nil, "c1")
+
+		c.Body = "updated"
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Comments.Update(ctx, c)
+		})
+
+		actual, err := h.Comments.FindByID(context.Background(), c.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "updated", actual.Body)
+
+		var notFound *wuErr.RecordNotFoundError
+		err = h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Comments.Update(ctx, &entities.Comment{ID: c.ID + 100, Body: "hoge"})
+		})
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("DeleteByID deletes replies and activities", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		c := f.comment(author, work, nil, "c1")
+		r := f.comment(author, work, c, "r1")
+		f.inTransaction(func(ctx context.Context) error {
+			for _, v := range []*entities.Comment{c, r} {
+				act := &entities.Activity{Type: constants.ActivityCommented, UserID: author.ID, WorkID: work.ID, CommentID: &v.ID}
+				if err := h.Activities.Create(ctx, act); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Comments.DeleteByID(ctx, c.ID)
+		})
+
+		var notFound *wuErr.RecordNotFoundError
+		_, err := h.Comments.FindByID(context.Background(), r.ID)
+		assert.True(t, errors.As(err, &notFound))
+		acts, err := h.Activities.FindByUserID(context.Background(), author.ID, author.ID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, acts)
+
+		err = h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Comments.DeleteByID(ctx, c.ID)
+		})
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Comments are deleted with the work", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		c := f.comment(author, work, nil, "c1")
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, work.ID)
+		})
+
+		_, err := h.Comments.FindByID(context.Background(), c.ID)
+		var notFound *wuErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Writes require a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		work := f.work(author, "hoge")
+		ctx := context.Background()
+
+		assert.Error(t, h.Comments.Create(ctx, &entities.Comment{WorkID: work.ID, AuthorID: author.ID, Body: "hoge"}))
+		assert.Error(t, h.Comments.Update(ctx, &entities.Comment{ID: 1, Body: "hoge"}))
+		assert.Error(t, h.Comments.DeleteByID(ctx, 1))
+	})
+}
@@ -0,0 +1,167 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunFavoritesRepositoryTests は、FavoritesRepositoryの契約テストを実行する
+func RunFavoritesRepositoryTests(t *testing.T, setup SetupFunc) {
+	workIDs := func(works []*entities.Work) []uint64 {
+		var result []uint64
+		for _, v := range works {
+			result = append(result, v.ID)
+		}
+		return result
+	}
+
+	t.Run("Add and Remove are idempotent", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		user := f.user("user")
+		work := f.work(f.user("author"), "hoge")
+
+		var results []bool
+		f.inTransaction(func(ctx context.Context) error {
+			for _, add := range []bool{true, true, false, false} {
+				var changed bool
+				var err error
+				if add {
+					changed, err = h.Favorites.Add(ctx, user.ID, work.ID)
+				} else {
+					changed, err = h.Favorites.Remove(ctx, user.ID, work.ID)
+				}
+				if err != nil {
+					return err
+				}
+				results = append(results, changed)
+			}
+			return nil
+		})
+
+		assert.Equal(t, []bool{true, false, true, false}, results)
+	})
+
+	t.Run("FindWorks and CountWorks", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		user := f.user("user")
+		ctx := context.Background()
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+		w3 := f.work(author, "w3")
+		f.work(author, "not favorited")
+		for _, w := range []*entities.Work{w2, w1, w3} {
+			w := w
+			f.inTransaction(func(ctx context.Context) error {
+				_, err := h.Favorites.Add(ctx, user.ID, w.ID)
+				return err
+			})
+		}
+		f.inTransaction(func(ctx context.Context) error {
+			_, err := h.Favorites.Add(ctx, author.ID, w1.ID)
+			return err
+		})
+
+		count, err := h.Favorites.CountWorks(ctx, "", user.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		works, err := h.Favorites.FindWorks(ctx, "", user.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []uint64{w1.ID, w2.ID, w3.ID}, workIDs(works))
+		for _, w := range works {
+			if assert.NotNil(t, w.Author) {
+				assert.Equal(t, author.ID, w.Author.ID)
+			}
+		}
+
+		page, err := h.Favorites.FindWorks(ctx, "", user.ID, 1, 1)
+		assert.Nil(t, err)
+		if assert.Len(t, page, 1) {
+			assert.Equal(t, works[1].ID, page[0].ID)
+		}
+	})
+
+	t.Run("Works not listed are hidden", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		user := f.user("user")
+		ctx := context.Background()
+		public := f.work(author, "public")
+		private := &entities.Work{
+			Title:      "private",
+			AuthorID:   author.ID,
+			Visibility: constants.VisibilityPrivate,
+			Status:     constants.WorkPublished,
+			ScanStatus: constants.ScanClean,
+		}
+		deleted := f.work(author, "deleted")
+		f.inTransaction(func(ctx context.Context) error {
+			if err := h.Works.Create(ctx, private); err != nil {
+				return err
+			}
+			for _, w := range []*entities.Work{public, private, deleted} {
+				if _, err := h.Favorites.Add(ctx, user.ID, w.ID); err != nil {
+					return err
+				}
+			}
+			return h.Works.DeleteByID(ctx, deleted.ID)
+		})
+
+		for _, viewer := range []string{"", user.ID} {
+			works, err := h.Favorites.FindWorks(ctx, viewer, user.ID, 0, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, []uint64{public.ID}, workIDs(works))
+			count, err := h.Favorites.CountWorks(ctx, viewer, user.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), count)
+		}
+
+		works, err := h.Favorites.FindWorks(ctx, author.ID, user.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []uint64{public.ID, private.ID}, workIDs(works))
+	})
+
+	t.Run("Favorites are deleted with the work", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		user := f.user("user")
+		work := f.work(f.user("author"), "hoge")
+		f.inTransaction(func(ctx context.Context) error {
+			_, err := h.Favorites.Add(ctx, user.ID, work.ID)
+			return err
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, work.ID)
+		})
+
+		var removed bool
+		f.inTransaction(func(ctx context.Context) error {
+			var err error
+			removed, err = h.Favorites.Remove(ctx, user.ID, work.ID)
+			return err
+		})
+		assert.False(t, removed)
+	})
+
+	t.Run("Add and Remove require a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		user := f.user("user")
+		work := f.work(user, "hoge")
+
+		_, err := h.Favorites.Add(context.Background(), user.ID, work.ID)
+		assert.Error(t, err)
+
+		_, err = h.Favorites.Remove(context.Background(), user.ID, work.ID)
+		assert.Error(t, err)
+	})
+}
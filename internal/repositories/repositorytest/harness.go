@@ -0,0 +1,196 @@
+// Package repositorytest は、repositoriesパッケージのインターフェースの実装が満たすべき振る舞いを
+// 実装に依存しない形で検証するテストを提供する。
+// 各実装は、空のスキーマを持つDBに接続したHarnessを返すSetupFuncを用意し、Run〜Tests関数を呼び出す。
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+// Harness は、同じDBを共有するリポジトリ群を表す
+type Harness struct {
+	TransactionRunner repositories.TransactionRunner
+	Works             repositories.WorksRepository
+	Activities        repositories.ActivitiesRepository
+	Users             repositories.UsersRepository
+	Uploads           repositories.UploadsRepository
+	Blobs             repositories.BlobsRepository
+	ScanResults       repositories.ScanResultsRepository
+	WorkRevisions     repositories.WorkRevisionsRepository
+	Tags              repositories.TagsRepository
+	Collections       repositories.CollectionsRepository
+	Favorites         repositories.FavoritesRepository
+	Comments          repositories.CommentsRepository
+	Reports           repositories.ReportsRepository
+	ModerationActions repositories.ModerationActionsRepository
+}
+
+// SetupFunc は、テスト毎に独立した空のDBに接続したHarnessを返す。
+// 後始末はt.Cleanupで登録すること。
+type SetupFunc func(t *testing.T) *Harness
+
+// RunAll は、全てのリポジトリの契約テストを実行する
+func RunAll(t *testing.T, setup SetupFunc) {
+	t.Run("TransactionRunner", func(t *testing.T) { RunTransactionRunnerTests(t, setup) })
+	t.Run("WorksRepository", func(t *testing.T) { RunWorksRepositoryTests(t, setup) })
+	t.Run("ActivitiesRepository", func(t *testing.T) { RunActivitiesRepositoryTests(t, setup) })
+	t.Run("UsersRepository", func(t *testing.T) { RunUsersRepositoryTests(t, setup) })
+	t.Run("UploadsRepository", func(t *testing.T) { RunUploadsRepositoryTests(t, setup) })
+	t.Run("BlobsRepository", func(t *testing.T) { RunBlobsRepositoryTests(t, setup) })
+	t.Run("ScanResultsRepository", func(t *testing.T) { RunScanResultsRepositoryTests(t, setup) })
+	t.Run("WorkRevisionsRepository", func(t *testing.T) { RunWorkRevisionsRepositoryTests(t, setup) })
+	t.Run("TagsRepository", func(t *testing.T) { RunTagsRepositoryTests(t, setup) })
+	t.Run("CollectionsRepository", func(t *testing.T) { RunCollectionsRepositoryTests(t, setup) })
+	t.Run("FavoritesRepository", func(t *testing.T) { RunFavoritesRepositoryTests(t, setup) })
+	t.Run("CommentsRepository", func(t *testing.T) { RunCommentsRepositoryTests(t, setup) })
+	t.Run("ReportsRepository", func(t *testing.T) { RunReportsRepositoryTests(t, setup) })
+	t.Run("ModerationActionsRepository", func(t *testing.T) { RunModerationActionsRepositoryTests(t, setup) })
+}
+
+// fixtures は、テストで使用する初期データを登録する
+type fixtures struct {
+	t *testing.T
+	h *Harness
+}
+
+func newFixtures(t *testing.T, h *Harness) *fixtures {
+	return &fixtures{t: t, h: h}
+}
+
+func (r *fixtures) user(id string) *entities.User {
+	r.t.Helper()
+
+	u := &entities.User{
+		ID:       id,
+		Name:     id + " name",
+		Nickname: id + " nickname",
+		Picture:  "https://example.com/" + id + ".png",
+	}
+	if err := r.h.Users.Save(context.Background(), u); err != nil {
+		r.t.Fatalf("failed to save user fixture: %v", err)
+	}
+	return u
+}
+
+func (r *fixtures) work(author *entities.User, title string) *entities.Work {
+	r.t.Helper()
+
+	w := &entities.Work{
+		Type:        constants.ContentTypeURL,
+		Title:       title,
+		AuthorID:    author.ID,
+		Description: title + " description",
+		ContentURL:  "https://example.com/" + title,
+		Visibility:  constants.VisibilityPublic,
+		Status:      constants.WorkPublished,
+		ScanStatus:  constants.ScanClean,
+		Version:     1,
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		return r.h.Works.Create(ctx, w)
+	})
+	return w
+}
+
+func (r *fixtures) activity(user *entities.User, work *entities.Work, createdAt time.Time) *entities.Activity {
+	r.t.Helper()
+
+	a := &entities.Activity{
+		Type:      constants.ActivityAdded,
+		UserID:    user.ID,
+		Work:      work,
+		CreatedAt: createdAt,
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		return r.h.Activities.Create(ctx, a)
+	})
+	return a
+}
+
+func (r *fixtures) collection(owner *entities.User, title string, visibility constants.Visibility, works ...*entities.Work) *entities.Collection {
+	r.t.Helper()
+
+	c := &entities.Collection{
+		OwnerID:     owner.ID,
+		Title:       title,
+		Description: title + " description",
+		Visibility:  visibility,
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		if err := r.h.Collections.Create(ctx, c); err != nil {
+			return err
+		}
+		for _, w := range works {
+			if _, err := r.h.Collections.AddWork(ctx, c.ID, w.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return c
+}
+
+func (r *fixtures) comment(author *entities.User, work *entities.Work, parent *entities.Comment, body string) *entities.Comment {
+	r.t.Helper()
+
+	c := &entities.Comment{
+		WorkID:   work.ID,
+		AuthorID: author.ID,
+		Body:     body,
+	}
+	if parent != nil {
+		c.ParentID = &parent.ID
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		return r.h.Comments.Create(ctx, c)
+	})
+	return c
+}
+
+func (r *fixtures) report(reporter *entities.User, work *entities.Work, createdAt time.Time) *entities.Report {
+	r.t.Helper()
+
+	report := &entities.Report{
+		WorkID:     work.ID,
+		ReporterID: reporter.ID,
+		Reason:     constants.ReportSpam,
+		Status:     constants.ReportOpen,
+		CreatedAt:  createdAt,
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		_, err := r.h.Reports.Create(ctx, report)
+		return err
+	})
+	return report
+}
+
+func (r *fixtures) upload(user *entities.User, id string, expiresAt time.Time) *entities.Upload {
+	r.t.Helper()
+
+	u := &entities.Upload{
+		ID:         id,
+		UserID:     user.ID,
+		Filename:   id + ".mp4",
+		StorageKey: id + "-key.mp4",
+		Length:     1024,
+		ExpiresAt:  expiresAt.UTC().Truncate(time.Second),
+	}
+	r.inTransaction(func(ctx context.Context) error {
+		return r.h.Uploads.Create(ctx, u)
+	})
+	return u
+}
+
+func (r *fixtures) inTransaction(f repositories.TransactionFunction) {
+	r.t.Helper()
+
+	if err := r.h.TransactionRunner.Run(context.Background(), f); err != nil {
+		r.t.Fatalf("failed to save fixture: %v", err)
+	}
+}
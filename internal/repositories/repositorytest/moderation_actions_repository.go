@@ -0,0 +1,74 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunModerationActionsRepositoryTests は、ModerationActionsRepositoryの契約テストを実行する
+func RunModerationActionsRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create, GetAll and CountAll", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		moderator := f.user("moderator")
+		user := f.user("user")
+		ctx := context.Background()
+		w := f.work(user, "hoge")
+		base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		autoHide := &entities.ModerationAction{Type: constants.ModerationAutoHide, WorkID: &w.ID, CreatedAt: base}
+		remove := &entities.ModerationAction{
+			Type:        constants.ModerationRemove,
+			ModeratorID: &moderator.ID,
+			WorkID:      &w.ID,
+			Note:        "spam",
+			CreatedAt:   base.Add(time.Hour),
+		}
+		suspend := &entities.ModerationAction{
+			Type:        constants.ModerationSuspend,
+			ModeratorID: &moderator.ID,
+			UserID:      &user.ID,
+			CreatedAt:   base.Add(2 * time.Hour),
+		}
+		f.inTransaction(func(ctx context.Context) error {
+			for _, v := range []*entities.ModerationAction{autoHide, remove, suspend} {
+				if err := h.ModerationActions.Create(ctx, v); err != nil {
+					return err
+				}
+			}
+			// 削除した作品の操作の記録も読み込む
+			return h.Works.DeleteByID(ctx, w.ID)
+		})
+
+		actions, err := h.ModerationActions.GetAll(ctx, 0, 10)
+		if assert.Nil(t, err) && assert.Len(t, actions, 3) {
+			assert.Equal(t, []uint64{suspend.ID, remove.ID, autoHide.ID}, []uint64{actions[0].ID, actions[1].ID, actions[2].ID})
+			if assert.NotNil(t, actions[0].User) && assert.NotNil(t, actions[0].Moderator) {
+				assert.Equal(t, "user name", actions[0].User.Name)
+				assert.Equal(t, "moderator name", actions[0].Moderator.Name)
+			}
+			assert.Nil(t, actions[0].Work)
+			if assert.NotNil(t, actions[1].Work) {
+				assert.Equal(t, "hoge", actions[1].Work.Title)
+			}
+			assert.Equal(t, "spam", actions[1].Note)
+			assert.Nil(t, actions[2].Moderator)
+		}
+		count, err := h.ModerationActions.CountAll(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.ModerationActions.Create(context.Background(), &entities.ModerationAction{Type: constants.ModerationAutoHide})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunReportsRepositoryTests は、ReportsRepositoryの契約テストを実行する
+func RunReportsRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create is idempotent for the same reporter", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		reporter := f.user("reporter")
+		w := f.work(f.user("author"), "hoge")
+
+		var results []bool
+		f.inTransaction(func(ctx context.Context) error {
+			for i := 0; i < 2; i++ {
+				added, err := h.Reports.Create(ctx, &entities.Report{
+					WorkID:     w.ID,
+					ReporterID: reporter.ID,
+					Reason:     constants.ReportSpam,
+					Status:     constants.ReportOpen,
+				})
+				if err != nil {
+					return err
+				}
+				results = append(results, added)
+			}
+			return nil
+		})
+
+		assert.Equal(t, []bool{true, false}, results)
+		count, err := h.Reports.CountOpenByWorkID(context.Background(), w.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		_, err := h.Reports.Create(context.Background(), &entities.Report{
+			WorkID:     w.ID,
+			ReporterID: "author",
+			Reason:     constants.ReportSpam,
+			Status:     constants.ReportOpen,
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("FindOpen, CountOpen and ResolveByWorkID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		u1 := f.user("u1")
+		u2 := f.user("u2")
+		ctx := context.Background()
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+		base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		r1 := f.report(u1, w1, base.Add(2*time.Hour))
+		r2 := f.report(u1, w2, base)
+		r3 := f.report(u2, w1, base.Add(time.Hour))
+
+		reports, err := h.Reports.FindOpen(ctx, 0, 10)
+		if assert.Nil(t, err) && assert.Len(t, reports, 3) {
+			assert.Equal(t, []uint64{r2.ID, r3.ID, r1.ID}, []uint64{reports[0].ID, reports[1].ID, reports[2].ID})
+			if assert.NotNil(t, reports[0].Reporter) {
+				assert.Equal(t, "u1 name", reports[0].Reporter.Name)
+			}
+			if assert.NotNil(t, reports[0].Work) && assert.NotNil(t, reports[0].Work.Author) {
+				assert.Equal(t, "w2", reports[0].Work.Title)
+				assert.Equal(t, "author name", reports[0].Work.Author.Name)
+			}
+		}
+		count, err := h.Reports.CountOpen(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Reports.ResolveByWorkID(ctx, w1.ID, constants.ReportDismissed)
+		})
+
+		reports, err = h.Reports.FindOpen(ctx, 0, 10)
+		if assert.Nil(t, err) && assert.Len(t, reports, 1) {
+			assert.Equal(t, r2.ID, reports[0].ID)
+		}
+		count, err = h.Reports.CountOpenByWorkID(ctx, w1.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("FindOpen loads deleted works", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+		f.report(f.user("reporter"), w, time.Now())
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, w.ID)
+		})
+
+		reports, err := h.Reports.FindOpen(context.Background(), 0, 10)
+		if assert.Nil(t, err) && assert.Len(t, reports, 1) && assert.NotNil(t, reports[0].Work) {
+			assert.Equal(t, w.ID, reports[0].Work.ID)
+		}
+	})
+
+	t.Run("ResolveByWorkID requires a transaction", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.Reports.ResolveByWorkID(context.Background(), 1, constants.ReportResolved)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,86 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunScanResultsRepositoryTests は、ScanResultsRepositoryの契約テストを実行する
+func RunScanResultsRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create and FindByWorkID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "scanned")
+		other := f.work(author, "other")
+		ctx := context.Background()
+
+		results := []*entities.ScanResult{
+			{WorkID: w.ID, Field: "thumbnail", Key: "thumb.png", Scanner: "clamd"},
+			{WorkID: w.ID, Field: "content", Key: "content.zip", Scanner: "clamd", Signature: "Eicar-Test-Signature"},
+			{WorkID: other.ID, Field: "content", Key: "other.zip", Scanner: "clamd"},
+		}
+		f.inTransaction(func(ctx context.Context) error {
+			for _, r := range results {
+				if err := h.ScanResults.Create(ctx, r); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		actual, err := h.ScanResults.FindByWorkID(ctx, w.ID)
+
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			for i, r := range actual {
+				assert.NotZero(t, r.ID)
+				assert.Equal(t, results[i].Field, r.Field)
+				assert.Equal(t, results[i].Key, r.Key)
+				assert.Equal(t, results[i].Scanner, r.Scanner)
+				assert.Equal(t, results[i].Signature, r.Signature)
+			}
+		}
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.ScanResults.Create(context.Background(), &entities.ScanResult{WorkID: w.ID, Scanner: "clamd"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Create fails when the work does not exist", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.ScanResults.Create(ctx, &entities.ScanResult{WorkID: 12345, Scanner: "clamd"})
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Results are deleted with the work", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "purged")
+		ctx := context.Background()
+		f.inTransaction(func(ctx context.Context) error {
+			return h.ScanResults.Create(ctx, &entities.ScanResult{WorkID: w.ID, Key: "a.png", Scanner: "clamd"})
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, w.ID)
+		})
+
+		actual, err := h.ScanResults.FindByWorkID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, actual)
+	})
+}
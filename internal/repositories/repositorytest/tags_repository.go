@@ -0,0 +1,157 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunTagsRepositoryTests は、TagsRepositoryの契約テストを実行する
+func RunTagsRepositoryTests(t *testing.T, setup SetupFunc) {
+	// tag は、作品のタグを置き換える
+	tag := func(t *testing.T, h *Harness, w *entities.Work, names ...string) []*entities.Tag {
+		t.Helper()
+
+		var tags []*entities.Tag
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			var err error
+			tags, err = h.Tags.ReplaceWorkTags(ctx, w.ID, names)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("failed to tag work: %v", err)
+		}
+		return tags
+	}
+
+	names := func(tags []*entities.Tag) []string {
+		var result []string
+		for _, v := range tags {
+			result = append(result, v.Name)
+		}
+		return result
+	}
+
+	t.Run("ReplaceWorkTags", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+
+		tags1 := tag(t, h, w1, "go", "art")
+		tags2 := tag(t, h, w2, "art")
+		// 登録済みのタグは共有する
+		assert.Equal(t, []string{"go", "art"}, names(tags1))
+		assert.Equal(t, tags1[1].ID, tags2[0].ID)
+
+		tag(t, h, w1, "music", "go")
+
+		actual, err := h.Works.FindByID(ctx, w1.ID)
+		assert.Nil(t, err)
+		// 名前の順に読み込む
+		assert.Equal(t, []string{"go", "music"}, names(actual.Tags))
+
+		tag(t, h, w2)
+
+		actual, err = h.Works.FindByID(ctx, w2.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, actual.Tags)
+	})
+
+	t.Run("ReplaceWorkTags requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		_, err := h.Tags.ReplaceWorkTags(context.Background(), w.ID, []string{"go"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		tag(t, h, f.work(author, "w1"), "game", "garden")
+		tag(t, h, f.work(author, "w2"), "game", "music")
+		tag(t, h, f.work(author, "w3"), "gallery", "game")
+
+		// 一覧に表示しない作品のタグは数えない
+		private := f.work(author, "private")
+		tag(t, h, private, "garden", "gallery", "go")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.UpdateScanStatus(ctx, private.ID, constants.ScanPending)
+		})
+		deleted := f.work(author, "deleted")
+		tag(t, h, deleted, "garden", "gallery")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, deleted.ID)
+		})
+
+		actual, err := h.Tags.Search(ctx, "ga", 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*entities.TagCount{
+			{Name: "game", Count: 3},
+			{Name: "gallery", Count: 1},
+			{Name: "garden", Count: 1},
+		}, actual)
+
+		limited, err := h.Tags.Search(ctx, "", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entities.TagCount{{Name: "game", Count: 3}}, limited)
+	})
+
+	t.Run("Search escapes the prefix", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		tag(t, h, f.work(f.user("author"), "w1"), "100%", "1000", "a_b", "axb")
+
+		percent, err := h.Tags.Search(context.Background(), "100%", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entities.TagCount{{Name: "100%", Count: 1}}, percent)
+
+		underscore, err := h.Tags.Search(context.Background(), "a_", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entities.TagCount{{Name: "a_b", Count: 1}}, underscore)
+	})
+
+	t.Run("GetAll filters works by tags", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		both := f.work(author, "both")
+		tag(t, h, both, "go", "art")
+		tag(t, h, f.work(author, "go only"), "go")
+		f.work(author, "untagged")
+
+		all, err := h.Works.GetAll(ctx, "", []string{"go"}, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, all, 2)
+		count, err := h.Works.CountAll(ctx, "", []string{"go"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 全てのタグが付いた作品に絞り込む
+		all, err = h.Works.GetAll(ctx, "", []string{"go", "art"}, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, all, 1) {
+			assert.Equal(t, both.ID, all[0].ID)
+			assert.Equal(t, []string{"art", "go"}, names(all[0].Tags))
+		}
+		count, err = h.Works.CountAll(ctx, "", []string{"go", "art"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+
+		count, err = h.Works.CountAll(ctx, "", []string{"nothing"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
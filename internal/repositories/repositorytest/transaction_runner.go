@@ -0,0 +1,74 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunTransactionRunnerTests は、TransactionRunnerの契約テストを実行する
+func RunTransactionRunnerTests(t *testing.T, setup SetupFunc) {
+	t.Run("Commits when the function succeeds", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+
+		w := &entities.Work{Type: constants.ContentTypeURL, Title: "committed", AuthorID: author.ID, ScanStatus: constants.ScanClean}
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+
+		assert.Nil(t, err)
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "committed", actual.Title)
+	})
+
+	t.Run("Rolls back when the function fails", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+
+		expect := errors.New("error")
+		w := &entities.Work{Type: constants.ContentTypeURL, Title: "rolled back", AuthorID: author.ID, ScanStatus: constants.ScanClean}
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			if err := h.Works.Create(ctx, w); err != nil {
+				return err
+			}
+			return expect
+		})
+
+		assert.True(t, errors.Is(err, expect))
+		count, err := h.Works.CountAll(ctx, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Reads in the transaction see its own writes", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			w := &entities.Work{Type: constants.ContentTypeURL, Title: "uncommitted", AuthorID: author.ID, ScanStatus: constants.ScanClean}
+			if err := h.Works.Create(ctx, w); err != nil {
+				return err
+			}
+
+			actual, err := h.Works.FindByID(ctx, w.ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, "uncommitted", actual.Title)
+			return nil
+		})
+
+		assert.Nil(t, err)
+	})
+}
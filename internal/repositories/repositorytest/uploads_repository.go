@@ -0,0 +1,204 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunUploadsRepositoryTests は、UploadsRepositoryの契約テストを実行する
+func RunUploadsRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create and FindByID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+
+		actual, err := h.Uploads.FindByID(context.Background(), u.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, u.UserID, actual.UserID)
+		assert.Equal(t, u.Filename, actual.Filename)
+		assert.Equal(t, u.StorageKey, actual.StorageKey)
+		assert.Equal(t, u.Length, actual.Length)
+		assert.Equal(t, int64(0), actual.Offset)
+		assert.Equal(t, 0, actual.Chunks)
+		assert.Nil(t, actual.CompletedAt)
+		assert.True(t, u.ExpiresAt.Equal(actual.ExpiresAt), "%v %v", u.ExpiresAt, actual.ExpiresAt)
+	})
+
+	t.Run("FindByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		_, err := h.Uploads.FindByID(context.Background(), "nothing")
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+
+		err := h.Uploads.Create(context.Background(), &entities.Upload{ID: "upload1", UserID: owner.ID})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("UpdateProgress", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+		ctx := context.Background()
+
+		completedAt := time.Now().UTC().Truncate(time.Second)
+		u.Offset = u.Length
+		u.Chunks = 1
+		u.CompletedAt = &completedAt
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Uploads.UpdateProgress(ctx, u, 0)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Uploads.FindByID(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, u.Length, actual.Offset)
+		assert.Equal(t, 1, actual.Chunks)
+		if assert.NotNil(t, actual.CompletedAt) {
+			assert.True(t, completedAt.Equal(*actual.CompletedAt))
+		}
+	})
+
+	t.Run("UpdateProgress conflicts with another update", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+		ctx := context.Background()
+
+		u.Offset = 10
+		u.Chunks = 1
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Uploads.UpdateProgress(ctx, u, 1)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+
+		actual, err := h.Uploads.FindByID(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), actual.Offset)
+	})
+
+	t.Run("CreateChunk and FindChunks", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		u := f.upload(owner, "upload1", time.Now().Add(time.Hour))
+		other := f.upload(owner, "upload2", time.Now().Add(time.Hour))
+		ctx := context.Background()
+
+		f.inTransaction(func(ctx context.Context) error {
+			for _, c := range []*entities.UploadChunk{
+				{UploadID: u.ID, Number: 1, StorageKey: "key.1.b.part"},
+				{UploadID: u.ID, Number: 0, StorageKey: "key.0.a.part"},
+				{UploadID: other.ID, Number: 0, StorageKey: "other.0.c.part"},
+			} {
+				if err := h.Uploads.CreateChunk(ctx, c); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		actual, err := h.Uploads.FindChunks(ctx, u.ID)
+
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, "key.0.a.part", actual[0].StorageKey)
+			assert.Equal(t, "key.1.b.part", actual[1].StorageKey)
+		}
+	})
+
+	t.Run("CreateChunk rejects the same number", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+		ctx := context.Background()
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Uploads.CreateChunk(ctx, &entities.UploadChunk{UploadID: u.ID, Number: 0, StorageKey: "key.0.a.part"})
+		})
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Uploads.CreateChunk(ctx, &entities.UploadChunk{UploadID: u.ID, Number: 0, StorageKey: "key.0.b.part"})
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("CreateChunk requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+
+		err := h.Uploads.CreateChunk(context.Background(), &entities.UploadChunk{UploadID: u.ID, StorageKey: "key.0.a.part"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("FindExpired", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		owner := f.user("owner")
+		now := time.Now()
+		f.upload(owner, "alive", now.Add(time.Hour))
+		f.upload(owner, "expired2", now.Add(-time.Minute))
+		f.upload(owner, "expired1", now.Add(-time.Hour))
+
+		actual, err := h.Uploads.FindExpired(context.Background(), now, 10)
+
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, "expired1", actual[0].ID)
+			assert.Equal(t, "expired2", actual[1].ID)
+		}
+
+		limited, err := h.Uploads.FindExpired(context.Background(), now, 1)
+		assert.Nil(t, err)
+		assert.Len(t, limited, 1)
+	})
+
+	t.Run("DeleteByID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		u := f.upload(f.user("owner"), "upload1", time.Now().Add(time.Hour))
+		ctx := context.Background()
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Uploads.CreateChunk(ctx, &entities.UploadChunk{UploadID: u.ID, StorageKey: "key.0.a.part"})
+		})
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Uploads.DeleteByID(ctx, u.ID)
+		})
+		assert.Nil(t, err)
+
+		_, err = h.Uploads.FindByID(ctx, u.ID)
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+
+		// チャンクの記録も削除する
+		chunks, err := h.Uploads.FindChunks(ctx, u.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, chunks)
+
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Uploads.DeleteByID(ctx, u.ID)
+		})
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+}
@@ -0,0 +1,129 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunUsersRepositoryTests は、UsersRepositoryの契約テストを実行する
+func RunUsersRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Save inserts and then updates", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		ctx := context.Background()
+
+		err := h.Users.Save(ctx, &entities.User{
+			ID:       "user",
+			Name:     "before",
+			Nickname: "before",
+			Picture:  "https://example.com/before.png",
+		})
+		assert.Nil(t, err)
+
+		err = h.Users.Save(ctx, &entities.User{
+			ID:       "user",
+			Name:     "after",
+			Nickname: "after",
+			Picture:  "https://example.com/after.png",
+		})
+		assert.Nil(t, err)
+
+		// ユーザーは作品の作者として取得する
+		w := f.work(&entities.User{ID: "user"}, "hoge")
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		assert.Nil(t, err)
+		if assert.NotNil(t, actual.Author) {
+			assert.Equal(t, "after", actual.Author.Name)
+			assert.Equal(t, "after", actual.Author.Nickname)
+			assert.Equal(t, "https://example.com/after.png", actual.Author.Picture)
+		}
+	})
+
+	t.Run("Save keeps the creation time", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		ctx := context.Background()
+		created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		err := h.Users.Save(ctx, &entities.User{ID: "user", Name: "before", CreatedAt: created})
+		assert.Nil(t, err)
+		err = h.Users.Save(ctx, &entities.User{ID: "user", Name: "after"})
+		assert.Nil(t, err)
+
+		w := f.work(&entities.User{ID: "user"}, "hoge")
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		assert.Nil(t, err)
+		if assert.NotNil(t, actual.Author) {
+			assert.True(t, created.Equal(actual.Author.CreatedAt), "%v", actual.Author.CreatedAt)
+		}
+	})
+	t.Run("FindByID and UpdateSuspended", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		ctx := context.Background()
+		f.user("user")
+		suspendedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		actual, err := h.Users.FindByID(ctx, "user")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "user name", actual.Name)
+			assert.Nil(t, actual.SuspendedAt)
+		}
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Users.UpdateSuspended(ctx, "user", &suspendedAt)
+		})
+		// プロフィールの同期では、停止した日時を変更しない
+		assert.Nil(t, h.Users.Save(ctx, &entities.User{ID: "user", Name: "after"}))
+
+		actual, err = h.Users.FindByID(ctx, "user")
+		if assert.Nil(t, err) && assert.NotNil(t, actual.SuspendedAt) {
+			assert.True(t, suspendedAt.Equal(*actual.SuspendedAt), "%v", actual.SuspendedAt)
+		}
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Users.UpdateSuspended(ctx, "user", nil)
+		})
+
+		actual, err = h.Users.FindByID(ctx, "user")
+		if assert.Nil(t, err) {
+			assert.Nil(t, actual.SuspendedAt)
+		}
+	})
+
+	t.Run("FindByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		_, err := h.Users.FindByID(context.Background(), "nobody")
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateSuspended returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Users.UpdateSuspended(ctx, "nobody", nil)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateSuspended requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		f.user("user")
+
+		err := h.Users.UpdateSuspended(context.Background(), "user", nil)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,130 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunWorkRevisionsRepositoryTests は、WorkRevisionsRepositoryの契約テストを実行する
+func RunWorkRevisionsRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create and FindByVersion", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "hoge")
+		w.Thumbnails = entities.ImageVariants{"320": "https://example.com/320.jpg"}
+		revision := entities.NewWorkRevision(w, author.ID)
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.WorkRevisions.Create(ctx, revision)
+		})
+
+		actual, err := h.WorkRevisions.FindByVersion(context.Background(), w.ID, w.Version)
+		if assert.Nil(t, err) {
+			assert.NotZero(t, actual.ID)
+			assert.Equal(t, w.ID, actual.WorkID)
+			assert.Equal(t, w.Version, actual.Version)
+			assert.Equal(t, w.Type, actual.Type)
+			assert.Equal(t, w.Title, actual.Title)
+			assert.Equal(t, w.Description, actual.Description)
+			assert.Equal(t, w.ContentURL, actual.ContentURL)
+			assert.Equal(t, w.Thumbnails, actual.Thumbnails)
+			assert.Equal(t, author.ID, actual.EditorID)
+			assert.False(t, actual.CreatedAt.IsZero())
+		}
+	})
+
+	t.Run("FindByVersion returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		_, err := h.WorkRevisions.FindByVersion(context.Background(), w.ID, 2)
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("FindByWorkID and CountByWorkID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "hoge")
+		other := f.work(author, "other")
+		ctx := context.Background()
+		f.inTransaction(func(ctx context.Context) error {
+			for _, target := range []*entities.Work{w, other} {
+				for v := uint(1); v <= 3; v++ {
+					target.Version = v
+					if err := h.WorkRevisions.Create(ctx, entities.NewWorkRevision(target, author.ID)); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+
+		actual, err := h.WorkRevisions.FindByWorkID(ctx, w.ID, 1, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, uint(2), actual[0].Version)
+			assert.Equal(t, uint(1), actual[1].Version)
+			assert.Equal(t, w.ID, actual[0].WorkID)
+		}
+
+		count, err := h.WorkRevisions.CountByWorkID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("Create fails when the version already exists", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "hoge")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.WorkRevisions.Create(ctx, entities.NewWorkRevision(w, author.ID))
+		})
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.WorkRevisions.Create(ctx, entities.NewWorkRevision(w, author.ID))
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "hoge")
+
+		err := h.WorkRevisions.Create(context.Background(), entities.NewWorkRevision(w, author.ID))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Revisions are deleted with the work", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w := f.work(author, "purged")
+		ctx := context.Background()
+		f.inTransaction(func(ctx context.Context) error {
+			return h.WorkRevisions.Create(ctx, entities.NewWorkRevision(w, author.ID))
+		})
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, w.ID)
+		})
+
+		count, err := h.WorkRevisions.CountByWorkID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Zero(t, count)
+	})
+}
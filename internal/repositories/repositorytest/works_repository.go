@@ -0,0 +1,917 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// RunWorksRepositoryTests は、WorksRepositoryの契約テストを実行する
+func RunWorksRepositoryTests(t *testing.T, setup SetupFunc) {
+	t.Run("Create and FindByID", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+
+		w := &entities.Work{
+			Type:          constants.ContentTypeFile,
+			Title:         "hoge",
+			AuthorID:      author.ID,
+			Description:   "hogehoge",
+			ThumbnailURL:  "https://example.com/thumb",
+			Thumbnails:    entities.ImageVariants{"320": "https://example.com/thumb_320.jpg"},
+			ContentURL:    "https://example.com/content",
+			ContentType:   "application/zip",
+			Version:       1,
+			ContentSHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			ContentSize:   1 << 33,
+			ScanStatus:    constants.ScanClean,
+		}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+
+		assert.NotZero(t, w.ID)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, w.Type, actual.Type)
+		assert.Equal(t, w.Title, actual.Title)
+		assert.Equal(t, w.Description, actual.Description)
+		assert.Equal(t, w.ThumbnailURL, actual.ThumbnailURL)
+		assert.Equal(t, w.Thumbnails, actual.Thumbnails)
+		assert.Equal(t, w.ContentURL, actual.ContentURL)
+		assert.Equal(t, w.ContentType, actual.ContentType)
+		assert.Equal(t, w.ContentSHA256, actual.ContentSHA256)
+		assert.Equal(t, w.ContentSize, actual.ContentSize)
+		assert.Equal(t, w.Version, actual.Version)
+		if assert.NotNil(t, actual.Author) {
+			assert.Equal(t, author.ID, actual.Author.ID)
+			assert.Equal(t, author.Name, actual.Author.Name)
+		}
+	})
+
+	t.Run("Create requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+
+		err := h.Works.Create(context.Background(), &entities.Work{AuthorID: author.ID})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Create fails when the author does not exist", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.Create(ctx, &entities.Work{Title: "orphan", AuthorID: "nobody"})
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("FindByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		_, err := h.Works.FindByID(context.Background(), 12345)
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("GetAll and CountAll", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+
+		var works []*entities.Work
+		for _, title := range []string{"w1", "w2", "w3"} {
+			works = append(works, f.work(author, title))
+		}
+
+		count, err := h.Works.CountAll(ctx, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		all, err := h.Works.GetAll(ctx, "", nil, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, all, 3)
+		for _, w := range all {
+			if assert.NotNil(t, w.Author) {
+				assert.Equal(t, author.ID, w.Author.ID)
+			}
+		}
+
+		page, err := h.Works.GetAll(ctx, "", nil, constants.OrderDefault, 1, 1)
+		assert.Nil(t, err)
+		assert.Len(t, page, 1)
+		assert.NotEqual(t, all[0].ID, page[0].ID)
+	})
+
+	t.Run("Works not public are listed only to the author", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		public := f.work(author, "public")
+		var hidden []*entities.Work
+		for _, v := range []constants.Visibility{constants.VisibilityUnlisted, constants.VisibilityPrivate} {
+			w := &entities.Work{Title: "hidden", AuthorID: author.ID, Visibility: v, ScanStatus: constants.ScanClean}
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.Create(ctx, w)
+			})
+			hidden = append(hidden, w)
+		}
+
+		for _, viewer := range []string{"", "other"} {
+			all, err := h.Works.GetAll(ctx, viewer, nil, constants.OrderDefault, 0, 10)
+			assert.Nil(t, err)
+			if assert.Len(t, all, 1) {
+				assert.Equal(t, public.ID, all[0].ID)
+			}
+			count, err := h.Works.CountAll(ctx, viewer, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), count)
+		}
+
+		all, err := h.Works.GetAll(ctx, author.ID, nil, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, all, 3)
+		count, err := h.Works.CountAll(ctx, author.ID, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		// FindByIDは公開範囲を区別しない
+		for _, w := range hidden {
+			found, err := h.Works.FindByID(ctx, w.ID)
+			if assert.Nil(t, err) {
+				assert.Equal(t, w.Visibility, found.Visibility)
+			}
+		}
+	})
+
+	t.Run("Drafts and scheduled works are listed only to the author", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		published := f.work(author, "published")
+		publishAt := time.Now().Add(time.Hour)
+		for _, s := range []constants.WorkStatus{constants.WorkDraft, constants.WorkScheduled} {
+			w := &entities.Work{Title: "unpublished", AuthorID: author.ID, Visibility: constants.VisibilityPublic,
+				Status: s, ScanStatus: constants.ScanClean}
+			if s == constants.WorkScheduled {
+				w.PublishAt = &publishAt
+			}
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.Create(ctx, w)
+			})
+		}
+
+		for _, viewer := range []string{"", "other"} {
+			all, err := h.Works.GetAll(ctx, viewer, nil, constants.OrderDefault, 0, 10)
+			assert.Nil(t, err)
+			if assert.Len(t, all, 1) {
+				assert.Equal(t, published.ID, all[0].ID)
+			}
+			count, err := h.Works.CountAll(ctx, viewer, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), count)
+		}
+
+		all, err := h.Works.GetAll(ctx, author.ID, nil, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("FindDueToPublish and Publish", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		now := time.Now()
+		schedule := func(title string, at time.Time) *entities.Work {
+			w := &entities.Work{Title: title, AuthorID: author.ID, Visibility: constants.VisibilityPublic,
+				Status: constants.WorkScheduled, PublishAt: &at, ScanStatus: constants.ScanClean}
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.Create(ctx, w)
+			})
+			return w
+		}
+		later := schedule("later", now.Add(-time.Minute))
+		earlier := schedule("earlier", now.Add(-time.Hour))
+		schedule("future", now.Add(time.Hour))
+		f.work(author, "published")
+
+		due, err := h.Works.FindDueToPublish(ctx, now, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, due, 2) {
+			assert.Equal(t, earlier.ID, due[0].ID)
+			assert.Equal(t, later.ID, due[1].ID)
+		}
+
+		due, err = h.Works.FindDueToPublish(ctx, now, 1)
+		assert.Nil(t, err)
+		assert.Len(t, due, 1)
+
+		err = h.Works.Publish(ctx, earlier.ID)
+		assert.Error(t, err, "Publish requires a transaction")
+
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Publish(ctx, earlier.ID)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Works.FindByID(ctx, earlier.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, constants.WorkPublished, actual.Status)
+		}
+
+		// 公開済みの作品は再度公開しない
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Publish(ctx, earlier.ID)
+		})
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+
+		due, err = h.Works.FindDueToPublish(ctx, now, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, due, 1) {
+			assert.Equal(t, later.ID, due[0].ID)
+		}
+	})
+
+	t.Run("Works not scanned are hidden", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		published := f.work(author, "published")
+
+		var hidden []*entities.Work
+		for _, status := range []constants.ScanStatus{constants.ScanPending, constants.ScanInfected} {
+			w := &entities.Work{Title: "hidden", AuthorID: author.ID, ScanStatus: status}
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.Create(ctx, w)
+			})
+			hidden = append(hidden, w)
+		}
+
+		all, err := h.Works.GetAll(ctx, "", nil, constants.OrderDefault, 0, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, all, 1) {
+			assert.Equal(t, published.ID, all[0].ID)
+		}
+		count, err := h.Works.CountAll(ctx, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+		for _, w := range hidden {
+			_, err = h.Works.FindByID(ctx, w.ID)
+			var rnfErr *myErr.RecordNotFoundError
+			assert.True(t, errors.As(err, &rnfErr), "%v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+		ctx := context.Background()
+
+		w.Title = "updated"
+		w.Description = "updated description"
+		w.ThumbnailURL = "https://example.com/thumb.png"
+		w.Thumbnails = entities.ImageVariants{"320": "https://example.com/320.jpg"}
+		w.ContentURL = "https://example.com/updated"
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Update(ctx, w)
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), w.Version)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "updated", actual.Title)
+			assert.Equal(t, "updated description", actual.Description)
+			assert.Equal(t, "https://example.com/thumb.png", actual.ThumbnailURL)
+			assert.Equal(t, w.Thumbnails, actual.Thumbnails)
+			assert.Equal(t, "https://example.com/updated", actual.ContentURL)
+			assert.Equal(t, uint(2), actual.Version)
+		}
+	})
+
+	t.Run("Update changes the publication", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := &entities.Work{Title: "draft", AuthorID: author.ID, Visibility: constants.VisibilityPublic,
+			Status: constants.WorkDraft, ScanStatus: constants.ScanClean, Version: 1}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+
+		publishAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		w.Status = constants.WorkScheduled
+		w.PublishAt = &publishAt
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Update(ctx, w)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, constants.WorkScheduled, actual.Status)
+			if assert.NotNil(t, actual.PublishAt) {
+				assert.True(t, publishAt.Equal(*actual.PublishAt))
+			}
+		}
+	})
+
+	t.Run("Update does not unpublish a work published after loading", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		publishAt := time.Now().Add(-time.Minute)
+		w := &entities.Work{Title: "scheduled", AuthorID: author.ID, Visibility: constants.VisibilityPublic,
+			Status: constants.WorkScheduled, PublishAt: &publishAt, ScanStatus: constants.ScanClean, Version: 1}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Publish(ctx, w.ID)
+		})
+
+		w.Status = constants.WorkDraft
+		w.PublishAt = nil
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Update(ctx, w)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, constants.WorkPublished, actual.Status)
+		}
+	})
+
+	t.Run("Publish publishes drafts", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := &entities.Work{Title: "draft", AuthorID: author.ID, Visibility: constants.VisibilityPublic,
+			Status: constants.WorkDraft, ScanStatus: constants.ScanClean, Version: 1}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Publish(ctx, w.ID)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, constants.WorkPublished, actual.Status)
+			assert.Equal(t, uint(1), actual.Version)
+		}
+	})
+
+	t.Run("Update returns RecordNotFoundError when the version has changed", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+		ctx := context.Background()
+		stale := *w
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Update(ctx, w)
+		})
+
+		stale.Title = "stale"
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Update(ctx, &stale)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+		assert.Equal(t, uint(1), stale.Version)
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "hoge", actual.Title)
+		}
+	})
+
+	t.Run("Update requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.Update(context.Background(), w)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("UpdateScanStatus", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := &entities.Work{Title: "pending", AuthorID: author.ID, ScanStatus: constants.ScanPending}
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.Create(ctx, w)
+		})
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.UpdateScanStatus(ctx, w.ID, constants.ScanClean)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, constants.ScanClean, actual.ScanStatus)
+		}
+	})
+
+	t.Run("UpdateScanStatus returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.UpdateScanStatus(ctx, 12345, constants.ScanClean)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateScanStatus requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.UpdateScanStatus(context.Background(), w.ID, constants.ScanInfected)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("UpdateFavoriteCount", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		ctx := context.Background()
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			if err := h.Works.UpdateFavoriteCount(ctx, w.ID, 1); err != nil {
+				return err
+			}
+			return h.Works.UpdateFavoriteCount(ctx, w.ID, 1)
+		})
+		assert.Nil(t, err)
+
+		// 削除した作品も対象にする
+		f.inTransaction(func(ctx context.Context) error {
+			if err := h.Works.DeleteByID(ctx, w.ID); err != nil {
+				return err
+			}
+			return h.Works.UpdateFavoriteCount(ctx, w.ID, -1)
+		})
+
+		actual, err := h.Works.FindTrashedByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, int64(1), actual.FavoriteCount)
+			assert.Equal(t, w.Version, actual.Version)
+		}
+	})
+
+	t.Run("UpdateFavoriteCount returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.UpdateFavoriteCount(ctx, 12345, 1)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateFavoriteCount requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.UpdateFavoriteCount(context.Background(), w.ID, 1)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("UpdateHidden hides works from other users", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := f.work(author, "hoge")
+		hiddenAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.UpdateHidden(ctx, w.ID, &hiddenAt)
+		})
+
+		for _, viewer := range []string{"", "other"} {
+			count, err := h.Works.CountAll(ctx, viewer, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), count)
+		}
+		count, err := h.Works.CountAll(ctx, author.ID, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// FindByIDは非表示の作品も取得し、更新日時とバージョンは変更しない
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) && assert.NotNil(t, actual.HiddenAt) {
+			assert.True(t, hiddenAt.Equal(*actual.HiddenAt), "%v", actual.HiddenAt)
+			assert.Equal(t, w.Version, actual.Version)
+		}
+
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.UpdateHidden(ctx, w.ID, nil)
+		})
+
+		count, err = h.Works.CountAll(ctx, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("UpdateHidden returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.UpdateHidden(ctx, 12345, nil)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateHidden requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.UpdateHidden(context.Background(), w.ID, nil)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("GetAll orders by favorites", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		w1 := f.work(author, "w1")
+		w2 := f.work(author, "w2")
+		w3 := f.work(author, "w3")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.UpdateFavoriteCount(ctx, w2.ID, 2)
+		})
+
+		// 同数の場合は新しい作品から並べる
+		all, err := h.Works.GetAll(context.Background(), "", nil, constants.OrderMostFavorited, 0, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, all, 3) {
+			assert.Equal(t, []uint64{w2.ID, w3.ID, w1.ID}, []uint64{all[0].ID, all[1].ID, all[2].ID})
+			assert.Equal(t, int64(2), all[0].FavoriteCount)
+		}
+	})
+
+	t.Run("FindLinksToCheck", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		unchecked := f.work(author, "unchecked")
+		stale := f.work(author, "stale")
+		fresh := f.work(author, "fresh")
+		for _, w := range []*entities.Work{stale, fresh} {
+			checkedAt := now.Add(-time.Hour)
+			if w == fresh {
+				checkedAt = now
+			}
+			w.LinkCheckedAt = &checkedAt
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.UpdateLinkStatus(ctx, w)
+			})
+		}
+		file := &entities.Work{Type: constants.ContentTypeFile, Title: "file", AuthorID: author.ID, ScanStatus: constants.ScanClean}
+		pending := &entities.Work{Type: constants.ContentTypeURL, Title: "pending", AuthorID: author.ID, ScanStatus: constants.ScanPending}
+		for _, w := range []*entities.Work{file, pending} {
+			w := w
+			f.inTransaction(func(ctx context.Context) error {
+				return h.Works.Create(ctx, w)
+			})
+		}
+		deleted := f.work(author, "deleted")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, deleted.ID)
+		})
+
+		works, err := h.Works.FindLinksToCheck(ctx, now.Add(-time.Minute), 0, 10)
+		assert.Nil(t, err)
+		var ids []uint64
+		for _, w := range works {
+			ids = append(ids, w.ID)
+		}
+		assert.Equal(t, []uint64{unchecked.ID, stale.ID}, ids)
+
+		works, err = h.Works.FindLinksToCheck(ctx, now.Add(-time.Minute), 0, 1)
+		assert.Nil(t, err)
+		if assert.Len(t, works, 1) {
+			assert.Equal(t, unchecked.ID, works[0].ID)
+		}
+
+		works, err = h.Works.FindLinksToCheck(ctx, now.Add(-time.Minute), unchecked.ID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, works, 1) {
+			assert.Equal(t, stale.ID, works[0].ID)
+		}
+	})
+
+	t.Run("UpdateLinkStatus", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		ctx := context.Background()
+		w := f.work(f.user("author"), "hoge")
+		checkedAt := time.Now().UTC().Truncate(time.Second)
+		downSince := checkedAt.Add(-72 * time.Hour)
+
+		update := *w
+		update.Title = "ignored"
+		update.LinkStatus = 404
+		update.LinkCheckedAt = &checkedAt
+		update.LinkFailures = 3
+		update.LinkDownSince = &downSince
+		update.LinkBroken = true
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.UpdateLinkStatus(ctx, &update)
+		})
+		assert.Nil(t, err)
+
+		actual, err := h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "hoge", actual.Title)
+			assert.Equal(t, 404, actual.LinkStatus)
+			if assert.NotNil(t, actual.LinkCheckedAt) {
+				assert.True(t, checkedAt.Equal(*actual.LinkCheckedAt), "%v", actual.LinkCheckedAt)
+			}
+			assert.Equal(t, 3, actual.LinkFailures)
+			if assert.NotNil(t, actual.LinkDownSince) {
+				assert.True(t, downSince.Equal(*actual.LinkDownSince), "%v", actual.LinkDownSince)
+			}
+			assert.True(t, actual.LinkBroken)
+			assert.Equal(t, w.Version, actual.Version)
+			assert.True(t, w.UpdatedAt.Equal(actual.UpdatedAt), "%v", actual.UpdatedAt)
+		}
+
+		update.LinkStatus = 200
+		update.LinkFailures = 0
+		update.LinkDownSince = nil
+		update.LinkBroken = false
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.UpdateLinkStatus(ctx, &update)
+		})
+		assert.Nil(t, err)
+
+		actual, err = h.Works.FindByID(ctx, w.ID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, 200, actual.LinkStatus)
+			assert.Zero(t, actual.LinkFailures)
+			assert.Nil(t, actual.LinkDownSince)
+			assert.False(t, actual.LinkBroken)
+		}
+	})
+
+	t.Run("UpdateLinkStatus returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.UpdateLinkStatus(ctx, &entities.Work{ID: 12345})
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("UpdateLinkStatus requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.UpdateLinkStatus(context.Background(), w)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("DeleteByID deletes softly", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := f.work(author, "deleted")
+		f.work(author, "alive")
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, w.ID)
+		})
+		assert.Nil(t, err)
+
+		_, err = h.Works.FindByID(ctx, w.ID)
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+
+		count, err := h.Works.CountAll(ctx, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("DeleteByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, 12345)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("DeleteByID requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.DeleteByID(context.Background(), w.ID)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("PurgeByID deletes the work and its activities", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		w := f.work(author, "purged")
+		f.activity(author, w, time.Now())
+
+		err := h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, w.ID)
+		})
+		assert.Nil(t, err)
+
+		_, err = h.Works.FindByID(ctx, w.ID)
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+
+		acts, err := h.Activities.FindByUserID(ctx, author.ID, author.ID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, acts)
+	})
+
+	t.Run("PurgeByID returns RecordNotFoundError", func(t *testing.T) {
+		h := setup(t)
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.PurgeByID(ctx, 12345)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("PurgeByID requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+
+		err := h.Works.PurgeByID(context.Background(), w.ID)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("FindTrashed, CountTrashed and Restore", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		other := f.user("other")
+		ctx := context.Background()
+		older := f.work(author, "older")
+		newer := f.work(author, "newer")
+		f.work(author, "alive")
+		othersWork := f.work(other, "others")
+		f.inTransaction(func(ctx context.Context) error {
+			for _, w := range []*entities.Work{older, newer, othersWork} {
+				if err := h.Works.DeleteByID(ctx, w.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		actual, err := h.Works.FindTrashed(ctx, author.ID, 0, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, newer.ID, actual[0].ID)
+			assert.Equal(t, older.ID, actual[1].ID)
+			assert.Equal(t, author.ID, actual[0].Author.ID)
+			assert.True(t, actual[0].DeletedAt.Valid)
+		}
+		count, err := h.Works.CountTrashed(ctx, author.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		trashed, err := h.Works.FindTrashedByID(ctx, older.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "older", trashed.Title)
+
+		err = h.TransactionRunner.Run(ctx, func(ctx context.Context) error {
+			return h.Works.Restore(ctx, older.ID)
+		})
+		assert.Nil(t, err)
+
+		restored, err := h.Works.FindByID(ctx, older.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, older.Version, restored.Version)
+		count, err = h.Works.CountTrashed(ctx, author.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("FindTrashedByID returns RecordNotFoundError when the work is not deleted", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "alive")
+
+		_, err := h.Works.FindTrashedByID(context.Background(), w.ID)
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("Restore returns RecordNotFoundError when the work is not deleted", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "alive")
+
+		err := h.TransactionRunner.Run(context.Background(), func(ctx context.Context) error {
+			return h.Works.Restore(ctx, w.ID)
+		})
+
+		var rnfErr *myErr.RecordNotFoundError
+		assert.True(t, errors.As(err, &rnfErr), "%v", err)
+	})
+
+	t.Run("Restore requires a transaction", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		w := f.work(f.user("author"), "hoge")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, w.ID)
+		})
+
+		err := h.Works.Restore(context.Background(), w.ID)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("FindDeletedBefore", func(t *testing.T) {
+		h := setup(t)
+		f := newFixtures(t, h)
+		author := f.user("author")
+		ctx := context.Background()
+		deleted := f.work(author, "deleted")
+		f.work(author, "alive")
+		f.inTransaction(func(ctx context.Context) error {
+			return h.Works.DeleteByID(ctx, deleted.ID)
+		})
+
+		actual, err := h.Works.FindDeletedBefore(ctx, time.Now().Add(time.Minute), 10)
+		assert.Nil(t, err)
+		if assert.Len(t, actual, 1) {
+			assert.Equal(t, deleted.ID, actual[0].ID)
+		}
+
+		actual, err = h.Works.FindDeletedBefore(ctx, time.Now().Add(-time.Minute), 10)
+		assert.Nil(t, err)
+		assert.Empty(t, actual)
+	})
+}
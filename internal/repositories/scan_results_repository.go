@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+type ScanResultsRepository interface {
+	Create(context.Context, *entities.ScanResult) error
+	// FindByWorkID は、作品のファイルの検査結果を登録順に取得する
+	FindByWorkID(context.Context, uint64) ([]*entities.ScanResult, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// TagsRepository は、作品のタグの永続化を表す。タグの名前は正規化済みのものを扱う。
+type TagsRepository interface {
+	// Search は、名前がprefixで始まるタグを、付いた作品の多い順に最大limit件取得する。
+	// 数えるのは誰の一覧にも表示する公開済みかつ公開の作品のみで、それらに付いていないタグは含まない。
+	Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error)
+	// ReplaceWorkTags は、作品のタグをnamesに置き換え、付けたタグをnamesの順に返す。未登録のタグは登録する。
+	ReplaceWorkTags(ctx context.Context, workID uint64, names []string) ([]*entities.Tag, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+type UploadsRepository interface {
+	FindByID(context.Context, string) (*entities.Upload, error)
+	// FindExpired は、指定日時までに期限が切れたアップロードを最大limit件取得する
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Upload, error)
+	Create(context.Context, *entities.Upload) error
+	// UpdateProgress は、受信位置・チャンク数・完了日時を更新する。
+	// 保存されているチャンク数がexpectedChunksと異なる場合は、他の要求が先に更新したとしてRecordNotFoundErrorを返す。
+	UpdateProgress(ctx context.Context, upload *entities.Upload, expectedChunks int) error
+	// FindChunks は、アップロードが受信したチャンクを番号の順に取得する
+	FindChunks(ctx context.Context, uploadID string) ([]*entities.UploadChunk, error)
+	// CreateChunk は、受信したチャンクを記録する。アップロードを削除すると、チャンクの記録も削除する。
+	CreateChunk(context.Context, *entities.UploadChunk) error
+	DeleteByID(context.Context, string) error
+}
@@ -2,10 +2,16 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/entities"
 )
 
 type UsersRepository interface {
 	Save(context.Context, *entities.User) error
+	// FindByID は、ユーザーを取得する。ない場合はRecordNotFoundErrorを返す。
+	FindByID(context.Context, string) (*entities.User, error)
+	// UpdateSuspended は、ユーザーのアカウントを停止した日時を更新する。nilの場合は停止を解除する。
+	// ユーザーがない場合はRecordNotFoundErrorを返す。
+	UpdateSuspended(ctx context.Context, id string, suspendedAt *time.Time) error
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+)
+
+// WorkRevisionsRepository は、作品の履歴の永続化を表す。履歴は登録のみ行い、更新しない。
+type WorkRevisionsRepository interface {
+	Create(context.Context, *entities.WorkRevision) error
+	// FindByWorkID, CountByWorkID は、作品の履歴を対象にする。FindByWorkIDは新しいバージョンから順に取得する。
+	FindByWorkID(ctx context.Context, workID uint64, offset int, limit int) ([]*entities.WorkRevision, error)
+	CountByWorkID(ctx context.Context, workID uint64) (int64, error)
+	// FindByVersion は、作品の指定したバージョンの履歴を取得する。ない場合はRecordNotFoundErrorを返す。
+	FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error)
+}
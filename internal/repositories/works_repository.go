@@ -2,14 +2,57 @@ package repositories
 
 import (
 	"context"
+	"time"
 
+	"github.com/edy4c7/works-uploader/internal/common/constants"
 	"github.com/edy4c7/works-uploader/internal/entities"
 )
 
+// WorksRepository は、作品の永続化を表す。取得する作品は、マルウェアの検査を通過したもののみ。
 type WorksRepository interface {
-	GetAll(ctx context.Context, offset int, limit int) ([]*entities.Work, error)
-	CountAll(context.Context) (int64, error)
+	// GetAll, CountAll は、一覧に表示する作品を対象にする。公開済みかつ公開の作品と、viewerが作者の作品を含む。
+	// viewerが空の場合は、公開の作品のみを対象にする。tagsを指定した場合は、その全てのタグ (正規化済み) が付いた作品に絞り込む。
+	// GetAll は、orderの順に並べる。
+	GetAll(ctx context.Context, viewer string, tags []string, order constants.WorkOrder, offset int, limit int) ([]*entities.Work, error)
+	CountAll(ctx context.Context, viewer string, tags []string) (int64, error)
+	// FindByID は、公開範囲と公開状況に関わらず作品を取得する
 	FindByID(context.Context, uint64) (*entities.Work, error)
 	Create(context.Context, *entities.Work) error
+	// Update は、作品の内容 (公開状況、タイトル、説明、ファイルとリンク先) を更新し、バージョンを1つ進める。
+	// 作品がない、またはバージョンがworkのVersionから変わっている場合はRecordNotFoundErrorを返す。
+	// 公開前の状態に更新する場合、作品が既に公開されていた場合もRecordNotFoundErrorを返す。
+	Update(ctx context.Context, work *entities.Work) error
+	// UpdateScanStatus は、作品のマルウェアの検査状況を更新する。作品がない場合はRecordNotFoundErrorを返す。
+	UpdateScanStatus(context.Context, uint64, constants.ScanStatus) error
+	// UpdateFavoriteCount は、作品のお気に入りの数にdeltaを加える。削除済みの作品も対象にし、更新日時とバージョンは変更しない。
+	// 作品がない場合はRecordNotFoundErrorを返す。
+	UpdateFavoriteCount(ctx context.Context, id uint64, delta int) error
+	// UpdateHidden は、モデレーターが作品を非表示にした日時を更新する。nilの場合は非表示を解除する。
+	// 削除済みの作品も対象にし、更新日時とバージョンは変更しない。作品がない場合はRecordNotFoundErrorを返す。
+	UpdateHidden(ctx context.Context, id uint64, hiddenAt *time.Time) error
+	// FindLinksToCheck は、リンク先をcheckedBeforeより後に確認していないURLの作品を、IDの昇順でafterより後から最大limit件取得する
+	FindLinksToCheck(ctx context.Context, checkedBefore time.Time, after uint64, limit int) ([]*entities.Work, error)
+	// UpdateLinkStatus は、作品のリンク先の確認結果 (Link〜の項目) を更新する。更新日時とバージョンは変更しない。
+	// 作品がない場合はRecordNotFoundErrorを返す。
+	UpdateLinkStatus(context.Context, *entities.Work) error
+	// FindDueToPublish は、公開する日時がnow以前になった公開予定の作品を、公開する日時の順に最大limit件取得する
+	FindDueToPublish(ctx context.Context, now time.Time, limit int) ([]*entities.Work, error)
+	// Publish は、下書きまたは公開予定の作品を公開済みにする。更新日時とバージョンは変更しない。
+	// 作品がない、または既に公開済みの場合はRecordNotFoundErrorを返す。
+	Publish(context.Context, uint64) error
+	// DeleteByID は、作品を論理削除する。削除した作品はゴミ箱から復元できる。
 	DeleteByID(context.Context, uint64) error
+	// PurgeByID は、削除済みかに関わらず作品を物理削除する。作品のアクティビティ、履歴と参照するファイルの記録も削除する。
+	PurgeByID(context.Context, uint64) error
+	// FindTrashed, CountTrashed は、作者が削除した作品を、削除した日時の新しい順に対象にする
+	FindTrashed(ctx context.Context, authorID string, offset int, limit int) ([]*entities.Work, error)
+	CountTrashed(ctx context.Context, authorID string) (int64, error)
+	// FindTrashedByID は、削除済みの作品を取得する。作品がない、または削除されていない場合はRecordNotFoundErrorを返す。
+	FindTrashedByID(context.Context, uint64) (*entities.Work, error)
+	// FindDeletedBefore は、beforeより前に削除した作品を、削除した日時の順に最大limit件取得する。
+	// 物理削除するため、マルウェアの検査を通過していない作品も含む。
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Work, error)
+	// Restore は、削除済みの作品を復元する。更新日時とバージョンは変更しない。
+	// 作品がない、または削除されていない場合はRecordNotFoundErrorを返す。
+	Restore(context.Context, uint64) error
 }
@@ -65,11 +65,13 @@ func TestGetAllActivities(t *testing.T) {
 	t.Run("is valid", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
+		// ログインしている場合は、自分の作品のアクティビティも取得する
+		ctx = setupContext(ctx)
 
 		repo := mocks.NewMockActivitiesRepository(ctrl)
 		expect := activitiesTestData
 		limit := 20
-		repo.EXPECT().GetAll(ctx, limit).Return(expect, nil)
+		repo.EXPECT().GetAll(ctx, subject, limit).Return(expect, nil)
 
 		service := &ActivitiesServiceImpl{
 			repository: repo,
@@ -87,7 +89,7 @@ func TestGetAllActivities(t *testing.T) {
 
 		repo := mocks.NewMockActivitiesRepository(ctrl)
 		errExpect := errors.New("error")
-		repo.EXPECT().GetAll(ctx, gomock.Any()).Return(nil, errExpect)
+		repo.EXPECT().GetAll(ctx, "", gomock.Any()).Return(nil, errExpect)
 
 		service := &ActivitiesServiceImpl{
 			repository: repo,
@@ -117,7 +119,7 @@ func TestFindActivitiesByUserID(t *testing.T) {
 		}
 		userID := "user"
 		limit := 10
-		repo.EXPECT().FindByUserID(ctx, userID, limit).Return(expect, nil)
+		repo.EXPECT().FindByUserID(ctx, "", userID, limit).Return(expect, nil)
 
 		service := &ActivitiesServiceImpl{
 			repository: repo,
@@ -136,7 +138,7 @@ func TestFindActivitiesByUserID(t *testing.T) {
 		repo := mocks.NewMockActivitiesRepository(ctrl)
 		errExpect := myErr.NewRecordNotFoundError("", nil)
 		userID := "user"
-		repo.EXPECT().FindByUserID(ctx, userID, gomock.Any()).Return(nil, errExpect)
+		repo.EXPECT().FindByUserID(ctx, "", userID, gomock.Any()).Return(nil, errExpect)
 
 		service := &ActivitiesServiceImpl{
 			repository: repo,
@@ -161,7 +163,7 @@ func TestFindActivitiesByUserID(t *testing.T) {
 		repo := mocks.NewMockActivitiesRepository(ctrl)
 		errExpect := errors.New("error")
 		userID := "user"
-		repo.EXPECT().FindByUserID(ctx, userID, gomock.Any()).Return(nil, errExpect)
+		repo.EXPECT().FindByUserID(ctx, "", userID, gomock.Any()).Return(nil, errExpect)
 
 		service := &ActivitiesServiceImpl{
 			repository: repo,
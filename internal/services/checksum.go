@@ -0,0 +1,43 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"path/filepath"
+)
+
+// digestReader は、読み込んだ内容のSHA-256とバイト数を計算する
+type digestReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	size   int64
+}
+
+func newDigestReader(r io.Reader) *digestReader {
+	return &digestReader{reader: r, hash: sha256.New()}
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+	r.size += int64(n)
+	return n, err
+}
+
+// sum は、それまでに読み込んだ内容のSHA-256を16進数で返す
+func (r *digestReader) sum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// sha256Hex は、内容のSHA-256を16進数で返す
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// contentAddressedKey は、重複排除する場合の公開後のキーを返す。同じ内容で拡張子が同じファイルは同じキーになる。
+func contentAddressedKey(sha256 string, key string) string {
+	return sha256 + filepath.Ext(key)
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgCollectionsRepository = "collections repository"
+
+// fieldCoverWorkID, fieldWorkIDs は、コレクションの表紙と作品の並び順のフォーム項目名
+const fieldCoverWorkID = "coverWorkId"
+const fieldWorkIDs = "workIds"
+
+// CollectionsService は、作品のコレクションの管理機能のインターフェースを定義する。
+// コレクションは作品と同様の公開範囲を持ち、非公開のコレクションは所有者以外には存在しないものとしてWUE01を返す。
+// コレクションの変更は、所有者のみが行える。
+type CollectionsService interface {
+	// GetAll は、公開のコレクションと、閲覧しているユーザーのコレクションを取得する。
+	// ownerIDを指定した場合は、そのユーザーのコレクションに絞り込む。
+	GetAll(ctx context.Context, ownerID string, offset int, limit int) (*beans.PaginationBean, error)
+	FindByID(context.Context, uint64) (*entities.Collection, error)
+	// Create は、ログイン中のユーザーが所有する空のコレクションを登録する
+	Create(context.Context, *beans.CollectionFormBean) (*entities.Collection, error)
+	Update(context.Context, uint64, *beans.CollectionFormBean) (*entities.Collection, error)
+	// DeleteByID は、コレクションを削除する。含まれる作品は削除しない。
+	DeleteByID(context.Context, uint64) error
+	// GetWorks は、コレクションの作品のうち、閲覧しているユーザーが一覧で閲覧できるものを並び順に取得する
+	GetWorks(ctx context.Context, id uint64, offset int, limit int) (*beans.PaginationBean, error)
+	// AddWork は、所有者の作品をコレクションの末尾に追加し、アクティビティとして登録する。追加済みの場合は何もしない。
+	AddWork(ctx context.Context, id uint64, workID uint64) error
+	// RemoveWork は、作品をコレクションから取り除く。表紙の作品の場合は、表紙なしにする。
+	RemoveWork(ctx context.Context, id uint64, workID uint64) error
+	// Reorder は、workIDsの作品をその順にコレクションの先頭に並べる。指定しなかった作品は、現在の順でその後に並べる。
+	Reorder(ctx context.Context, id uint64, workIDs []uint64) error
+}
+
+// CollectionsServiceImpl は、作品のコレクションの管理機能を実装する
+type CollectionsServiceImpl struct {
+	transactionRunner     repositories.TransactionRunner
+	collectionsRepository repositories.CollectionsRepository
+	worksRepository       repositories.WorksRepository
+	activitiesRepository  repositories.ActivitiesRepository
+	fileUploader          lib.StorageClient
+	privateURLExpiration  time.Duration
+}
+
+// NewCollectionsServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、CollectionsServiceImplの新しいインスタンスを生成する。
+// privateURLExpirationは、非公開の作品のファイルを閲覧するための署名付きURLの有効期間。
+func NewCollectionsServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	collectionsRepo repositories.CollectionsRepository,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+	fileUploader lib.StorageClient,
+	privateURLExpiration time.Duration,
+) *CollectionsServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if collectionsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgCollectionsRepository))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &CollectionsServiceImpl{
+		transactionRunner:     tranRnr,
+		collectionsRepository: collectionsRepo,
+		worksRepository:       worksRepo,
+		activitiesRepository:  actRepo,
+		fileUploader:          fileUploader,
+		privateURLExpiration:  privateURLExpiration,
+	}
+}
+
+//GetAll は、コレクションの一覧を取得する
+func (r *CollectionsServiceImpl) GetAll(ctx context.Context, ownerID string, offset int, limit int) (*beans.PaginationBean, error) {
+	viewer := viewerOf(ctx)
+	count, err := r.collectionsRepository.CountAll(ctx, viewer, ownerID)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.collectionsRepository.GetAll(ctx, viewer, ownerID, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		if err := r.prepareCover(ctx, v); err != nil {
+			return nil, err
+		}
+		pagination.Items = append(pagination.Items, v)
+	}
+
+	return pagination, nil
+}
+
+//FindByID は、指定したIDのコレクションを取得する
+func (r *CollectionsServiceImpl) FindByID(ctx context.Context, id uint64) (*entities.Collection, error) {
+	c, err := r.findViewableCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.prepareCover(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+//Create は、コレクションを登録する
+func (r *CollectionsServiceImpl) Create(ctx context.Context, bean *beans.CollectionFormBean) (*entities.Collection, error) {
+	owner, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	// 登録時のコレクションは空のため、表紙にできる作品はない
+	if bean.CoverWorkID != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldCoverWorkID))
+	}
+
+	c := &entities.Collection{
+		OwnerID:     owner,
+		Title:       bean.Title,
+		Description: bean.Description,
+		Visibility:  bean.Visibility,
+	}
+	if c.Visibility == 0 {
+		c.Visibility = constants.VisibilityPublic
+	}
+
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.collectionsRepository.Create(ctx, c)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return c, nil
+}
+
+//Update は、コレクションのタイトル、説明、公開範囲と表紙を更新する
+func (r *CollectionsServiceImpl) Update(ctx context.Context, id uint64, bean *beans.CollectionFormBean) (*entities.Collection, error) {
+	c, err := r.findOwnedCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if bean.CoverWorkID != nil {
+		contains, err := r.collectionsRepository.ContainsWork(ctx, id, *bean.CoverWorkID)
+		if err != nil {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+		if !contains {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldCoverWorkID))
+		}
+	}
+
+	c.Title = bean.Title
+	c.Description = bean.Description
+	if bean.Visibility != 0 {
+		c.Visibility = bean.Visibility
+	}
+	c.CoverWorkID = bean.CoverWorkID
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.collectionsRepository.Update(ctx, c)
+	})
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	// 表紙の作品を読み込み直す
+	return r.FindByID(ctx, id)
+}
+
+//DeleteByID は、指定したIDのコレクションを削除する
+func (r *CollectionsServiceImpl) DeleteByID(ctx context.Context, id uint64) error {
+	if _, err := r.findOwnedCollection(ctx, id); err != nil {
+		return err
+	}
+
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.collectionsRepository.DeleteByID(ctx, id)
+	})
+	if err != nil {
+		return collectionError(err)
+	}
+	return nil
+}
+
+//GetWorks は、コレクションの作品を取得する
+func (r *CollectionsServiceImpl) GetWorks(ctx context.Context, id uint64, offset int, limit int) (*beans.PaginationBean, error) {
+	if _, err := r.findViewableCollection(ctx, id); err != nil {
+		return nil, err
+	}
+
+	viewer := viewerOf(ctx)
+	count, err := r.collectionsRepository.CountWorks(ctx, viewer, id)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.collectionsRepository.FindWorks(ctx, viewer, id, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		if err := signFileURLs(r.fileUploader, r.privateURLExpiration, v); err != nil {
+			return nil, err
+		}
+		pagination.Items = append(pagination.Items, v)
+	}
+
+	return pagination, nil
+}
+
+//AddWork は、作品をコレクションに追加する
+func (r *CollectionsServiceImpl) AddWork(ctx context.Context, id uint64, workID uint64) error {
+	c, err := r.findOwnedCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+	// コレクションには、所有者が作者の作品のみ追加できる
+	w, _, err := findEditableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return err
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		added, err := r.collectionsRepository.AddWork(ctx, c.ID, w.ID)
+		if err != nil || !added {
+			return err
+		}
+
+		act := &entities.Activity{
+			Type:         constants.ActivityAddedToCollection,
+			UserID:       c.OwnerID,
+			WorkID:       w.ID,
+			CollectionID: &c.ID,
+		}
+		return r.activitiesRepository.Create(ctx, act)
+	})
+	if err != nil {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
+
+//RemoveWork は、作品をコレクションから取り除く
+func (r *CollectionsServiceImpl) RemoveWork(ctx context.Context, id uint64, workID uint64) error {
+	c, err := r.findOwnedCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.collectionsRepository.RemoveWork(ctx, id, workID); err != nil {
+			return err
+		}
+		if c.CoverWorkID == nil || *c.CoverWorkID != workID {
+			return nil
+		}
+		c.CoverWorkID = nil
+		return r.collectionsRepository.Update(ctx, c)
+	})
+	if err != nil {
+		return collectionError(err)
+	}
+	return nil
+}
+
+//Reorder は、コレクションの作品を並べ替える
+func (r *CollectionsServiceImpl) Reorder(ctx context.Context, id uint64, workIDs []uint64) error {
+	if _, err := r.findOwnedCollection(ctx, id); err != nil {
+		return err
+	}
+	seen := make(map[uint64]bool, len(workIDs))
+	for _, v := range workIDs {
+		if seen[v] {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldWorkIDs))
+		}
+		seen[v] = true
+	}
+
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.collectionsRepository.Reorder(ctx, id, workIDs)
+	})
+	if err != nil {
+		// コレクションに含まれない作品を指定した場合は、並び順の指定が不正なものとする
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldWorkIDs), myErr.Cause(err))
+		}
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
+
+// findViewableCollection は、閲覧しているユーザーが閲覧できるコレクションを取得する。
+// 非公開のコレクションの有無を所有者以外に知らせないよう、閲覧できない場合はWUE01を返す。
+func (r *CollectionsServiceImpl) findViewableCollection(ctx context.Context, id uint64) (*entities.Collection, error) {
+	c, err := r.collectionsRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+	if c.Visibility == constants.VisibilityPrivate && c.OwnerID != viewerOf(ctx) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01))
+	}
+	return c, nil
+}
+
+// findOwnedCollection は、ログイン中のユーザーが変更するコレクションを取得する。所有者でない場合はWUE02を返す。
+func (r *CollectionsServiceImpl) findOwnedCollection(ctx context.Context, id uint64) (*entities.Collection, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	c, err := r.findViewableCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.OwnerID != sub {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+	return c, nil
+}
+
+// prepareCover は、閲覧しているユーザーが閲覧できない表紙の作品を取り除き、非公開の作品のファイルのURLを署名付きURLに置き換える
+func (r *CollectionsServiceImpl) prepareCover(ctx context.Context, c *entities.Collection) error {
+	if c.Cover == nil {
+		return nil
+	}
+	hidden := c.Cover.Visibility == constants.VisibilityPrivate || c.Cover.Status != constants.WorkPublished || c.Cover.HiddenAt != nil
+	if hidden && c.Cover.AuthorID != viewerOf(ctx) {
+		c.Cover = nil
+		return nil
+	}
+	return signFileURLs(r.fileUploader, r.privateURLExpiration, c.Cover)
+}
+
+// collectionError は、リポジトリのエラーを、コレクションがない場合はWUE01、それ以外はWUE99に変換する
+func collectionError(err error) error {
+	var dbErr *myErr.RecordNotFoundError
+	if errors.As(err, &dbErr) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+	}
+	return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+}
@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollectionsServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewCollectionsServiceImpl(tr, collectionsRepo, worksRepo, actRepo, uploader, time.Minute)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.collectionsRepository, collectionsRepo)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+		assert.Same(t, service.fileUploader, uploader)
+		assert.Equal(t, time.Minute, service.privateURLExpiration)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewCollectionsServiceImpl(nil, collectionsRepo, worksRepo, actRepo, uploader, 0) }},
+			{"Collections repository", func() { NewCollectionsServiceImpl(tr, nil, worksRepo, actRepo, uploader, 0) }},
+			{"Works repository", func() { NewCollectionsServiceImpl(tr, collectionsRepo, nil, actRepo, uploader, 0) }},
+			{"Activities repository", func() { NewCollectionsServiceImpl(tr, collectionsRepo, worksRepo, nil, uploader, 0) }},
+			{"File uploader", func() { NewCollectionsServiceImpl(tr, collectionsRepo, worksRepo, actRepo, nil, 0) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// newCollectionsService は、トランザクションを実行するTransactionRunnerを使用したCollectionsServiceImplを生成する
+func newCollectionsService(ctrl *gomock.Controller, collectionsRepo *mocks.MockCollectionsRepository,
+	worksRepo *mocks.MockWorksRepository, actRepo *mocks.MockActivitiesRepository) *CollectionsServiceImpl {
+
+	return NewCollectionsServiceImpl(runTransactions(ctrl), collectionsRepo, worksRepo, actRepo,
+		mocks.NewMockStorageClient(ctrl), time.Minute)
+}
+
+func TestCollectionsGetAll(t *testing.T) {
+	t.Run("Hides the cover that can't be viewed", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		public := &entities.Collection{ID: 1, OwnerID: "owner", Cover: publicWork(10, "owner")}
+		draft := &entities.Collection{ID: 2, OwnerID: "owner", Cover: publicWork(11, "owner")}
+		draft.Cover.Status = constants.WorkDraft
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().CountAll(ctx, "", "owner").Return(int64(2), nil)
+		collectionsRepo.EXPECT().GetAll(ctx, "", "owner", 0, 10).Return([]*entities.Collection{public, draft}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetAll(ctx, "owner", 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 2,
+			Offset:     0,
+			Items:      []interface{}{public, draft},
+		}, actual)
+		assert.NotNil(t, public.Cover)
+		assert.Nil(t, draft.Cover)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		errExpect := errors.New("error")
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().CountAll(ctx, "", "").Return(int64(0), errExpect)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetAll(ctx, "", 0, 10)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestCollectionsFindByID(t *testing.T) {
+	t.Run("Private collection of the viewer", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := &entities.Collection{ID: 1, OwnerID: subject, Visibility: constants.VisibilityPrivate}
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(expect, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.FindByID(ctx, 1)
+
+		assert.Nil(t, err)
+		assert.Same(t, expect, actual)
+	})
+
+	t.Run("Private collection of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: "owner", Visibility: constants.VisibilityPrivate,
+		}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.FindByID(ctx, 1)
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		errExpect := myErr.NewRecordNotFoundError("", nil)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(nil, errExpect)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.FindByID(ctx, 1)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestCollectionsCreate(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := &entities.Collection{
+			OwnerID:     subject,
+			Title:       "hoge",
+			Description: "hogehoge",
+			Visibility:  constants.VisibilityPublic,
+		}
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().Create(gomock.Any(), expect).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Create(ctx, &beans.CollectionFormBean{Title: "hoge", Description: "hogehoge"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("Cover is specified", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var cover uint64 = 1
+		service := newCollectionsService(ctrl, mocks.NewMockCollectionsRepository(ctrl),
+			mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Create(ctx, &beans.CollectionFormBean{Title: "hoge", CoverWorkID: &cover})
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newCollectionsService(ctrl, mocks.NewMockCollectionsRepository(ctrl),
+			mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Create(ctx, &beans.CollectionFormBean{Title: "hoge"})
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestCollectionsUpdate(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var cover uint64 = 10
+		current := &entities.Collection{ID: 1, OwnerID: subject, Title: "hoge", Visibility: constants.VisibilityUnlisted}
+		updated := &entities.Collection{ID: 1, OwnerID: subject, Title: "fuga", CoverWorkID: &cover}
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		gomock.InOrder(
+			collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(current, nil),
+			collectionsRepo.EXPECT().ContainsWork(ctx, uint64(1), cover).Return(true, nil),
+			// 公開範囲を指定しない場合は変更しない
+			collectionsRepo.EXPECT().Update(gomock.Any(), &entities.Collection{
+				ID:          1,
+				OwnerID:     subject,
+				Title:       "fuga",
+				Description: "fugafuga",
+				Visibility:  constants.VisibilityUnlisted,
+				CoverWorkID: &cover,
+			}).Return(nil),
+			collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(updated, nil),
+		)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Update(ctx, 1, &beans.CollectionFormBean{
+			Title:       "fuga",
+			Description: "fugafuga",
+			CoverWorkID: &cover,
+		})
+
+		assert.Nil(t, err)
+		assert.Same(t, updated, actual)
+	})
+
+	t.Run("Cover is not in the collection", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var cover uint64 = 10
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().ContainsWork(ctx, uint64(1), cover).Return(false, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Update(ctx, 1, &beans.CollectionFormBean{Title: "fuga", CoverWorkID: &cover})
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Not the owner", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: "owner", Visibility: constants.VisibilityPublic,
+		}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Update(ctx, 1, &beans.CollectionFormBean{Title: "fuga"})
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+}
+
+func TestCollectionsDeleteByID(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().DeleteByID(gomock.Any(), uint64(1)).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assert.Nil(t, service.DeleteByID(ctx, 1))
+	})
+
+	t.Run("Not the owner", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: "owner", Visibility: constants.VisibilityPublic,
+		}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE02, service.DeleteByID(ctx, 1))
+	})
+}
+
+func TestCollectionsGetWorks(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		works := []*entities.Work{{ID: 10}, {ID: 11}}
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: "owner", Visibility: constants.VisibilityUnlisted,
+		}, nil)
+		collectionsRepo.EXPECT().CountWorks(ctx, "", uint64(1)).Return(int64(2), nil)
+		collectionsRepo.EXPECT().FindWorks(ctx, "", uint64(1), 0, 10).Return(works, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetWorks(ctx, 1, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 2,
+			Offset:     0,
+			Items:      []interface{}{works[0], works[1]},
+		}, actual)
+	})
+
+	t.Run("Private collection of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: "owner", Visibility: constants.VisibilityPrivate,
+		}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetWorks(ctx, 1, 0, 10)
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestCollectionsAddWork(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var id uint64 = 1
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, id).Return(&entities.Collection{ID: id, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().AddWork(gomock.Any(), id, uint64(10)).Return(true, nil)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(10)).Return(publicWork(10, subject), nil)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Any(), &entities.Activity{
+			Type:         constants.ActivityAddedToCollection,
+			UserID:       subject,
+			WorkID:       10,
+			CollectionID: &id,
+		}).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, worksRepo, actRepo)
+
+		assert.Nil(t, service.AddWork(ctx, id, 10))
+	})
+
+	t.Run("Already added", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().AddWork(gomock.Any(), uint64(1), uint64(10)).Return(false, nil)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(10)).Return(publicWork(10, subject), nil)
+		// 追加済みの場合は、アクティビティを登録しない
+		service := newCollectionsService(ctrl, collectionsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		assert.Nil(t, service.AddWork(ctx, 1, 10))
+	})
+
+	t.Run("Work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(10)).Return(publicWork(10, "author"), nil)
+		service := newCollectionsService(ctrl, collectionsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE02, service.AddWork(ctx, 1, 10))
+	})
+}
+
+func TestCollectionsRemoveWork(t *testing.T) {
+	t.Run("Removes the cover", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var cover uint64 = 10
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: subject, CoverWorkID: &cover,
+		}, nil)
+		collectionsRepo.EXPECT().RemoveWork(gomock.Any(), uint64(1), cover).Return(nil)
+		collectionsRepo.EXPECT().Update(gomock.Any(), &entities.Collection{ID: 1, OwnerID: subject}).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assert.Nil(t, service.RemoveWork(ctx, 1, cover))
+	})
+
+	t.Run("Keeps the cover", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var cover uint64 = 10
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{
+			ID: 1, OwnerID: subject, CoverWorkID: &cover,
+		}, nil)
+		collectionsRepo.EXPECT().RemoveWork(gomock.Any(), uint64(1), uint64(11)).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assert.Nil(t, service.RemoveWork(ctx, 1, 11))
+	})
+
+	t.Run("Not in the collection", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().RemoveWork(gomock.Any(), uint64(1), uint64(11)).
+			Return(myErr.NewRecordNotFoundError("", nil))
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE01, service.RemoveWork(ctx, 1, 11))
+	})
+}
+
+func TestCollectionsReorder(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().Reorder(gomock.Any(), uint64(1), []uint64{12, 10}).Return(nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assert.Nil(t, service.Reorder(ctx, 1, []uint64{12, 10}))
+	})
+
+	t.Run("Duplicated works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE00, service.Reorder(ctx, 1, []uint64{10, 10}))
+	})
+
+	t.Run("Not in the collection", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Collection{ID: 1, OwnerID: subject}, nil)
+		collectionsRepo.EXPECT().Reorder(gomock.Any(), uint64(1), []uint64{99}).
+			Return(myErr.NewRecordNotFoundError("", nil))
+		service := newCollectionsService(ctrl, collectionsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE00, service.Reorder(ctx, 1, []uint64{99}))
+	})
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+)
+
+// maxCommentLength は、コメントの本文の最大文字数
+const maxCommentLength = 1000
+
+// fieldBody は、コメントの本文のフォーム項目名
+const fieldBody = "body"
+
+// コメントの本文に使用できないMarkdownの記法。本文はそのまま保存し、表示する側で描画するため、
+// 埋め込みや見出しでページの表示を崩せないよう、HTML、画像、見出し、表を使用できないものとする。
+var (
+	htmlTagPattern  = regexp.MustCompile(`<[A-Za-z!/?]`)
+	imagePattern    = regexp.MustCompile(`!\[[^\]]*\]\(`)
+	headingPattern  = regexp.MustCompile(`(?m)^ {0,3}(#{1,6}(\s|$)|=+\s*$|-{2,}\s*$)`)
+	tablePattern    = regexp.MustCompile(`(?m)^\s*\|.*\|\s*$`)
+	linkDestPattern = regexp.MustCompile(`\]\(\s*<?([^)\s>]*)`)
+)
+
+// allowedLinkSchemes は、コメントのリンク先に使用できるスキーム
+var allowedLinkSchemes = []string{"http://", "https://", "mailto:"}
+
+// normalizeCommentBody は、コメントの本文の改行をLFに揃えて前後の空白を取り除き、使用できる記法のみであることを検証する。
+// 空の場合はWUE00、長すぎる場合はWUE08、使用できない記法を含む場合はWUE09を返す。
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return "", myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldBody))
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", myErr.NewApplicationError(myErr.Code(myErr.WUE08), myErr.MessageParams(fieldBody, maxCommentLength))
+	}
+
+	for _, p := range []*regexp.Regexp{htmlTagPattern, imagePattern, headingPattern, tablePattern} {
+		if p.MatchString(body) {
+			return "", myErr.NewApplicationError(myErr.Code(myErr.WUE09), myErr.MessageParams(fieldBody))
+		}
+	}
+	for _, m := range linkDestPattern.FindAllStringSubmatch(body, -1) {
+		if !allowedLink(m[1]) {
+			return "", myErr.NewApplicationError(myErr.Code(myErr.WUE09), myErr.MessageParams(fieldBody))
+		}
+	}
+	return body, nil
+}
+
+// allowedLink は、リンク先がhttp、https、mailtoのいずれかのスキームのURLかを返す
+func allowedLink(dest string) bool {
+	dest = strings.ToLower(dest)
+	for _, s := range allowedLinkSchemes {
+		if strings.HasPrefix(dest, s) {
+			return true
+		}
+	}
+	return false
+}
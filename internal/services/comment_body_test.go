@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCommentBody(t *testing.T) {
+	t.Run("Allowed markup", func(t *testing.T) {
+		for expect, body := range map[string]string{
+			"hoge":                                "  hoge \r\n",
+			"**bold** and _em_":                   "**bold** and _em_",
+			"`code`\n\n> quote\n\n- item":         "`code`\r\n\r\n> quote\r\n\r\n- item",
+			"[link](https://example.com/a)":       "[link](https://example.com/a)",
+			"[mail](mailto:hoge@example.com)":     "[mail](mailto:hoge@example.com)",
+			"1 < 2 and 3 > 2":                     "1 < 2 and 3 > 2",
+			"#hashtag":                            "#hashtag",
+			strings.Repeat("あ", maxCommentLength): strings.Repeat("あ", maxCommentLength),
+		} {
+			actual, err := normalizeCommentBody(body)
+			assert.Nil(t, err, body)
+			assert.Equal(t, expect, actual)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := normalizeCommentBody(" \r\n ")
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Too long", func(t *testing.T) {
+		_, err := normalizeCommentBody(strings.Repeat("あ", maxCommentLength+1))
+		assertErrorCode(t, myErr.WUE08, err)
+	})
+
+	t.Run("Unsupported markup", func(t *testing.T) {
+		for _, body := range []string{
+			"<script>alert(1)</script>",
+			"<!-- comment -->",
+			"![image](https://example.com/a.png)",
+			"# heading",
+			"heading\n===",
+			"| a | b |",
+			"[link](javascript:alert(1))",
+			"[link](/relative)",
+		} {
+			_, err := normalizeCommentBody(body)
+			assertErrorCode(t, myErr.WUE09, err)
+		}
+	})
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgCommentsRepository = "comments repository"
+
+// fieldParentID は、返信先のコメントのフォーム項目名
+const fieldParentID = "parentId"
+
+// CommentsService は、作品へのコメントの機能のインターフェースを定義する。
+// コメントは作品を閲覧できるユーザーのみが閲覧・投稿でき、閲覧できない作品のコメントはWUE01を返す。
+// 編集は投稿したユーザーのみ、削除は投稿したユーザーと作品の作者のみが行える。
+type CommentsService interface {
+	// GetAll は、作品へのコメントのうち返信でないものを、投稿した日時の古い順に取得する
+	GetAll(ctx context.Context, workID uint64, offset int, limit int) (*beans.PaginationBean, error)
+	// GetReplies は、コメントへの返信を、投稿した日時の古い順に取得する
+	GetReplies(ctx context.Context, id uint64, offset int, limit int) (*beans.PaginationBean, error)
+	// Create は、ログイン中のユーザーのコメントを投稿し、アクティビティとして登録する。
+	// 返信先には、同じ作品への返信でないコメントのみ指定できる。
+	Create(ctx context.Context, workID uint64, bean *beans.CommentFormBean) (*entities.Comment, error)
+	// Update は、コメントの本文を更新する
+	Update(ctx context.Context, id uint64, bean *beans.CommentFormBean) (*entities.Comment, error)
+	// DeleteByID は、コメントを削除する。返信と、投稿したアクティビティも削除する。
+	DeleteByID(context.Context, uint64) error
+}
+
+// CommentsServiceImpl は、作品へのコメントの機能を実装する
+type CommentsServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	commentsRepository   repositories.CommentsRepository
+	worksRepository      repositories.WorksRepository
+	activitiesRepository repositories.ActivitiesRepository
+}
+
+// NewCommentsServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、CommentsServiceImplの新しいインスタンスを生成する。
+func NewCommentsServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	commentsRepo repositories.CommentsRepository,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+) *CommentsServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if commentsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgCommentsRepository))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+
+	return &CommentsServiceImpl{
+		transactionRunner:    tranRnr,
+		commentsRepository:   commentsRepo,
+		worksRepository:      worksRepo,
+		activitiesRepository: actRepo,
+	}
+}
+
+//GetAll は、作品へのコメントを取得する
+func (r *CommentsServiceImpl) GetAll(ctx context.Context, workID uint64, offset int, limit int) (*beans.PaginationBean, error) {
+	if _, err := findViewableWork(ctx, r.worksRepository, workID); err != nil {
+		return nil, err
+	}
+
+	count, err := r.commentsRepository.CountByWorkID(ctx, workID)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.commentsRepository.FindByWorkID(ctx, workID, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	return commentsPage(count, offset, result), nil
+}
+
+//GetReplies は、コメントへの返信を取得する
+func (r *CommentsServiceImpl) GetReplies(ctx context.Context, id uint64, offset int, limit int) (*beans.PaginationBean, error) {
+	if _, _, err := r.findViewableComment(ctx, id); err != nil {
+		return nil, err
+	}
+
+	count, err := r.commentsRepository.CountReplies(ctx, id)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.commentsRepository.FindReplies(ctx, id, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	return commentsPage(count, offset, result), nil
+}
+
+//Create は、作品にコメントを投稿する
+func (r *CommentsServiceImpl) Create(ctx context.Context, workID uint64, bean *beans.CommentFormBean) (*entities.Comment, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	if _, err := findViewableWork(ctx, r.worksRepository, workID); err != nil {
+		return nil, err
+	}
+	body, err := normalizeCommentBody(bean.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	actType := constants.ActivityCommented
+	if bean.ParentID != nil {
+		// 返信は1階層までとし、返信への返信は受け付けない
+		parent, err := r.commentsRepository.FindByID(ctx, *bean.ParentID)
+		if err != nil {
+			var dbErr *myErr.RecordNotFoundError
+			if errors.As(err, &dbErr) {
+				return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldParentID), myErr.Cause(err))
+			}
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+		if parent.WorkID != workID || parent.ParentID != nil {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldParentID))
+		}
+		actType = constants.ActivityReplied
+	}
+
+	c := &entities.Comment{
+		WorkID:   workID,
+		AuthorID: sub,
+		ParentID: bean.ParentID,
+		Body:     body,
+	}
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.commentsRepository.Create(ctx, c); err != nil {
+			return err
+		}
+
+		act := &entities.Activity{
+			Type:      actType,
+			UserID:    sub,
+			WorkID:    workID,
+			CommentID: &c.ID,
+		}
+		return r.activitiesRepository.Create(ctx, act)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	// 投稿したユーザーを読み込む
+	return r.find(ctx, c.ID)
+}
+
+//Update は、コメントの本文を更新する
+func (r *CommentsServiceImpl) Update(ctx context.Context, id uint64, bean *beans.CommentFormBean) (*entities.Comment, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	c, _, err := r.findViewableComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.AuthorID != sub {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+	body, err := normalizeCommentBody(bean.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Body = body
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.commentsRepository.Update(ctx, c)
+	})
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return r.find(ctx, id)
+}
+
+//DeleteByID は、指定したIDのコメントを削除する
+func (r *CommentsServiceImpl) DeleteByID(ctx context.Context, id uint64) error {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	c, w, err := r.findViewableComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 作品の作者は、自分の作品へのコメントを削除できる
+	if c.AuthorID != sub && w.AuthorID != sub {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.commentsRepository.DeleteByID(ctx, id)
+	})
+	if err != nil {
+		return commentError(err)
+	}
+	return nil
+}
+
+// find は、コメントを取得する。ない場合はWUE01を返す。
+func (r *CommentsServiceImpl) find(ctx context.Context, id uint64) (*entities.Comment, error) {
+	c, err := r.commentsRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, commentError(err)
+	}
+	return c, nil
+}
+
+// findViewableComment は、閲覧しているユーザーが作品を閲覧できるコメントを、作品とともに取得する。
+// 作品を閲覧できない場合は、コメントがない場合と同様にWUE01を返す。
+func (r *CommentsServiceImpl) findViewableComment(ctx context.Context, id uint64) (*entities.Comment, *entities.Work, error) {
+	c, err := r.find(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := findViewableWork(ctx, r.worksRepository, c.WorkID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, w, nil
+}
+
+// commentsPage は、コメントのページを生成する
+func commentsPage(count int64, offset int, comments []*entities.Comment) *beans.PaginationBean {
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(comments)),
+	}
+	for _, v := range comments {
+		pagination.Items = append(pagination.Items, v)
+	}
+	return pagination
+}
+
+// commentError は、リポジトリのエラーを、コメントがない場合はWUE01、それ以外はWUE99に変換する
+func commentError(err error) error {
+	var dbErr *myErr.RecordNotFoundError
+	if errors.As(err, &dbErr) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+	}
+	return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommentsServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+
+		service := NewCommentsServiceImpl(tr, commentsRepo, worksRepo, actRepo)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.commentsRepository, commentsRepo)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewCommentsServiceImpl(nil, commentsRepo, worksRepo, actRepo) }},
+			{"Comments repository", func() { NewCommentsServiceImpl(tr, nil, worksRepo, actRepo) }},
+			{"Works repository", func() { NewCommentsServiceImpl(tr, commentsRepo, nil, actRepo) }},
+			{"Activities repository", func() { NewCommentsServiceImpl(tr, commentsRepo, worksRepo, nil) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// newCommentsService は、トランザクションを実行するTransactionRunnerを使用したCommentsServiceImplを生成する
+func newCommentsService(ctrl *gomock.Controller, commentsRepo *mocks.MockCommentsRepository,
+	worksRepo *mocks.MockWorksRepository, actRepo *mocks.MockActivitiesRepository) *CommentsServiceImpl {
+
+	return NewCommentsServiceImpl(runTransactions(ctrl), commentsRepo, worksRepo, actRepo)
+}
+
+func TestCommentsGetAll(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		comments := []*entities.Comment{{ID: 1, WorkID: 1, ReplyCount: 2}, {ID: 3, WorkID: 1}}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().CountByWorkID(ctx, uint64(1)).Return(int64(12), nil)
+		commentsRepo.EXPECT().FindByWorkID(ctx, uint64(1), 10, 2).Return(comments, nil)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetAll(ctx, 1, 10, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 12,
+			Offset:     10,
+			Items:      []interface{}{comments[0], comments[1]},
+		}, actual)
+	})
+
+	t.Run("Private work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, "author")
+		w.Visibility = constants.VisibilityPrivate
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(w, nil)
+		service := newCommentsService(ctrl, mocks.NewMockCommentsRepository(ctrl), worksRepo,
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		errExpect := errors.New("error")
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().CountByWorkID(ctx, uint64(1)).Return(int64(0), errExpect)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestCommentsGetReplies(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		var parentID uint64 = 1
+		replies := []*entities.Comment{{ID: 2, WorkID: 1, ParentID: &parentID}}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Comment{ID: 1, WorkID: 1}, nil)
+		commentsRepo.EXPECT().CountReplies(ctx, uint64(1)).Return(int64(1), nil)
+		commentsRepo.EXPECT().FindReplies(ctx, uint64(1), 0, 10).Return(replies, nil)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetReplies(ctx, 1, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{TotalItems: 1, Items: []interface{}{replies[0]}}, actual)
+	})
+
+	t.Run("Comment not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(1)).Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+		service := newCommentsService(ctrl, commentsRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.GetReplies(ctx, 1, 0, 10)
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestCommentsCreate(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := &entities.Comment{ID: 10, WorkID: 1, AuthorID: subject, Body: "hoge"}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		gomock.InOrder(
+			commentsRepo.EXPECT().Create(gomock.Any(), &entities.Comment{WorkID: 1, AuthorID: subject, Body: "hoge"}).
+				DoAndReturn(func(ctx context.Context, c *entities.Comment) error {
+					c.ID = 10
+					return nil
+				}),
+			actRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, act *entities.Activity) error {
+					assert.Equal(t, constants.ActivityCommented, act.Type)
+					assert.Equal(t, subject, act.UserID)
+					assert.Equal(t, uint64(1), act.WorkID)
+					if assert.NotNil(t, act.CommentID) {
+						assert.Equal(t, uint64(10), *act.CommentID)
+					}
+					return nil
+				}),
+			commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(expect, nil),
+		)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, actRepo)
+
+		actual, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: " hoge\r\n"})
+
+		assert.Nil(t, err)
+		assert.Same(t, expect, actual)
+	})
+
+	t.Run("Reply", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		var parentID uint64 = 5
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, parentID).Return(&entities.Comment{ID: parentID, WorkID: 1}, nil)
+		commentsRepo.EXPECT().Create(gomock.Any(), &entities.Comment{WorkID: 1, AuthorID: subject, ParentID: &parentID, Body: "hoge"})
+		commentsRepo.EXPECT().FindByID(ctx, gomock.Any()).Return(&entities.Comment{}, nil)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, act *entities.Activity) error {
+				assert.Equal(t, constants.ActivityReplied, act.Type)
+				return nil
+			})
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, actRepo)
+
+		_, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: "hoge", ParentID: &parentID})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Invalid parent", func(t *testing.T) {
+		var grandParentID uint64 = 4
+		for name, parent := range map[string]*entities.Comment{
+			"Reply to a reply":        {ID: 5, WorkID: 1, ParentID: &grandParentID},
+			"Comment on another work": {ID: 5, WorkID: 2},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl, ctx := gomock.WithContext(context.Background(), t)
+				defer ctrl.Finish()
+				ctx = setupContext(ctx)
+
+				worksRepo := mocks.NewMockWorksRepository(ctrl)
+				worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+				commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+				commentsRepo.EXPECT().FindByID(ctx, uint64(5)).Return(parent, nil)
+				service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+				parentID := uint64(5)
+				actual, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: "hoge", ParentID: &parentID})
+
+				assert.Nil(t, actual)
+				assertErrorCode(t, myErr.WUE00, err)
+			})
+		}
+	})
+
+	t.Run("Parent not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(5)).Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		parentID := uint64(5)
+		_, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: "hoge", ParentID: &parentID})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		service := newCommentsService(ctrl, mocks.NewMockCommentsRepository(ctrl), worksRepo,
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		_, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: "<b>hoge</b>"})
+
+		assertErrorCode(t, myErr.WUE09, err)
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newCommentsService(ctrl, mocks.NewMockCommentsRepository(ctrl), mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl))
+
+		_, err := service.Create(ctx, 1, &beans.CommentFormBean{Body: "hoge"})
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestCommentsUpdate(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := &entities.Comment{ID: 10, WorkID: 1, AuthorID: subject, Body: "fuga"}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		gomock.InOrder(
+			commentsRepo.EXPECT().FindByID(ctx, uint64(10)).
+				Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: subject, Body: "hoge"}, nil),
+			commentsRepo.EXPECT().Update(gomock.Any(), &entities.Comment{ID: 10, WorkID: 1, AuthorID: subject, Body: "fuga"}),
+			commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(expect, nil),
+		)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Update(ctx, 10, &beans.CommentFormBean{Body: "fuga"})
+
+		assert.Nil(t, err)
+		assert.Same(t, expect, actual)
+	})
+
+	t.Run("Work owner can't edit", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, subject), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: "user01"}, nil)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		actual, err := service.Update(ctx, 10, &beans.CommentFormBean{Body: "fuga"})
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Too long", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: subject}, nil)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		body := make([]rune, maxCommentLength+1)
+		for i := range body {
+			body[i] = 'a'
+		}
+		_, err := service.Update(ctx, 10, &beans.CommentFormBean{Body: string(body)})
+
+		assertErrorCode(t, myErr.WUE08, err)
+	})
+}
+
+func TestCommentsDeleteByID(t *testing.T) {
+	for name, authorID := range map[string]string{
+		"By the author":     subject,
+		"By the work owner": "user01",
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			defer ctrl.Finish()
+			ctx = setupContext(ctx)
+
+			workOwner := subject
+			if authorID == subject {
+				workOwner = "author"
+			}
+			worksRepo := mocks.NewMockWorksRepository(ctrl)
+			worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, workOwner), nil)
+			commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+			commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: authorID}, nil)
+			commentsRepo.EXPECT().DeleteByID(gomock.Any(), uint64(10))
+			service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+			assert.Nil(t, service.DeleteByID(ctx, 10))
+		})
+	}
+
+	t.Run("By another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: "user01"}, nil)
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE02, service.DeleteByID(ctx, 10))
+	})
+
+	t.Run("Already deleted", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		commentsRepo := mocks.NewMockCommentsRepository(ctrl)
+		commentsRepo.EXPECT().FindByID(ctx, uint64(10)).Return(&entities.Comment{ID: 10, WorkID: 1, AuthorID: subject}, nil)
+		commentsRepo.EXPECT().DeleteByID(gomock.Any(), uint64(10)).Return(myErr.NewRecordNotFoundError("not found", nil))
+		service := newCommentsService(ctrl, commentsRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl))
+
+		assertErrorCode(t, myErr.WUE01, service.DeleteByID(ctx, 10))
+	})
+}
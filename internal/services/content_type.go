@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLen は、Content-Typeの判定に使用する先頭のバイト数
+const sniffLen = 512
+
+const octetStream = "application/octet-stream"
+
+// ftypBrands は、ISO BMFF (ftypボックスで始まるファイル) のブランドと、それが表すContent-Typeの対応。
+// http.DetectContentTypeは、ブランドが"mp4"で始まるもの以外を判定しない。
+var ftypBrands = map[string]string{
+	"avif": "image/avif",
+	"heic": "image/heic",
+	"heix": "image/heic",
+	"mif1": "image/heif",
+	"qt  ": "video/quicktime",
+	"M4A ": "audio/mp4",
+	"M4V ": "video/mp4",
+	"isom": "video/mp4",
+	"iso2": "video/mp4",
+	"avc1": "video/mp4",
+	"3gp4": "video/3gpp",
+	"3gp5": "video/3gpp",
+}
+
+// detectContentType は、ファイルの先頭のバイト列からContent-Typeを判定する。
+// http.DetectContentTypeが判定しない形式を先に判定し、判定できない場合は"application/octet-stream"を返す。
+func detectContentType(head []byte) string {
+	switch {
+	case len(head) >= 12 && bytes.Equal(head[4:8], []byte("ftyp")):
+		if t, ok := ftypBrands[string(head[8:12])]; ok {
+			return t
+		}
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return "audio/flac"
+	case len(head) >= 2 && head[0] == 0xFF && (head[1]&0xF6) == 0xF0:
+		// ADTS形式のAAC
+		return "audio/aac"
+	case len(head) >= 2 && head[0] == 0xFF && (head[1]&0xE0) == 0xE0 && head[1] != 0xFE:
+		// ID3タグのないMP3は、フレームの同期ワードで始まる。0xFFFEはUTF-16のBOMのため除く。
+		return "audio/mpeg"
+	}
+
+	detected := http.DetectContentType(head)
+	// SVGはスクリプトを含められるため、XMLやテキストとして許可されないよう区別する
+	if strings.HasPrefix(detected, "text/xml") || strings.HasPrefix(detected, "text/plain") {
+		if bytes.Contains(bytes.ToLower(head), []byte("<svg")) {
+			return "image/svg+xml"
+		}
+	}
+
+	return detected
+}
+
+// sniffContentType は、bodyの先頭を読み込んでContent-Typeを判定する。
+// 判定に読み込んだ分も含めて、bodyの内容を全て読み込めるReaderを合わせて返す。
+func sniffContentType(body io.Reader) (string, io.Reader, error) {
+	reader := bufio.NewReaderSize(body, sniffLen)
+	head, err := reader.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", nil, err
+	}
+
+	return detectContentType(head), reader, nil
+}
+
+// isAllowedType は、contentTypeが許可リストに含まれるかを返す。"image/*"のようにサブタイプを省略できる。
+func isAllowedType(allowed []string, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if strings.HasSuffix(a, "/*") {
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+			continue
+		}
+		if a == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectContentType(t *testing.T) {
+	for expect, head := range map[string]string{
+		"image/png":                "\x89PNG\r\n\x1a\n",
+		"image/heic":               "\x00\x00\x00\x18ftypheic",
+		"image/avif":               "\x00\x00\x00\x1cftypavif",
+		"video/quicktime":          "\x00\x00\x00\x14ftypqt  ",
+		"audio/mp4":                "\x00\x00\x00\x20ftypM4A ",
+		"audio/flac":               "fLaC\x00\x00\x00\x22",
+		"audio/aac":                "\xff\xf1\x50\x80",
+		"audio/mpeg":               "\xff\xfb\x90\x64",
+		"image/svg+xml":            `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+		"application/zip":          "PK\x03\x04",
+		"application/pdf":          "%PDF-1.4\n",
+		"application/octet-stream": "\x00\x01\x02\x03",
+	} {
+		assert.Equal(t, expect, detectContentType([]byte(head)), expect)
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	t.Run("Keeps sniffed bytes", func(t *testing.T) {
+		body := "%PDF-1.4\n" + strings.Repeat("1", 1024)
+
+		contentType, reader, err := sniffContentType(strings.NewReader(body))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "application/pdf", contentType)
+		b, err := ioutil.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, body, string(b))
+	})
+
+	t.Run("Shorter than sniff length", func(t *testing.T) {
+		contentType, reader, err := sniffContentType(strings.NewReader("PK\x03\x04"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "application/zip", contentType)
+		b, _ := ioutil.ReadAll(reader)
+		assert.Equal(t, "PK\x03\x04", string(b))
+	})
+}
+
+func TestIsAllowedType(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf"}
+
+	assert.True(t, isAllowedType(allowed, "image/png"))
+	assert.True(t, isAllowedType(allowed, "Application/PDF"))
+	assert.False(t, isAllowedType(allowed, "application/zip"))
+	assert.False(t, isAllowedType(allowed, "imagex/png"))
+	assert.False(t, isAllowedType(allowed, "text/plain; charset=utf-8"))
+	assert.False(t, isAllowedType(allowed, "invalid"))
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgFavoritesRepository = "favorites repository"
+
+// currentUser は、ユーザーIDの代わりに指定する、ログイン中のユーザーを表す値
+const currentUser = "me"
+
+// FavoritesService は、作品のお気に入りの機能のインターフェースを定義する。
+// お気に入りの登録と解除は冪等で、作品のお気に入りの数も同じトランザクションで更新する。
+type FavoritesService interface {
+	// GetAll は、ユーザーがお気に入りに登録した作品のうち、閲覧しているユーザーが一覧で閲覧できるものを、登録した日時の新しい順に取得する。
+	// userIDが me の場合は、ログイン中のユーザーのお気に入りを取得する。
+	GetAll(ctx context.Context, userID string, offset int, limit int) (*beans.PaginationBean, error)
+	// Add は、ログイン中のユーザーが閲覧できる作品をお気に入りに登録し、アクティビティとして登録する。登録済みの場合は何もしない。
+	Add(context.Context, uint64) error
+	// Remove は、作品のお気に入りを解除し、登録したアクティビティを削除する。登録していない場合は何もしない。
+	Remove(context.Context, uint64) error
+}
+
+// FavoritesServiceImpl は、作品のお気に入りの機能を実装する
+type FavoritesServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	favoritesRepository  repositories.FavoritesRepository
+	worksRepository      repositories.WorksRepository
+	activitiesRepository repositories.ActivitiesRepository
+	fileUploader         lib.StorageClient
+	privateURLExpiration time.Duration
+}
+
+// NewFavoritesServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、FavoritesServiceImplの新しいインスタンスを生成する。
+// privateURLExpirationは、非公開の作品のファイルを閲覧するための署名付きURLの有効期間。
+func NewFavoritesServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	favoritesRepo repositories.FavoritesRepository,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+	fileUploader lib.StorageClient,
+	privateURLExpiration time.Duration,
+) *FavoritesServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if favoritesRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFavoritesRepository))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &FavoritesServiceImpl{
+		transactionRunner:    tranRnr,
+		favoritesRepository:  favoritesRepo,
+		worksRepository:      worksRepo,
+		activitiesRepository: actRepo,
+		fileUploader:         fileUploader,
+		privateURLExpiration: privateURLExpiration,
+	}
+}
+
+//GetAll は、ユーザーのお気に入りの作品を取得する
+func (r *FavoritesServiceImpl) GetAll(ctx context.Context, userID string, offset int, limit int) (*beans.PaginationBean, error) {
+	if userID == currentUser {
+		sub, ok := extractSubject(ctx)
+		if !ok {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+		}
+		userID = sub
+	}
+
+	viewer := viewerOf(ctx)
+	count, err := r.favoritesRepository.CountWorks(ctx, viewer, userID)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.favoritesRepository.FindWorks(ctx, viewer, userID, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		if err := signFileURLs(r.fileUploader, r.privateURLExpiration, v); err != nil {
+			return nil, err
+		}
+		pagination.Items = append(pagination.Items, v)
+	}
+
+	return pagination, nil
+}
+
+//Add は、作品をお気に入りに登録する
+func (r *FavoritesServiceImpl) Add(ctx context.Context, workID uint64) error {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	if _, err := findViewableWork(ctx, r.worksRepository, workID); err != nil {
+		return err
+	}
+
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		added, err := r.favoritesRepository.Add(ctx, sub, workID)
+		if err != nil || !added {
+			return err
+		}
+		if err := r.worksRepository.UpdateFavoriteCount(ctx, workID, 1); err != nil {
+			return err
+		}
+
+		act := &entities.Activity{
+			Type:   constants.ActivityFavorited,
+			UserID: sub,
+			WorkID: workID,
+		}
+		return r.activitiesRepository.Create(ctx, act)
+	})
+	if err != nil {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
+
+//Remove は、作品のお気に入りを解除する
+func (r *FavoritesServiceImpl) Remove(ctx context.Context, workID uint64) error {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	// 登録した後で閲覧できなくなった作品や削除された作品も、解除できるようにする
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		removed, err := r.favoritesRepository.Remove(ctx, sub, workID)
+		if err != nil || !removed {
+			return err
+		}
+		if err := r.worksRepository.UpdateFavoriteCount(ctx, workID, -1); err != nil {
+			return err
+		}
+		return r.activitiesRepository.DeleteByType(ctx, constants.ActivityFavorited, sub, workID)
+	})
+	if err != nil {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFavoritesServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewFavoritesServiceImpl(tr, favoritesRepo, worksRepo, actRepo, uploader, time.Minute)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.favoritesRepository, favoritesRepo)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+		assert.Same(t, service.fileUploader, uploader)
+		assert.Equal(t, time.Minute, service.privateURLExpiration)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewFavoritesServiceImpl(nil, favoritesRepo, worksRepo, actRepo, uploader, 0) }},
+			{"Favorites repository", func() { NewFavoritesServiceImpl(tr, nil, worksRepo, actRepo, uploader, 0) }},
+			{"Works repository", func() { NewFavoritesServiceImpl(tr, favoritesRepo, nil, actRepo, uploader, 0) }},
+			{"Activities repository", func() { NewFavoritesServiceImpl(tr, favoritesRepo, worksRepo, nil, uploader, 0) }},
+			{"File uploader", func() { NewFavoritesServiceImpl(tr, favoritesRepo, worksRepo, actRepo, nil, 0) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// newFavoritesService は、トランザクションを実行するTransactionRunnerを使用したFavoritesServiceImplを生成する
+func newFavoritesService(ctrl *gomock.Controller, favoritesRepo *mocks.MockFavoritesRepository,
+	worksRepo *mocks.MockWorksRepository, actRepo *mocks.MockActivitiesRepository, uploader *mocks.MockStorageClient) *FavoritesServiceImpl {
+
+	return NewFavoritesServiceImpl(runTransactions(ctrl), favoritesRepo, worksRepo, actRepo, uploader, time.Minute)
+}
+
+func TestFavoritesGetAll(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		private := publicWork(2, subject)
+		private.Visibility = constants.VisibilityPrivate
+		private.ContentURL = "https://example.com/private/content"
+		works := []*entities.Work{publicWork(1, "author"), private}
+
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().CountWorks(ctx, subject, "user01").Return(int64(12), nil)
+		favoritesRepo.EXPECT().FindWorks(ctx, subject, "user01", 10, 2).Return(works, nil)
+		uploader := mocks.NewMockStorageClient(ctrl)
+		uploader.EXPECT().SignURL("https://example.com/private/content", time.Minute).Return("signed", nil)
+		service := newFavoritesService(ctrl, favoritesRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), uploader)
+
+		actual, err := service.GetAll(ctx, "user01", 10, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 12,
+			Offset:     10,
+			Items:      []interface{}{works[0], works[1]},
+		}, actual)
+		assert.Equal(t, "signed", private.ContentURL)
+	})
+
+	t.Run("Favorites of the user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().CountWorks(ctx, subject, subject).Return(int64(0), nil)
+		favoritesRepo.EXPECT().FindWorks(ctx, subject, subject, 0, 10).Return([]*entities.Work{}, nil)
+		service := newFavoritesService(ctrl, favoritesRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.GetAll(ctx, "me", 0, 10)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Favorites of the user without login", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newFavoritesService(ctrl, mocks.NewMockFavoritesRepository(ctrl), mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		actual, err := service.GetAll(ctx, "me", 0, 10)
+
+		assert.Nil(t, actual)
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		errExpect := errors.New("error")
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().CountWorks(ctx, "", "user01").Return(int64(0), errExpect)
+		service := newFavoritesService(ctrl, favoritesRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		actual, err := service.GetAll(ctx, "user01", 0, 10)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestFavoritesAdd(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, "author")
+		w.Visibility = constants.VisibilityUnlisted
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(w, nil)
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		gomock.InOrder(
+			favoritesRepo.EXPECT().Add(gomock.Any(), subject, uint64(1)).Return(true, nil),
+			worksRepo.EXPECT().UpdateFavoriteCount(gomock.Any(), uint64(1), 1).Return(nil),
+			actRepo.EXPECT().Create(gomock.Any(), &entities.Activity{
+				Type:   constants.ActivityFavorited,
+				UserID: subject,
+				WorkID: 1,
+			}).Return(nil),
+		)
+		service := newFavoritesService(ctrl, favoritesRepo, worksRepo, actRepo, mocks.NewMockStorageClient(ctrl))
+
+		assert.Nil(t, service.Add(ctx, 1))
+	})
+
+	t.Run("Already added", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().Add(gomock.Any(), subject, uint64(1)).Return(false, nil)
+		// 登録済みの場合は、数とアクティビティを変更しない
+		service := newFavoritesService(ctrl, favoritesRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockStorageClient(ctrl))
+
+		assert.Nil(t, service.Add(ctx, 1))
+	})
+
+	t.Run("Private work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, "author")
+		w.Visibility = constants.VisibilityPrivate
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(w, nil)
+		service := newFavoritesService(ctrl, mocks.NewMockFavoritesRepository(ctrl), worksRepo,
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		assertErrorCode(t, myErr.WUE01, service.Add(ctx, 1))
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newFavoritesService(ctrl, mocks.NewMockFavoritesRepository(ctrl), mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		assertErrorCode(t, myErr.WUE99, service.Add(ctx, 1))
+	})
+
+	t.Run("Fail to update the count", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		errExpect := errors.New("error")
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(publicWork(1, "author"), nil)
+		worksRepo.EXPECT().UpdateFavoriteCount(gomock.Any(), uint64(1), 1).Return(errExpect)
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().Add(gomock.Any(), subject, uint64(1)).Return(true, nil)
+		service := newFavoritesService(ctrl, favoritesRepo, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockStorageClient(ctrl))
+
+		err := service.Add(ctx, 1)
+
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestFavoritesRemove(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		gomock.InOrder(
+			favoritesRepo.EXPECT().Remove(gomock.Any(), subject, uint64(1)).Return(true, nil),
+			worksRepo.EXPECT().UpdateFavoriteCount(gomock.Any(), uint64(1), -1).Return(nil),
+			actRepo.EXPECT().DeleteByType(gomock.Any(), constants.ActivityFavorited, subject, uint64(1)).Return(nil),
+		)
+		service := newFavoritesService(ctrl, favoritesRepo, worksRepo, actRepo, mocks.NewMockStorageClient(ctrl))
+
+		assert.Nil(t, service.Remove(ctx, 1))
+	})
+
+	t.Run("Not added", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		favoritesRepo := mocks.NewMockFavoritesRepository(ctrl)
+		favoritesRepo.EXPECT().Remove(gomock.Any(), subject, uint64(1)).Return(false, nil)
+		service := newFavoritesService(ctrl, favoritesRepo, mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		assert.Nil(t, service.Remove(ctx, 1))
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newFavoritesService(ctrl, mocks.NewMockFavoritesRepository(ctrl), mocks.NewMockWorksRepository(ctrl),
+			mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		assertErrorCode(t, myErr.WUE99, service.Remove(ctx, 1))
+	})
+}
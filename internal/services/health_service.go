@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/lib"
+)
+
+const healthStatusOK = "ok"
+const healthStatusUnavailable = "unavailable"
+const readinessCheckTimeout = 5 * time.Second
+
+//HealthService は、死活監視機能のインターフェースを定義する
+type HealthService interface {
+	Live(context.Context) *beans.HealthBean
+	Ready(context.Context) (*beans.HealthBean, bool)
+}
+
+//HealthServiceImpl は、死活監視機能を実装する
+type HealthServiceImpl struct {
+	checkers map[string]lib.HealthChecker
+}
+
+//NewHealthServiceImpl は、確認対象の名前とHealthCheckerの組を指定し、HealthServiceImplの新しいインスタンスを生成する
+func NewHealthServiceImpl(checkers map[string]lib.HealthChecker) *HealthServiceImpl {
+	for name, c := range checkers {
+		if c == nil {
+			panic(name + " checker can't be null")
+		}
+	}
+
+	return &HealthServiceImpl{
+		checkers: checkers,
+	}
+}
+
+//Live は、プロセスが応答可能であることを返す
+func (r *HealthServiceImpl) Live(ctx context.Context) *beans.HealthBean {
+	return &beans.HealthBean{Status: healthStatusOK}
+}
+
+//Ready は、全ての依存先を並行して確認し、リクエストを受け付け可能かを返す
+func (r *HealthServiceImpl) Ready(ctx context.Context) (*beans.HealthBean, bool) {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	result := &beans.HealthBean{
+		Status: healthStatusOK,
+		Checks: make(map[string]string, len(r.checkers)),
+	}
+	ready := true
+
+	for name, checker := range r.checkers {
+		wg.Add(1)
+		go func(name string, checker lib.HealthChecker) {
+			defer wg.Done()
+			err := checker.Check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// 認証なしで取得できるため、エラーの詳細は応答に含めずログにのみ出力する
+				log.Printf("%s is not ready: %v", name, err)
+				result.Checks[name] = healthStatusUnavailable
+				ready = false
+				return
+			}
+			result.Checks[name] = healthStatusOK
+		}(name, checker)
+	}
+	wg.Wait()
+
+	if !ready {
+		result.Status = healthStatusUnavailable
+	}
+
+	return result, ready
+}
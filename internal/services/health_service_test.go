@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthServiceImpl(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		checkers := map[string]lib.HealthChecker{
+			"database": mocks.NewMockHealthChecker(ctrl),
+		}
+
+		service := NewHealthServiceImpl(checkers)
+
+		assert.Equal(t, checkers, service.checkers)
+	})
+
+	t.Run("checker is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewHealthServiceImpl(map[string]lib.HealthChecker{"database": nil})
+		})
+	})
+}
+
+func TestLive(t *testing.T) {
+	service := &HealthServiceImpl{}
+
+	assert.Equal(t, &beans.HealthBean{Status: "ok"}, service.Live(context.Background()))
+}
+
+func TestReady(t *testing.T) {
+	t.Run("is ready", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		db := mocks.NewMockHealthChecker(ctrl)
+		db.EXPECT().Check(gomock.Any()).Return(nil)
+		storage := mocks.NewMockHealthChecker(ctrl)
+		storage.EXPECT().Check(gomock.Any()).Return(nil)
+
+		service := &HealthServiceImpl{
+			checkers: map[string]lib.HealthChecker{
+				"database": db,
+				"storage":  storage,
+			},
+		}
+
+		res, ok := service.Ready(ctx)
+
+		assert.True(t, ok)
+		assert.Equal(t, &beans.HealthBean{
+			Status: "ok",
+			Checks: map[string]string{
+				"database": "ok",
+				"storage":  "ok",
+			},
+		}, res)
+	})
+
+	t.Run("is not ready", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		db := mocks.NewMockHealthChecker(ctrl)
+		db.EXPECT().Check(gomock.Any()).Return(errors.New("connection refused"))
+		storage := mocks.NewMockHealthChecker(ctrl)
+		storage.EXPECT().Check(gomock.Any()).Return(nil)
+
+		service := &HealthServiceImpl{
+			checkers: map[string]lib.HealthChecker{
+				"database": db,
+				"storage":  storage,
+			},
+		}
+
+		res, ok := service.Ready(ctx)
+
+		assert.False(t, ok)
+		assert.Equal(t, &beans.HealthBean{
+			Status: "unavailable",
+			Checks: map[string]string{
+				"database": "unavailable",
+				"storage":  "ok",
+			},
+		}, res)
+	})
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+// verifyBatchSize は、Verifyが1回に取得するファイルの件数
+const verifyBatchSize = 100
+
+// 検証に失敗した理由。VerificationFailureBeanのReasonに設定する。
+const (
+	VerificationMissing  = "missing"
+	VerificationSize     = "size"
+	VerificationChecksum = "checksum"
+)
+
+// IntegrityService は、保存したファイルの検証機能のインターフェースを定義する
+type IntegrityService interface {
+	// Verify は、公開済みの全てのファイルを読み込み直し、サイズとSHA-256が公開時と一致するかを検証する。
+	// 一致しないファイルはreportに渡し、検証したファイルの数を返す。
+	Verify(ctx context.Context, report func(*beans.VerificationFailureBean)) (int, error)
+}
+
+// IntegrityServiceImpl は、保存したファイルの検証機能を実装する
+type IntegrityServiceImpl struct {
+	blobsRepository repositories.BlobsRepository
+	fileUploader    lib.StorageClient
+}
+
+// NewIntegrityServiceImpl は、IntegrityServiceImplの新しいインスタンスを生成する
+func NewIntegrityServiceImpl(blobsRepo repositories.BlobsRepository, fileUploader lib.StorageClient) *IntegrityServiceImpl {
+	if blobsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgBlobsRepository))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &IntegrityServiceImpl{
+		blobsRepository: blobsRepo,
+		fileUploader:    fileUploader,
+	}
+}
+
+//Verify は、キーの順に公開済みのファイルを検証する。ストレージから読み込めない場合は、その時点で中断する。
+func (r *IntegrityServiceImpl) Verify(ctx context.Context, report func(*beans.VerificationFailureBean)) (int, error) {
+	verified := 0
+	after := ""
+	for {
+		blobs, err := r.blobsRepository.FindAfter(ctx, after, verifyBatchSize)
+		if err != nil {
+			return verified, err
+		}
+
+		for _, blob := range blobs {
+			if err := ctx.Err(); err != nil {
+				return verified, err
+			}
+
+			digest, err := r.digest(blob.Key)
+			if errors.Is(err, lib.ErrObjectNotFound) {
+				report(&beans.VerificationFailureBean{Key: blob.Key, Reason: VerificationMissing})
+				verified++
+				continue
+			}
+			if err != nil {
+				return verified, fmt.Errorf("failed to read %s: %w", blob.Key, err)
+			}
+
+			switch {
+			case digest.size != blob.Size:
+				report(&beans.VerificationFailureBean{
+					Key:      blob.Key,
+					Reason:   VerificationSize,
+					Expected: strconv.FormatInt(blob.Size, 10),
+					Actual:   strconv.FormatInt(digest.size, 10),
+				})
+			case digest.sum() != blob.SHA256:
+				report(&beans.VerificationFailureBean{
+					Key:      blob.Key,
+					Reason:   VerificationChecksum,
+					Expected: blob.SHA256,
+					Actual:   digest.sum(),
+				})
+			}
+			verified++
+		}
+
+		if len(blobs) < verifyBatchSize {
+			return verified, nil
+		}
+		after = blobs[len(blobs)-1].Key
+	}
+}
+
+// digest は、公開済みのファイルを全て読み込んでハッシュ値を計算する
+func (r *IntegrityServiceImpl) digest(key string) (*digestReader, error) {
+	object, err := r.fileUploader.OpenPublished(key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	digest := newDigestReader(object)
+	if _, err := io.Copy(ioutil.Discard, digest); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIntegrityServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewIntegrityServiceImpl(blobsRepo, uploader)
+
+		assert.Same(t, service.blobsRepository, blobsRepo)
+		assert.Same(t, service.fileUploader, uploader)
+	})
+
+	t.Run("Blobs repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		assert.Panics(t, func() {
+			NewIntegrityServiceImpl(nil, uploader)
+		})
+	})
+
+	t.Run("File uploader is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+
+		assert.Panics(t, func() {
+			NewIntegrityServiceImpl(blobsRepo, nil)
+		})
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), "", verifyBatchSize).Return([]*entities.Blob{
+			{Key: "a.png", SHA256: sha256Hex([]byte("aaa")), Size: 3},
+			{Key: "b.zip", SHA256: sha256Hex([]byte("bbbb")), Size: 4},
+		}, nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().OpenPublished("a.png").Return(ioutil.NopCloser(strings.NewReader("aaa")), nil)
+		fileUploader.EXPECT().OpenPublished("b.zip").Return(ioutil.NopCloser(strings.NewReader("bbbb")), nil)
+
+		service := &IntegrityServiceImpl{blobsRepository: blobsRepo, fileUploader: fileUploader}
+
+		verified, err := service.Verify(ctx, func(f *beans.VerificationFailureBean) {
+			assert.Failf(t, "Unexpected failure", "%v", f)
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, verified)
+	})
+
+	t.Run("Reads every page", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		first := make([]*entities.Blob, verifyBatchSize)
+		for i := range first {
+			first[i] = &entities.Blob{Key: fmt.Sprintf("%03d", i), SHA256: sha256Hex(nil)}
+		}
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		gomock.InOrder(
+			blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), "", verifyBatchSize).Return(first, nil),
+			blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), first[verifyBatchSize-1].Key, verifyBatchSize).Return(nil, nil),
+		)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().OpenPublished(gomock.Any()).Return(ioutil.NopCloser(strings.NewReader("")), nil).Times(verifyBatchSize)
+
+		service := &IntegrityServiceImpl{blobsRepository: blobsRepo, fileUploader: fileUploader}
+
+		verified, err := service.Verify(ctx, func(f *beans.VerificationFailureBean) {})
+
+		assert.Nil(t, err)
+		assert.Equal(t, verifyBatchSize, verified)
+	})
+
+	t.Run("Report mismatches", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), "", verifyBatchSize).Return([]*entities.Blob{
+			{Key: "checksum.png", SHA256: sha256Hex([]byte("aaa")), Size: 3},
+			{Key: "missing.png", SHA256: sha256Hex([]byte("aaa")), Size: 3},
+			{Key: "size.png", SHA256: sha256Hex([]byte("aaa")), Size: 3},
+		}, nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().OpenPublished("checksum.png").Return(ioutil.NopCloser(strings.NewReader("bbb")), nil)
+		fileUploader.EXPECT().OpenPublished("missing.png").Return(nil, lib.ErrObjectNotFound)
+		fileUploader.EXPECT().OpenPublished("size.png").Return(ioutil.NopCloser(strings.NewReader("aaaa")), nil)
+
+		service := &IntegrityServiceImpl{blobsRepository: blobsRepo, fileUploader: fileUploader}
+
+		var failures []*beans.VerificationFailureBean
+		verified, err := service.Verify(ctx, func(f *beans.VerificationFailureBean) {
+			failures = append(failures, f)
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, verified)
+		assert.Equal(t, []*beans.VerificationFailureBean{
+			{
+				Key:      "checksum.png",
+				Reason:   VerificationChecksum,
+				Expected: sha256Hex([]byte("aaa")),
+				Actual:   sha256Hex([]byte("bbb")),
+			},
+			{Key: "missing.png", Reason: VerificationMissing},
+			{Key: "size.png", Reason: VerificationSize, Expected: "3", Actual: "4"},
+		}, failures)
+	})
+
+	t.Run("Fail to find blobs", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("error")
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), "", verifyBatchSize).Return(nil, expect)
+
+		service := &IntegrityServiceImpl{blobsRepository: blobsRepo, fileUploader: mocks.NewMockStorageClient(ctrl)}
+
+		_, err := service.Verify(ctx, func(f *beans.VerificationFailureBean) {})
+
+		assert.True(t, errors.Is(err, expect))
+	})
+
+	t.Run("Fail to read object", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("error")
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindAfter(gomock.Eq(ctx), "", verifyBatchSize).Return([]*entities.Blob{
+			{Key: "a.png"},
+			{Key: "b.png"},
+		}, nil)
+		// 読み込めない場合は、後続のファイルを検証せずに中断する
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().OpenPublished("a.png").Return(nil, expect)
+
+		service := &IntegrityServiceImpl{blobsRepository: blobsRepo, fileUploader: fileUploader}
+
+		verified, err := service.Verify(ctx, func(f *beans.VerificationFailureBean) {})
+
+		assert.True(t, errors.Is(err, expect))
+		assert.Equal(t, 0, verified)
+	})
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgLinkChecker = "link checker"
+
+// linkCheckBatchSize は、CheckLinksが1回に取得する作品の件数
+const linkCheckBatchSize = 100
+
+// LinkCheckService は、URLの作品のリンク切れの確認機能のインターフェースを定義する
+type LinkCheckService interface {
+	// CheckLinks は、一定期間確認していない全てのURLの作品のリンク先を確認し、確認した作品の数を返す
+	CheckLinks(context.Context) (int, error)
+}
+
+// LinkCheckServiceImpl は、URLの作品のリンク切れの確認機能を実装する
+type LinkCheckServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	worksRepository      repositories.WorksRepository
+	activitiesRepository repositories.ActivitiesRepository
+	linkChecker          lib.LinkChecker
+	interval             time.Duration
+	brokenAfter          time.Duration
+	concurrency          int
+	hostInterval         time.Duration
+}
+
+// NewLinkCheckServiceImpl は、同じ作品を確認する間隔、リンク切れとして作者に知らせるまでの失敗し続けた期間、
+// 同時に確認する作品の数、同じホストへのリクエストの最小の間隔を指定し、LinkCheckServiceImplの新しいインスタンスを生成する
+func NewLinkCheckServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+	linkChecker lib.LinkChecker,
+	interval time.Duration,
+	brokenAfter time.Duration,
+	concurrency int,
+	hostInterval time.Duration,
+) *LinkCheckServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+	if linkChecker == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgLinkChecker))
+	}
+	if concurrency <= 0 {
+		panic("concurrency must be greater than 0")
+	}
+
+	return &LinkCheckServiceImpl{
+		transactionRunner:    tranRnr,
+		worksRepository:      worksRepo,
+		activitiesRepository: actRepo,
+		linkChecker:          linkChecker,
+		interval:             interval,
+		brokenAfter:          brokenAfter,
+		concurrency:          concurrency,
+		hostInterval:         hostInterval,
+	}
+}
+
+// CheckLinks は、IDの順に作品を取得し、concurrency件ずつ並行してリンク先を確認する。
+// 確認中に削除された作品は数えない。
+func (r *LinkCheckServiceImpl) CheckLinks(ctx context.Context) (int, error) {
+	checkedBefore := time.Now().Add(-r.interval)
+	throttle := newHostThrottle(r.hostInterval)
+
+	checked := 0
+	var after uint64
+	for {
+		works, err := r.worksRepository.FindLinksToCheck(ctx, checkedBefore, after, linkCheckBatchSize)
+		if err != nil {
+			return checked, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+
+		n, err := r.checkAll(ctx, works, throttle)
+		checked += n
+		if err != nil {
+			return checked, err
+		}
+		if len(works) < linkCheckBatchSize {
+			return checked, nil
+		}
+		after = works[len(works)-1].ID
+	}
+}
+
+// checkAll は、worksのリンク先を並行して確認し、確認した作品の数と、最初に発生したエラーを返す
+func (r *LinkCheckServiceImpl) checkAll(ctx context.Context, works []*entities.Work, throttle *hostThrottle) (int, error) {
+	queue := make(chan *entities.Work)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		checked  int
+		firstErr error
+	)
+	for i := 0; i < r.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w := range queue {
+				err := r.check(ctx, w, throttle)
+				var dbErr *myErr.RecordNotFoundError
+				if errors.As(err, &dbErr) {
+					continue
+				}
+
+				mu.Lock()
+				if err == nil {
+					checked++
+				} else if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, w := range works {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- w
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil && !errors.Is(firstErr, context.Canceled) && !errors.Is(firstErr, context.DeadlineExceeded) {
+		firstErr = myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(firstErr))
+	}
+	return checked, firstErr
+}
+
+// check は、作品のリンク先を確認して結果を保存する。
+// リンク先に一定期間到達できなかった場合は、リンク切れとし、作者へのアクティビティを登録する。
+func (r *LinkCheckServiceImpl) check(ctx context.Context, w *entities.Work, throttle *hostThrottle) error {
+	if err := throttle.wait(ctx, linkHost(w.ContentURL)); err != nil {
+		return err
+	}
+	status, err := r.linkChecker.Check(ctx, w.ContentURL)
+	if ctx.Err() != nil {
+		// 中断による失敗は、リンク先の状態として記録しない
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	w.LinkStatus = status
+	w.LinkCheckedAt = &now
+	notify := false
+	switch {
+	case err == nil && status == http.StatusTooManyRequests:
+		// 到達できるかを判定できないため、失敗の回数は変えない
+	case err != nil || isDeadLinkStatus(status):
+		w.LinkFailures++
+		if w.LinkDownSince == nil {
+			w.LinkDownSince = &now
+		}
+		if !w.LinkBroken && now.Sub(*w.LinkDownSince) >= r.brokenAfter {
+			w.LinkBroken = true
+			notify = true
+		}
+	default:
+		w.LinkFailures = 0
+		w.LinkDownSince = nil
+		w.LinkBroken = false
+	}
+
+	return r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.UpdateLinkStatus(ctx, w); err != nil {
+			return err
+		}
+		if !notify {
+			return nil
+		}
+		return r.activitiesRepository.Create(ctx, &entities.Activity{
+			Type:   constants.ActivityLinkBroken,
+			UserID: w.AuthorID,
+			Work:   w,
+		})
+	})
+}
+
+// isDeadLinkStatus は、リンク先がなくなった、または応答できない状態を表すステータスコードかを返す。
+// 認証が必要な場合など、それ以外のクライアントエラーは到達できたものとする。
+func isDeadLinkStatus(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusGone || status >= http.StatusInternalServerError
+}
+
+// linkHost は、リクエストの間隔を制限する単位として、URLのホスト名を返す
+func linkHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// hostThrottle は、同じホストへのリクエストの間隔を一定以上空ける
+type hostThrottle struct {
+	interval time.Duration
+	mu       sync.Mutex
+	// next は、ホスト毎の次にリクエストを送信できる日時
+	next map[string]time.Time
+}
+
+func newHostThrottle(interval time.Duration) *hostThrottle {
+	return &hostThrottle{
+		interval: interval,
+		next:     map[string]time.Time{},
+	}
+}
+
+// wait は、hostへリクエストを送信できるまで待つ。ctxがキャンセルされた場合はそのエラーを返す。
+func (r *hostThrottle) wait(ctx context.Context, host string) error {
+	if r.interval <= 0 {
+		return ctx.Err()
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	at := r.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	r.next[host] = at.Add(r.interval)
+	r.mu.Unlock()
+
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLinkCheckServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		checker := mocks.NewMockLinkChecker(ctrl)
+
+		service := NewLinkCheckServiceImpl(tr, worksRepo, actRepo, checker, 24*time.Hour, 72*time.Hour, 4, time.Second)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+		assert.Same(t, service.linkChecker, checker)
+		assert.Equal(t, 24*time.Hour, service.interval)
+		assert.Equal(t, 72*time.Hour, service.brokenAfter)
+		assert.Equal(t, 4, service.concurrency)
+		assert.Equal(t, time.Second, service.hostInterval)
+	})
+
+	t.Run("Transaction runner is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewLinkCheckServiceImpl(nil, mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl),
+				mocks.NewMockLinkChecker(ctrl), time.Hour, time.Hour, 1, 0)
+		})
+	})
+
+	t.Run("Works repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewLinkCheckServiceImpl(mocks.NewMockTransactionRunner(ctrl), nil, mocks.NewMockActivitiesRepository(ctrl),
+				mocks.NewMockLinkChecker(ctrl), time.Hour, time.Hour, 1, 0)
+		})
+	})
+
+	t.Run("Activities repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewLinkCheckServiceImpl(mocks.NewMockTransactionRunner(ctrl), mocks.NewMockWorksRepository(ctrl), nil,
+				mocks.NewMockLinkChecker(ctrl), time.Hour, time.Hour, 1, 0)
+		})
+	})
+
+	t.Run("Link checker is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewLinkCheckServiceImpl(mocks.NewMockTransactionRunner(ctrl), mocks.NewMockWorksRepository(ctrl),
+				mocks.NewMockActivitiesRepository(ctrl), nil, time.Hour, time.Hour, 1, 0)
+		})
+	})
+
+	t.Run("Concurrency is zero", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewLinkCheckServiceImpl(mocks.NewMockTransactionRunner(ctrl), mocks.NewMockWorksRepository(ctrl),
+				mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockLinkChecker(ctrl), time.Hour, time.Hour, 0, 0)
+		})
+	})
+}
+
+func TestCheckLinks(t *testing.T) {
+	// newService は、トランザクションを実行するTransactionRunnerを使用したLinkCheckServiceImplを生成する
+	newService := func(ctrl *gomock.Controller, ctx context.Context, worksRepo *mocks.MockWorksRepository,
+		actRepo *mocks.MockActivitiesRepository, checker *mocks.MockLinkChecker) *LinkCheckServiceImpl {
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			AnyTimes()
+
+		return &LinkCheckServiceImpl{
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			linkChecker:          checker,
+			interval:             24 * time.Hour,
+			brokenAfter:          72 * time.Hour,
+			concurrency:          2,
+		}
+	}
+
+	t.Run("Alive link", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		downSince := time.Now().Add(-time.Hour)
+		work := &entities.Work{ID: 1, ContentURL: "https://example.com/1", LinkFailures: 2, LinkDownSince: &downSince, LinkBroken: true}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return([]*entities.Work{work}, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), work)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusOK, nil)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, http.StatusOK, work.LinkStatus)
+		assert.NotNil(t, work.LinkCheckedAt)
+		assert.Zero(t, work.LinkFailures)
+		assert.Nil(t, work.LinkDownSince)
+		assert.False(t, work.LinkBroken)
+	})
+
+	t.Run("Dead link", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		works := []*entities.Work{
+			{ID: 1, ContentURL: "https://example.com/1"},
+			{ID: 2, ContentURL: "https://example.com/2"},
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return(works, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), gomock.Any()).Times(2)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusNotFound, nil)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/2").Return(0, errors.New("connection refused"))
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, http.StatusNotFound, works[0].LinkStatus)
+		assert.Zero(t, works[1].LinkStatus)
+		for _, w := range works {
+			assert.Equal(t, 1, w.LinkFailures)
+			if assert.NotNil(t, w.LinkDownSince) {
+				assert.Equal(t, *w.LinkCheckedAt, *w.LinkDownSince)
+			}
+			// 失敗し続けた期間が短い間は、リンク切れとしない
+			assert.False(t, w.LinkBroken)
+		}
+	})
+
+	t.Run("Broken link", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		downSince := time.Now().Add(-73 * time.Hour)
+		work := &entities.Work{ID: 1, AuthorID: "author", ContentURL: "https://example.com/1", LinkFailures: 3, LinkDownSince: &downSince}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return([]*entities.Work{work}, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), work)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
+			Type:   constants.ActivityLinkBroken,
+			UserID: "author",
+			Work:   work,
+		})
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusServiceUnavailable, nil)
+
+		service := newService(ctrl, ctx, worksRepo, actRepo, checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 4, work.LinkFailures)
+		assert.Equal(t, &downSince, work.LinkDownSince)
+		assert.True(t, work.LinkBroken)
+	})
+
+	t.Run("Already broken link", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		downSince := time.Now().Add(-100 * time.Hour)
+		work := &entities.Work{ID: 1, ContentURL: "https://example.com/1", LinkFailures: 4, LinkDownSince: &downSince, LinkBroken: true}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return([]*entities.Work{work}, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), work)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusGone, nil)
+
+		// 作者には1度だけ知らせる
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		_, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, work.LinkFailures)
+		assert.True(t, work.LinkBroken)
+	})
+
+	t.Run("Inconclusive status", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		downSince := time.Now().Add(-100 * time.Hour)
+		works := []*entities.Work{
+			{ID: 1, ContentURL: "https://example.com/1", LinkFailures: 1, LinkDownSince: &downSince},
+			{ID: 2, ContentURL: "https://example.com/2", LinkFailures: 1, LinkDownSince: &downSince},
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return(works, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), gomock.Any()).Times(2)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusTooManyRequests, nil)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/2").Return(http.StatusForbidden, nil)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		_, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		// 429は失敗の回数を変えず、403は到達できたものとする
+		assert.Equal(t, http.StatusTooManyRequests, works[0].LinkStatus)
+		assert.Equal(t, 1, works[0].LinkFailures)
+		assert.False(t, works[0].LinkBroken)
+		assert.Equal(t, http.StatusForbidden, works[1].LinkStatus)
+		assert.Zero(t, works[1].LinkFailures)
+		assert.Nil(t, works[1].LinkDownSince)
+	})
+
+	t.Run("Pages through works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		var first []*entities.Work
+		for i := 1; i <= linkCheckBatchSize; i++ {
+			first = append(first, &entities.Work{ID: uint64(i), ContentURL: "https://example.com/"})
+		}
+		second := []*entities.Work{{ID: linkCheckBatchSize + 1, ContentURL: "https://example.com/"}}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		gomock.InOrder(
+			worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return(first, nil),
+			worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(linkCheckBatchSize), linkCheckBatchSize).Return(second, nil),
+		)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), gomock.Any()).Times(linkCheckBatchSize + 1)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/").Return(http.StatusOK, nil).Times(linkCheckBatchSize + 1)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, linkCheckBatchSize+1, count)
+	})
+
+	t.Run("Work deleted while checking", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		work := &entities.Work{ID: 1, ContentURL: "https://example.com/1"}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return([]*entities.Work{work}, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), work).Return(myErr.NewRecordNotFoundError("", nil))
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").Return(http.StatusOK, nil)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		assert.Nil(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Fail to find works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("Failed to find")
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return(nil, expect)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), mocks.NewMockLinkChecker(ctrl))
+
+		_, actual := service.CheckLinks(ctx)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Fail to update", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("Failed to update")
+		works := []*entities.Work{
+			{ID: 1, ContentURL: "https://example.com/1"},
+			{ID: 2, ContentURL: "https://example.com/2"},
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return(works, nil)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), works[0]).Return(expect)
+		worksRepo.EXPECT().UpdateLinkStatus(gomock.Eq(ctx), works[1])
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), gomock.Any()).Return(http.StatusOK, nil).Times(2)
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, actual := service.CheckLinks(ctx)
+
+		assert.Equal(t, 1, count)
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx, cancel := context.WithCancel(ctx)
+
+		work := &entities.Work{ID: 1, ContentURL: "https://example.com/1"}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindLinksToCheck(gomock.Eq(ctx), gomock.Any(), uint64(0), linkCheckBatchSize).Return([]*entities.Work{work}, nil)
+		checker := mocks.NewMockLinkChecker(ctrl)
+		checker.EXPECT().Check(gomock.Eq(ctx), "https://example.com/1").DoAndReturn(func(context.Context, string) (int, error) {
+			cancel()
+			return 0, context.Canceled
+		})
+
+		service := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl), checker)
+
+		count, err := service.CheckLinks(ctx)
+
+		// 中断による失敗は記録しない
+		assert.Zero(t, count)
+		assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+		assert.Nil(t, work.LinkCheckedAt)
+	})
+}
+
+func TestHostThrottle(t *testing.T) {
+	t.Run("Waits between requests to the same host", func(t *testing.T) {
+		throttle := newHostThrottle(50 * time.Millisecond)
+		ctx := context.Background()
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.Nil(t, throttle.wait(ctx, "example.com"))
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+		start = time.Now()
+		assert.Nil(t, throttle.wait(ctx, "example.org"))
+		assert.Less(t, int64(time.Since(start)), int64(50*time.Millisecond))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		throttle := newHostThrottle(time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		assert.Nil(t, throttle.wait(ctx, "example.com"))
+		cancel()
+
+		assert.True(t, errors.Is(throttle.wait(ctx, "example.com"), context.Canceled))
+	})
+}
+
+func TestLinkHost(t *testing.T) {
+	assert.Equal(t, "example.com", linkHost("https://Example.COM:8443/works/1"))
+	assert.Equal(t, "", linkHost("%"))
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// previewFilenameFallback は、URLからファイル名を決められないプレビューの画像のファイル名
+const previewFilenameFallback = "preview"
+
+// previewFilename は、プレビューの画像のURLのパスの最後の要素をファイル名として返す
+func previewFilename(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return previewFilenameFallback
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return previewFilenameFallback
+	}
+	return name
+}
+
+// truncateRunes は、文字列の前後の空白を取り除き、最大でmax文字に切り詰める
+func truncateRunes(s string, max int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:max]))
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewFilename(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"https://example.com/images/og.png?v=2": "og.png",
+		"https://example.com/":                  previewFilenameFallback,
+		"https://example.com":                   previewFilenameFallback,
+		"://":                                   previewFilenameFallback,
+	} {
+		assert.Equal(t, expected, previewFilename(rawURL), rawURL)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "作品", truncateRunes(" 作品 ", 2))
+	assert.Equal(t, "作品の", truncateRunes("作品の題名", 3))
+	assert.Equal(t, "ab", truncateRunes("ab cd", 3))
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgUsersRepository = "users repository"
+
+// fieldAction は、モデレーターの操作のフォーム項目名
+const fieldAction = "action"
+
+// モデレーターが作品とユーザーに対して行える操作
+const (
+	ModerationActionHide      = "hide"
+	ModerationActionUnhide    = "unhide"
+	ModerationActionRemove    = "remove"
+	ModerationActionDismiss   = "dismiss"
+	ModerationActionSuspend   = "suspend"
+	ModerationActionUnsuspend = "unsuspend"
+)
+
+// ModerationService は、通報された作品のモデレーションの機能のインターフェースを定義する。
+// いずれの操作もモデレーターのscopeを持つユーザーのみが行え、それ以外のユーザーにはWUE02を返す。
+// 作品とユーザーに対する操作は、操作したモデレーターとともに記録する。
+type ModerationService interface {
+	// GetReports は、未対応の通報を、通報した日時の古い順に取得する
+	GetReports(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error)
+	// GetActions は、モデレーターの操作の記録を、操作した日時の新しい順に取得する
+	GetActions(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error)
+	// ModerateWork は、作品を非表示にする (hide)、非表示を解除する (unhide)、非表示にしてゴミ箱に移す (remove)、
+	// または通報を対応不要として閉じる (dismiss)。作品への未対応の通報は、dismiss と unhide では対応不要、それ以外では対応済みにする。
+	ModerateWork(ctx context.Context, workID uint64, bean *beans.ModerationFormBean) error
+	// ModerateUser は、ユーザーのアカウントを停止する (suspend)、または停止を解除する (unsuspend)
+	ModerateUser(ctx context.Context, userID string, bean *beans.ModerationFormBean) error
+}
+
+// ModerationServiceImpl は、通報された作品のモデレーションの機能を実装する
+type ModerationServiceImpl struct {
+	transactionRunner           repositories.TransactionRunner
+	reportsRepository           repositories.ReportsRepository
+	moderationActionsRepository repositories.ModerationActionsRepository
+	worksRepository             repositories.WorksRepository
+	usersRepository             repositories.UsersRepository
+	moderatorScope              string
+}
+
+// NewModerationServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、ModerationServiceImplの新しいインスタンスを生成する。
+// moderatorScopeは、モデレーターのアクセストークンが持つscope。空の場合はモデレーターを認めない。
+func NewModerationServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	reportsRepo repositories.ReportsRepository,
+	moderationActionsRepo repositories.ModerationActionsRepository,
+	worksRepo repositories.WorksRepository,
+	usersRepo repositories.UsersRepository,
+	moderatorScope string,
+) *ModerationServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if reportsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgReportsRepository))
+	}
+	if moderationActionsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgModerationActionsRepository))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if usersRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgUsersRepository))
+	}
+
+	return &ModerationServiceImpl{
+		transactionRunner:           tranRnr,
+		reportsRepository:           reportsRepo,
+		moderationActionsRepository: moderationActionsRepo,
+		worksRepository:             worksRepo,
+		usersRepository:             usersRepo,
+		moderatorScope:              moderatorScope,
+	}
+}
+
+//GetReports は、未対応の通報を取得する
+func (r *ModerationServiceImpl) GetReports(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error) {
+	if !hasScope(ctx, r.moderatorScope) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	count, err := r.reportsRepository.CountOpen(ctx)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.reportsRepository.FindOpen(ctx, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		pagination.Items = append(pagination.Items, v)
+	}
+	return pagination, nil
+}
+
+//GetActions は、モデレーターの操作の記録を取得する
+func (r *ModerationServiceImpl) GetActions(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error) {
+	if !hasScope(ctx, r.moderatorScope) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	count, err := r.moderationActionsRepository.CountAll(ctx)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.moderationActionsRepository.GetAll(ctx, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		pagination.Items = append(pagination.Items, v)
+	}
+	return pagination, nil
+}
+
+//ModerateWork は、作品に対するモデレーターの操作を行う
+func (r *ModerationServiceImpl) ModerateWork(ctx context.Context, workID uint64, bean *beans.ModerationFormBean) error {
+	sub, err := r.moderator(ctx)
+	if err != nil {
+		return err
+	}
+
+	var actType constants.ModerationActionType
+	switch bean.Action {
+	case ModerationActionHide:
+		actType = constants.ModerationHide
+	case ModerationActionUnhide:
+		actType = constants.ModerationUnhide
+	case ModerationActionRemove:
+		actType = constants.ModerationRemove
+	case ModerationActionDismiss:
+		actType = constants.ModerationDismiss
+	default:
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldAction))
+	}
+
+	w, err := r.worksRepository.FindByID(ctx, workID)
+	if err != nil {
+		return workError(err)
+	}
+	// 既に非表示の作品を非表示にする、または非表示でない作品の非表示を解除する操作は受け付けない
+	if (actType == constants.ModerationHide && w.HiddenAt != nil) ||
+		(actType == constants.ModerationUnhide && w.HiddenAt == nil) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldAction))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		status := constants.ReportResolved
+		switch actType {
+		case constants.ModerationHide:
+			now := time.Now()
+			if err := r.worksRepository.UpdateHidden(ctx, workID, &now); err != nil {
+				return err
+			}
+		case constants.ModerationUnhide:
+			if err := r.worksRepository.UpdateHidden(ctx, workID, nil); err != nil {
+				return err
+			}
+			status = constants.ReportDismissed
+		case constants.ModerationRemove:
+			// 作者がゴミ箱から復元しても表示されないよう、非表示にしてから削除する
+			if w.HiddenAt == nil {
+				now := time.Now()
+				if err := r.worksRepository.UpdateHidden(ctx, workID, &now); err != nil {
+					return err
+				}
+			}
+			if err := r.worksRepository.DeleteByID(ctx, workID); err != nil {
+				return err
+			}
+		case constants.ModerationDismiss:
+			status = constants.ReportDismissed
+		}
+
+		if err := r.reportsRepository.ResolveByWorkID(ctx, workID, status); err != nil {
+			return err
+		}
+		return r.moderationActionsRepository.Create(ctx, &entities.ModerationAction{
+			Type:        actType,
+			ModeratorID: &sub,
+			WorkID:      &workID,
+			Note:        bean.Note,
+		})
+	})
+	if err != nil {
+		return workError(err)
+	}
+	return nil
+}
+
+//ModerateUser は、ユーザーに対するモデレーターの操作を行う
+func (r *ModerationServiceImpl) ModerateUser(ctx context.Context, userID string, bean *beans.ModerationFormBean) error {
+	sub, err := r.moderator(ctx)
+	if err != nil {
+		return err
+	}
+
+	var actType constants.ModerationActionType
+	switch bean.Action {
+	case ModerationActionSuspend:
+		actType = constants.ModerationSuspend
+	case ModerationActionUnsuspend:
+		actType = constants.ModerationUnsuspend
+	default:
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldAction))
+	}
+
+	u, err := r.usersRepository.FindByID(ctx, userID)
+	if err != nil {
+		return userError(err)
+	}
+	// 停止中のユーザーを停止する、または停止していないユーザーの停止を解除する操作は受け付けない
+	if (actType == constants.ModerationSuspend && u.SuspendedAt != nil) ||
+		(actType == constants.ModerationUnsuspend && u.SuspendedAt == nil) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldAction))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		var suspendedAt *time.Time
+		if actType == constants.ModerationSuspend {
+			now := time.Now()
+			suspendedAt = &now
+		}
+		if err := r.usersRepository.UpdateSuspended(ctx, userID, suspendedAt); err != nil {
+			return err
+		}
+		return r.moderationActionsRepository.Create(ctx, &entities.ModerationAction{
+			Type:        actType,
+			ModeratorID: &sub,
+			UserID:      &userID,
+			Note:        bean.Note,
+		})
+	})
+	if err != nil {
+		return userError(err)
+	}
+	return nil
+}
+
+// moderator は、ログイン中のモデレーターのユーザーIDを返す。モデレーターでない場合はWUE02を返す。
+func (r *ModerationServiceImpl) moderator(ctx context.Context) (string, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return "", myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	if !hasScope(ctx, r.moderatorScope) {
+		return "", myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+	return sub, nil
+}
+
+// workError は、リポジトリのエラーを、作品がない場合はWUE01、それ以外はWUE99に変換する
+func workError(err error) error {
+	var dbErr *myErr.RecordNotFoundError
+	if errors.As(err, &dbErr) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+	}
+	return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+}
+
+// userError は、リポジトリのエラーを、ユーザーがない場合はWUE11、それ以外はWUE99に変換する
+func userError(err error) error {
+	var dbErr *myErr.RecordNotFoundError
+	if errors.As(err, &dbErr) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE11), myErr.Cause(err))
+	}
+	return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const moderatorScope string = "moderate:works"
+
+func TestNewModerationServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		usersRepo := mocks.NewMockUsersRepository(ctrl)
+
+		service := NewModerationServiceImpl(tr, reportsRepo, actionsRepo, worksRepo, usersRepo, moderatorScope)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.reportsRepository, reportsRepo)
+		assert.Same(t, service.moderationActionsRepository, actionsRepo)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.usersRepository, usersRepo)
+		assert.Equal(t, moderatorScope, service.moderatorScope)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		usersRepo := mocks.NewMockUsersRepository(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewModerationServiceImpl(nil, reportsRepo, actionsRepo, worksRepo, usersRepo, moderatorScope) }},
+			{"Reports repository", func() { NewModerationServiceImpl(tr, nil, actionsRepo, worksRepo, usersRepo, moderatorScope) }},
+			{"Moderation actions repository", func() { NewModerationServiceImpl(tr, reportsRepo, nil, worksRepo, usersRepo, moderatorScope) }},
+			{"Works repository", func() { NewModerationServiceImpl(tr, reportsRepo, actionsRepo, nil, usersRepo, moderatorScope) }},
+			{"Users repository", func() { NewModerationServiceImpl(tr, reportsRepo, actionsRepo, worksRepo, nil, moderatorScope) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// moderationMocks は、ModerationServiceImplが使用するリポジトリのモックを表す
+type moderationMocks struct {
+	reports *mocks.MockReportsRepository
+	actions *mocks.MockModerationActionsRepository
+	works   *mocks.MockWorksRepository
+	users   *mocks.MockUsersRepository
+}
+
+// newModerationService は、トランザクションを実行するTransactionRunnerを使用したModerationServiceImplを生成する
+func newModerationService(ctrl *gomock.Controller) (*ModerationServiceImpl, *moderationMocks) {
+	m := &moderationMocks{
+		reports: mocks.NewMockReportsRepository(ctrl),
+		actions: mocks.NewMockModerationActionsRepository(ctrl),
+		works:   mocks.NewMockWorksRepository(ctrl),
+		users:   mocks.NewMockUsersRepository(ctrl),
+	}
+	return NewModerationServiceImpl(runTransactions(ctrl), m.reports, m.actions, m.works, m.users, moderatorScope), m
+}
+
+func setupModeratorContext(ctx context.Context) context.Context {
+	//lint:ignore SA1029 can use string only
+	return context.WithValue(ctx, userKey, &jwt.Token{
+		Claims: jwt.MapClaims{
+			"sub":   "moderator",
+			"scope": "openid " + moderatorScope,
+		},
+	})
+}
+
+// expectAction は、モデレーターの操作が記録されることを期待する
+func expectAction(t *testing.T, m *moderationMocks, actType constants.ModerationActionType) {
+	m.actions.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, action *entities.ModerationAction) {
+			assert.Equal(t, actType, action.Type)
+			assert.Equal(t, "moderator", *action.ModeratorID)
+			assert.Equal(t, "reason", action.Note)
+		}).
+		Return(nil)
+}
+
+func TestModerationGetReports(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		reports := []*entities.Report{{ID: 1}, {ID: 2}}
+		m.reports.EXPECT().CountOpen(gomock.Any()).Return(int64(12), nil)
+		m.reports.EXPECT().FindOpen(gomock.Any(), 10, 2).Return(reports, nil)
+
+		result, err := service.GetReports(ctx, 10, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(12), result.TotalItems)
+		assert.Equal(t, 10, result.Offset)
+		assert.Equal(t, []interface{}{reports[0], reports[1]}, result.Items)
+	})
+
+	t.Run("Not a moderator", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		service, _ := newModerationService(ctrl)
+
+		_, err := service.GetReports(ctx, 0, 10)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Failed to count", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.reports.EXPECT().CountOpen(gomock.Any()).Return(int64(0), errors.New("error"))
+
+		_, err := service.GetReports(ctx, 0, 10)
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestModerationGetActions(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		actions := []*entities.ModerationAction{{ID: 2}, {ID: 1}}
+		m.actions.EXPECT().CountAll(gomock.Any()).Return(int64(2), nil)
+		m.actions.EXPECT().GetAll(gomock.Any(), 0, 10).Return(actions, nil)
+
+		result, err := service.GetActions(ctx, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), result.TotalItems)
+		assert.Equal(t, []interface{}{actions[0], actions[1]}, result.Items)
+	})
+
+	t.Run("Not a moderator", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		service, _ := newModerationService(ctrl)
+
+		_, err := service.GetActions(ctx, 0, 10)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+}
+
+func TestModerateWork(t *testing.T) {
+	hiddenAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Hide", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		m.works.EXPECT().UpdateHidden(gomock.Any(), uint64(1), gomock.Not(gomock.Nil())).Return(nil)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportResolved).Return(nil)
+		expectAction(t, m, constants.ModerationHide)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionHide, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Unhide", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		w := publicWork(1, "author")
+		w.HiddenAt = &hiddenAt
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+		m.works.EXPECT().UpdateHidden(gomock.Any(), uint64(1), gomock.Nil()).Return(nil)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportDismissed).Return(nil)
+		expectAction(t, m, constants.ModerationUnhide)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionUnhide, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		gomock.InOrder(
+			m.works.EXPECT().UpdateHidden(gomock.Any(), uint64(1), gomock.Not(gomock.Nil())).Return(nil),
+			m.works.EXPECT().DeleteByID(gomock.Any(), uint64(1)).Return(nil),
+		)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportResolved).Return(nil)
+		expectAction(t, m, constants.ModerationRemove)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionRemove, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Remove a hidden work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		w := publicWork(1, "author")
+		w.HiddenAt = &hiddenAt
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+		m.works.EXPECT().DeleteByID(gomock.Any(), uint64(1)).Return(nil)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportResolved).Return(nil)
+		expectAction(t, m, constants.ModerationRemove)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionRemove, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Dismiss", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportDismissed).Return(nil)
+		expectAction(t, m, constants.ModerationDismiss)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionDismiss, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Already hidden", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		w := publicWork(1, "author")
+		w.HiddenAt = &hiddenAt
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionHide})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Not hidden", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionUnhide})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Action is invalid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, _ := newModerationService(ctrl)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionSuspend})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Work is not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionHide})
+
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Not a moderator", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		service, _ := newModerationService(ctrl)
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionHide})
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Failed to record", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.works.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		m.reports.EXPECT().ResolveByWorkID(gomock.Any(), uint64(1), constants.ReportDismissed).Return(nil)
+		m.actions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+		err := service.ModerateWork(ctx, 1, &beans.ModerationFormBean{Action: ModerationActionDismiss})
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestModerateUser(t *testing.T) {
+	suspendedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Suspend", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.users.EXPECT().FindByID(gomock.Any(), "user").Return(&entities.User{ID: "user"}, nil)
+		m.users.EXPECT().UpdateSuspended(gomock.Any(), "user", gomock.Not(gomock.Nil())).Return(nil)
+		m.actions.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, action *entities.ModerationAction) {
+				assert.Equal(t, constants.ModerationSuspend, action.Type)
+				assert.Equal(t, "user", *action.UserID)
+				assert.Nil(t, action.WorkID)
+			}).
+			Return(nil)
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionSuspend, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Unsuspend", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.users.EXPECT().FindByID(gomock.Any(), "user").Return(&entities.User{ID: "user", SuspendedAt: &suspendedAt}, nil)
+		m.users.EXPECT().UpdateSuspended(gomock.Any(), "user", gomock.Nil()).Return(nil)
+		expectAction(t, m, constants.ModerationUnsuspend)
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionUnsuspend, Note: "reason"})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Already suspended", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.users.EXPECT().FindByID(gomock.Any(), "user").Return(&entities.User{ID: "user", SuspendedAt: &suspendedAt}, nil)
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionSuspend})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Action is invalid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, _ := newModerationService(ctrl)
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionHide})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("User is not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupModeratorContext(ctx)
+		service, m := newModerationService(ctrl)
+
+		m.users.EXPECT().FindByID(gomock.Any(), "user").Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionSuspend})
+
+		assertErrorCode(t, myErr.WUE11, err)
+	})
+
+	t.Run("Not a moderator", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		service, _ := newModerationService(ctrl)
+
+		err := service.ModerateUser(ctx, "user", &beans.ModerationFormBean{Action: ModerationActionSuspend})
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+// publishBatchSize は、PublishDueが1回に取得する作品の件数
+const publishBatchSize = 100
+
+// PublishService は、公開予定の作品の公開機能のインターフェースを定義する
+type PublishService interface {
+	// PublishDue は、公開日時を過ぎた全ての公開予定の作品を公開し、公開した作品の数を返す
+	PublishDue(context.Context) (int, error)
+}
+
+// PublishServiceImpl は、公開予定の作品の公開機能を実装する
+type PublishServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	worksRepository      repositories.WorksRepository
+	activitiesRepository repositories.ActivitiesRepository
+}
+
+// NewPublishServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、PublishServiceImplの新しいインスタンスを生成する
+func NewPublishServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+) *PublishServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+
+	return &PublishServiceImpl{
+		transactionRunner:    tranRnr,
+		worksRepository:      worksRepo,
+		activitiesRepository: actRepo,
+	}
+}
+
+// PublishDue は、公開日時の順に作品を公開し、作品を追加したアクティビティを登録する。
+// 公開予定の状態は公開するまでデータベースに残るため、停止中に公開日時を過ぎた作品も起動後に公開する。
+// 他のサーバーが先に公開した作品や、削除された作品は数えない。
+func (r *PublishServiceImpl) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		works, err := r.worksRepository.FindDueToPublish(ctx, time.Now(), publishBatchSize)
+		if err != nil {
+			return published, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+
+		for _, w := range works {
+			if err := r.publish(ctx, w); err != nil {
+				var dbErr *myErr.RecordNotFoundError
+				if errors.As(err, &dbErr) {
+					continue
+				}
+				return published, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+			}
+			published++
+		}
+		if len(works) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// publish は、作品を公開し、作品を追加したアクティビティを登録する
+func (r *PublishServiceImpl) publish(ctx context.Context, w *entities.Work) error {
+	return r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.Publish(ctx, w.ID); err != nil {
+			return err
+		}
+		w.Status = constants.WorkPublished
+
+		act := &entities.Activity{
+			Type:   constants.ActivityAdded,
+			UserID: w.AuthorID,
+			Work:   w,
+		}
+		return r.activitiesRepository.Create(ctx, act)
+	})
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublishServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+
+		service := NewPublishServiceImpl(tr, worksRepo, actRepo)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+	})
+
+	t.Run("Transaction runner is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewPublishServiceImpl(nil, mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl))
+		})
+	})
+
+	t.Run("Works repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewPublishServiceImpl(mocks.NewMockTransactionRunner(ctrl), nil, mocks.NewMockActivitiesRepository(ctrl))
+		})
+	})
+
+	t.Run("Activities repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		assert.Panics(t, func() {
+			NewPublishServiceImpl(mocks.NewMockTransactionRunner(ctrl), mocks.NewMockWorksRepository(ctrl), nil)
+		})
+	})
+}
+
+func TestPublishDue(t *testing.T) {
+	// newService は、トランザクションを実行するTransactionRunnerを使用したPublishServiceImplを生成する
+	newService := func(ctrl *gomock.Controller, ctx context.Context, worksRepo *mocks.MockWorksRepository,
+		actRepo *mocks.MockActivitiesRepository) *PublishServiceImpl {
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			AnyTimes()
+
+		return NewPublishServiceImpl(tranRunner, worksRepo, actRepo)
+	}
+
+	t.Run("Publishes due works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		publishAt := time.Now().Add(-time.Minute)
+		works := []*entities.Work{
+			{ID: 1, AuthorID: "author1", Status: constants.WorkScheduled, PublishAt: &publishAt},
+			{ID: 2, AuthorID: "author2", Status: constants.WorkScheduled, PublishAt: &publishAt},
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return(works, nil)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		for _, w := range works {
+			worksRepo.EXPECT().Publish(gomock.Eq(ctx), w.ID)
+			actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
+				Type:   constants.ActivityAdded,
+				UserID: w.AuthorID,
+				Work:   w,
+			})
+		}
+
+		count, err := newService(ctrl, ctx, worksRepo, actRepo).PublishDue(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+		for _, w := range works {
+			assert.Equal(t, constants.WorkPublished, w.Status)
+		}
+	})
+
+	t.Run("Nothing to publish", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return([]*entities.Work{}, nil)
+
+		count, err := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl)).PublishDue(ctx)
+
+		assert.Nil(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Fetches next batch", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		first := make([]*entities.Work, publishBatchSize)
+		for i := range first {
+			first[i] = &entities.Work{ID: uint64(i + 1)}
+		}
+		second := []*entities.Work{{ID: publishBatchSize + 1}}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		gomock.InOrder(
+			worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return(first, nil),
+			worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return(second, nil),
+		)
+		worksRepo.EXPECT().Publish(gomock.Eq(ctx), gomock.Any()).Times(publishBatchSize + 1)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Times(publishBatchSize + 1)
+
+		count, err := newService(ctrl, ctx, worksRepo, actRepo).PublishDue(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, publishBatchSize+1, count)
+	})
+
+	t.Run("Skips works published by another server", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		works := []*entities.Work{{ID: 1}, {ID: 2}}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return(works, nil)
+		worksRepo.EXPECT().Publish(gomock.Eq(ctx), uint64(1)).Return(myErr.NewRecordNotFoundError("", nil))
+		worksRepo.EXPECT().Publish(gomock.Eq(ctx), uint64(2))
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		count, err := newService(ctrl, ctx, worksRepo, actRepo).PublishDue(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Failed to find works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		dbErr := errors.New("db error")
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).Return(nil, dbErr)
+
+		count, err := newService(ctrl, ctx, worksRepo, mocks.NewMockActivitiesRepository(ctrl)).PublishDue(ctx)
+
+		assert.True(t, errors.Is(err, dbErr))
+		assertErrorCode(t, myErr.WUE99, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Failed to add activity", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		dbErr := errors.New("db error")
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDueToPublish(gomock.Eq(ctx), gomock.Any(), publishBatchSize).
+			Return([]*entities.Work{{ID: 1}, {ID: 2}}, nil)
+		worksRepo.EXPECT().Publish(gomock.Eq(ctx), uint64(1))
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Return(dbErr)
+
+		count, err := newService(ctrl, ctx, worksRepo, actRepo).PublishDue(ctx)
+
+		assert.True(t, errors.Is(err, dbErr))
+		assertErrorCode(t, myErr.WUE99, err)
+		assert.Zero(t, count)
+	})
+}
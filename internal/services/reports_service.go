@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgReportsRepository = "reports repository"
+const msgModerationActionsRepository = "moderation actions repository"
+
+// ReportsService は、作品の通報の機能のインターフェースを定義する
+type ReportsService interface {
+	// Create は、ログイン中のユーザーが閲覧できる作品を通報する。通報済みの場合は何もしない。自分の作品は通報できない。
+	// 未対応の通報の数がしきい値に達した場合は、作品を自動で非表示にし、モデレーターの操作として記録する。
+	Create(ctx context.Context, workID uint64, bean *beans.ReportFormBean) error
+}
+
+// ReportsServiceImpl は、作品の通報の機能を実装する
+type ReportsServiceImpl struct {
+	transactionRunner           repositories.TransactionRunner
+	reportsRepository           repositories.ReportsRepository
+	worksRepository             repositories.WorksRepository
+	moderationActionsRepository repositories.ModerationActionsRepository
+	reportThreshold             int
+}
+
+// NewReportsServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、ReportsServiceImplの新しいインスタンスを生成する。
+// reportThresholdは、作品を自動で非表示にする未対応の通報の数。0の場合は自動で非表示にしない。
+func NewReportsServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	reportsRepo repositories.ReportsRepository,
+	worksRepo repositories.WorksRepository,
+	moderationActionsRepo repositories.ModerationActionsRepository,
+	reportThreshold int,
+) *ReportsServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if reportsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgReportsRepository))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if moderationActionsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgModerationActionsRepository))
+	}
+
+	return &ReportsServiceImpl{
+		transactionRunner:           tranRnr,
+		reportsRepository:           reportsRepo,
+		worksRepository:             worksRepo,
+		moderationActionsRepository: moderationActionsRepo,
+		reportThreshold:             reportThreshold,
+	}
+}
+
+//Create は、作品を通報する
+func (r *ReportsServiceImpl) Create(ctx context.Context, workID uint64, bean *beans.ReportFormBean) error {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+	w, err := findViewableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return err
+	}
+	if w.AuthorID == sub {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	report := &entities.Report{
+		WorkID:     workID,
+		ReporterID: sub,
+		Reason:     bean.Reason,
+		Note:       bean.Note,
+		Status:     constants.ReportOpen,
+	}
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		added, err := r.reportsRepository.Create(ctx, report)
+		if err != nil {
+			return err
+		}
+		if !added || r.reportThreshold <= 0 || w.HiddenAt != nil {
+			return nil
+		}
+
+		count, err := r.reportsRepository.CountOpenByWorkID(ctx, workID)
+		if err != nil {
+			return err
+		}
+		if count < int64(r.reportThreshold) {
+			return nil
+		}
+
+		// 通報の数がしきい値に達したため、モデレーターが確認するまで作者以外に表示しない
+		now := time.Now()
+		if err := r.worksRepository.UpdateHidden(ctx, workID, &now); err != nil {
+			return err
+		}
+		return r.moderationActionsRepository.Create(ctx, &entities.ModerationAction{
+			Type:   constants.ModerationAutoHide,
+			WorkID: &workID,
+			Note:   fmt.Sprintf("%d open reports", count),
+		})
+	})
+	if err != nil {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReportsServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+
+		service := NewReportsServiceImpl(tr, reportsRepo, worksRepo, actionsRepo, 5)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.reportsRepository, reportsRepo)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.moderationActionsRepository, actionsRepo)
+		assert.Equal(t, 5, service.reportThreshold)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewReportsServiceImpl(nil, reportsRepo, worksRepo, actionsRepo, 5) }},
+			{"Reports repository", func() { NewReportsServiceImpl(tr, nil, worksRepo, actionsRepo, 5) }},
+			{"Works repository", func() { NewReportsServiceImpl(tr, reportsRepo, nil, actionsRepo, 5) }},
+			{"Moderation actions repository", func() { NewReportsServiceImpl(tr, reportsRepo, worksRepo, nil, 5) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// newReportsService は、トランザクションを実行するTransactionRunnerを使用したReportsServiceImplを生成する
+func newReportsService(ctrl *gomock.Controller, reportsRepo *mocks.MockReportsRepository, worksRepo *mocks.MockWorksRepository,
+	actionsRepo *mocks.MockModerationActionsRepository, threshold int) *ReportsServiceImpl {
+
+	return NewReportsServiceImpl(runTransactions(ctrl), reportsRepo, worksRepo, actionsRepo, threshold)
+}
+
+func TestReportsCreate(t *testing.T) {
+	bean := &beans.ReportFormBean{Reason: constants.ReportSpam, Note: "advertisement"}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		reportsRepo.EXPECT().Create(gomock.Any(), &entities.Report{
+			WorkID:     1,
+			ReporterID: subject,
+			Reason:     constants.ReportSpam,
+			Note:       "advertisement",
+			Status:     constants.ReportOpen,
+		}).Return(true, nil)
+		reportsRepo.EXPECT().CountOpenByWorkID(gomock.Any(), uint64(1)).Return(int64(2), nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Reaches the threshold", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		reportsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(true, nil)
+		reportsRepo.EXPECT().CountOpenByWorkID(gomock.Any(), uint64(1)).Return(int64(3), nil)
+		worksRepo.EXPECT().UpdateHidden(gomock.Any(), uint64(1), gomock.Not(gomock.Nil())).Return(nil)
+		actionsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, action *entities.ModerationAction) {
+				assert.Equal(t, constants.ModerationAutoHide, action.Type)
+				assert.Equal(t, uint64(1), *action.WorkID)
+				assert.Nil(t, action.ModeratorID)
+			}).
+			Return(nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Already reported", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 1)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		reportsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(false, nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Threshold is disabled", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 0)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		reportsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Own work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, subject), nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Work is hidden", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		w := publicWork(1, "author")
+		w.HiddenAt = &w.CreatedAt
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+
+		err := service.Create(ctx, 1, bean)
+
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Failed to create", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(publicWork(1, "author"), nil)
+		reportsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(false, errors.New("error"))
+
+		err := service.Create(ctx, 1, bean)
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		reportsRepo := mocks.NewMockReportsRepository(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actionsRepo := mocks.NewMockModerationActionsRepository(ctrl)
+		service := newReportsService(ctrl, reportsRepo, worksRepo, actionsRepo, 3)
+
+		err := service.Create(ctx, 1, bean)
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
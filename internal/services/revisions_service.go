@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+// RevisionsService は、作品の履歴の閲覧と復元機能のインターフェースを定義する。
+// 履歴は、作品を閲覧できるユーザーのみが閲覧できる。
+type RevisionsService interface {
+	// GetAll は、作品の履歴を新しいバージョンから順に取得する
+	GetAll(ctx context.Context, workID uint64, offset int, limit int) (*beans.PaginationBean, error)
+	// FindByVersion は、作品の指定したバージョンの履歴を取得する。ない場合はWUE01を返す。
+	FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error)
+	// Diff は、作品の2つのバージョンの間で変更された項目を返す。toが0の場合は、現在のバージョンと比較する。
+	Diff(ctx context.Context, workID uint64, from uint, to uint) (*beans.RevisionDiffBean, error)
+	// Restore は、作者が以前のバージョンの内容で作品を更新し、新しいバージョンとして記録する。
+	// 更新で差し替えたファイルは削除されずに残っているため、以前のバージョンのファイルをそのまま使用する。
+	Restore(ctx context.Context, workID uint64, version uint) (*entities.Work, error)
+}
+
+// RevisionsServiceImpl は、作品の履歴の閲覧と復元機能を実装する
+type RevisionsServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	worksRepository      repositories.WorksRepository
+	revisionsRepository  repositories.WorkRevisionsRepository
+	fileUploader         lib.StorageClient
+	privateURLExpiration time.Duration
+}
+
+// NewRevisionsServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、RevisionsServiceImplの新しいインスタンスを生成する。
+// privateURLExpirationは、非公開の作品の履歴のファイルを閲覧するための署名付きURLの有効期間。
+func NewRevisionsServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	worksRepo repositories.WorksRepository,
+	revisionsRepo repositories.WorkRevisionsRepository,
+	fileUploader lib.StorageClient,
+	privateURLExpiration time.Duration,
+) *RevisionsServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if revisionsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorkRevisionsRepository))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &RevisionsServiceImpl{
+		transactionRunner:    tranRnr,
+		worksRepository:      worksRepo,
+		revisionsRepository:  revisionsRepo,
+		fileUploader:         fileUploader,
+		privateURLExpiration: privateURLExpiration,
+	}
+}
+
+//GetAll は、作品の履歴を取得する
+func (r *RevisionsServiceImpl) GetAll(ctx context.Context, workID uint64, offset int, limit int) (*beans.PaginationBean, error) {
+	w, err := findViewableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := r.revisionsRepository.CountByWorkID(ctx, workID)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.revisionsRepository.FindByWorkID(ctx, workID, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		if err := r.signRevisionURLs(w, v); err != nil {
+			return nil, err
+		}
+		pagination.Items = append(pagination.Items, v)
+	}
+
+	return pagination, nil
+}
+
+//FindByVersion は、作品の指定したバージョンの履歴を取得する
+func (r *RevisionsServiceImpl) FindByVersion(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error) {
+	w, err := findViewableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := r.findRevision(ctx, workID, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.signRevisionURLs(w, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+//Diff は、2つのバージョンの項目を比較し、値が異なるものを項目の順に返す
+func (r *RevisionsServiceImpl) Diff(ctx context.Context, workID uint64, from uint, to uint) (*beans.RevisionDiffBean, error) {
+	w, err := findViewableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = w.Version
+	}
+
+	before, err := r.findRevision(ctx, workID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.findRevision(ctx, workID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// 署名付きURLは署名する毎に異なるため、署名する前に比較する
+	beforeFields, afterFields := revisionFields(before), revisionFields(after)
+	var changed []int
+	for i := range beforeFields {
+		if !reflect.DeepEqual(beforeFields[i].value, afterFields[i].value) {
+			changed = append(changed, i)
+		}
+	}
+	if err := r.signRevisionURLs(w, before); err != nil {
+		return nil, err
+	}
+	if err := r.signRevisionURLs(w, after); err != nil {
+		return nil, err
+	}
+
+	diff := &beans.RevisionDiffBean{
+		From:    from,
+		To:      to,
+		Changes: make([]*beans.FieldChangeBean, 0, len(changed)),
+	}
+	beforeFields, afterFields = revisionFields(before), revisionFields(after)
+	for _, i := range changed {
+		diff.Changes = append(diff.Changes, &beans.FieldChangeBean{
+			Field: beforeFields[i].name,
+			From:  beforeFields[i].value,
+			To:    afterFields[i].value,
+		})
+	}
+	return diff, nil
+}
+
+//Restore は、以前のバージョンの内容で作品を更新する
+func (r *RevisionsServiceImpl) Restore(ctx context.Context, workID uint64, version uint) (*entities.Work, error) {
+	w, editor, err := findEditableWork(ctx, r.worksRepository, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := r.findRevision(ctx, workID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Title = revision.Title
+	w.Description = revision.Description
+	w.ThumbnailURL = revision.ThumbnailURL
+	w.Thumbnails = revision.Thumbnails
+	w.ContentURL = revision.ContentURL
+	w.ContentType = revision.ContentType
+	w.ContentSHA256 = revision.ContentSHA256
+	w.ContentSize = revision.ContentSize
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.Update(ctx, w); err != nil {
+			return err
+		}
+		return r.revisionsRepository.Create(ctx, entities.NewWorkRevision(w, editor))
+	})
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE07), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if err := signFileURLs(r.fileUploader, r.privateURLExpiration, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// findRevision は、作品の指定したバージョンの履歴を取得する
+func (r *RevisionsServiceImpl) findRevision(ctx context.Context, workID uint64, version uint) (*entities.WorkRevision, error) {
+	revision, err := r.revisionsRepository.FindByVersion(ctx, workID, version)
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return revision, nil
+}
+
+// signRevisionURLs は、非公開の作品の履歴のファイルのURLを、有効期間のある署名付きURLに置き換える
+func (r *RevisionsServiceImpl) signRevisionURLs(w *entities.Work, revision *entities.WorkRevision) error {
+	if w.Visibility != constants.VisibilityPrivate {
+		return nil
+	}
+	return signStorageURLs(r.fileUploader, r.privateURLExpiration, revision.Type,
+		&revision.ThumbnailURL, &revision.ContentURL, revision.Thumbnails)
+}
+
+// revisionField は、比較する履歴の項目名と値を表す
+type revisionField struct {
+	name  string
+	value interface{}
+}
+
+// revisionFields は、比較する履歴の項目を、APIの項目名とともに返す
+func revisionFields(revision *entities.WorkRevision) []revisionField {
+	return []revisionField{
+		{"title", revision.Title},
+		{"description", revision.Description},
+		{"thumbnailUrl", revision.ThumbnailURL},
+		{"thumbnails", revision.Thumbnails},
+		{"contentUrl", revision.ContentURL},
+		{"contentType", revision.ContentType},
+		{"contentSha256", revision.ContentSHA256},
+		{"contentSize", revision.ContentSize},
+	}
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevisionsServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewRevisionsServiceImpl(tr, worksRepo, revisionsRepo, uploader, time.Minute)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.revisionsRepository, revisionsRepo)
+		assert.Same(t, service.fileUploader, uploader)
+		assert.Equal(t, time.Minute, service.privateURLExpiration)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewRevisionsServiceImpl(nil, worksRepo, revisionsRepo, uploader, 0) }},
+			{"Works repository", func() { NewRevisionsServiceImpl(tr, nil, revisionsRepo, uploader, 0) }},
+			{"Work revisions repository", func() { NewRevisionsServiceImpl(tr, worksRepo, nil, uploader, 0) }},
+			{"File uploader", func() { NewRevisionsServiceImpl(tr, worksRepo, revisionsRepo, nil, 0) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name+" is nil", func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// publishedWork は、指定した版の、作者の公開中の作品を表す
+func publishedWork(version uint) *entities.Work {
+	w := publicWork(1, subject)
+	w.Title = "hoge"
+	w.ContentURL = "https://example.com/content02"
+	w.Version = version
+	return w
+}
+
+func TestRevisionsGetAll(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+
+		revisions := []*entities.WorkRevision{
+			{WorkID: 1, Version: 2, Title: "hoge"},
+			{WorkID: 1, Version: 1, Title: "fuga"},
+		}
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().CountByWorkID(gomock.Eq(ctx), uint64(1)).Return(int64(2), nil)
+		revisionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), uint64(1), 0, 10).Return(revisions, nil)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 2,
+			Offset:     0,
+			Items:      []interface{}{revisions[0], revisions[1]},
+		}, res)
+	})
+
+	t.Run("Private work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		w := publishedWork(1)
+		w.Visibility = constants.VisibilityPrivate
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(w, nil)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: mocks.NewMockWorkRevisionsRepository(ctrl),
+		}
+
+		res, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Private work of the viewer", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publishedWork(1)
+		w.Visibility = constants.VisibilityPrivate
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(w, nil)
+
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().CountByWorkID(gomock.Eq(ctx), uint64(1)).Return(int64(1), nil)
+		revisionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), uint64(1), 0, 10).Return([]*entities.WorkRevision{
+			{WorkID: 1, Version: 1, Type: constants.ContentTypeFile, ContentURL: "https://example.com/content01"},
+		}, nil)
+
+		// 非公開の作品の履歴のファイルは、署名付きURLで返す
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().SignURL("https://example.com/content01", time.Minute).Return("https://example.com/content01?signature", nil)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:      worksRepo,
+			revisionsRepository:  revisionsRepo,
+			fileUploader:         fileUploader,
+			privateURLExpiration: time.Minute,
+		}
+
+		res, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/content01?signature", res.Items[0].(*entities.WorkRevision).ContentURL)
+	})
+
+	t.Run("Fail to count", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(1), nil)
+
+		dbErr := errors.New("db error")
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().CountByWorkID(gomock.Eq(ctx), uint64(1)).Return(int64(0), dbErr)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.GetAll(ctx, 1, 0, 10)
+
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, dbErr))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestRevisionsFindByVersion(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+
+		revision := &entities.WorkRevision{WorkID: 1, Version: 1, Title: "fuga"}
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(1)).Return(revision, nil)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.FindByVersion(ctx, 1, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, revision, res)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(3)).Return(nil, myErr.NewRecordNotFoundError("", nil))
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.FindByVersion(ctx, 1, 3)
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestRevisionsDiff(t *testing.T) {
+	t.Run("Compare with current version", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(3), nil)
+
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(1)).Return(&entities.WorkRevision{
+			Version:     1,
+			Title:       "hoge",
+			Description: "hogehoge",
+			ContentURL:  "https://example.com/content01",
+			ContentSize: 1,
+		}, nil)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(3)).Return(&entities.WorkRevision{
+			Version:     3,
+			Title:       "hoge",
+			Description: "fugafuga",
+			ContentURL:  "https://example.com/content02",
+			ContentSize: 1,
+		}, nil)
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.Diff(ctx, 1, 1, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.RevisionDiffBean{
+			From: 1,
+			To:   3,
+			Changes: []*beans.FieldChangeBean{
+				{Field: "description", From: "hogehoge", To: "fugafuga"},
+				{Field: "contentUrl", From: "https://example.com/content01", To: "https://example.com/content02"},
+			},
+		}, res)
+	})
+
+	t.Run("Version not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(3), nil)
+
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(4)).Return(nil, myErr.NewRecordNotFoundError("", nil))
+
+		service := &RevisionsServiceImpl{
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.Diff(ctx, 1, 4, 2)
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestRevisionsRestore(t *testing.T) {
+	// newService は、トランザクションを実行するTransactionRunnerを使用したRevisionsServiceImplを生成する
+	newService := func(ctrl *gomock.Controller, ctx context.Context, worksRepo *mocks.MockWorksRepository,
+		revisionsRepo *mocks.MockWorkRevisionsRepository) *RevisionsServiceImpl {
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			AnyTimes()
+
+		return NewRevisionsServiceImpl(tranRunner, worksRepo, revisionsRepo, mocks.NewMockStorageClient(ctrl), time.Minute)
+	}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+
+		revision := &entities.WorkRevision{
+			WorkID:        1,
+			Version:       1,
+			Type:          constants.ContentTypeFile,
+			Title:         "fuga",
+			ContentURL:    "https://example.com/content01",
+			ContentType:   "application/zip",
+			ContentSHA256: "oldsha256",
+			ContentSize:   1,
+		}
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(1)).Return(revision, nil)
+
+		// 以前のバージョンのファイルをそのまま使用し、新しいバージョンとして記録する
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, w *entities.Work) error {
+			assert.Equal(t, revision.ContentURL, w.ContentURL)
+			w.Version++
+			return nil
+		})
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, r *entities.WorkRevision) error {
+			assert.Equal(t, uint(3), r.Version)
+			assert.Equal(t, revision.Title, r.Title)
+			assert.Equal(t, revision.ContentSHA256, r.ContentSHA256)
+			assert.Equal(t, subject, r.EditorID)
+			return nil
+		})
+
+		res, err := newService(ctrl, ctx, worksRepo, revisionsRepo).Restore(ctx, 1, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "fuga", res.Title)
+		assert.Equal(t, "https://example.com/content01", res.ContentURL)
+		assert.Equal(t, uint(3), res.Version)
+	})
+
+	t.Run("Not the author", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publishedWork(2)
+		w.AuthorID = "other"
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(w, nil)
+
+		res, err := newService(ctrl, ctx, worksRepo, mocks.NewMockWorkRevisionsRepository(ctrl)).Restore(ctx, 1, 1)
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Updated by another request", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any()).Return(myErr.NewRecordNotFoundError("", nil))
+
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(1)).Return(&entities.WorkRevision{Version: 1}, nil)
+
+		res, err := newService(ctrl, ctx, worksRepo, revisionsRepo).Restore(ctx, 1, 1)
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE07, err)
+	})
+
+	t.Run("Fail to record revision", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(publishedWork(2), nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any())
+
+		dbErr := errors.New("db error")
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().FindByVersion(gomock.Eq(ctx), uint64(1), uint(1)).Return(&entities.WorkRevision{Version: 1}, nil)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Return(dbErr)
+
+		res, err := newService(ctrl, ctx, worksRepo, revisionsRepo).Restore(ctx, 1, 1)
+
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, dbErr))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+	"golang.org/x/text/unicode/norm"
+)
+
+const msgTagsRepository = "tags repository"
+
+// maxTagLength は、タグの名前の最大文字数
+const maxTagLength = 30
+
+// maxTagsPerWork は、1つの作品に付けられるタグの最大数
+const maxTagsPerWork = 10
+
+// fieldTags は、タグのフォーム項目名
+const fieldTags = "tags"
+
+// TagsService は、タグの検索機能のインターフェースを定義する
+type TagsService interface {
+	// Search は、名前がprefixで始まるタグを、付いた公開の作品が多い順に最大limit件取得する。
+	// prefixは、タグと同様に正規化してから比較する。
+	Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error)
+}
+
+// TagsServiceImpl は、タグの検索機能を実装する
+type TagsServiceImpl struct {
+	tagsRepository repositories.TagsRepository
+}
+
+// NewTagsServiceImpl は、リポジトリオブジェクトを指定し、TagsServiceImplの新しいインスタンスを生成する
+func NewTagsServiceImpl(tagsRepo repositories.TagsRepository) *TagsServiceImpl {
+	if tagsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTagsRepository))
+	}
+
+	return &TagsServiceImpl{
+		tagsRepository: tagsRepo,
+	}
+}
+
+//Search は、タグを前方一致で検索する
+func (r *TagsServiceImpl) Search(ctx context.Context, prefix string, limit int) ([]*entities.TagCount, error) {
+	result, err := r.tagsRepository.Search(ctx, normalizeTag(prefix), limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return result, nil
+}
+
+// normalizeTags は、フォームで指定されたタグを正規化し、重複を除いて指定された順に返す。
+// 1つの項目にカンマで区切って複数のタグを指定することもできる。
+// 長すぎるタグがある場合と、タグが多すぎる場合はWUE00を返す。
+func normalizeTags(values []string) ([]string, error) {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			name = normalizeTag(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			if utf8.RuneCountInString(name) > maxTagLength {
+				return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldTags))
+			}
+			seen[name] = true
+			tags = append(tags, name)
+		}
+	}
+	if len(tags) > maxTagsPerWork {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldTags))
+	}
+	return tags, nil
+}
+
+// normalizeTag は、全角と半角、大文字と小文字の違いを除き、連続する空白を1つにしたタグの名前を返す。
+// 先頭の "#" は、ハッシュタグの記法として取り除く。
+func normalizeTag(name string) string {
+	name = strings.ToLower(norm.NFKC.String(name))
+	name = strings.Join(strings.Fields(name), " ")
+	return strings.TrimSpace(strings.TrimLeft(name, "#"))
+}
+
+// sameTags は、作品のタグがnamesと同じかを返す
+func sameTags(tags []*entities.Tag, names []string) bool {
+	if len(tags) != len(names) {
+		return false
+	}
+	current := make(map[string]bool, len(tags))
+	for _, v := range tags {
+		current[v.Name] = true
+	}
+	for _, name := range names {
+		if !current[name] {
+			return false
+		}
+	}
+	return true
+}
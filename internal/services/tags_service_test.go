@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTagsServiceImpl(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		tagsRepo := mocks.NewMockTagsRepository(ctrl)
+
+		service := NewTagsServiceImpl(tagsRepo)
+
+		assert.Same(t, tagsRepo, service.tagsRepository)
+	})
+
+	t.Run("repository is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTagsServiceImpl(nil)
+		})
+	})
+}
+
+func TestSearchTags(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := []*entities.TagCount{{Name: "game", Count: 3}}
+		tagsRepo := mocks.NewMockTagsRepository(ctrl)
+		// 前方一致の文字列もタグと同様に正規化する
+		tagsRepo.EXPECT().Search(ctx, "ga", 20).Return(expect, nil)
+
+		service := &TagsServiceImpl{
+			tagsRepository: tagsRepo,
+		}
+
+		actual, err := service.Search(ctx, "#ＧＡ", 20)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		errExpect := errors.New("error")
+		tagsRepo := mocks.NewMockTagsRepository(ctrl)
+		tagsRepo.EXPECT().Search(ctx, "", 20).Return(nil, errExpect)
+
+		service := &TagsServiceImpl{
+			tagsRepository: tagsRepo,
+		}
+
+		actual, err := service.Search(ctx, "", 20)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, errExpect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Run("is valid", func(t *testing.T) {
+		actual, err := normalizeTags([]string{" Go ", "#go,ＡＲＴ", "digital   art", ",", ""})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"go", "art", "digital art"}, actual)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		actual, err := normalizeTags(nil)
+
+		assert.Nil(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := normalizeTags([]string{strings.Repeat("あ", maxTagLength+1)})
+
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("max length", func(t *testing.T) {
+		actual, err := normalizeTags([]string{strings.Repeat("あ", maxTagLength)})
+
+		assert.Nil(t, err)
+		assert.Len(t, actual, 1)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		// 重複したタグは数えない
+		_, err := normalizeTags([]string{"a,b,c,d,e,f,g,h,i,j", "a"})
+		assert.Nil(t, err)
+
+		_, err = normalizeTags([]string{"a,b,c,d,e,f,g,h,i,j", "k"})
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+}
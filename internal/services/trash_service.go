@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+// trashPurgeBatchSize は、PurgeExpiredが1回に取得する作品の件数
+const trashPurgeBatchSize = 100
+
+// TrashService は、削除した作品のゴミ箱機能のインターフェースを定義する
+type TrashService interface {
+	// GetAll は、ログイン中のユーザーが削除した作品を、削除した日時の新しい順に取得する
+	GetAll(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error)
+	// Restore は、削除した作品を復元する。作者と管理者のみが復元できる。
+	Restore(ctx context.Context, id uint64) (*entities.Work, error)
+	// Purge は、削除した作品を保存期間を待たずに物理削除する。管理者のみが削除できる。
+	Purge(ctx context.Context, id uint64) error
+	// PurgeExpired は、保存期間を過ぎた削除済みの作品を物理削除し、削除した作品の数を返す
+	PurgeExpired(context.Context) (int, error)
+}
+
+// TrashServiceImpl は、削除した作品のゴミ箱機能を実装する
+type TrashServiceImpl struct {
+	transactionRunner    repositories.TransactionRunner
+	worksRepository      repositories.WorksRepository
+	activitiesRepository repositories.ActivitiesRepository
+	blobsRepository      repositories.BlobsRepository
+	fileUploader         lib.StorageClient
+	adminScope           string
+	retention            time.Duration
+	privateURLExpiration time.Duration
+}
+
+// NewTrashServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、TrashServiceImplの新しいインスタンスを生成する。
+// adminScopeは、管理者のアクセストークンが持つscope。retentionは、削除した作品をゴミ箱に残す期間。
+func NewTrashServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	worksRepo repositories.WorksRepository,
+	actRepo repositories.ActivitiesRepository,
+	blobsRepo repositories.BlobsRepository,
+	fileUploader lib.StorageClient,
+	adminScope string,
+	retention time.Duration,
+	privateURLExpiration time.Duration,
+) *TrashServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if worksRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorksRepository))
+	}
+	if actRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgActivitiesRepository))
+	}
+	if blobsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgBlobsRepository))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &TrashServiceImpl{
+		transactionRunner:    tranRnr,
+		worksRepository:      worksRepo,
+		activitiesRepository: actRepo,
+		blobsRepository:      blobsRepo,
+		fileUploader:         fileUploader,
+		adminScope:           adminScope,
+		retention:            retention,
+		privateURLExpiration: privateURLExpiration,
+	}
+}
+
+//GetAll は、ログイン中のユーザーが削除した作品を取得する
+func (r *TrashServiceImpl) GetAll(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	count, err := r.worksRepository.CountTrashed(ctx, sub)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	result, err := r.worksRepository.FindTrashed(ctx, sub, offset, limit)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	pagination := &beans.PaginationBean{
+		TotalItems: count,
+		Offset:     offset,
+		Items:      make([]interface{}, 0, len(result)),
+	}
+	for _, v := range result {
+		if err := signFileURLs(r.fileUploader, r.privateURLExpiration, v); err != nil {
+			return nil, err
+		}
+		pagination.Items = append(pagination.Items, v)
+	}
+
+	return pagination, nil
+}
+
+//Restore は、削除した作品を復元し、作者のアクティビティとして登録する
+func (r *TrashServiceImpl) Restore(ctx context.Context, id uint64) (*entities.Work, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	w, err := r.findTrashedWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w.AuthorID != sub && !hasScope(ctx, r.adminScope) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.Restore(ctx, w.ID); err != nil {
+			return err
+		}
+
+		act := &entities.Activity{
+			Type:   constants.ActivityRestored,
+			UserID: w.AuthorID,
+			Work:   w,
+		}
+		return r.activitiesRepository.Create(ctx, act)
+	})
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	w.DeletedAt.Valid = false
+
+	if err := signFileURLs(r.fileUploader, r.privateURLExpiration, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+//Purge は、削除した作品をすぐに物理削除する
+func (r *TrashServiceImpl) Purge(ctx context.Context, id uint64) error {
+	if !hasScope(ctx, r.adminScope) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+
+	w, err := r.findTrashedWork(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.purge(ctx, w); err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+		}
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return nil
+}
+
+// PurgeExpired は、削除した日時の順に保存期間を過ぎた作品を物理削除する。
+// 他のサーバーが先に削除した作品は数えない。
+func (r *TrashServiceImpl) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		batchPurged := 0
+		works, err := r.worksRepository.FindDeletedBefore(ctx, time.Now().Add(-r.retention), trashPurgeBatchSize)
+		if err != nil {
+			return purged, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+
+		for _, w := range works {
+			if err := r.purge(ctx, w); err != nil {
+				var dbErr *myErr.RecordNotFoundError
+				if errors.As(err, &dbErr) {
+					continue
+				}
+				return purged, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+			}
+			batchPurged++
+		}
+		purged += batchPurged
+		// 削除できない作品は次の取得でも返るため、1件も削除できなければ終了する
+		if len(works) < trashPurgeBatchSize || batchPurged == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// findTrashedWork は、削除済みの作品を取得する。ない場合はWUE01を返す。
+func (r *TrashServiceImpl) findTrashedWork(ctx context.Context, id uint64) (*entities.Work, error) {
+	w, err := r.worksRepository.FindTrashedByID(ctx, id)
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return w, nil
+}
+
+// purge は、作品とその履歴を物理削除し、他の作品から参照されなくなったファイルを削除する。
+// 履歴のファイルも作品の参照として登録されているため、まとめて削除される。
+func (r *TrashServiceImpl) purge(ctx context.Context, w *entities.Work) error {
+	var released []string
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		keys, err := r.blobsRepository.FindKeysByWorkID(ctx, w.ID)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			refs, err := r.blobsRepository.Release(ctx, w.ID, key)
+			if err != nil {
+				return err
+			}
+			if refs == 0 {
+				released = append(released, key)
+			}
+		}
+		return r.worksRepository.PurgeByID(ctx, w.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 作品は削除済みのため、ファイルの削除の失敗はログに出力するのみとする
+	for _, key := range released {
+		if err := r.fileUploader.Delete(key); err != nil {
+			log.Printf("failed to delete %s: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/common/constants"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const adminScope string = "admin:works"
+
+func TestNewTrashServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewTrashServiceImpl(tr, worksRepo, actRepo, blobsRepo, uploader, adminScope, time.Hour, time.Minute)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.worksRepository, worksRepo)
+		assert.Same(t, service.activitiesRepository, actRepo)
+		assert.Same(t, service.blobsRepository, blobsRepo)
+		assert.Same(t, service.fileUploader, uploader)
+		assert.Equal(t, adminScope, service.adminScope)
+		assert.Equal(t, time.Hour, service.retention)
+		assert.Equal(t, time.Minute, service.privateURLExpiration)
+	})
+
+	t.Run("Dependencies are nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() { NewTrashServiceImpl(nil, worksRepo, actRepo, blobsRepo, uploader, adminScope, 0, 0) }},
+			{"Works repository", func() { NewTrashServiceImpl(tr, nil, actRepo, blobsRepo, uploader, adminScope, 0, 0) }},
+			{"Activities repository", func() { NewTrashServiceImpl(tr, worksRepo, nil, blobsRepo, uploader, adminScope, 0, 0) }},
+			{"Blobs repository", func() { NewTrashServiceImpl(tr, worksRepo, actRepo, nil, uploader, adminScope, 0, 0) }},
+			{"File uploader", func() { NewTrashServiceImpl(tr, worksRepo, actRepo, blobsRepo, nil, adminScope, 0, 0) }},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
+	})
+}
+
+// newTrashService は、トランザクションを実行するTransactionRunnerを使用したTrashServiceImplを生成する
+func newTrashService(ctrl *gomock.Controller, worksRepo *mocks.MockWorksRepository, actRepo *mocks.MockActivitiesRepository,
+	blobsRepo *mocks.MockBlobsRepository, uploader *mocks.MockStorageClient) *TrashServiceImpl {
+
+	return NewTrashServiceImpl(runTransactions(ctrl), worksRepo, actRepo, blobsRepo, uploader, adminScope, time.Hour, time.Minute)
+}
+
+// setupAdminContext は、管理者のscopeを持つ、作者でないユーザーのcontextを返す
+func setupAdminContext(ctx context.Context) context.Context {
+	//lint:ignore SA1029 can use string only
+	return context.WithValue(ctx, userKey, &jwt.Token{
+		Claims: jwt.MapClaims{
+			"sub":   "admin",
+			"scope": "openid " + adminScope,
+		},
+	})
+}
+
+func TestTrashGetAll(t *testing.T) {
+	t.Run("Returns the trash of the user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		private := publicWork(2, subject)
+		private.Visibility = constants.VisibilityPrivate
+		private.ContentURL = "https://example.com/private/content"
+		works := []*entities.Work{publicWork(1, subject), private}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().CountTrashed(gomock.Eq(ctx), subject).Return(int64(12), nil)
+		worksRepo.EXPECT().FindTrashed(gomock.Eq(ctx), subject, 10, 2).Return(works, nil)
+		uploader := mocks.NewMockStorageClient(ctrl)
+		uploader.EXPECT().SignURL("https://example.com/private/content", time.Minute).Return("signed", nil)
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), uploader)
+
+		actual, err := service.GetAll(ctx, 10, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.PaginationBean{
+			TotalItems: 12,
+			Offset:     10,
+			Items:      []interface{}{works[0], works[1]},
+		}, actual)
+		assert.Equal(t, "signed", private.ContentURL)
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := newTrashService(ctrl, mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.GetAll(ctx, 0, 10)
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestTrashRestore(t *testing.T) {
+	t.Run("Author restores the work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, subject)
+		w.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).Return(w, nil)
+		worksRepo.EXPECT().Restore(gomock.Eq(ctx), uint64(1))
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
+			Type:   constants.ActivityRestored,
+			UserID: subject,
+			Work:   w,
+		})
+		service := newTrashService(ctrl, worksRepo, actRepo, mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		actual, err := service.Restore(ctx, 1)
+
+		assert.Nil(t, err)
+		assert.Same(t, w, actual)
+		assert.False(t, actual.DeletedAt.Valid)
+	})
+
+	t.Run("Admin restores the work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupAdminContext(ctx)
+
+		w := publicWork(1, subject)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).Return(w, nil)
+		worksRepo.EXPECT().Restore(gomock.Eq(ctx), uint64(1))
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		// アクティビティは作者のものとして登録する
+		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
+			Type:   constants.ActivityRestored,
+			UserID: subject,
+			Work:   w,
+		})
+		service := newTrashService(ctrl, worksRepo, actRepo, mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.Restore(ctx, 1)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Not the author", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).Return(publicWork(1, "other"), nil)
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.Restore(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Not in the trash", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).
+			Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.Restore(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Restored by another operation", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).Return(publicWork(1, subject), nil)
+		worksRepo.EXPECT().Restore(gomock.Eq(ctx), uint64(1)).Return(myErr.NewRecordNotFoundError("not found", nil))
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.Restore(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestTrashPurge(t *testing.T) {
+	t.Run("Admin purges the work and files no longer referenced", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupAdminContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).Return(publicWork(1, subject), nil)
+		worksRepo.EXPECT().PurgeByID(gomock.Eq(ctx), uint64(1))
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindKeysByWorkID(gomock.Eq(ctx), uint64(1)).Return([]string{"shared.png", "own.zip"}, nil)
+		blobsRepo.EXPECT().Release(gomock.Eq(ctx), uint64(1), "shared.png").Return(1, nil)
+		blobsRepo.EXPECT().Release(gomock.Eq(ctx), uint64(1), "own.zip").Return(0, nil)
+		uploader := mocks.NewMockStorageClient(ctrl)
+		// 他の作品から参照されているファイルは削除しない
+		uploader.EXPECT().Delete("own.zip")
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl), blobsRepo, uploader)
+
+		err := service.Purge(ctx, 1)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Not an admin", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := newTrashService(ctrl, mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		err := service.Purge(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Admin scope is not configured", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupAdminContext(ctx)
+
+		service := newTrashService(ctrl, mocks.NewMockWorksRepository(ctrl), mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+		service.adminScope = ""
+
+		err := service.Purge(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE02, err)
+	})
+
+	t.Run("Not in the trash", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupAdminContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindTrashedByID(gomock.Eq(ctx), uint64(1)).
+			Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		err := service.Purge(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+}
+
+func TestTrashPurgeExpired(t *testing.T) {
+	t.Run("Purges works deleted before the retention", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDeletedBefore(gomock.Eq(ctx), gomock.Any(), trashPurgeBatchSize).
+			DoAndReturn(func(_ context.Context, before time.Time, _ int) ([]*entities.Work, error) {
+				assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+				return []*entities.Work{publicWork(1, subject), publicWork(2, subject)}, nil
+			})
+		worksRepo.EXPECT().PurgeByID(gomock.Eq(ctx), uint64(1))
+		// 他のサーバーが先に削除した作品は数えない
+		worksRepo.EXPECT().PurgeByID(gomock.Eq(ctx), uint64(2)).Return(myErr.NewRecordNotFoundError("not found", nil))
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindKeysByWorkID(gomock.Eq(ctx), gomock.Any()).Return(nil, nil).Times(2)
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl), blobsRepo,
+			mocks.NewMockStorageClient(ctrl))
+
+		n, err := service.PurgeExpired(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Stops when nothing in a full batch was purged", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		works := make([]*entities.Work, trashPurgeBatchSize)
+		for i := range works {
+			works[i] = publicWork(uint64(i+1), subject)
+		}
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDeletedBefore(gomock.Eq(ctx), gomock.Any(), trashPurgeBatchSize).Return(works, nil)
+		worksRepo.EXPECT().PurgeByID(gomock.Eq(ctx), gomock.Any()).
+			Return(myErr.NewRecordNotFoundError("not found", nil)).Times(trashPurgeBatchSize)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().FindKeysByWorkID(gomock.Eq(ctx), gomock.Any()).Return(nil, nil).Times(trashPurgeBatchSize)
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl), blobsRepo,
+			mocks.NewMockStorageClient(ctrl))
+
+		n, err := service.PurgeExpired(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Fail to find works", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("error")
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindDeletedBefore(gomock.Eq(ctx), gomock.Any(), trashPurgeBatchSize).Return(nil, expect)
+		service := newTrashService(ctrl, worksRepo, mocks.NewMockActivitiesRepository(ctrl),
+			mocks.NewMockBlobsRepository(ctrl), mocks.NewMockStorageClient(ctrl))
+
+		_, err := service.PurgeExpired(ctx)
+
+		assert.True(t, errors.Is(err, expect))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
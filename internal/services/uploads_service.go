@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+)
+
+const msgUploadsRepository = "uploads repository"
+
+// purgeBatchSize は、PurgeExpiredが1回で削除するアップロードの最大件数
+const purgeBatchSize = 100
+
+// UploadsService は、再開可能なアップロード機能のインターフェースを定義する
+type UploadsService interface {
+	// Create は、指定したバイト数のファイルを受け付けるアップロードを作成する
+	Create(ctx context.Context, length int64, filename string) (*entities.Upload, error)
+	// CreateSession は、クライアントがストレージへ直接送信するアップロードを作成し、送信先の署名付きリクエストを返す
+	CreateSession(context.Context, *beans.UploadSessionFormBean) (*beans.UploadSessionBean, error)
+	FindByID(context.Context, string) (*entities.Upload, error)
+	// Append は、offsetの位置にbodyの内容を追記する。全て受信した場合は1つのファイルにまとめる。
+	Append(ctx context.Context, id string, offset int64, body io.Reader) (*entities.Upload, error)
+	// DeleteByID は、アップロードを中止し、受信済みの内容を削除する
+	DeleteByID(context.Context, string) error
+	// PurgeExpired は、期限切れのアップロードを削除し、削除した件数を返す
+	PurgeExpired(context.Context) (int, error)
+}
+
+// UploadsServiceImpl は、再開可能なアップロード機能を実装する
+type UploadsServiceImpl struct {
+	transactionRunner repositories.TransactionRunner
+	uploadsRepository repositories.UploadsRepository
+	uuidGenerator     lib.UUIDGenerator
+	fileUploader      lib.StorageClient
+	maxSize           int64
+	expiration        time.Duration
+	presignExpiration time.Duration
+}
+
+// NewUploadsServiceImpl は、受け付ける最大バイト数と、作成からの有効期間、直接送信する署名付きURLの有効期間を指定し、
+// UploadsServiceImplの新しいインスタンスを生成する
+func NewUploadsServiceImpl(
+	tranRnr repositories.TransactionRunner,
+	uploadsRepo repositories.UploadsRepository,
+	uuidGenerator lib.UUIDGenerator,
+	fileUploader lib.StorageClient,
+	maxSize int64,
+	expiration time.Duration,
+	presignExpiration time.Duration,
+) *UploadsServiceImpl {
+
+	if tranRnr == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTransactionRunner))
+	}
+	if uploadsRepo == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgUploadsRepository))
+	}
+	if uuidGenerator == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgUUIDGenerator))
+	}
+	if fileUploader == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
+	}
+
+	return &UploadsServiceImpl{
+		transactionRunner: tranRnr,
+		uploadsRepository: uploadsRepo,
+		uuidGenerator:     uuidGenerator,
+		fileUploader:      fileUploader,
+		maxSize:           maxSize,
+		expiration:        expiration,
+		presignExpiration: presignExpiration,
+	}
+}
+
+//Create は、アップロードを作成する。内容はAppendで受信する。
+func (r *UploadsServiceImpl) Create(ctx context.Context, length int64, filename string) (*entities.Upload, error) {
+	u, err := r.newUpload(ctx, length, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.uploadsRepository.Create(ctx, u)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if u.Length == 0 {
+		return r.complete(ctx, u)
+	}
+
+	return u, nil
+}
+
+//CreateSession は、アップロードを作成し、StorageKeyへ直接送信する署名付きリクエストを生成する。
+//送信されたファイルは、作品の登録時にストレージ上のサイズとContent-Typeを検証する。
+func (r *UploadsServiceImpl) CreateSession(ctx context.Context, form *beans.UploadSessionFormBean) (*beans.UploadSessionBean, error) {
+	if mediaType, _, err := mime.ParseMediaType(form.ContentType); err != nil || !strings.Contains(mediaType, "/") {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams("contentType"))
+	}
+
+	u, err := r.newUpload(ctx, form.Length, form.Filename)
+	if err != nil {
+		return nil, err
+	}
+	u.ContentType = form.ContentType
+
+	req, err := r.fileUploader.PresignUpload(u.StorageKey, u.Filename, &lib.ObjectInfo{
+		Size:        u.Length,
+		ContentType: u.ContentType,
+	}, r.presignExpiration)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.uploadsRepository.Create(ctx, u)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	headers := make(map[string]string, len(req.Headers))
+	for k := range req.Headers {
+		headers[k] = req.Headers.Get(k)
+	}
+
+	return &beans.UploadSessionBean{
+		ID:        u.ID,
+		Method:    req.Method,
+		URL:       req.URL,
+		Headers:   headers,
+		ExpiresAt: req.ExpiresAt,
+	}, nil
+}
+
+//FindByID は、自分が作成した期限内のアップロードを取得する
+func (r *UploadsServiceImpl) FindByID(ctx context.Context, id string) (*entities.Upload, error) {
+	owner, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	return findOwnUpload(ctx, r.uploadsRepository, id, owner)
+}
+
+//Append は、受信した内容を1つのチャンクとしてストレージに保存し、受信位置を進める
+func (r *UploadsServiceImpl) Append(ctx context.Context, id string, offset int64, body io.Reader) (*entities.Upload, error) {
+	u, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != u.Offset {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE05))
+	}
+	if u.Offset == u.Length {
+		if u.CompletedAt != nil {
+			return u, nil
+		}
+		// 前回まとめる処理に失敗している場合は、やり直す
+		return r.complete(ctx, u)
+	}
+
+	// 同じ位置への要求が同時に行われても互いに上書きしないよう、要求毎に異なるキーに保存する
+	number := u.Chunks
+	key := chunkKey(u.StorageKey, number, r.uuidGenerator.Generate())
+	// 接続が切れた場合も受信した分から再開できるよう、読み込みのエラーはそこまでの内容で送信を終える
+	received := &interruptedReader{reader: body}
+	reader := &sizeLimitedReader{reader: received, limit: u.Length - u.Offset}
+	if err := r.fileUploader.Upload(key, reader, u.Filename, octetStream); err != nil {
+		r.deleteFiles([]string{key})
+		if reader.exceeded {
+			return nil, myErr.NewApplicationError(
+				myErr.Code(myErr.WUE03), myErr.MessageParams("Upload-Length", u.Length), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if reader.written == 0 {
+		r.deleteFiles([]string{key})
+		if received.err != nil {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(received.err))
+		}
+		return u, nil
+	}
+
+	u.Offset += reader.written
+	u.Chunks++
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.uploadsRepository.UpdateProgress(ctx, u, number); err != nil {
+			return err
+		}
+		return r.uploadsRepository.CreateChunk(ctx, &entities.UploadChunk{UploadID: u.ID, Number: number, StorageKey: key})
+	})
+	if err != nil {
+		// 記録できなかったチャンクは使用しない。同じ位置への要求が同時に行われた場合は、先に更新した要求のチャンクが残る。
+		r.deleteFiles([]string{key})
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE05), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if received.err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(received.err))
+	}
+
+	if u.Offset == u.Length {
+		return r.complete(ctx, u)
+	}
+
+	return u, nil
+}
+
+//DeleteByID は、自分が作成したアップロードを削除する
+func (r *UploadsServiceImpl) DeleteByID(ctx context.Context, id string) error {
+	u, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.delete(ctx, u); err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	return nil
+}
+
+//PurgeExpired は、作品から参照されないまま期限が切れたアップロードを削除する
+func (r *UploadsServiceImpl) PurgeExpired(ctx context.Context) (int, error) {
+	expired, err := r.uploadsRepository.FindExpired(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	count := 0
+	for _, u := range expired {
+		if err := r.delete(ctx, u); err != nil {
+			var dbErr *myErr.RecordNotFoundError
+			if errors.As(err, &dbErr) {
+				continue
+			}
+			return count, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// newUpload は、ログイン中のユーザーが作成するアップロードを生成する
+func (r *UploadsServiceImpl) newUpload(ctx context.Context, length int64, filename string) (*entities.Upload, error) {
+	owner, ok := extractSubject(ctx)
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	if length < 0 {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams("Upload-Length"))
+	}
+	if length > r.maxSize {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE03), myErr.MessageParams("Upload-Length", r.maxSize))
+	}
+
+	return &entities.Upload{
+		ID:         r.uuidGenerator.Generate(),
+		UserID:     owner,
+		Filename:   filename,
+		StorageKey: fmt.Sprintf("%s%s", r.uuidGenerator.Generate(), filepath.Ext(filename)),
+		Length:     length,
+		ExpiresAt:  time.Now().Add(r.expiration),
+	}, nil
+}
+
+// complete は、受信したチャンクを順に読み込んで1つのファイルにまとめ、アップロードを完了する
+func (r *UploadsServiceImpl) complete(ctx context.Context, u *entities.Upload) (*entities.Upload, error) {
+	chunks, err := r.chunkKeys(ctx, u.ID)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if len(chunks) != u.Chunks {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99),
+			myErr.Cause(fmt.Errorf("upload %s has %d chunks recorded, expected %d", u.ID, len(chunks), u.Chunks)))
+	}
+
+	reader := &chunksReader{fileUploader: r.fileUploader, keys: chunks}
+	contentType, body, err := sniffContentType(reader)
+	if err == nil {
+		err = r.fileUploader.Upload(u.StorageKey, body, u.Filename, contentType)
+	}
+	reader.Close()
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	completedAt := time.Now()
+	u.CompletedAt = &completedAt
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.uploadsRepository.UpdateProgress(ctx, u, u.Chunks)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	r.deleteFiles(chunks)
+
+	return u, nil
+}
+
+// delete は、アップロードを削除し、受信済みの内容を削除する
+func (r *UploadsServiceImpl) delete(ctx context.Context, u *entities.Upload) error {
+	// チャンクの記録はアップロードと共に削除されるため、先に取得する
+	chunks, err := r.chunkKeys(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.uploadsRepository.DeleteByID(ctx, u.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.deleteFiles(append([]string{u.StorageKey}, chunks...))
+
+	return nil
+}
+
+// chunkKeys は、アップロードが受信したチャンクのキーを受信した順に返す
+func (r *UploadsServiceImpl) chunkKeys(ctx context.Context, uploadID string) ([]string, error) {
+	chunks, err := r.uploadsRepository.FindChunks(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		keys = append(keys, c.StorageKey)
+	}
+	return keys, nil
+}
+
+func (r *UploadsServiceImpl) deleteFiles(keys []string) {
+	for _, key := range keys {
+		if err := r.fileUploader.Delete(key); err != nil {
+			log.Printf("failed to delete %s: %v", key, err)
+		}
+	}
+}
+
+// findOwnUpload は、ownerが作成した期限内のアップロードを取得する。
+// 他のユーザーのアップロードは、存在を知らせないよう見つからないものとして扱う。
+func findOwnUpload(ctx context.Context, repo repositories.UploadsRepository, id string, owner string) (*entities.Upload, error) {
+	u, err := repo.FindByID(ctx, id)
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if u.UserID != owner || !u.ExpiresAt.After(time.Now()) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE04))
+	}
+
+	return u, nil
+}
+
+// sameMediaType は、パラメーターを除いたメディアタイプが一致するかを返す
+func sameMediaType(a string, b string) bool {
+	ma, _, errA := mime.ParseMediaType(a)
+	mb, _, errB := mime.ParseMediaType(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return strings.EqualFold(ma, mb)
+}
+
+// chunkKey は、i番目に受信したチャンクを保存するキーを返す。attemptは、要求毎に異なる値にする。
+func chunkKey(key string, i int, attempt string) string {
+	return fmt.Sprintf("%s.%d.%s.part", key, i, attempt)
+}
+
+// interruptedReader は、読み込み中にEOF以外のエラーが発生した場合、エラーを保持して読み込みを終える
+type interruptedReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// chunksReader は、複数のチャンクを順に開き、1つのファイルとして読み込む
+type chunksReader struct {
+	fileUploader lib.StorageClient
+	keys         []string
+	current      io.ReadCloser
+}
+
+func (r *chunksReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := r.fileUploader.Open(r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current = rc
+			r.keys = r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			return n, nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunksReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
@@ -0,0 +1,868 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
+	"github.com/edy4c7/works-uploader/internal/mocks"
+	"github.com/edy4c7/works-uploader/internal/repositories"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUploadsServiceImpl(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		service := NewUploadsServiceImpl(tr, uploadsRepo, uuidGenerator, uploader, 10, time.Hour, time.Minute)
+
+		assert.Same(t, service.transactionRunner, tr)
+		assert.Same(t, service.uploadsRepository, uploadsRepo)
+		assert.Same(t, service.uuidGenerator, uuidGenerator)
+		assert.Same(t, service.fileUploader, uploader)
+		assert.Equal(t, int64(10), service.maxSize)
+		assert.Equal(t, time.Hour, service.expiration)
+		assert.Equal(t, time.Minute, service.presignExpiration)
+	})
+
+	t.Run("Transaction runner is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		assert.Panics(t, func() {
+			NewUploadsServiceImpl(nil, uploadsRepo, uuidGenerator, uploader, 10, time.Hour, time.Minute)
+		})
+	})
+
+	t.Run("Uploads repository is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		assert.Panics(t, func() {
+			NewUploadsServiceImpl(tr, nil, uuidGenerator, uploader, 10, time.Hour, time.Minute)
+		})
+	})
+
+	t.Run("UUID generator is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploader := mocks.NewMockStorageClient(ctrl)
+
+		assert.Panics(t, func() {
+			NewUploadsServiceImpl(tr, uploadsRepo, nil, uploader, 10, time.Hour, time.Minute)
+		})
+	})
+
+	t.Run("File uploader is nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tr := mocks.NewMockTransactionRunner(ctrl)
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+
+		assert.Panics(t, func() {
+			NewUploadsServiceImpl(tr, uploadsRepo, uuidGenerator, nil, 10, time.Hour, time.Minute)
+		})
+	})
+}
+
+func TestUploadsCreate(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		gomock.InOrder(
+			uuidGenerator.EXPECT().Generate().Return("upload01"),
+			uuidGenerator.EXPECT().Generate().Return("abcde12345"),
+		)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     uuidGenerator,
+			maxSize:           10,
+			expiration:        time.Hour,
+		}
+
+		before := time.Now()
+		res, err := service.Create(ctx, 10, "content01.zip")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "upload01", res.ID)
+		assert.Equal(t, subject, res.UserID)
+		assert.Equal(t, "content01.zip", res.Filename)
+		assert.Equal(t, "abcde12345.zip", res.StorageKey)
+		assert.Equal(t, int64(10), res.Length)
+		assert.Equal(t, int64(0), res.Offset)
+		assert.Nil(t, res.CompletedAt)
+		assert.False(t, res.ExpiresAt.Before(before.Add(time.Hour)))
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345").Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		uploadsRepo.EXPECT().FindChunks(gomock.Eq(ctx), "abcde12345").Return(nil, nil)
+		uploadsRepo.EXPECT().UpdateProgress(gomock.Eq(ctx), gomock.Any(), 0)
+
+		// 受信する内容がないため、空のファイルとして完了する
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345", gomock.Any(), "", "text/plain; charset=utf-8").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Empty(t, b)
+				return err
+			})
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     uuidGenerator,
+			fileUploader:      fileUploader,
+			maxSize:           10,
+			expiration:        time.Hour,
+		}
+
+		res, err := service.Create(ctx, 0, "")
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res.CompletedAt)
+	})
+
+	t.Run("Negative length", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &UploadsServiceImpl{maxSize: 10}
+
+		_, actual := service.Create(ctx, -1, "")
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("File too large", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &UploadsServiceImpl{maxSize: 10}
+
+		_, actual := service.Create(ctx, 11, "")
+
+		assertErrorCode(t, myErr.WUE03, actual)
+	})
+
+	t.Run("Fail to save upload", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to save")
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345").Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Return(expect)
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     uuidGenerator,
+			maxSize:           10,
+		}
+
+		_, actual := service.Create(ctx, 10, "")
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+}
+
+func TestCreateSession(t *testing.T) {
+	form := &beans.UploadSessionFormBean{
+		Filename:    "content01.zip",
+		ContentType: "application/zip",
+		Length:      10,
+	}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		gomock.InOrder(
+			uuidGenerator.EXPECT().Generate().Return("upload01"),
+			uuidGenerator.EXPECT().Generate().Return("abcde12345"),
+		)
+
+		expiresAt := time.Now().Add(time.Minute)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			PresignUpload("abcde12345.zip", "content01.zip", &lib.ObjectInfo{Size: 10, ContentType: "application/zip"}, time.Minute).
+			Return(&lib.PresignedRequest{
+				Method:    http.MethodPut,
+				URL:       "https://example.com/pending/abcde12345.zip?X-Amz-Signature=abc",
+				Headers:   http.Header{"Content-Type": {"application/zip"}},
+				ExpiresAt: expiresAt,
+			}, nil)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.
+			EXPECT().
+			Create(gomock.Eq(ctx), gomock.Any()).
+			Do(func(ctx context.Context, u *entities.Upload) {
+				assert.Equal(t, "upload01", u.ID)
+				assert.Equal(t, subject, u.UserID)
+				assert.Equal(t, "abcde12345.zip", u.StorageKey)
+				assert.Equal(t, "application/zip", u.ContentType)
+				assert.Equal(t, int64(10), u.Length)
+			})
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     uuidGenerator,
+			fileUploader:      fileUploader,
+			maxSize:           10,
+			expiration:        time.Hour,
+			presignExpiration: time.Minute,
+		}
+
+		res, err := service.CreateSession(ctx, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.UploadSessionBean{
+			ID:        "upload01",
+			Method:    http.MethodPut,
+			URL:       "https://example.com/pending/abcde12345.zip?X-Amz-Signature=abc",
+			Headers:   map[string]string{"Content-Type": "application/zip"},
+			ExpiresAt: expiresAt,
+		}, res)
+	})
+
+	t.Run("Invalid content type", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &UploadsServiceImpl{maxSize: 10}
+
+		_, actual := service.CreateSession(ctx, &beans.UploadSessionFormBean{
+			Filename:    "content01.zip",
+			ContentType: "zip",
+			Length:      10,
+		})
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("File too large", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &UploadsServiceImpl{maxSize: 9}
+
+		_, actual := service.CreateSession(ctx, form)
+
+		assertErrorCode(t, myErr.WUE03, actual)
+	})
+
+	t.Run("Fail to presign", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to presign")
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345").Times(2)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().PresignUpload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expect)
+
+		service := &UploadsServiceImpl{
+			uuidGenerator: uuidGenerator,
+			fileUploader:  fileUploader,
+			maxSize:       10,
+		}
+
+		_, actual := service.CreateSession(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+}
+
+func TestUploadsAppend(t *testing.T) {
+	newUpload := func() *entities.Upload {
+		return &entities.Upload{
+			ID:         "upload01",
+			UserID:     subject,
+			Filename:   "content01.zip",
+			StorageKey: "abcde12345.zip",
+			Length:     8,
+			Offset:     4,
+			Chunks:     1,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+	}
+	// newUUIDGenerator は、チャンクを保存するキーに使用する値を生成する
+	newUUIDGenerator := func(ctrl *gomock.Controller) *mocks.MockUUIDGenerator {
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("attempt1")
+		return uuidGenerator
+	}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345.zip.1.attempt1.part", gomock.Any(), "content01.zip", "application/octet-stream").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("12"), b)
+				return err
+			})
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		// 受信前のチャンク数を条件に更新する
+		uploadsRepo.
+			EXPECT().
+			UpdateProgress(gomock.Eq(ctx), gomock.Any(), 1).
+			Do(func(ctx context.Context, u *entities.Upload, expectedChunks int) {
+				assert.Equal(t, int64(6), u.Offset)
+				assert.Equal(t, 2, u.Chunks)
+			})
+		uploadsRepo.EXPECT().CreateChunk(gomock.Eq(ctx), &entities.UploadChunk{
+			UploadID:   "upload01",
+			Number:     1,
+			StorageKey: "abcde12345.zip.1.attempt1.part",
+		})
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		res, err := service.Append(ctx, "upload01", 4, strings.NewReader("12"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), res.Offset)
+		assert.Nil(t, res.CompletedAt)
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		gomock.InOrder(
+			fileUploader.
+				EXPECT().
+				Upload("abcde12345.zip.1.attempt1.part", gomock.Any(), "content01.zip", "application/octet-stream").
+				DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+					_, err := ioutil.ReadAll(body)
+					return err
+				}),
+			uploadsRepo.EXPECT().UpdateProgress(gomock.Eq(ctx), gomock.Any(), 1),
+			uploadsRepo.EXPECT().CreateChunk(gomock.Eq(ctx), gomock.Any()),
+			// 記録したチャンクを順に読み込んで1つのファイルにまとめる
+			uploadsRepo.EXPECT().FindChunks(gomock.Eq(ctx), "upload01").Return([]*entities.UploadChunk{
+				{UploadID: "upload01", Number: 0, StorageKey: "abcde12345.zip.0.attempt0.part"},
+				{UploadID: "upload01", Number: 1, StorageKey: "abcde12345.zip.1.attempt1.part"},
+			}, nil),
+			fileUploader.
+				EXPECT().
+				Upload("abcde12345.zip", gomock.Any(), "content01.zip", "text/plain; charset=utf-8").
+				DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+					b, err := ioutil.ReadAll(body)
+					assert.Equal(t, []byte("12345678"), b)
+					return err
+				}),
+			uploadsRepo.
+				EXPECT().
+				UpdateProgress(gomock.Eq(ctx), gomock.Any(), 2).
+				Do(func(ctx context.Context, u *entities.Upload, expectedChunks int) {
+					assert.NotNil(t, u.CompletedAt)
+				}),
+			fileUploader.EXPECT().Delete("abcde12345.zip.0.attempt0.part"),
+			fileUploader.EXPECT().Delete("abcde12345.zip.1.attempt1.part"),
+		)
+		fileUploader.EXPECT().Open("abcde12345.zip.0.attempt0.part").Return(ioutil.NopCloser(strings.NewReader("1234")), nil)
+		fileUploader.EXPECT().Open("abcde12345.zip.1.attempt1.part").Return(ioutil.NopCloser(strings.NewReader("5678")), nil)
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		res, err := service.Append(ctx, "upload01", 4, strings.NewReader("5678"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(8), res.Offset)
+		assert.NotNil(t, res.CompletedAt)
+	})
+
+	t.Run("Interrupted", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("connection reset")
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		// 接続が切れるまでに受信した内容を、チャンクとして残す
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345.zip.1.attempt1.part", gomock.Any(), "content01.zip", "application/octet-stream").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("12"), b)
+				return err
+			})
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		var saved *entities.Upload
+		uploadsRepo.
+			EXPECT().
+			UpdateProgress(gomock.Eq(ctx), gomock.Any(), 1).
+			Do(func(ctx context.Context, u *entities.Upload, expectedChunks int) {
+				saved = u
+			})
+		uploadsRepo.EXPECT().CreateChunk(gomock.Eq(ctx), gomock.Any())
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 4, io.MultiReader(strings.NewReader("12"), iotest.ErrReader(expect)))
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+
+		// 受信した位置から再開できる
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(saved, nil)
+
+		res, err := service.FindByID(ctx, "upload01")
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), res.Offset)
+		assert.Equal(t, 2, res.Chunks)
+	})
+
+	t.Run("Interrupted before receiving anything", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("connection reset")
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return err
+			})
+		fileUploader.EXPECT().Delete("abcde12345.zip.1.attempt1.part")
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 4, iotest.ErrReader(expect))
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Chunk is not recorded", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		u := newUpload()
+		u.Offset = u.Length
+		u.Chunks = 2
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(u, nil)
+		// 記録と数が合わない場合は、内容が欠けているためまとめない
+		uploadsRepo.EXPECT().FindChunks(gomock.Eq(ctx), "upload01").Return([]*entities.UploadChunk{
+			{UploadID: "upload01", Number: 0, StorageKey: "abcde12345.zip.0.attempt0.part"},
+		}, nil)
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 8, strings.NewReader(""))
+
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Offset mismatch", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 0, strings.NewReader("12"))
+
+		assertErrorCode(t, myErr.WUE05, actual)
+	})
+
+	t.Run("Exceeds length", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return err
+			})
+		// 途中まで書き込んだチャンクを削除する
+		fileUploader.EXPECT().Delete("abcde12345.zip.1.attempt1.part")
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 4, strings.NewReader("123456"))
+
+		assertErrorCode(t, myErr.WUE03, actual)
+	})
+
+	t.Run("Updated concurrently", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := myErr.NewRecordNotFoundError("", nil)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		uploadsRepo.EXPECT().UpdateProgress(gomock.Eq(ctx), gomock.Any(), 1).Return(expect)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Upload("abcde12345.zip.1.attempt1.part", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return err
+			})
+		// 先に更新した要求のチャンクを残し、自分のチャンクを削除する
+		fileUploader.EXPECT().Delete("abcde12345.zip.1.attempt1.part")
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			uuidGenerator:     newUUIDGenerator(ctrl),
+			fileUploader:      fileUploader,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 4, strings.NewReader("12"))
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE05, actual)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(nil, myErr.NewRecordNotFoundError("", nil))
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+		}
+
+		_, actual := service.Append(ctx, "upload01", 4, strings.NewReader("12"))
+
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+}
+
+func TestUploadsDeleteByID(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := &entities.Upload{
+			ID:         "upload01",
+			UserID:     subject,
+			StorageKey: "abcde12345.zip",
+			Chunks:     1,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+		uploadsRepo.EXPECT().FindChunks(gomock.Eq(ctx), "upload01").Return([]*entities.UploadChunk{
+			{UploadID: "upload01", Number: 0, StorageKey: "abcde12345.zip.0.attempt0.part"},
+		}, nil)
+		uploadsRepo.EXPECT().DeleteByID(gomock.Eq(ctx), "upload01")
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete("abcde12345.zip")
+		fileUploader.EXPECT().Delete("abcde12345.zip.0.attempt0.part")
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+		}
+
+		err := service.DeleteByID(ctx, "upload01")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Created by other user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(&entities.Upload{
+			ID:        "upload01",
+			UserID:    "other",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+		}
+
+		actual := service.DeleteByID(ctx, "upload01")
+
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+}
+
+func TestPurgeExpired(t *testing.T) {
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expired := []*entities.Upload{
+			{ID: "upload01", StorageKey: "key01"},
+			{ID: "upload02", StorageKey: "key02"},
+		}
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindExpired(gomock.Eq(ctx), gomock.Any(), purgeBatchSize).Return(expired, nil)
+		uploadsRepo.EXPECT().FindChunks(gomock.Eq(ctx), gomock.Any()).Return(nil, nil).Times(2)
+		uploadsRepo.EXPECT().DeleteByID(gomock.Eq(ctx), "upload01")
+		// 作品の作成で先に使用されたものは数えない
+		uploadsRepo.EXPECT().DeleteByID(gomock.Eq(ctx), "upload02").Return(myErr.NewRecordNotFoundError("", nil))
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete("key01")
+
+		service := &UploadsServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+		}
+
+		count, err := service.PurgeExpired(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Fail to find expired uploads", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("Failed to find")
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindExpired(gomock.Eq(ctx), gomock.Any(), purgeBatchSize).Return(nil, expect)
+
+		service := &UploadsServiceImpl{
+			uploadsRepository: uploadsRepo,
+		}
+
+		_, actual := service.PurgeExpired(ctx)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+}
+
+func assertErrorCode(t *testing.T, code string, actual error) {
+	var appErr *myErr.ApplicationError
+	if errors.As(actual, &appErr) {
+		assert.Equal(t, code, appErr.Code())
+	} else {
+		assert.Failf(t, "Invalid error type", "%w", actual)
+	}
+}
@@ -2,15 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/edy4c7/works-uploader/internal/beans"
 	"github.com/edy4c7/works-uploader/internal/entities"
-	"github.com/edy4c7/works-uploader/internal/errors"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
 	"github.com/edy4c7/works-uploader/internal/repositories"
 )
 
 type UsersService interface {
 	Save(context.Context, *beans.UserFormBean) error
+	// IsSuspended は、ログイン中のユーザーのアカウントが停止されているかを返す。匿名の場合と、未登録のユーザーはfalseを返す。
+	IsSuspended(context.Context) (bool, error)
 }
 
 type UsersServiceImpl struct {
@@ -30,8 +33,25 @@ func (r *UsersServiceImpl) Save(ctx context.Context, form *beans.UserFormBean) e
 	}
 
 	if err := r.repository.Save(ctx, user); err != nil {
-		return errors.NewApplicationError(errors.Code(errors.WUE99), errors.Cause(err))
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
 
 	return nil
 }
+
+func (r *UsersServiceImpl) IsSuspended(ctx context.Context) (bool, error) {
+	sub, ok := extractSubject(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	user, err := r.repository.FindByID(ctx, sub)
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return false, nil
+		}
+		return false, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return user.SuspendedAt != nil, nil
+}
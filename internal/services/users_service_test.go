@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/beans"
 	"github.com/edy4c7/works-uploader/internal/entities"
+	myErr "github.com/edy4c7/works-uploader/internal/errors"
 	"github.com/edy4c7/works-uploader/internal/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +67,77 @@ func TestSave(t *testing.T) {
 		}
 	})
 }
+
+func TestIsSuspended(t *testing.T) {
+	t.Run("Is suspended", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		suspendedAt := time.Now()
+		repo := mocks.NewMockUsersRepository(ctrl)
+		repo.EXPECT().FindByID(gomock.Any(), subject).Return(&entities.User{ID: subject, SuspendedAt: &suspendedAt}, nil)
+		service := &UsersServiceImpl{repository: repo}
+
+		suspended, err := service.IsSuspended(ctx)
+
+		assert.Nil(t, err)
+		assert.True(t, suspended)
+	})
+
+	t.Run("Is not suspended", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		repo := mocks.NewMockUsersRepository(ctrl)
+		repo.EXPECT().FindByID(gomock.Any(), subject).Return(&entities.User{ID: subject}, nil)
+		service := &UsersServiceImpl{repository: repo}
+
+		suspended, err := service.IsSuspended(ctx)
+
+		assert.Nil(t, err)
+		assert.False(t, suspended)
+	})
+
+	t.Run("User is not registered", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		repo := mocks.NewMockUsersRepository(ctrl)
+		repo.EXPECT().FindByID(gomock.Any(), subject).Return(nil, myErr.NewRecordNotFoundError("not found", nil))
+		service := &UsersServiceImpl{repository: repo}
+
+		suspended, err := service.IsSuspended(ctx)
+
+		assert.Nil(t, err)
+		assert.False(t, suspended)
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := &UsersServiceImpl{repository: mocks.NewMockUsersRepository(ctrl)}
+
+		suspended, err := service.IsSuspended(ctx)
+
+		assert.Nil(t, err)
+		assert.False(t, suspended)
+	})
+
+	t.Run("Is error", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		repo := mocks.NewMockUsersRepository(ctrl)
+		repo.EXPECT().FindByID(gomock.Any(), subject).Return(nil, errors.New("error"))
+		service := &UsersServiceImpl{repository: repo}
+
+		_, err := service.IsSuspended(ctx)
+
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
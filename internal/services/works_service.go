@@ -1,10 +1,17 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/beans"
 	"github.com/edy4c7/works-uploader/internal/common/constants"
@@ -17,38 +24,140 @@ import (
 
 const userKey string = "user"
 const subjectKey string = "sub"
+const scopeKey string = "scope"
 const cannotBeNullMessage = "%s can't be null"
 const msgTransactionRunner = "transaction runner"
 const msgWorksRepository = "works repository"
 const msgActivitiesRepository = "activities repository"
 const msgUUIDGenerator = "UUID generator"
 const msgFileUploader = "file uploader"
+const msgImageProcessor = "image processor"
+const msgBlobsRepository = "blobs repository"
+const msgScanResultsRepository = "scan results repository"
+const msgScanner = "scanner"
+const msgLinkUnfurler = "link unfurler"
+const msgWorkRevisionsRepository = "work revisions repository"
 const initialVersion uint = 1
 
+// maxTitleLength, maxDescriptionLength は、フォームで受け付けるタイトルと説明の最大文字数
+const maxTitleLength = 40
+const maxDescriptionLength = 200
+
+// fieldTitle は、タイトルのフォーム項目名
+const fieldTitle = "title"
+
+// fieldStatus, fieldPublishAt は、公開状況と公開予定の日時のフォーム項目名
+const fieldStatus = "status"
+const fieldPublishAt = "publishAt"
+
+// fieldSort は、作品の一覧の並び順のクエリパラメータ名
+const fieldSort = "sort"
+
+// workOrders は、作品の一覧の並び順の指定と、その並び順の対応を表す。空の場合は既定の並び順にする。
+var workOrders = map[string]constants.WorkOrder{
+	"":          constants.OrderDefault,
+	"favorites": constants.OrderMostFavorited,
+}
+
+const (
+	// FieldThumbnail は、サムネイルを送信するフォーム項目名
+	FieldThumbnail = "thumbnail"
+	// FieldContent は、作品のファイルを送信するフォーム項目名
+	FieldContent = "content"
+)
+
+// UploadPolicy は、フォームのファイル項目毎のアップロードの制限を表す
+type UploadPolicy struct {
+	// MaxSize は、アップロードできる最大バイト数
+	MaxSize int64
+	// AllowedTypes は、ファイルの内容から判定したContent-Typeの許可リスト。空の場合は制限しない。
+	AllowedTypes []string
+}
+
+// allows は、判定したContent-Typeのファイルをアップロードできるかを返す
+func (r UploadPolicy) allows(contentType string) bool {
+	return len(r.AllowedTypes) == 0 || isAllowedType(r.AllowedTypes, contentType)
+}
+
 //WorksService は、作品管理機能のインターフェースを定義する
 type WorksService interface {
-	GetAll(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error)
+	// GetAll は、公開の作品と、閲覧しているユーザーの作品を取得する。tagsを指定した場合は、その全てのタグが付いた作品に絞り込む。
+	// sortに favorites を指定した場合は、お気に入りの多い順に並べる。それ以外の値の場合はWUE00を返す。
+	GetAll(ctx context.Context, tags []string, sort string, offset int, limit int) (*beans.PaginationBean, error)
+	// FindByID は、作品を取得する。非公開の作品と公開済みでない作品は、作者以外には存在しないものとしてWUE01を返す。
+	// 作品を含むコレクションのうち、閲覧しているユーザーが一覧で閲覧できるものも読み込む。
 	FindByID(context.Context, uint64) (*entities.Work, error)
+	// Stage は、フォームのファイル項目を受信しながらストレージの一時領域にアップロードする。
+	// 引数は フォーム項目名、ファイル名、内容 の順。
+	Stage(context.Context, string, string, io.Reader) (*beans.StagedFileBean, error)
+	// StageUpload は、フォームのファイル項目の代わりに、完了済みのアップロードを使用する。
+	// 引数は フォーム項目名、アップロードのID の順。
+	StageUpload(context.Context, string, string) (*beans.StagedFileBean, error)
+	// Discard は、Createに渡さなかった一時領域のファイルを破棄する
+	Discard(context.Context, ...*beans.StagedFileBean)
+	// Create は、作品を登録する。フォームのファイルは登録の成否に関わらず一時領域から取り除かれる。
+	// ファイルはマルウェアの検査を通過した後で公開し、検出した場合はWUE06を返す。
+	// 公開予定の作品は、公開日時に PublishService が公開する。
 	Create(context.Context, *beans.WorksFormBean) (*entities.Work, error)
+	// Update は、作者が作品の内容を更新し、更新後の内容を新しいバージョンの履歴として記録する。
+	// 差し替えたファイルは、以前のバージョンの履歴から参照できるよう削除しない。
+	// 作品を取得した後に他の更新が行われていた場合はWUE07を返す。
+	Update(context.Context, uint64, *beans.WorkUpdateFormBean) (*entities.Work, error)
 	DeleteByID(context.Context, uint64) error
 }
 
 //WorksServiceImpl は、作品管理機能を実装する
 type WorksServiceImpl struct {
-	transactionRunner    repositories.TransactionRunner
-	worksRepository      repositories.WorksRepository
-	activitiesRepository repositories.ActivitiesRepository
-	uuidGenerator        lib.UUIDGenerator
-	fileUploader         lib.StorageClient
+	transactionRunner     repositories.TransactionRunner
+	worksRepository       repositories.WorksRepository
+	activitiesRepository  repositories.ActivitiesRepository
+	uploadsRepository     repositories.UploadsRepository
+	blobsRepository       repositories.BlobsRepository
+	scanResultsRepository repositories.ScanResultsRepository
+	revisionsRepository   repositories.WorkRevisionsRepository
+	tagsRepository        repositories.TagsRepository
+	collectionsRepository repositories.CollectionsRepository
+	uuidGenerator         lib.UUIDGenerator
+	fileUploader          lib.StorageClient
+	imageProcessor        lib.ImageProcessor
+	scanner               lib.Scanner
+	linkUnfurler          lib.LinkUnfurler
+	uploadPolicies        map[string]UploadPolicy
+	variantWidths         []int
+	deduplicate           bool
+	privateURLExpiration  time.Duration
 }
 
-//NewWorksServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、WorksServiceImplの新しいインスタンスを生成する
+// WorksServiceDeps は、WorksServiceImplが作品の基本的な管理以外に使用するリポジトリ、ライブラリと設定を表す
+type WorksServiceDeps struct {
+	UploadsRepository     repositories.UploadsRepository
+	BlobsRepository       repositories.BlobsRepository
+	ScanResultsRepository repositories.ScanResultsRepository
+	RevisionsRepository   repositories.WorkRevisionsRepository
+	TagsRepository        repositories.TagsRepository
+	CollectionsRepository repositories.CollectionsRepository
+	ImageProcessor        lib.ImageProcessor
+	Scanner               lib.Scanner
+	LinkUnfurler          lib.LinkUnfurler
+	// UploadPolicies は、フォームのファイル項目毎のアップロードの制限
+	UploadPolicies map[string]UploadPolicy
+	// VariantWidths は、画像から生成する縮小版の幅
+	VariantWidths []int
+	// Deduplicate がtrueの場合、内容のSHA-256を公開後のキーにして、同じ内容のファイルを共有する
+	Deduplicate bool
+	// PrivateURLExpiration は、非公開の作品のファイルを閲覧するための署名付きURLの有効期間
+	PrivateURLExpiration time.Duration
+}
+
+// NewWorksServiceImpl は、TransuctionRunner、リポジトリオブジェクトを指定し、WorksServiceImplの新しいインスタンスを生成する。
+// 作品の基本的な管理以外に使用する依存と設定は、depsで指定する。
 func NewWorksServiceImpl(
 	tranRnr repositories.TransactionRunner,
 	worksRepo repositories.WorksRepository,
 	activitiesRepo repositories.ActivitiesRepository,
 	uuidGenerator lib.UUIDGenerator,
 	fileUploader lib.StorageClient,
+	deps WorksServiceDeps,
 ) *WorksServiceImpl {
 
 	if tranRnr == nil {
@@ -66,24 +175,74 @@ func NewWorksServiceImpl(
 	if fileUploader == nil {
 		panic(fmt.Sprintf(cannotBeNullMessage, msgFileUploader))
 	}
+	if deps.UploadsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgUploadsRepository))
+	}
+	if deps.BlobsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgBlobsRepository))
+	}
+	if deps.ScanResultsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgScanResultsRepository))
+	}
+	if deps.RevisionsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgWorkRevisionsRepository))
+	}
+	if deps.TagsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgTagsRepository))
+	}
+	if deps.CollectionsRepository == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgCollectionsRepository))
+	}
+	if deps.ImageProcessor == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgImageProcessor))
+	}
+	if deps.Scanner == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgScanner))
+	}
+	if deps.LinkUnfurler == nil {
+		panic(fmt.Sprintf(cannotBeNullMessage, msgLinkUnfurler))
+	}
 
 	return &WorksServiceImpl{
-		transactionRunner:    tranRnr,
-		worksRepository:      worksRepo,
-		activitiesRepository: activitiesRepo,
-		uuidGenerator:        uuidGenerator,
-		fileUploader:         fileUploader,
+		transactionRunner:     tranRnr,
+		worksRepository:       worksRepo,
+		activitiesRepository:  activitiesRepo,
+		uploadsRepository:     deps.UploadsRepository,
+		blobsRepository:       deps.BlobsRepository,
+		scanResultsRepository: deps.ScanResultsRepository,
+		revisionsRepository:   deps.RevisionsRepository,
+		tagsRepository:        deps.TagsRepository,
+		collectionsRepository: deps.CollectionsRepository,
+		uuidGenerator:         uuidGenerator,
+		fileUploader:          fileUploader,
+		imageProcessor:        deps.ImageProcessor,
+		scanner:               deps.Scanner,
+		linkUnfurler:          deps.LinkUnfurler,
+		uploadPolicies:        deps.UploadPolicies,
+		variantWidths:         deps.VariantWidths,
+		deduplicate:           deps.Deduplicate,
+		privateURLExpiration:  deps.PrivateURLExpiration,
 	}
 }
 
 //GetAll は、作品の全件取得を行う
-func (r *WorksServiceImpl) GetAll(ctx context.Context, offset int, limit int) (*beans.PaginationBean, error) {
-	count, err := r.worksRepository.CountAll(ctx)
+func (r *WorksServiceImpl) GetAll(ctx context.Context, tags []string, sort string, offset int, limit int) (*beans.PaginationBean, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	order, ok := workOrders[sort]
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldSort))
+	}
+
+	viewer := viewerOf(ctx)
+	count, err := r.worksRepository.CountAll(ctx, viewer, tags)
 	if err != nil {
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
 
-	result, err := r.worksRepository.GetAll(ctx, offset, limit)
+	result, err := r.worksRepository.GetAll(ctx, viewer, tags, order, offset, limit)
 	if err != nil {
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
@@ -95,6 +254,9 @@ func (r *WorksServiceImpl) GetAll(ctx context.Context, offset int, limit int) (*
 	}
 
 	for _, v := range result {
+		if err := signFileURLs(r.fileUploader, r.privateURLExpiration, v); err != nil {
+			return nil, err
+		}
 		pagination.Items = append(pagination.Items, v)
 	}
 
@@ -103,92 +265,934 @@ func (r *WorksServiceImpl) GetAll(ctx context.Context, offset int, limit int) (*
 
 //FindByID は、指定したIDの作品を取得する
 func (r *WorksServiceImpl) FindByID(ctx context.Context, id uint64) (*entities.Work, error) {
-	result, err := r.worksRepository.FindByID(ctx, id)
-
+	result, err := findViewableWork(ctx, r.worksRepository, id)
 	if err != nil {
-		var dbErr *myErr.RecordNotFoundError
-		if errors.As(err, &dbErr) {
-			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
-		}
+		return nil, err
+	}
+	if err := signFileURLs(r.fileUploader, r.privateURLExpiration, result); err != nil {
+		return nil, err
+	}
 
+	result.Collections, err = r.collectionsRepository.FindByWorkID(ctx, viewerOf(ctx), id)
+	if err != nil {
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
 
 	return result, nil
 }
 
-func (r *WorksServiceImpl) Create(ctx context.Context, bean *beans.WorksFormBean) (*entities.Work, error) {
-	token, ok := ctx.Value(userKey).(*jwt.Token)
+//Stage は、ファイルをバッファリングせずに一時領域へアップロードし、書き込んだバイト数を返す
+func (r *WorksServiceImpl) Stage(ctx context.Context, field string, filename string, body io.Reader) (*beans.StagedFileBean, error) {
+	policy, ok := r.uploadPolicies[field]
 	if !ok {
-		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
 	}
-	clm, ok := token.Claims.(jwt.MapClaims)
+
+	reader := &sizeLimitedReader{reader: body, limit: policy.MaxSize}
+	uploadError := func(err error) error {
+		if reader.exceeded {
+			return myErr.NewApplicationError(
+				myErr.Code(myErr.WUE03), myErr.MessageParams(field, policy.MaxSize), myErr.Cause(err))
+		}
+		if errors.Is(err, lib.ErrUnsupportedImage) {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field), myErr.Cause(err))
+		}
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	// 許可しない形式のファイルは、ストレージへ送信する前に拒否する
+	contentType, sniffed, err := sniffContentType(reader)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	if !policy.allows(contentType) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+
+	key := fmt.Sprintf("%s%s", r.uuidGenerator.Generate(), filepath.Ext(filename))
+	var digest *digestReader
+	if stripped, ok := r.imageProcessor.StripMetadata(sniffed, contentType); ok {
+		digest, err = r.uploadStripped(key, stripped, filename, contentType)
+	} else {
+		digest = newDigestReader(sniffed)
+		err = r.fileUploader.Upload(key, digest, filename, contentType)
+	}
+	if err != nil {
+		r.deleteFiles([]string{key})
+		return nil, uploadError(err)
+	}
+
+	return &beans.StagedFileBean{
+		Key:         key,
+		Filename:    filename,
+		Size:        digest.size,
+		SHA256:      digest.sum(),
+		ContentType: contentType,
+	}, nil
+}
+
+//StageUpload は、自分が作成した完了済みのアップロードを、フォームのファイルとして使用できるようにする
+func (r *WorksServiceImpl) StageUpload(ctx context.Context, field string, uploadID string) (*beans.StagedFileBean, error) {
+	owner, ok := extractSubject(ctx)
 	if !ok {
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
 	}
-	author, ok := clm[subjectKey].(string)
+
+	policy, ok := r.uploadPolicies[field]
+	if !ok {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+
+	u, err := findOwnUpload(ctx, r.uploadsRepository, uploadID, owner)
+	if err != nil {
+		return nil, err
+	}
+	if u.Length > policy.MaxSize {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE03), myErr.MessageParams(field, policy.MaxSize))
+	}
+	if u.CompletedAt == nil {
+		// ストレージへ直接送信するアップロードは、送信されたファイルが申告どおりかを確認する
+		if err := r.verifyObject(field, u); err != nil {
+			return nil, err
+		}
+	}
+
+	object, err := r.openObject(u.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	contentType, sniffed, err := sniffContentType(object)
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if !policy.allows(contentType) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+	// 申告したContent-Typeで配信されるため、内容と異なるものは受け付けない
+	if u.ContentType != "" && !sameMediaType(u.ContentType, contentType) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+
+	// フォームのファイルと同様に、公開前にメタデータを取り除く。取り除かない場合は、読み込み直してハッシュ値を計算する。
+	var digest *digestReader
+	if stripped, ok := r.imageProcessor.StripMetadata(sniffed, contentType); ok {
+		digest, err = r.restageStripped(ctx, field, u, stripped, contentType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		digest = newDigestReader(sniffed)
+		if _, err := io.Copy(ioutil.Discard, digest); err != nil {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+	}
+
+	return &beans.StagedFileBean{
+		Key:         u.StorageKey,
+		Filename:    u.Filename,
+		Size:        digest.size,
+		SHA256:      digest.sum(),
+		ContentType: contentType,
+		UploadID:    u.ID,
+	}, nil
+}
+
+//Discard は、一時領域にアップロードしたファイルを削除する。アップロードのIDで指定されたファイルは、再度使用できるよう残す。
+func (r *WorksServiceImpl) Discard(ctx context.Context, files ...*beans.StagedFileBean) {
+	r.deleteFiles(disposableKeys(files...))
+}
+
+func (r *WorksServiceImpl) Create(ctx context.Context, bean *beans.WorksFormBean) (*entities.Work, error) {
+	author, ok := extractSubject(ctx)
 	if !ok {
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99))
 	}
 
+	if bean.Status == constants.WorkScheduled && bean.PublishAt.IsZero() {
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldPublishAt))
+	}
+	tags, err := normalizeTags(bean.Tags)
+	if err != nil {
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		return nil, err
+	}
+
+	// プレビューで補う項目を書き換えるため、受け取ったフォームは変更しない
+	form := *bean
+	bean = &form
+	if bean.Type != constants.ContentTypeFile {
+		// URLの作品では使用しないため、送信されていても破棄し、リンク先のプレビューの画像を使用する
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		bean.Thumbnail, bean.Content = nil, nil
+		r.unfurl(ctx, bean)
+		if bean.Title == "" {
+			// フォームで省略され、プレビューからも取得できなかった
+			r.Discard(ctx, bean.Thumbnail)
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldTitle))
+		}
+	}
+
 	w := &entities.Work{
 		Type:        bean.Type,
-		AuthorID:      author,
+		Visibility:  bean.Visibility,
+		AuthorID:    author,
 		Title:       bean.Title,
 		Description: bean.Description,
+		ScanStatus:  constants.ScanClean,
 		Version:     initialVersion,
 	}
+	if w.Visibility == 0 {
+		w.Visibility = constants.VisibilityPublic
+	}
+	w.Status, w.PublishAt = publication(bean.Status, bean.PublishAt, time.Now())
 
-	if bean.Type == constants.ContentTypeFile {
-		thumbURL, err := r.fileUploader.Upload(
-			fmt.Sprintf("%s%s", r.uuidGenerator.Generate(), filepath.Ext(bean.Thumbnail.Filename)),
-			bean.Thumbnail,
-		)
+	var files []*beans.StagedFileBean
+	if bean.Thumbnail != nil || bean.Content != nil {
+		variants, err := r.stageVariants(bean)
 		if err != nil {
+			r.Discard(ctx, bean.Thumbnail, bean.Content)
+			return nil, err
+		}
+
+		if bean.Thumbnail != nil {
+			files = append(files, bean.Thumbnail)
+		}
+		if bean.Content != nil {
+			files = append(files, bean.Content)
+		}
+		for _, v := range variants {
+			if w.Thumbnails == nil {
+				w.Thumbnails = entities.ImageVariants{}
+			}
+			files = append(files, v.file)
+			w.Thumbnails[strconv.Itoa(v.width)] = r.fileUploader.URL(r.publicKey(w, v.file))
+		}
+		if bean.Thumbnail != nil {
+			w.ThumbnailURL = r.fileUploader.URL(r.publicKey(w, bean.Thumbnail))
+		} else {
+			// 作品の画像から生成した、最も小さいものをサムネイルにする
+			w.ThumbnailURL = r.fileUploader.URL(r.publicKey(w, variants[0].file))
+		}
+		// 検査を通過するまで公開しない
+		w.ScanStatus = constants.ScanPending
+	}
+	if bean.Type == constants.ContentTypeFile {
+		w.ContentURL = r.fileUploader.URL(r.publicKey(w, bean.Content))
+		w.ContentType = bean.Content.ContentType
+		w.ContentSHA256 = bean.Content.SHA256
+		w.ContentSize = bean.Content.Size
+	} else {
+		w.ContentURL = bean.ContentURL
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.Create(ctx, w); err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			var err error
+			if w.Tags, err = r.tagsRepository.ReplaceWorkTags(ctx, w.ID, tags); err != nil {
+				return err
+			}
+		}
+
+		if err := r.revisionsRepository.Create(ctx, entities.NewWorkRevision(w, author)); err != nil {
+			return err
+		}
+
+		// ファイルの作品は検査を通過した時点で、公開予定の作品は公開した時点で追加したことにする
+		if w.ScanStatus == constants.ScanClean && w.Status == constants.WorkPublished {
+			if err := r.addActivity(ctx, w); err != nil {
+				return err
+			}
+		}
+
+		return r.acquireBlobs(ctx, w, files)
+	})
+
+	if err != nil {
+		r.Discard(ctx, files...)
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			// 同じアップロードが別の作品で先に使用された
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if w.ScanStatus == constants.ScanPending {
+		if err := r.scan(ctx, w, bean, files); err != nil {
+			return nil, err
+		}
+	}
+
+	// 検査後に公開する。重複排除する場合も、他の作品の公開が失敗していても参照できるよう、同じ内容で置き換える。
+	// 公開に失敗した場合は、作品を取り消してファイルを削除する。
+	promote := r.promoteFunc(w)
+	for _, f := range files {
+		if err := promote(f.Key, r.publicKey(w, f)); err != nil {
+			r.rollbackCreate(ctx, w, files)
 			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 		}
-		w.ThumbnailURL = thumbURL
+	}
+
+	if err := signFileURLs(r.fileUploader, r.privateURLExpiration, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
 
-		contentURL, err := r.fileUploader.Upload(
-			fmt.Sprintf("%s%s", r.uuidGenerator.Generate(), filepath.Ext(bean.Content.Filename)),
-			bean.Content,
-		)
+//Update は、フォームの内容で作品を更新する。差し替えるファイルは、作品を更新する前に検査して公開する。
+func (r *WorksServiceImpl) Update(ctx context.Context, id uint64, bean *beans.WorkUpdateFormBean) (*entities.Work, error) {
+	w, editor, err := findEditableWork(ctx, r.worksRepository, id)
+	if err != nil {
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		return nil, err
+	}
+	if w.Version != bean.Version {
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE07))
+	}
+	tags, err := normalizeTags(bean.Tags)
+	if err != nil {
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		return nil, err
+	}
+
+	// 公開状況は公開前の作品のみ変更でき、公開済みの作品を公開前の状態には戻せない
+	publishing := false
+	if bean.Status != 0 {
+		if w.Status == constants.WorkPublished && bean.Status != constants.WorkPublished {
+			r.Discard(ctx, bean.Thumbnail, bean.Content)
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldStatus))
+		}
+		if bean.Status == constants.WorkScheduled && bean.PublishAt.IsZero() {
+			r.Discard(ctx, bean.Thumbnail, bean.Content)
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(fieldPublishAt))
+		}
+		if w.Status != constants.WorkPublished {
+			w.Status, w.PublishAt = publication(bean.Status, bean.PublishAt, time.Now())
+			publishing = w.Status == constants.WorkPublished
+		}
+	}
+
+	if bean.Title != "" {
+		w.Title = bean.Title
+	}
+	w.Description = bean.Description
+	var files []*beans.StagedFileBean
+	var results []*entities.ScanResult
+	if w.Type == constants.ContentTypeFile {
+		files, results, err = r.replaceFiles(ctx, w, bean)
 		if err != nil {
-			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+			return nil, err
 		}
-		w.ContentURL = contentURL
 	} else {
-		w.ContentURL = bean.ContentURL
+		// URLの作品では使用しないため、送信されていても破棄する
+		r.Discard(ctx, bean.Thumbnail, bean.Content)
+		if bean.ContentURL != "" {
+			w.ContentURL = bean.ContentURL
+		}
+	}
+
+	conflict := false
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		// 公開予定の作品が同時に公開された場合に、アクティビティを重複して登録しないよう、公開前の場合のみ公開する
+		if publishing {
+			if err := r.worksRepository.Publish(ctx, w.ID); err != nil {
+				var dbErr *myErr.RecordNotFoundError
+				conflict = errors.As(err, &dbErr)
+				return err
+			}
+		}
+		if err := r.worksRepository.Update(ctx, w); err != nil {
+			var dbErr *myErr.RecordNotFoundError
+			conflict = errors.As(err, &dbErr)
+			return err
+		}
+		if err := r.revisionsRepository.Create(ctx, entities.NewWorkRevision(w, editor)); err != nil {
+			return err
+		}
+		// タグは履歴に記録しないため、変更した場合のみ置き換える
+		if !sameTags(w.Tags, tags) {
+			var err error
+			if w.Tags, err = r.tagsRepository.ReplaceWorkTags(ctx, w.ID, tags); err != nil {
+				return err
+			}
+		}
+		if err := r.createScanResults(ctx, results); err != nil {
+			return err
+		}
+		if publishing {
+			if err := r.addActivity(ctx, w); err != nil {
+				return err
+			}
+		}
+		return r.acquireBlobs(ctx, w, files)
+	})
+
+	if err != nil {
+		r.deleteFiles(r.unsharedKeys(w, files))
+		if conflict {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE07), myErr.Cause(err))
+		}
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			// 同じアップロードが別の作品で先に使用された
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if err := signFileURLs(r.fileUploader, r.privateURLExpiration, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// replaceFiles は、更新で差し替えるファイルを検査して公開し、作品のファイルのURLを書き換える。戻り値は公開したファイルと検査結果。
+// 作品本体を画像に差し替えた場合と、画像でない作品のサムネイルを差し替えた場合は、縮小した画像を生成し直し、
+// 縮小した画像をサムネイルとして使用していた場合は、生成し直したものに置き換える。
+// 失敗した場合は、一時領域と公開したファイルを削除する。
+func (r *WorksServiceImpl) replaceFiles(ctx context.Context, w *entities.Work, bean *beans.WorkUpdateFormBean) ([]*beans.StagedFileBean, []*entities.ScanResult, error) {
+	if bean.Thumbnail == nil && bean.Content == nil {
+		return nil, nil, nil
+	}
+
+	contentType := w.ContentType
+	if bean.Content != nil {
+		contentType = bean.Content.ContentType
+	}
+	isImage := strings.HasPrefix(contentType, "image/")
+	regenerate := (bean.Content != nil && isImage) || (bean.Thumbnail != nil && !isImage)
+
+	var variants []*stagedVariant
+	if regenerate {
+		var err error
+		variants, err = r.stageVariants(&beans.WorksFormBean{Thumbnail: bean.Thumbnail, Content: bean.Content})
+		if err != nil {
+			r.Discard(ctx, bean.Thumbnail, bean.Content)
+			return nil, nil, err
+		}
+	}
+
+	var files []*beans.StagedFileBean
+	for _, f := range []*beans.StagedFileBean{bean.Thumbnail, bean.Content} {
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+	for _, v := range variants {
+		files = append(files, v.file)
+	}
+
+	results, infected, err := r.scanTargets(w.ID, bean.Thumbnail, bean.Content)
+	if err != nil {
+		r.Discard(ctx, files...)
+		return nil, nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if len(infected) > 0 {
+		// 作品は更新しないが、検出したことは記録する
+		err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+			return r.createScanResults(ctx, results)
+		})
+		if err != nil {
+			log.Printf("failed to record scan results of work %d: %v", w.ID, err)
+		}
+		r.quarantine(files, infected, nil)
+		return nil, nil, myErr.NewApplicationError(myErr.Code(myErr.WUE06), myErr.MessageParams(infected[0].field))
+	}
+
+	promote := r.promoteFunc(w)
+	for i, f := range files {
+		if err := promote(f.Key, r.publicKey(w, f)); err != nil {
+			r.deleteFiles(append(stagedKeys(files[i:]...), r.unsharedKeys(w, files[:i])...))
+			return nil, nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+	}
+
+	if regenerate {
+		usedVariant := false
+		for _, u := range w.Thumbnails {
+			usedVariant = usedVariant || u == w.ThumbnailURL
+		}
+		w.Thumbnails = nil
+		for _, v := range variants {
+			if w.Thumbnails == nil {
+				w.Thumbnails = entities.ImageVariants{}
+			}
+			w.Thumbnails[strconv.Itoa(v.width)] = r.fileUploader.URL(r.publicKey(w, v.file))
+		}
+		if bean.Thumbnail == nil && usedVariant && len(variants) > 0 {
+			w.ThumbnailURL = r.fileUploader.URL(r.publicKey(w, variants[0].file))
+		}
+	}
+	if bean.Thumbnail != nil {
+		w.ThumbnailURL = r.fileUploader.URL(r.publicKey(w, bean.Thumbnail))
+	}
+	if bean.Content != nil {
+		w.ContentURL = r.fileUploader.URL(r.publicKey(w, bean.Content))
+		w.ContentType = bean.Content.ContentType
+		w.ContentSHA256 = bean.Content.SHA256
+		w.ContentSize = bean.Content.Size
+	}
+
+	return files, results, nil
+}
+
+// unsharedKeys は、公開したファイルのうち、重複排除せずに公開したため他の作品と共有していないもののキーを返す
+func (r *WorksServiceImpl) unsharedKeys(w *entities.Work, files []*beans.StagedFileBean) []string {
+	var keys []string
+	for _, f := range files {
+		if r.publicKey(w, f) == f.Key {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys
+}
+
+// unfurl は、URLの作品のリンク先のプレビューで省略されたタイトルと説明を補い、画像をサムネイルとして一時領域にアップロードする。
+// プレビューを取得できない場合も作品を登録できるよう、エラーは記録のみ行う。
+func (r *WorksServiceImpl) unfurl(ctx context.Context, bean *beans.WorksFormBean) {
+	preview, err := r.linkUnfurler.Unfurl(ctx, bean.ContentURL)
+	if err != nil {
+		log.Printf("skipped preview of %s: %v", bean.ContentURL, err)
+		return
+	}
+	if preview == nil {
+		return
+	}
+
+	if bean.Title == "" {
+		bean.Title = truncateRunes(preview.Title, maxTitleLength)
+	}
+	if bean.Description == "" {
+		bean.Description = truncateRunes(preview.Description, maxDescriptionLength)
+	}
+	if preview.ImageURL == "" {
+		return
+	}
+
+	// サムネイルと同じ制限でアップロードし、形式や大きさが合わない画像は使用しない
+	image, err := r.linkUnfurler.Fetch(ctx, preview.ImageURL)
+	if err != nil {
+		log.Printf("skipped preview image %s: %v", preview.ImageURL, err)
+		return
+	}
+	defer image.Close()
+
+	thumbnail, err := r.Stage(ctx, FieldThumbnail, previewFilename(preview.ImageURL), image)
+	if err != nil {
+		log.Printf("skipped preview image %s: %v", preview.ImageURL, err)
+		return
+	}
+	bean.Thumbnail = thumbnail
+}
+
+// publicKey は、一時領域にあるファイルの公開後のキーを返す。
+// 非公開の作品のファイルは、公開の作品のファイルと共有しないよう重複排除しない。
+func (r *WorksServiceImpl) publicKey(w *entities.Work, f *beans.StagedFileBean) string {
+	if r.deduplicate && w.Visibility != constants.VisibilityPrivate {
+		return contentAddressedKey(f.SHA256, f.Key)
+	}
+	return f.Key
+}
+
+// publication は、フォームで指定された公開状況と公開予定の日時を返す。
+// 日時を過ぎた公開予定は、すぐに公開する。
+func publication(status constants.WorkStatus, publishAt time.Time, now time.Time) (constants.WorkStatus, *time.Time) {
+	switch status {
+	case constants.WorkDraft:
+		return constants.WorkDraft, nil
+	case constants.WorkScheduled:
+		if publishAt.After(now) {
+			return constants.WorkScheduled, &publishAt
+		}
+	}
+	return constants.WorkPublished, nil
+}
+
+// promoteFunc は、作品の公開範囲に応じて、一時領域のファイルを公開する関数を返す
+func (r *WorksServiceImpl) promoteFunc(w *entities.Work) func(string, string) error {
+	if w.Visibility == constants.VisibilityPrivate {
+		return r.fileUploader.PromotePrivate
+	}
+	return r.fileUploader.Promote
+}
+
+// acquireBlobs は、作品で公開するファイルを、内容の検証と重複排除のために登録する。
+// 使用したアップロードは、期限切れで削除されないよう取り除く。
+func (r *WorksServiceImpl) acquireBlobs(ctx context.Context, w *entities.Work, files []*beans.StagedFileBean) error {
+	for _, f := range files {
+		blob := &entities.Blob{Key: r.publicKey(w, f), SHA256: f.SHA256, Size: f.Size}
+		if _, err := r.blobsRepository.Acquire(ctx, w.ID, blob); err != nil {
+			return err
+		}
+
+		if f.UploadID == "" {
+			continue
+		}
+		if err := r.uploadsRepository.DeleteByID(ctx, f.UploadID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addActivity は、作品を追加したアクティビティを登録する
+func (r *WorksServiceImpl) addActivity(ctx context.Context, w *entities.Work) error {
+	act := &entities.Activity{
+		Type:   constants.ActivityAdded,
+		UserID: w.AuthorID,
+		Work:   w,
+	}
+	return r.activitiesRepository.Create(ctx, act)
+}
+
+// scanTarget は、検査するフォームのファイルを表す
+type scanTarget struct {
+	field string
+	file  *beans.StagedFileBean
+}
+
+// scan は、登録した作品のフォームのファイルを検査し、結果を記録する。
+// 検出しなかった場合は作品を公開できる状態にする。検出した場合は作品を却下し、WUE06を返す。
+// 縮小した画像は、検査したファイルから生成するため検査しない。
+func (r *WorksServiceImpl) scan(ctx context.Context, w *entities.Work, bean *beans.WorksFormBean, files []*beans.StagedFileBean) error {
+	results, infected, err := r.scanTargets(w.ID, bean.Thumbnail, bean.Content)
+	if err != nil {
+		r.rollbackCreate(ctx, w, files)
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if len(infected) > 0 {
+		r.reject(ctx, w, files, results, infected)
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE06), myErr.MessageParams(infected[0].field))
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		if err := r.worksRepository.UpdateScanStatus(ctx, w.ID, constants.ScanClean); err != nil {
+			return err
+		}
+		if err := r.createScanResults(ctx, results); err != nil {
+			return err
+		}
+		if w.Status != constants.WorkPublished {
+			return nil
+		}
+		return r.addActivity(ctx, w)
+	})
+	if err != nil {
+		r.rollbackCreate(ctx, w, files)
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
 
+	w.ScanStatus = constants.ScanClean
+	return nil
+}
+
+// scanTargets は、フォームのファイルを検査し、記録する検査結果と、マルウェアを検出したファイルを返す
+func (r *WorksServiceImpl) scanTargets(workID uint64, thumbnail *beans.StagedFileBean, content *beans.StagedFileBean) ([]*entities.ScanResult, []scanTarget, error) {
+	var targets []scanTarget
+	if thumbnail != nil {
+		targets = append(targets, scanTarget{field: FieldThumbnail, file: thumbnail})
+	}
+	if content != nil {
+		targets = append(targets, scanTarget{field: FieldContent, file: content})
+	}
+
+	var results []*entities.ScanResult
+	var infected []scanTarget
+	for _, target := range targets {
+		verdict, err := r.scanFile(target.file.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if verdict == nil {
+			continue
+		}
+
+		results = append(results, &entities.ScanResult{
+			WorkID:    workID,
+			Field:     target.field,
+			Key:       target.file.Key,
+			Scanner:   verdict.Scanner,
+			Signature: verdict.Signature,
+		})
+		if verdict.Infected() {
+			infected = append(infected, target)
+		}
+	}
+	return results, infected, nil
+}
+
+// scanFile は、一時領域にあるファイルを検査する
+func (r *WorksServiceImpl) scanFile(key string) (*lib.ScanVerdict, error) {
+	object, err := r.fileUploader.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return r.scanner.Scan(object)
+}
+
+// reject は、マルウェアを検出した作品を却下し、検査結果とともに記録する。
+// 検出したファイルは隔離し、それ以外のファイルは削除する。
+func (r *WorksServiceImpl) reject(ctx context.Context, w *entities.Work, files []*beans.StagedFileBean, results []*entities.ScanResult, infected []scanTarget) {
+	var keys []string
 	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
-		if err := r.worksRepository.Create(ctx, w); err != nil {
+		if err := r.worksRepository.UpdateScanStatus(ctx, w.ID, constants.ScanInfected); err != nil {
+			return err
+		}
+		if err := r.createScanResults(ctx, results); err != nil {
 			return err
 		}
+		released, err := r.releaseBlobs(ctx, w, files)
+		keys = released
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to reject work %d: %v", w.ID, err)
+	}
+	w.ScanStatus = constants.ScanInfected
+
+	r.quarantine(files, infected, keys)
+}
 
-		act := &entities.Activity{
-			Type: constants.ActivityAdded,
-			UserID: author,
-			Work: w,
+// quarantine は、マルウェアを検出したファイルを隔離し、それ以外の一時領域のファイルとkeysを削除する
+func (r *WorksServiceImpl) quarantine(files []*beans.StagedFileBean, infected []scanTarget, keys []string) {
+	quarantined := make(map[string]bool, len(infected))
+	for _, target := range infected {
+		if err := r.fileUploader.Quarantine(target.file.Key); err != nil {
+			// 隔離できない場合も、公開されないよう削除する
+			log.Printf("failed to quarantine %s: %v", target.file.Key, err)
+			continue
+		}
+		quarantined[target.file.Key] = true
+	}
+	for _, key := range stagedKeys(files...) {
+		if !quarantined[key] {
+			keys = append(keys, key)
 		}
-		if err := r.activitiesRepository.Create(ctx, act); err != nil {
+	}
+
+	r.deleteFiles(keys)
+}
+
+func (r *WorksServiceImpl) createScanResults(ctx context.Context, results []*entities.ScanResult) error {
+	for _, result := range results {
+		if err := r.scanResultsRepository.Create(ctx, result); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
+// rollbackCreate は、公開に失敗した作品を取り消し、ファイルを削除する
+func (r *WorksServiceImpl) rollbackCreate(ctx context.Context, w *entities.Work, files []*beans.StagedFileBean) {
+	keys := stagedKeys(files...)
+	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		// 作品の削除で参照も削除されるため、先に登録を取り消す
+		released, err := r.releaseBlobs(ctx, w, files)
+		if err != nil {
+			return err
+		}
+		if err := r.worksRepository.PurgeByID(ctx, w.ID); err != nil {
+			return err
+		}
+		keys = append(keys, released...)
 		return nil
 	})
+	if err != nil {
+		log.Printf("failed to purge work %d: %v", w.ID, err)
+	}
 
+	r.deleteFiles(keys)
+}
+
+// releaseBlobs は、公開しなかったファイルの登録を取り消し、削除する公開後のキーを返す。
+// 公開後のファイルは、他の作品から参照されていない場合のみ削除する。
+func (r *WorksServiceImpl) releaseBlobs(ctx context.Context, w *entities.Work, files []*beans.StagedFileBean) ([]string, error) {
+	var released []string
+	for _, f := range files {
+		key := r.publicKey(w, f)
+		refs, err := r.blobsRepository.Release(ctx, w.ID, key)
+		if err != nil {
+			return nil, err
+		}
+		if refs == 0 && key != f.Key {
+			released = append(released, key)
+		}
+	}
+	return released, nil
+}
+
+// stagedVariant は、一時領域にアップロードした縮小画像を表す
+type stagedVariant struct {
+	width int
+	file  *beans.StagedFileBean
+}
+
+// stageVariants は、作品本体が画像の場合はそれを、そうでない場合はサムネイルを縮小し、一時領域にアップロードする。
+// 画像として読み込めない場合は生成しないが、サムネイルを省略した場合は代わりに使用するため、エラーとする。
+func (r *WorksServiceImpl) stageVariants(bean *beans.WorksFormBean) ([]*stagedVariant, error) {
+	source := bean.Thumbnail
+	if bean.Content != nil && strings.HasPrefix(bean.Content.ContentType, "image/") {
+		source = bean.Content
+	}
+	if source == nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(FieldThumbnail))
+	}
+
+	images, err := r.resize(source.Key)
 	if err != nil {
+		if errors.Is(err, lib.ErrUnsupportedImage) && bean.Thumbnail != nil {
+			log.Printf("skipped resizing %s: %v", source.Key, err)
+			return nil, nil
+		}
+		if errors.Is(err, lib.ErrUnsupportedImage) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(FieldThumbnail), myErr.Cause(err))
+		}
 		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
 	}
+	if len(images) == 0 && bean.Thumbnail == nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(FieldThumbnail))
+	}
 
-	return w, nil
+	base := strings.TrimSuffix(source.Filename, filepath.Ext(source.Filename))
+	variants := make([]*stagedVariant, 0, len(images))
+	for _, image := range images {
+		file := &beans.StagedFileBean{
+			Key:         fmt.Sprintf("%s.jpg", r.uuidGenerator.Generate()),
+			Filename:    fmt.Sprintf("%s_%d.jpg", base, image.Width),
+			Size:        int64(len(image.Body)),
+			SHA256:      sha256Hex(image.Body),
+			ContentType: image.ContentType,
+		}
+		if err := r.fileUploader.Upload(file.Key, bytes.NewReader(image.Body), file.Filename, file.ContentType); err != nil {
+			keys := []string{file.Key}
+			for _, v := range variants {
+				keys = append(keys, v.file.Key)
+			}
+			r.deleteFiles(keys)
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+		variants = append(variants, &stagedVariant{width: image.Width, file: file})
+	}
+
+	return variants, nil
 }
 
-//DeleteByID は、指定したIDの作品を削除する
+// resize は、一時領域にあるファイルを読み込んで縮小する
+func (r *WorksServiceImpl) resize(key string) ([]*lib.ImageVariant, error) {
+	object, err := r.fileUploader.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return r.imageProcessor.Resize(object, r.variantWidths)
+}
+
+// verifyObject は、アップロードのStorageKeyにファイルがあり、サイズとContent-Typeが申告と一致することを確認する。
+// tusで受信中のアップロードは、まとめたファイルが存在しないため見つからないものとして扱う。
+func (r *WorksServiceImpl) verifyObject(field string, u *entities.Upload) error {
+	info, err := r.fileUploader.Stat(u.StorageKey)
+	if err != nil {
+		if errors.Is(err, lib.ErrObjectNotFound) {
+			return myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	if info.Size != u.Length {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+	if u.ContentType != "" && info.ContentType != "" && !sameMediaType(u.ContentType, info.ContentType) {
+		return myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field))
+	}
+
+	return nil
+}
+
+// openObject は、一時領域にあるファイルを開く
+func (r *WorksServiceImpl) openObject(key string) (io.ReadCloser, error) {
+	object, err := r.fileUploader.Open(key)
+	if err != nil {
+		if errors.Is(err, lib.ErrObjectNotFound) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE04), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return object, nil
+}
+
+// uploadStripped は、メタデータを取り除いた画像をアップロードし、保存した内容のハッシュ値を返す。
+// ストレージのエラーからは取り除く際のエラーを判別できないため、読み込み中のエラーを優先して返す。
+func (r *WorksServiceImpl) uploadStripped(key string, stripped io.ReadCloser, filename string, contentType string) (*digestReader, error) {
+	reader := &errorRecordingReader{reader: stripped}
+	digest := newDigestReader(reader)
+	err := r.fileUploader.Upload(key, digest, filename, contentType)
+	// 読み込み元を参照し終えたことを保証するため、結果を確認する前に閉じる
+	stripped.Close()
+	if err != nil && reader.err != nil {
+		return nil, reader.err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// restageStripped は、アップロード済みのファイルをメタデータを取り除いたもので置き換える。
+// 置き換えると大きさが申告と変わるため、直接アップロードされたものは完了済みとし、再度使用する際に検証しないようにする。
+func (r *WorksServiceImpl) restageStripped(ctx context.Context, field string, u *entities.Upload, stripped io.ReadCloser, contentType string) (*digestReader, error) {
+	digest, err := r.uploadStripped(u.StorageKey, stripped, u.Filename, contentType)
+	if err != nil {
+		if errors.Is(err, lib.ErrUnsupportedImage) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE00), myErr.MessageParams(field), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	if u.CompletedAt != nil {
+		return digest, nil
+	}
+
+	completedAt := time.Now()
+	u.Offset = u.Length
+	u.CompletedAt = &completedAt
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.uploadsRepository.UpdateProgress(ctx, u, u.Chunks)
+	})
+	if err != nil {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+	return digest, nil
+}
+
+// deleteFiles は、補償処理としてアップロード済みのファイルを削除する。
+// 元のエラーを返すため、削除の失敗はログに出力するのみとする。
+func (r *WorksServiceImpl) deleteFiles(keys []string) {
+	for _, key := range keys {
+		if err := r.fileUploader.Delete(key); err != nil {
+			log.Printf("failed to delete %s: %v", key, err)
+		}
+	}
+}
+
+//DeleteByID は、指定したIDの作品を削除する。作者のみが削除できる。
 func (r *WorksServiceImpl) DeleteByID(ctx context.Context, id uint64) error {
-	err := r.transactionRunner.Run(ctx, func(ctx context.Context) error {
-		return r.worksRepository.DeleteByID(ctx, id)
+	w, _, err := findEditableWork(ctx, r.worksRepository, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.transactionRunner.Run(ctx, func(ctx context.Context) error {
+		return r.worksRepository.DeleteByID(ctx, w.ID)
 	})
 
 	if err != nil {
@@ -202,3 +1206,179 @@ func (r *WorksServiceImpl) DeleteByID(ctx context.Context, id uint64) error {
 
 	return nil
 }
+
+// extractSubject は、認証済みのJWTからユーザーIDを取り出す
+func extractSubject(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(userKey).(*jwt.Token)
+	if !ok {
+		return "", false
+	}
+	clm, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", false
+	}
+	sub, ok := clm[subjectKey].(string)
+	return sub, ok
+}
+
+// viewerOf は、閲覧しているユーザーのIDを返す。匿名の場合は空を返す。
+func viewerOf(ctx context.Context) string {
+	sub, _ := extractSubject(ctx)
+	return sub
+}
+
+// hasScope は、認証済みのJWTが指定したscopeを持つかを返す。scopeが空の場合はfalseを返す。
+func hasScope(ctx context.Context, scope string) bool {
+	if scope == "" {
+		return false
+	}
+	token, ok := ctx.Value(userKey).(*jwt.Token)
+	if !ok {
+		return false
+	}
+	clm, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	scopes, _ := clm[scopeKey].(string)
+	for _, v := range strings.Fields(scopes) {
+		if v == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// findViewableWork は、閲覧しているユーザーが閲覧できる作品を取得する。
+// 非公開の作品や公開前の作品、モデレーターが非表示にした作品の有無を作者以外に知らせないよう、閲覧できない場合はWUE01を返す。
+func findViewableWork(ctx context.Context, repo repositories.WorksRepository, id uint64) (*entities.Work, error) {
+	w, err := repo.FindByID(ctx, id)
+	if err != nil {
+		var dbErr *myErr.RecordNotFoundError
+		if errors.As(err, &dbErr) {
+			return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01), myErr.Cause(err))
+		}
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+	}
+
+	hidden := w.Visibility == constants.VisibilityPrivate || w.Status != constants.WorkPublished || w.HiddenAt != nil
+	if hidden && w.AuthorID != viewerOf(ctx) {
+		return nil, myErr.NewApplicationError(myErr.Code(myErr.WUE01))
+	}
+	return w, nil
+}
+
+// findEditableWork は、ログイン中のユーザーが編集する作品を取得する。作者でない場合はWUE02を返す。
+// 戻り値のユーザーIDは、編集するユーザーのもの。
+func findEditableWork(ctx context.Context, repo repositories.WorksRepository, id uint64) (*entities.Work, string, error) {
+	editor, ok := extractSubject(ctx)
+	if !ok {
+		return nil, "", myErr.NewApplicationError(myErr.Code(myErr.WUE99))
+	}
+
+	w, err := findViewableWork(ctx, repo, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if w.AuthorID != editor {
+		return nil, "", myErr.NewApplicationError(myErr.Code(myErr.WUE02))
+	}
+	return w, editor, nil
+}
+
+// signFileURLs は、非公開の作品のファイルのURLを、有効期間のある署名付きURLに置き換える
+func signFileURLs(storage lib.StorageClient, expiration time.Duration, w *entities.Work) error {
+	if w.Visibility != constants.VisibilityPrivate {
+		return nil
+	}
+	return signStorageURLs(storage, expiration, w.Type, &w.ThumbnailURL, &w.ContentURL, w.Thumbnails)
+}
+
+// signStorageURLs は、作品やその履歴のストレージのファイルのURLを、有効期間のある署名付きURLに置き換える
+func signStorageURLs(storage lib.StorageClient, expiration time.Duration, workType constants.WorkType,
+	thumbnailURL *string, contentURL *string, thumbnails entities.ImageVariants) error {
+
+	sign := func(u string) (string, error) {
+		if u == "" {
+			return "", nil
+		}
+		signed, err := storage.SignURL(u, expiration)
+		if err != nil {
+			return "", myErr.NewApplicationError(myErr.Code(myErr.WUE99), myErr.Cause(err))
+		}
+		return signed, nil
+	}
+
+	var err error
+	if *thumbnailURL, err = sign(*thumbnailURL); err != nil {
+		return err
+	}
+	// URLの作品のリンク先は、ストレージのファイルではない
+	if workType == constants.ContentTypeFile {
+		if *contentURL, err = sign(*contentURL); err != nil {
+			return err
+		}
+	}
+	for width, u := range thumbnails {
+		if thumbnails[width], err = sign(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disposableKeys は、フォームで送信されたファイルのキーを返す
+func disposableKeys(files ...*beans.StagedFileBean) []string {
+	var keys []string
+	for _, f := range files {
+		if f != nil && f.UploadID == "" {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys
+}
+
+func stagedKeys(files ...*beans.StagedFileBean) []string {
+	var keys []string
+	for _, f := range files {
+		if f != nil {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys
+}
+
+// errSizeLimitExceeded は、アップロード中のファイルが上限を超えたことを表す
+var errSizeLimitExceeded = errors.New("size limit exceeded")
+
+// errorRecordingReader は、読み込み中に発生したEOF以外のエラーを保持する
+type errorRecordingReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *errorRecordingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// sizeLimitedReader は、読み込んだバイト数を数え、上限を超えた時点でエラーを返す
+type sizeLimitedReader struct {
+	reader   io.Reader
+	limit    int64
+	written  int64
+	exceeded bool
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.written += int64(n)
+	if r.written > r.limit {
+		r.exceeded = true
+		return n, errSizeLimitExceeded
+	}
+	return n, err
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"mime/multipart"
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/edy4c7/works-uploader/internal/beans"
 	"github.com/edy4c7/works-uploader/internal/common/constants"
 	"github.com/edy4c7/works-uploader/internal/entities"
 	myErr "github.com/edy4c7/works-uploader/internal/errors"
+	"github.com/edy4c7/works-uploader/internal/lib"
 	"github.com/edy4c7/works-uploader/internal/mocks"
 	"github.com/edy4c7/works-uploader/internal/repositories"
 	"github.com/form3tech-oss/jwt-go"
@@ -27,80 +32,107 @@ func TestNewWorksServiceImpl(t *testing.T) {
 		actRepo := mocks.NewMockActivitiesRepository(ctrl)
 		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
 		uploader := mocks.NewMockStorageClient(ctrl)
+		deps := worksServiceDeps(ctrl)
+		deps.UploadPolicies = map[string]UploadPolicy{FieldContent: {MaxSize: 1}}
+		deps.VariantWidths = []int{320, 640}
+		deps.Deduplicate = true
+		deps.PrivateURLExpiration = time.Minute
 
-		service := NewWorksServiceImpl(tr, workRepo, actRepo, uuidGenerator, uploader)
+		service := NewWorksServiceImpl(tr, workRepo, actRepo, uuidGenerator, uploader, deps)
 
 		assert.Same(t, service.transactionRunner, tr)
 		assert.Same(t, service.worksRepository, workRepo)
 		assert.Same(t, service.activitiesRepository, actRepo)
+		assert.Same(t, service.uploadsRepository, deps.UploadsRepository)
+		assert.Same(t, service.blobsRepository, deps.BlobsRepository)
+		assert.Same(t, service.scanResultsRepository, deps.ScanResultsRepository)
+		assert.Same(t, service.revisionsRepository, deps.RevisionsRepository)
+		assert.Same(t, service.tagsRepository, deps.TagsRepository)
+		assert.Same(t, service.collectionsRepository, deps.CollectionsRepository)
 		assert.Same(t, service.uuidGenerator, uuidGenerator)
 		assert.Same(t, service.fileUploader, uploader)
+		assert.Same(t, service.imageProcessor, deps.ImageProcessor)
+		assert.Same(t, service.scanner, deps.Scanner)
+		assert.Same(t, service.linkUnfurler, deps.LinkUnfurler)
+		assert.Equal(t, service.uploadPolicies, deps.UploadPolicies)
+		assert.Equal(t, service.variantWidths, deps.VariantWidths)
+		assert.True(t, service.deduplicate)
+		assert.Equal(t, time.Minute, service.privateURLExpiration)
 	})
 
-	t.Run("Transaction runner is nil", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-		workRepo := mocks.NewMockWorksRepository(ctrl)
-		actRepo := mocks.NewMockActivitiesRepository(ctrl)
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uploader := mocks.NewMockStorageClient(ctrl)
-
-		assert.Panics(t, func() {
-			NewWorksServiceImpl(nil, workRepo, actRepo, uuidGenerator, uploader)
-		})
-	})
-
-	t.Run("Works repository is nil", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-		tr := mocks.NewMockTransactionRunner(ctrl)
-		actRepo := mocks.NewMockActivitiesRepository(ctrl)
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uploader := mocks.NewMockStorageClient(ctrl)
-
-		assert.Panics(t, func() {
-			NewWorksServiceImpl(tr, nil, actRepo, uuidGenerator, uploader)
-		})
-	})
-
-	t.Run("Activity repository is nil", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-		tr := mocks.NewMockTransactionRunner(ctrl)
-		workRepo := mocks.NewMockWorksRepository(ctrl)
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uploader := mocks.NewMockStorageClient(ctrl)
-
-		assert.Panics(t, func() {
-			NewWorksServiceImpl(tr, workRepo, nil, uuidGenerator, uploader)
-		})
-	})
-
-	t.Run("UUID generator is nil", func(t *testing.T) {
+	t.Run("Dependencies are nil", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		tr := mocks.NewMockTransactionRunner(ctrl)
 		workRepo := mocks.NewMockWorksRepository(ctrl)
 		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
 		uploader := mocks.NewMockStorageClient(ctrl)
-
-		assert.Panics(t, func() {
-			NewWorksServiceImpl(tr, workRepo, actRepo, nil, uploader)
-		})
+		deps := worksServiceDeps(ctrl)
+
+		tests := []struct {
+			name string
+			new  func()
+		}{
+			{"Transaction runner", func() {
+				NewWorksServiceImpl(nil, workRepo, actRepo, uuidGenerator, uploader, deps)
+			}},
+			{"Works repository", func() {
+				NewWorksServiceImpl(tr, nil, actRepo, uuidGenerator, uploader, deps)
+			}},
+			{"Activity repository", func() {
+				NewWorksServiceImpl(tr, workRepo, nil, uuidGenerator, uploader, deps)
+			}},
+			{"UUID generator", func() {
+				NewWorksServiceImpl(tr, workRepo, actRepo, nil, uploader, deps)
+			}},
+			{"File uploader", func() {
+				NewWorksServiceImpl(tr, workRepo, actRepo, uuidGenerator, nil, deps)
+			}},
+		}
+		without := map[string]func(d *WorksServiceDeps){
+			"Uploads repository":        func(d *WorksServiceDeps) { d.UploadsRepository = nil },
+			"Blobs repository":          func(d *WorksServiceDeps) { d.BlobsRepository = nil },
+			"Scan results repository":   func(d *WorksServiceDeps) { d.ScanResultsRepository = nil },
+			"Work revisions repository": func(d *WorksServiceDeps) { d.RevisionsRepository = nil },
+			"Tags repository":           func(d *WorksServiceDeps) { d.TagsRepository = nil },
+			"Collections repository":    func(d *WorksServiceDeps) { d.CollectionsRepository = nil },
+			"Image processor":           func(d *WorksServiceDeps) { d.ImageProcessor = nil },
+			"Scanner":                   func(d *WorksServiceDeps) { d.Scanner = nil },
+			"Link unfurler":             func(d *WorksServiceDeps) { d.LinkUnfurler = nil },
+		}
+		for name, clear := range without {
+			d := deps
+			clear(&d)
+			tests = append(tests, struct {
+				name string
+				new  func()
+			}{name, func() {
+				NewWorksServiceImpl(tr, workRepo, actRepo, uuidGenerator, uploader, d)
+			}})
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name+" is nil", func(t *testing.T) {
+				assert.Panics(t, tt.new)
+			})
+		}
 	})
+}
 
-	t.Run("File uploader is nil", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-		tr := mocks.NewMockTransactionRunner(ctrl)
-		workRepo := mocks.NewMockWorksRepository(ctrl)
-		actRepo := mocks.NewMockActivitiesRepository(ctrl)
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-
-		assert.Panics(t, func() {
-			NewWorksServiceImpl(tr, workRepo, actRepo, uuidGenerator, nil)
-		})
-	})
+// worksServiceDeps は、全ての依存をモックにしたWorksServiceDepsを返す
+func worksServiceDeps(ctrl *gomock.Controller) WorksServiceDeps {
+	return WorksServiceDeps{
+		UploadsRepository:     mocks.NewMockUploadsRepository(ctrl),
+		BlobsRepository:       mocks.NewMockBlobsRepository(ctrl),
+		ScanResultsRepository: mocks.NewMockScanResultsRepository(ctrl),
+		RevisionsRepository:   mocks.NewMockWorkRevisionsRepository(ctrl),
+		TagsRepository:        mocks.NewMockTagsRepository(ctrl),
+		CollectionsRepository: mocks.NewMockCollectionsRepository(ctrl),
+		ImageProcessor:        mocks.NewMockImageProcessor(ctrl),
+		Scanner:               mocks.NewMockScanner(ctrl),
+		LinkUnfurler:          mocks.NewMockLinkUnfurler(ctrl),
+	}
 }
 
 func TestGetAll(t *testing.T) {
@@ -130,14 +162,14 @@ func TestGetAll(t *testing.T) {
 		total := int64(200)
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
-		worksRepo.EXPECT().CountAll(gomock.Eq(ctx)).Return(total, nil)
-		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), offset, limit).Return(data, nil)
+		worksRepo.EXPECT().CountAll(gomock.Eq(ctx), subject, nil).Return(total, nil)
+		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), subject, nil, constants.OrderDefault, offset, limit).Return(data, nil)
 
 		service := &WorksServiceImpl{
 			worksRepository: worksRepo,
 		}
 
-		result, err := service.GetAll(ctx, offset, limit)
+		result, err := service.GetAll(ctx, nil, "", offset, limit)
 
 		pagination := &beans.PaginationBean{
 			TotalItems: total,
@@ -158,14 +190,14 @@ func TestGetAll(t *testing.T) {
 		errExpect := errors.New("error")
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
-		worksRepo.EXPECT().CountAll(gomock.Eq(ctx)).Return(int64(100), nil)
-		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(nil, errExpect)
+		worksRepo.EXPECT().CountAll(gomock.Eq(ctx), subject, nil).Return(int64(100), nil)
+		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), subject, nil, constants.OrderDefault, gomock.Any(), gomock.Any()).Return(nil, errExpect)
 
 		service := &WorksServiceImpl{
 			worksRepository: worksRepo,
 		}
 
-		result, err := service.GetAll(ctx, 0, 100)
+		result, err := service.GetAll(ctx, nil, "", 0, 100)
 		assert.Nil(t, result)
 		assert.True(t, errors.Is(err, errExpect))
 		var appErr *myErr.ApplicationError
@@ -175,6 +207,67 @@ func TestGetAll(t *testing.T) {
 			assert.Failf(t, "Invalid error type", "%w", err)
 		}
 	})
+
+	t.Run("Filter by tags", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		// タグは正規化してから検索する
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().CountAll(gomock.Eq(ctx), subject, []string{"go", "game"}).Return(int64(0), nil)
+		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), subject, []string{"go", "game"}, constants.OrderDefault, 0, 100).Return([]*entities.Work{}, nil)
+
+		service := &WorksServiceImpl{
+			worksRepository: worksRepo,
+		}
+
+		_, err := service.GetAll(ctx, []string{"#Go", "go,ＧＡＭＥ"}, "", 0, 100)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Too many tags", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &WorksServiceImpl{
+			worksRepository: mocks.NewMockWorksRepository(ctrl),
+		}
+
+		_, err := service.GetAll(ctx, []string{"a,b,c,d,e,f,g,h,i,j,k"}, "", 0, 100)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Sort by favorites", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().CountAll(gomock.Eq(ctx), subject, nil).Return(int64(0), nil)
+		worksRepo.EXPECT().GetAll(gomock.Eq(ctx), subject, nil, constants.OrderMostFavorited, 0, 100).Return([]*entities.Work{}, nil)
+
+		service := &WorksServiceImpl{
+			worksRepository: worksRepo,
+		}
+
+		_, err := service.GetAll(ctx, nil, "favorites", 0, 100)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Invalid sort", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &WorksServiceImpl{
+			worksRepository: mocks.NewMockWorksRepository(ctrl),
+		}
+
+		_, err := service.GetAll(ctx, nil, "views", 0, 100)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
 }
 
 func TestFindByID(t *testing.T) {
@@ -186,23 +279,206 @@ func TestFindByID(t *testing.T) {
 		var id uint64 = 1
 		data := &entities.Work{
 			ID:           id,
+			Status:       constants.WorkPublished,
 			Title:        "hoge",
 			Description:  "hogehoge",
 			ThumbnailURL: "https://example.com",
 			ContentURL:   "https://example.com",
 		}
+		collections := []*entities.Collection{{ID: 2, Title: "fuga"}}
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
 		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), id).Return(data, nil)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), subject, id).Return(collections, nil)
 
 		service := &WorksServiceImpl{
-			worksRepository: worksRepo,
+			worksRepository:       worksRepo,
+			collectionsRepository: collectionsRepo,
 		}
 
 		result, err := service.FindByID(ctx, id)
 
 		assert.Equal(t, data, result)
+		assert.Equal(t, collections, result.Collections)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Private work of the viewer", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		data := &entities.Work{
+			ID:           1,
+			Type:         constants.ContentTypeFile,
+			Visibility:   constants.VisibilityPrivate,
+			AuthorID:     subject,
+			ThumbnailURL: "https://example.com/thumb.png",
+			ContentURL:   "https://example.com/content.zip",
+			Thumbnails:   entities.ImageVariants{"320": "https://example.com/320.jpg"},
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), data.ID).Return(data, nil)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), subject, data.ID).Return([]*entities.Collection{}, nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().SignURL(gomock.Any(), time.Minute).DoAndReturn(func(u string, expires time.Duration) (string, error) {
+			return u + "?signature=abc", nil
+		}).Times(3)
+
+		service := &WorksServiceImpl{
+			worksRepository:       worksRepo,
+			collectionsRepository: collectionsRepo,
+			fileUploader:          fileUploader,
+			privateURLExpiration:  time.Minute,
+		}
+
+		result, err := service.FindByID(ctx, data.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/thumb.png?signature=abc", result.ThumbnailURL)
+		assert.Equal(t, "https://example.com/content.zip?signature=abc", result.ContentURL)
+		assert.Equal(t, entities.ImageVariants{"320": "https://example.com/320.jpg?signature=abc"}, result.Thumbnails)
+	})
+
+	t.Run("Private work of another user", func(t *testing.T) {
+		for name, ctx := range map[string]context.Context{
+			"anonymous": context.Background(),
+			"other":     setupContext(context.Background()),
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				worksRepo := mocks.NewMockWorksRepository(ctrl)
+				worksRepo.EXPECT().FindByID(ctx, uint64(1)).Return(&entities.Work{
+					ID:         1,
+					Visibility: constants.VisibilityPrivate,
+					AuthorID:   "author",
+				}, nil)
+
+				service := &WorksServiceImpl{
+					worksRepository: worksRepo,
+				}
+
+				result, err := service.FindByID(ctx, 1)
+
+				assert.Nil(t, result)
+				var appErr *myErr.ApplicationError
+				if errors.As(err, &appErr) {
+					assert.Equal(t, myErr.WUE01, appErr.Code())
+				} else {
+					assert.Failf(t, "Invalid error type", "%w", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Unlisted work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		data := &entities.Work{
+			ID:           1,
+			Visibility:   constants.VisibilityUnlisted,
+			Status:       constants.WorkPublished,
+			AuthorID:     "author",
+			ThumbnailURL: "https://example.com/thumb.png",
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), data.ID).Return(data, nil)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), "", data.ID).Return([]*entities.Collection{}, nil)
+
+		service := &WorksServiceImpl{
+			worksRepository:       worksRepo,
+			collectionsRepository: collectionsRepo,
+		}
+
+		result, err := service.FindByID(ctx, data.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, data, result)
+	})
+
+	t.Run("Unpublished work of another user", func(t *testing.T) {
+		for _, status := range []constants.WorkStatus{constants.WorkDraft, constants.WorkScheduled} {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			defer ctrl.Finish()
+
+			worksRepo := mocks.NewMockWorksRepository(ctrl)
+			worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+				ID:         1,
+				Visibility: constants.VisibilityPublic,
+				Status:     status,
+				AuthorID:   "author",
+			}, nil)
+
+			service := &WorksServiceImpl{
+				worksRepository: worksRepo,
+			}
+
+			result, err := service.FindByID(ctx, 1)
+
+			assert.Nil(t, result)
+			assertErrorCode(t, myErr.WUE01, err)
+		}
+	})
+
+	t.Run("Hidden work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		hiddenAt := time.Now()
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:         1,
+			Visibility: constants.VisibilityPublic,
+			Status:     constants.WorkPublished,
+			AuthorID:   "author",
+			HiddenAt:   &hiddenAt,
+		}, nil)
+
+		service := &WorksServiceImpl{
+			worksRepository: worksRepo,
+		}
+
+		result, err := service.FindByID(ctx, 1)
+
+		assert.Nil(t, result)
+		assertErrorCode(t, myErr.WUE01, err)
+	})
+
+	t.Run("Draft of the viewer", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		data := &entities.Work{
+			ID:         1,
+			Visibility: constants.VisibilityPublic,
+			Status:     constants.WorkDraft,
+			AuthorID:   subject,
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), data.ID).Return(data, nil)
+		collectionsRepo := mocks.NewMockCollectionsRepository(ctrl)
+		collectionsRepo.EXPECT().FindByWorkID(gomock.Eq(ctx), subject, data.ID).Return([]*entities.Collection{}, nil)
+
+		service := &WorksServiceImpl{
+			worksRepository:       worksRepo,
+			collectionsRepository: collectionsRepo,
+		}
+
+		result, err := service.FindByID(ctx, data.ID)
+
 		assert.Nil(t, err)
+		assert.Equal(t, data, result)
 	})
 
 	t.Run("Not found", func(t *testing.T) {
@@ -283,27 +559,35 @@ func TestCreate(t *testing.T) {
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
 		work := &entities.Work{
 			Type:        form.Type,
+			Visibility:  constants.VisibilityPublic,
+			Status:      constants.WorkPublished,
 			Title:       form.Title,
-			AuthorID:      subject,
+			AuthorID:    subject,
 			Description: form.Description,
 			ContentURL:  form.ContentURL,
+			ScanStatus:  constants.ScanClean,
 			Version:     initialVersion,
 		}
 		worksRepo.EXPECT().Create(gomock.Eq(ctx), work)
 
 		actRepo := mocks.NewMockActivitiesRepository(ctrl)
 		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
-			Type: constants.ActivityAdded,
+			Type:   constants.ActivityAdded,
 			UserID: subject,
-			Work: work,
+			Work:   work,
 		})
 
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), entities.NewWorkRevision(work, subject))
+
 		service := &WorksServiceImpl{
 			uuidGenerator:        uuidGenerator,
 			fileUploader:         fileUploader,
 			transactionRunner:    tranRunner,
 			worksRepository:      worksRepo,
 			activitiesRepository: actRepo,
+			revisionsRepository:  revisionsRepo,
+			linkUnfurler:         withoutPreview(ctrl),
 		}
 
 		res, err := service.Create(ctx, form)
@@ -311,37 +595,21 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, work, res)
 	})
 
-	t.Run("New with file", func(t *testing.T) {
+	t.Run("New with tags", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
 		form := &beans.WorksFormBean{
-			Type:        constants.ContentTypeFile,
+			Type:        constants.ContentTypeURL,
 			Title:       "hoge",
 			Description: "hogehoge",
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
+			ContentURL:  "https://example.com",
+			Tags:        []string{"Go, ART", "#go"},
 		}
 
-		thumbnailFileName := "abcde12345"
-		thumbnailURL := fmt.Sprintf("https://example.com/%s", thumbnailFileName)
-		contentFileName := "fghij67890"
-		contentURL := fmt.Sprintf("https://example.com/%s", contentFileName)
-
 		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uuidGenerator.EXPECT().Generate().Return(thumbnailFileName)
 		fileUploader := mocks.NewMockStorageClient(ctrl)
-		fileUploader.EXPECT().Upload(thumbnailFileName, form.Thumbnail).Return(thumbnailURL, nil)
-
-		uuidGenerator.EXPECT().Generate().Return(contentFileName)
-		fileUploader.EXPECT().Upload(contentFileName, form.Content).Return(contentURL, nil)
 
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		tranRunner.
@@ -353,85 +621,547 @@ func TestCreate(t *testing.T) {
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
 		work := &entities.Work{
-			Type:         form.Type,
-			Title:        form.Title,
-			AuthorID:       subject,
-			Description:  form.Description,
-			ThumbnailURL: thumbnailURL,
-			ContentURL:   contentURL,
-			Version:      initialVersion,
+			Type:        form.Type,
+			Visibility:  constants.VisibilityPublic,
+			Status:      constants.WorkPublished,
+			Title:       form.Title,
+			AuthorID:    subject,
+			Description: form.Description,
+			ContentURL:  form.ContentURL,
+			ScanStatus:  constants.ScanClean,
+			Version:     initialVersion,
 		}
 		worksRepo.EXPECT().Create(gomock.Eq(ctx), work)
 
+		// 正規化し、重複を除いたタグを登録する
+		tags := []*entities.Tag{{Name: "go"}, {Name: "art"}}
+		tagsRepo := mocks.NewMockTagsRepository(ctrl)
+		tagsRepo.EXPECT().ReplaceWorkTags(gomock.Eq(ctx), work.ID, []string{"go", "art"}).
+			DoAndReturn(func(context.Context, uint64, []string) ([]*entities.Tag, error) {
+				work.Tags = tags
+				return tags, nil
+			})
+
 		actRepo := mocks.NewMockActivitiesRepository(ctrl)
 		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
-			Type: constants.ActivityAdded,
+			Type:   constants.ActivityAdded,
 			UserID: subject,
-			Work: work,
+			Work:   work,
 		})
 
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), entities.NewWorkRevision(work, subject))
+
 		service := &WorksServiceImpl{
 			uuidGenerator:        uuidGenerator,
 			fileUploader:         fileUploader,
 			transactionRunner:    tranRunner,
 			worksRepository:      worksRepo,
 			activitiesRepository: actRepo,
+			revisionsRepository:  revisionsRepo,
+			tagsRepository:       tagsRepo,
+			linkUnfurler:         withoutPreview(ctrl),
 		}
 
 		res, err := service.Create(ctx, form)
 		assert.Nil(t, err)
 		assert.Equal(t, work, res)
+		assert.Equal(t, tags, res.Tags)
 	})
 
-	t.Run("Fail to extract token", func(t *testing.T) {
+	t.Run("New with URL preview", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
+		ctx = setupContext(ctx)
 
-		service := &WorksServiceImpl{}
-
-		_, err := service.Create(ctx, nil)
-
-		assert.Error(t, err)
-		var appErr *myErr.ApplicationError
-		if errors.As(err, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", err)
+		// タイトルと説明を省略すると、リンク先のプレビューで補う
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			ContentURL: "https://example.com/works/1",
 		}
-	})
 
-	t.Run("Fail to extract claims", func(t *testing.T) {
-		ctrl, ctx := gomock.WithContext(context.Background(), t)
-		defer ctrl.Finish()
-		//lint:ignore SA1029 can use string only
-		ctx = context.WithValue(ctx, userKey, &jwt.Token{})
+		imageURL := "https://example.com/images/og.png?v=2"
+		linkUnfurler := mocks.NewMockLinkUnfurler(ctrl)
+		linkUnfurler.EXPECT().Unfurl(gomock.Eq(ctx), form.ContentURL).Return(&lib.LinkPreview{
+			Title:       strings.Repeat("題", 41),
+			Description: "説明",
+			ImageURL:    imageURL,
+		}, nil)
+		linkUnfurler.EXPECT().Fetch(gomock.Eq(ctx), imageURL).Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
 
-		service := &WorksServiceImpl{}
+		// プレビューの画像は、サムネイルと同様に一時領域へアップロードしてから公開する
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("preview01")
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.EXPECT().StripMetadata(gomock.Any(), "image/png").Return(nil, false)
+		imageProcessor.EXPECT().Resize(gomock.Any(), gomock.Any()).Return(nil, lib.ErrUnsupportedImage)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("preview01.png", gomock.Any(), "og.png", "image/png").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return err
+			})
+		fileUploader.EXPECT().Open("preview01.png").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil).AnyTimes()
+		fileUploader.EXPECT().URL("preview01.png").Return("https://example.com/preview01.png")
 
-		_, err := service.Create(ctx, nil)
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		committed := tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+		gomock.InOrder(
+			committed,
+			fileUploader.EXPECT().Promote("preview01.png", "preview01.png"),
+		)
 
-		assert.Error(t, err)
-		var appErr *myErr.ApplicationError
-		if errors.As(err, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", err)
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{
+			Key:    "preview01.png",
+			SHA256: sha256Hex([]byte(pngHeader)),
+			Size:   int64(len(pngHeader)),
+		}).Return(1, nil)
+
+		service := &WorksServiceImpl{
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			uuidGenerator:        uuidGenerator,
+			fileUploader:         fileUploader,
+			imageProcessor:       imageProcessor,
+			scanner:              withoutScanning(ctrl),
+			linkUnfurler:         linkUnfurler,
+			uploadPolicies: map[string]UploadPolicy{
+				FieldThumbnail: {MaxSize: 100, AllowedTypes: []string{"image/png"}},
+			},
 		}
+
+		res, err := service.Create(ctx, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, strings.Repeat("題", 40), res.Title)
+		assert.Equal(t, "説明", res.Description)
+		assert.Equal(t, "https://example.com/preview01.png", res.ThumbnailURL)
+		assert.Equal(t, form.ContentURL, res.ContentURL)
+		assert.Equal(t, constants.ScanClean, res.ScanStatus)
+		// 受け取ったフォームは変更しない
+		assert.Empty(t, form.Title)
 	})
 
-	t.Run("Fail to extract subject", func(t *testing.T) {
+	t.Run("Preview is unavailable", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
-		//lint:ignore SA1029 can use string only
-		ctx = context.WithValue(ctx, userKey, &jwt.Token{
-			Claims: make(jwt.MapClaims),
-		})
+		ctx = setupContext(ctx)
 
-		service := &WorksServiceImpl{}
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			Title:      "hoge",
+			ContentURL: "https://example.com",
+		}
 
-		_, err := service.Create(ctx, nil)
+		// プレビューを取得できなくても、サムネイルなしで登録する
+		linkUnfurler := mocks.NewMockLinkUnfurler(ctrl)
+		linkUnfurler.EXPECT().Unfurl(gomock.Any(), form.ContentURL).Return(nil, errors.New("error"))
 
-		assert.Error(t, err)
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &WorksServiceImpl{
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			fileUploader:         mocks.NewMockStorageClient(ctrl),
+			linkUnfurler:         linkUnfurler,
+		}
+
+		res, err := service.Create(ctx, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "hoge", res.Title)
+		assert.Empty(t, res.ThumbnailURL)
+	})
+
+	t.Run("Preview image is not allowed", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			ContentURL: "https://example.com",
+		}
+
+		linkUnfurler := mocks.NewMockLinkUnfurler(ctrl)
+		linkUnfurler.EXPECT().Unfurl(gomock.Any(), form.ContentURL).Return(&lib.LinkPreview{
+			Title:    "hoge",
+			ImageURL: "https://example.com/login",
+		}, nil)
+		linkUnfurler.EXPECT().Fetch(gomock.Any(), "https://example.com/login").
+			Return(ioutil.NopCloser(strings.NewReader("<html></html>")), nil)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &WorksServiceImpl{
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			fileUploader:         mocks.NewMockStorageClient(ctrl),
+			linkUnfurler:         linkUnfurler,
+			uploadPolicies: map[string]UploadPolicy{
+				FieldThumbnail: {MaxSize: 100, AllowedTypes: []string{"image/png"}},
+			},
+		}
+
+		res, err := service.Create(ctx, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "hoge", res.Title)
+		assert.Empty(t, res.ThumbnailURL)
+	})
+
+	t.Run("Title is missing without preview", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			ContentURL: "https://example.com",
+		}
+
+		service := &WorksServiceImpl{
+			fileUploader: mocks.NewMockStorageClient(ctrl),
+			linkUnfurler: withoutPreview(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("New with file", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		thumbnailFileName := "abcde12345"
+		thumbnailURL := fmt.Sprintf("https://example.com/%s", thumbnailFileName)
+		contentFileName := "fghij67890"
+		contentURL := fmt.Sprintf("https://example.com/%s", contentFileName)
+
+		form := &beans.WorksFormBean{
+			Type:        constants.ContentTypeFile,
+			Title:       "hoge",
+			Description: "hogehoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:      thumbnailFileName,
+				Filename: "thumb01",
+				Size:     1,
+				SHA256:   "thumbsha256",
+			},
+			Content: &beans.StagedFileBean{
+				Key:         contentFileName,
+				Filename:    "content01",
+				Size:        1,
+				SHA256:      "contentsha256",
+				ContentType: "application/zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(thumbnailFileName).Return(thumbnailURL)
+		fileUploader.EXPECT().URL(contentFileName).Return(contentURL)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		committed := tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		gomock.InOrder(
+			committed,
+			fileUploader.EXPECT().Promote(thumbnailFileName, thumbnailFileName),
+			fileUploader.EXPECT().Promote(contentFileName, contentFileName),
+		)
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: thumbnailFileName, SHA256: "thumbsha256", Size: 1}).Return(1, nil)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: contentFileName, SHA256: "contentsha256", Size: 1}).Return(1, nil)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		work := &entities.Work{
+			Type:          form.Type,
+			Visibility:    constants.VisibilityPublic,
+			Status:        constants.WorkPublished,
+			Title:         form.Title,
+			AuthorID:      subject,
+			Description:   form.Description,
+			ThumbnailURL:  thumbnailURL,
+			ContentURL:    contentURL,
+			ContentType:   "application/zip",
+			ContentSHA256: "contentsha256",
+			ContentSize:   1,
+			ScanStatus:    constants.ScanPending,
+			Version:       initialVersion,
+		}
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), work)
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), work.ID, constants.ScanClean)
+
+		// 検査したフォームのファイルの結果を記録する
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd"}, nil).Times(2)
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), &entities.ScanResult{
+			Field:   FieldThumbnail,
+			Key:     thumbnailFileName,
+			Scanner: "clamd",
+		})
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), &entities.ScanResult{
+			Field:   FieldContent,
+			Key:     contentFileName,
+			Scanner: "clamd",
+		})
+
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), &entities.Activity{
+			Type:   constants.ActivityAdded,
+			UserID: subject,
+			Work:   work,
+		})
+
+		service := &WorksServiceImpl{
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			activitiesRepository:  actRepo,
+			revisionsRepository:   withRevisions(ctrl),
+			blobsRepository:       blobsRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		// 検査を通過した作品は公開される
+		work.ScanStatus = constants.ScanClean
+		assert.Equal(t, work, res)
+	})
+
+	t.Run("New with uploads", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb.png",
+				Filename: "thumb01.png",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content.zip",
+				Filename: "content01.zip",
+				Size:     1,
+				UploadID: "upload01",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Return("https://example.com/").Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		// 使用したアップロードのみ取り除く
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		consumed := uploadsRepo.EXPECT().DeleteByID(gomock.Eq(ctx), "upload01")
+
+		gomock.InOrder(
+			consumed,
+			fileUploader.EXPECT().Promote("thumb.png", "thumb.png"),
+			fileUploader.EXPECT().Promote("content.zip", "content.zip"),
+		)
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			uploadsRepository:    uploadsRepo,
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+		}
+
+		_, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Upload already used", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key: "thumb.png",
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content.zip",
+				UploadID: "upload01",
+			},
+		}
+
+		expect := myErr.NewRecordNotFoundError("", nil)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Return("https://example.com/").Times(2)
+		// アップロードのIDで指定されたファイルは残す
+		fileUploader.EXPECT().Delete("thumb.png")
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().DeleteByID(gomock.Eq(ctx), "upload01").Return(expect)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			uploadsRepository:    uploadsRepo,
+			blobsRepository:      blobsRepo,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE04, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Fail to extract token", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := &WorksServiceImpl{}
+
+		_, err := service.Create(ctx, nil)
+
+		assert.Error(t, err)
+		var appErr *myErr.ApplicationError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", err)
+		}
+	})
+
+	t.Run("Fail to extract claims", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		//lint:ignore SA1029 can use string only
+		ctx = context.WithValue(ctx, userKey, &jwt.Token{})
+
+		service := &WorksServiceImpl{}
+
+		_, err := service.Create(ctx, nil)
+
+		assert.Error(t, err)
+		var appErr *myErr.ApplicationError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", err)
+		}
+	})
+
+	t.Run("Fail to extract subject", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		//lint:ignore SA1029 can use string only
+		ctx = context.WithValue(ctx, userKey, &jwt.Token{
+			Claims: make(jwt.MapClaims),
+		})
+
+		service := &WorksServiceImpl{}
+
+		_, err := service.Create(ctx, nil)
+
+		assert.Error(t, err)
 		var appErr *myErr.ApplicationError
 		if errors.As(err, &appErr) {
 			assert.Equal(t, myErr.WUE99, appErr.Code())
@@ -440,246 +1170,2540 @@ func TestCreate(t *testing.T) {
 		}
 	})
 
-	t.Run("Fail to upload thumbnail", func(t *testing.T) {
+	t.Run("Fail to run transaction", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Title:       "hoge",
+			Description: "hogehoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb01",
+				Filename: "thumb01",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content01",
+				Filename: "content01",
+				Size:     1,
+			},
+		}
+
+		expect := errors.New("error")
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete(gomock.Any()).AnyTimes()
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return expect
+			})
+
+		service := &WorksServiceImpl{
+			fileUploader:      fileUploader,
+			transactionRunner: tranRunner,
+			linkUnfurler:      withoutPreview(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Fail to save work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Title: "hoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb01",
+				Filename: "thumb01",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content01",
+				Filename: "content01",
+				Size:     1,
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete(gomock.Any()).AnyTimes()
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		expect := errors.New("error")
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+
+		service := &WorksServiceImpl{
+			fileUploader:        fileUploader,
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: withRevisions(ctrl),
+			linkUnfurler:        withoutPreview(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Fail to save activity", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Title: "hoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb01",
+				Filename: "thumb01",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content01",
+				Filename: "content01",
+				Size:     1,
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete(gomock.Any()).AnyTimes()
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		expect := errors.New("error")
+		actRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			linkUnfurler:         withoutPreview(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+	t.Run("Fail to save work with files", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb.png",
+				Filename: "thumb01.png",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content.zip",
+				Filename: "content01.zip",
+				Size:     1,
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		expect := errors.New("error")
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+
+		// 公開せずに、アップロード済みのファイルを削除する
+		fileUploader.EXPECT().Delete("thumb.png")
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:        fileUploader,
+			imageProcessor:      withoutVariants(ctrl, fileUploader),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: withRevisions(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Fail to promote files", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb",
+				Filename: "thumb01",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content",
+				Filename: "content01",
+				Size:     1,
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(3)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, w *entities.Work) { w.ID = 1 })
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Any(), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		expect := errors.New("error")
+		fileUploader.EXPECT().Promote("thumb", "thumb")
+		fileUploader.EXPECT().Promote("content", "content").Return(expect)
+
+		// 登録した作品を取り消し、公開済みのファイルも含めて削除する
+		worksRepo.EXPECT().PurgeByID(gomock.Any(), uint64(1))
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), "thumb").Return(0, nil)
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), "content").Return(0, nil)
+		fileUploader.EXPECT().Delete("thumb")
+		fileUploader.EXPECT().Delete("content")
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Fail to promote files and purge work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key:      "thumb",
+				Filename: "thumb01",
+				Size:     1,
+			},
+			Content: &beans.StagedFileBean{
+				Key:      "content",
+				Filename: "content01",
+				Size:     1,
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(3)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Any(), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		expect := errors.New("error")
+		fileUploader.EXPECT().Promote("thumb", "thumb").Return(expect)
+
+		// 補償処理の失敗ではなく、元のエラーを返す
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).Times(2)
+		worksRepo.EXPECT().PurgeByID(gomock.Any(), gomock.Any()).Return(errors.New("purge error"))
+		fileUploader.EXPECT().Delete("thumb").Return(errors.New("delete error"))
+		fileUploader.EXPECT().Delete("content")
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("New with deduplicated files", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key:    "thumb.png",
+				Size:   1,
+				SHA256: "aaaa",
+			},
+			Content: &beans.StagedFileBean{
+				Key:    "content.zip",
+				Size:   2,
+				SHA256: "bbbb",
+			},
+		}
+
+		// 公開後のキーは、内容のSHA-256と元の拡張子から決まる
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL("aaaa.png").Return("https://example.com/aaaa.png")
+		fileUploader.EXPECT().URL("bbbb.zip").Return("https://example.com/bbbb.zip")
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		// 既に同じ内容が公開されていても、参照を増やして置き換える
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "aaaa.png", SHA256: "aaaa", Size: 1}).Return(3, nil)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "bbbb.zip", SHA256: "bbbb", Size: 2}).Return(1, nil)
+		fileUploader.EXPECT().Promote("thumb.png", "aaaa.png")
+		fileUploader.EXPECT().Promote("content.zip", "bbbb.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+			deduplicate:          true,
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/aaaa.png", res.ThumbnailURL)
+		assert.Equal(t, "https://example.com/bbbb.zip", res.ContentURL)
+		assert.Equal(t, "bbbb", res.ContentSHA256)
+		assert.Equal(t, int64(2), res.ContentSize)
+	})
+
+	t.Run("New private work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeFile,
+			Visibility: constants.VisibilityPrivate,
+			Thumbnail: &beans.StagedFileBean{
+				Key:    "thumb.png",
+				Size:   1,
+				SHA256: "aaaa",
+			},
+			Content: &beans.StagedFileBean{
+				Key:    "content.zip",
+				Size:   2,
+				SHA256: "bbbb",
+			},
+		}
+
+		// 非公開の作品のファイルは、重複排除せずに非公開で保存する
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL("thumb.png").Return("https://example.com/thumb.png")
+		fileUploader.EXPECT().URL("content.zip").Return("https://example.com/content.zip")
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Do(func(ctx context.Context, w *entities.Work) {
+			assert.Equal(t, constants.VisibilityPrivate, w.Visibility)
+			assert.Equal(t, "https://example.com/thumb.png", w.ThumbnailURL)
+		})
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "thumb.png", SHA256: "aaaa", Size: 1}).Return(1, nil)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "content.zip", SHA256: "bbbb", Size: 2}).Return(1, nil)
+		fileUploader.EXPECT().PromotePrivate("thumb.png", "thumb.png")
+		fileUploader.EXPECT().PromotePrivate("content.zip", "content.zip")
+
+		// 登録した作品は、署名付きURLで返す
+		fileUploader.EXPECT().SignURL(gomock.Any(), 15*time.Minute).DoAndReturn(func(u string, expires time.Duration) (string, error) {
+			return u + "?signature=abc", nil
+		}).Times(2)
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+			deduplicate:          true,
+			privateURLExpiration: 15 * time.Minute,
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/thumb.png?signature=abc", res.ThumbnailURL)
+		assert.Equal(t, "https://example.com/content.zip?signature=abc", res.ContentURL)
+	})
+
+	t.Run("New scheduled work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		publishAt := time.Now().Add(time.Hour)
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			Title:      "hoge",
+			ContentURL: "https://example.com",
+			Status:     constants.WorkScheduled,
+			PublishAt:  publishAt,
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		// 公開するまで、作品を追加したアクティビティは登録しない
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &WorksServiceImpl{
+			fileUploader:         mocks.NewMockStorageClient(ctrl),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: mocks.NewMockActivitiesRepository(ctrl),
+			revisionsRepository:  withRevisions(ctrl),
+			linkUnfurler:         withoutPreview(ctrl),
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		assert.Equal(t, constants.WorkScheduled, res.Status)
+		if assert.NotNil(t, res.PublishAt) {
+			assert.True(t, publishAt.Equal(*res.PublishAt))
+		}
+	})
+
+	t.Run("New draft", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			Title:      "hoge",
+			ContentURL: "https://example.com",
+			Status:     constants.WorkDraft,
+			PublishAt:  time.Now().Add(time.Hour),
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &WorksServiceImpl{
+			fileUploader:         mocks.NewMockStorageClient(ctrl),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: mocks.NewMockActivitiesRepository(ctrl),
+			revisionsRepository:  withRevisions(ctrl),
+			linkUnfurler:         withoutPreview(ctrl),
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		assert.Equal(t, constants.WorkDraft, res.Status)
+		assert.Nil(t, res.PublishAt)
+	})
+
+	t.Run("Scheduled in the past is published", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:       constants.ContentTypeURL,
+			Title:      "hoge",
+			ContentURL: "https://example.com",
+			Status:     constants.WorkScheduled,
+			PublishAt:  time.Now().Add(-time.Hour),
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		service := &WorksServiceImpl{
+			fileUploader:         mocks.NewMockStorageClient(ctrl),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			linkUnfurler:         withoutPreview(ctrl),
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, err)
+		assert.Equal(t, constants.WorkPublished, res.Status)
+		assert.Nil(t, res.PublishAt)
+	})
+
+	t.Run("Scheduled without publishAt", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:   constants.ContentTypeFile,
+			Title:  "hoge",
+			Status: constants.WorkScheduled,
+			Content: &beans.StagedFileBean{
+				Key: "content.zip",
+			},
+		}
+
+		// 一時領域のファイルは破棄する
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader: fileUploader,
+		}
+
+		res, err := service.Create(ctx, form)
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Fail to promote deduplicated files", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key:    "thumb.png",
+				SHA256: "aaaa",
+			},
+			Content: &beans.StagedFileBean{
+				Key:    "content.zip",
+				SHA256: "bbbb",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(3)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Any(), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		expect := errors.New("error")
+		fileUploader.EXPECT().Promote("thumb.png", "aaaa.png")
+		fileUploader.EXPECT().Promote("content.zip", "bbbb.zip").Return(expect)
+
+		// 他の作品から参照されている公開済みのファイルは残す
+		worksRepo.EXPECT().PurgeByID(gomock.Any(), gomock.Any())
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), "aaaa.png").Return(1, nil)
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), "bbbb.zip").Return(0, nil)
+		fileUploader.EXPECT().Delete("thumb.png")
+		fileUploader.EXPECT().Delete("content.zip")
+		fileUploader.EXPECT().Delete("bbbb.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:         fileUploader,
+			imageProcessor:       withoutVariants(ctrl, fileUploader),
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			scanner:              withoutScanning(ctrl),
+			deduplicate:          true,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Infected content", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key: "thumb.png",
+			},
+			Content: &beans.StagedFileBean{
+				Key: "content.zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).
+			Do(func(ctx context.Context, w *entities.Work) { w.ID = 1 })
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		scanner := mocks.NewMockScanner(ctrl)
+		gomock.InOrder(
+			scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd"}, nil),
+			scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd", Signature: "Eicar-Test-Signature"}, nil),
+		)
+
+		// 作品を却下し、検出した結果も記録する
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), uint64(1), constants.ScanInfected)
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), &entities.ScanResult{
+			WorkID:  1,
+			Field:   FieldThumbnail,
+			Key:     "thumb.png",
+			Scanner: "clamd",
+		})
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), &entities.ScanResult{
+			WorkID:    1,
+			Field:     FieldContent,
+			Key:       "content.zip",
+			Scanner:   "clamd",
+			Signature: "Eicar-Test-Signature",
+		})
+		blobsRepo.EXPECT().Release(gomock.Eq(ctx), gomock.Any(), "thumb.png").Return(0, nil)
+		blobsRepo.EXPECT().Release(gomock.Eq(ctx), gomock.Any(), "content.zip").Return(0, nil)
+
+		// 検出したファイルは隔離し、公開しない
+		fileUploader.EXPECT().Quarantine("content.zip")
+		fileUploader.EXPECT().Delete("thumb.png")
+
+		service := &WorksServiceImpl{
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			activitiesRepository:  mocks.NewMockActivitiesRepository(ctrl),
+			revisionsRepository:   withRevisions(ctrl),
+			blobsRepository:       blobsRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assertErrorCode(t, myErr.WUE06, actual)
+	})
+
+	t.Run("Fail to quarantine", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key: "thumb.png",
+			},
+			Content: &beans.StagedFileBean{
+				Key: "content.zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Any(), gomock.Any(), constants.ScanInfected)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).Times(2)
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2)
+
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd", Signature: "Eicar-Test-Signature"}, nil).Times(2)
+
+		// 隔離できない場合も、公開されないよう削除する
+		fileUploader.EXPECT().Quarantine("thumb.png").Return(errors.New("error"))
+		fileUploader.EXPECT().Quarantine("content.zip")
+		fileUploader.EXPECT().Delete("thumb.png")
+
+		service := &WorksServiceImpl{
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			revisionsRepository:   withRevisions(ctrl),
+			blobsRepository:       blobsRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assertErrorCode(t, myErr.WUE06, actual)
+	})
+
+	t.Run("Fail to scan", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key: "thumb.png",
+			},
+			Content: &beans.StagedFileBean{
+				Key: "content.zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, w *entities.Work) { w.ID = 1 })
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		expect := errors.New("error")
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(nil, expect)
+
+		// 検査を完了できない場合は、公開できないため作品を取り消す
+		worksRepo.EXPECT().PurgeByID(gomock.Any(), uint64(1))
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).Times(2)
+		fileUploader.EXPECT().Delete("thumb.png")
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:        fileUploader,
+			imageProcessor:      withoutVariants(ctrl, fileUploader),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: withRevisions(ctrl),
+			blobsRepository:     blobsRepo,
+			scanner:             scanner,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Fail to record scan results", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type: constants.ContentTypeFile,
+			Thumbnail: &beans.StagedFileBean{
+				Key: "thumb.png",
+			},
+			Content: &beans.StagedFileBean{
+				Key: "content.zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().URL(gomock.Any()).Times(2)
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(3)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Any(), gomock.Any(), constants.ScanClean)
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd"}, nil).Times(2)
+		expect := errors.New("error")
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+
+		worksRepo.EXPECT().PurgeByID(gomock.Any(), gomock.Any())
+		blobsRepo.EXPECT().Release(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).Times(2)
+		fileUploader.EXPECT().Delete("thumb.png")
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			revisionsRepository:   withRevisions(ctrl),
+			blobsRepository:       blobsRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("New with image content", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		// サムネイルを省略すると、作品の画像を縮小したものを使用する
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Content: &beans.StagedFileBean{
+				Key:         "content.png",
+				Filename:    "photo.png",
+				Size:        10,
+				ContentType: "image/png",
+			},
+		}
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		gomock.InOrder(
+			uuidGenerator.EXPECT().Generate().Return("variant320"),
+			uuidGenerator.EXPECT().Generate().Return("variant640"),
+		)
+
+		object := ioutil.NopCloser(strings.NewReader(pngHeader))
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("content.png").Return(object, nil)
+		fileUploader.EXPECT().Open("content.png").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.EXPECT().Resize(object, []int{320, 640}).Return([]*lib.ImageVariant{
+			{Width: 320, ContentType: "image/jpeg", Body: []byte("320")},
+			{Width: 640, ContentType: "image/jpeg", Body: []byte("640")},
+		}, nil)
+		fileUploader.
+			EXPECT().
+			Upload("variant320.jpg", gomock.Any(), "photo_320.jpg", "image/jpeg").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("320"), b)
+				return err
+			})
+		fileUploader.EXPECT().Upload("variant640.jpg", gomock.Any(), "photo_640.jpg", "image/jpeg")
+		fileUploader.EXPECT().URL(gomock.Any()).DoAndReturn(func(key string) string {
+			return "https://example.com/" + key
+		}).AnyTimes()
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		committed := tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			}).
+			Times(2)
+		gomock.InOrder(
+			committed,
+			fileUploader.EXPECT().Promote("content.png", "content.png"),
+			fileUploader.EXPECT().Promote("variant320.jpg", "variant320.jpg"),
+			fileUploader.EXPECT().Promote("variant640.jpg", "variant640.jpg"),
+		)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		worksRepo.EXPECT().UpdateScanStatus(gomock.Eq(ctx), gomock.Any(), constants.ScanClean)
+		actRepo := mocks.NewMockActivitiesRepository(ctrl)
+		actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
+		// 生成した画像も、生成した内容のSHA-256で登録する
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "content.png", Size: 10}).Return(1, nil)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{
+			Key:    "variant320.jpg",
+			SHA256: "88820462180e5c893eff2ed73f4ec33e205d1cd5acc4d17fa7b2bca2495d3448",
+			Size:   3,
+		}).Return(1, nil)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(1, nil)
+
+		service := &WorksServiceImpl{
+			transactionRunner:    tranRunner,
+			worksRepository:      worksRepo,
+			activitiesRepository: actRepo,
+			revisionsRepository:  withRevisions(ctrl),
+			blobsRepository:      blobsRepo,
+			uuidGenerator:        uuidGenerator,
+			fileUploader:         fileUploader,
+			imageProcessor:       imageProcessor,
+			scanner:              withoutScanning(ctrl),
+			variantWidths:        []int{320, 640},
+		}
+
+		res, err := service.Create(ctx, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/variant320.jpg", res.ThumbnailURL)
+		assert.Equal(t, entities.ImageVariants{
+			"320": "https://example.com/variant320.jpg",
+			"640": "https://example.com/variant640.jpg",
+		}, res.Thumbnails)
+		assert.Equal(t, "https://example.com/content.png", res.ContentURL)
+	})
+
+	t.Run("Thumbnail is required for non-image content", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Content: &beans.StagedFileBean{
+				Key:         "content.zip",
+				Filename:    "content01.zip",
+				ContentType: "application/zip",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			fileUploader:   fileUploader,
+			imageProcessor: mocks.NewMockImageProcessor(ctrl),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("Image content can't be decoded without thumbnail", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Content: &beans.StagedFileBean{
+				Key:         "content.heic",
+				Filename:    "photo.heic",
+				ContentType: "image/heic",
+			},
+		}
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Delete("content.heic")
+
+		service := &WorksServiceImpl{
+			fileUploader:   fileUploader,
+			imageProcessor: withoutVariants(ctrl, fileUploader),
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, lib.ErrUnsupportedImage))
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("Fail to upload variant", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to upload")
+
+		form := &beans.WorksFormBean{
+			Type:  constants.ContentTypeFile,
+			Title: "hoge",
+			Thumbnail: &beans.StagedFileBean{
+				Key:         "thumb.png",
+				Filename:    "thumb01.png",
+				ContentType: "image/png",
+			},
+			Content: &beans.StagedFileBean{
+				Key:         "content.zip",
+				Filename:    "content01.zip",
+				ContentType: "application/zip",
+			},
+		}
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		gomock.InOrder(
+			uuidGenerator.EXPECT().Generate().Return("variant320"),
+			uuidGenerator.EXPECT().Generate().Return("variant640"),
+		)
+
+		// 作品本体が画像でない場合は、サムネイルを縮小する
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("thumb.png").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.EXPECT().Resize(gomock.Any(), gomock.Any()).Return([]*lib.ImageVariant{
+			{Width: 320, ContentType: "image/jpeg", Body: []byte("320")},
+			{Width: 640, ContentType: "image/jpeg", Body: []byte("640")},
+		}, nil)
+		fileUploader.EXPECT().Upload("variant320.jpg", gomock.Any(), "thumb01_320.jpg", "image/jpeg")
+		fileUploader.EXPECT().Upload("variant640.jpg", gomock.Any(), "thumb01_640.jpg", "image/jpeg").Return(expect)
+		// 生成済みのものと、フォームのファイルを削除する
+		fileUploader.EXPECT().Delete("variant640.jpg")
+		fileUploader.EXPECT().Delete("variant320.jpg")
+		fileUploader.EXPECT().Delete("thumb.png")
+		fileUploader.EXPECT().Delete("content.zip")
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: imageProcessor,
+			variantWidths:  []int{320, 640},
+		}
+
+		_, actual := service.Create(ctx, form)
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+}
+
+// withoutVariants は、縮小した画像を生成しない場合の作品の登録で、画像の読み込みに失敗させる
+func withoutVariants(ctrl *gomock.Controller, fileUploader *mocks.MockStorageClient) lib.ImageProcessor {
+	fileUploader.EXPECT().Open(gomock.Any()).Return(ioutil.NopCloser(strings.NewReader("")), nil).AnyTimes()
+	imageProcessor := mocks.NewMockImageProcessor(ctrl)
+	imageProcessor.EXPECT().Resize(gomock.Any(), gomock.Any()).Return(nil, lib.ErrUnsupportedImage).AnyTimes()
+	return imageProcessor
+}
+
+// withoutPreview は、リンク先のプレビューを取得しない設定のLinkUnfurlerを表す
+func withoutPreview(ctrl *gomock.Controller) lib.LinkUnfurler {
+	linkUnfurler := mocks.NewMockLinkUnfurler(ctrl)
+	linkUnfurler.EXPECT().Unfurl(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return linkUnfurler
+}
+
+// withRevisions は、作品の履歴を記録するWorkRevisionsRepositoryを表す
+func withRevisions(ctrl *gomock.Controller) repositories.WorkRevisionsRepository {
+	revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+	revisionsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes()
+	return revisionsRepo
+}
+
+// withoutScanning は、検査しない設定のScannerを表す
+func withoutScanning(ctrl *gomock.Controller) lib.Scanner {
+	scanner := mocks.NewMockScanner(ctrl)
+	scanner.EXPECT().Scan(gomock.Any()).Return(nil, nil).AnyTimes()
+	return scanner
+}
+
+// withoutStripping は、メタデータを取り除く対象でない場合の画像の加工を表す
+func withoutStripping(ctrl *gomock.Controller) lib.ImageProcessor {
+	imageProcessor := mocks.NewMockImageProcessor(ctrl)
+	imageProcessor.EXPECT().StripMetadata(gomock.Any(), gomock.Any()).Return(nil, false).AnyTimes()
+	return imageProcessor
+}
+
+// pngHeader, zipHeader は、Content-Typeの判定に使用されるファイルの先頭
+const pngHeader = "\x89PNG\r\n\x1a\n"
+const zipHeader = "PK\x03\x04"
+
+func TestStage(t *testing.T) {
+	policies := map[string]UploadPolicy{
+		FieldThumbnail: {MaxSize: 600, AllowedTypes: []string{"image/png"}},
+	}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345")
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345.png", gomock.Any(), "thumb01.png", "image/png").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte(pngHeader+"1234"), b)
+				return err
+			})
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: withoutStripping(ctrl),
+			uploadPolicies: policies,
+		}
+
+		res, err := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(pngHeader+"1234"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.StagedFileBean{
+			Key:         "abcde12345.png",
+			Filename:    "thumb01.png",
+			Size:        12,
+			SHA256:      "c9880aff1980992d9f1969638642174d0cfb59db28ea2336eda8648b0b07d6e9",
+			ContentType: "image/png",
+		}, res)
+	})
+
+	t.Run("Type not allowed", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		// 拡張子ではなく内容から判定し、ストレージへは送信しない
+		service := &WorksServiceImpl{
+			fileUploader:   mocks.NewMockStorageClient(ctrl),
+			uploadPolicies: policies,
+		}
+
+		_, actual := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader("%PDF-1.4\n"))
+
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE00, appErr.Code())
+			assert.Equal(t, []interface{}{FieldThumbnail}, appErr.MessageParams())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := &WorksServiceImpl{
+			uploadPolicies: policies,
+		}
+
+		_, actual := service.Stage(ctx, "other", "other.png", strings.NewReader("1234"))
+
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE00, appErr.Code())
+			assert.Equal(t, []interface{}{"other"}, appErr.MessageParams())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("File too large", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345")
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return fmt.Errorf("upload aborted: %w", err)
+			})
+		// 途中まで書き込んだファイルを削除する
+		fileUploader.EXPECT().Delete("abcde12345.png")
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: withoutStripping(ctrl),
+			uploadPolicies: policies,
+		}
+
+		body := pngHeader + strings.Repeat("1", 593)
+		_, actual := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(body))
+
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE03, appErr.Code())
+			assert.Equal(t, []interface{}{FieldThumbnail, int64(600)}, appErr.MessageParams())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("File too large to sniff", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := &WorksServiceImpl{
+			fileUploader: mocks.NewMockStorageClient(ctrl),
+			uploadPolicies: map[string]UploadPolicy{
+				FieldThumbnail: {MaxSize: 4, AllowedTypes: []string{"image/png"}},
+			},
+		}
+
+		_, actual := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(pngHeader))
+
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE03, appErr.Code())
+			assert.Equal(t, []interface{}{FieldThumbnail, int64(4)}, appErr.MessageParams())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+
+	t.Run("Strip metadata", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345")
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.
+			EXPECT().
+			StripMetadata(gomock.Any(), "image/png").
+			DoAndReturn(func(body io.Reader, contentType string) (io.ReadCloser, bool) {
+				b, err := ioutil.ReadAll(body)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte(pngHeader+"1234"), b)
+				return ioutil.NopCloser(strings.NewReader(pngHeader)), true
+			})
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345.png", gomock.Any(), "thumb01.png", "image/png").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte(pngHeader), b)
+				return err
+			})
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: imageProcessor,
+			uploadPolicies: policies,
+		}
+
+		res, err := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(pngHeader+"1234"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "abcde12345.png", res.Key)
+		assert.Equal(t, "image/png", res.ContentType)
+		// 取り除いた後の内容で記録する
+		assert.Equal(t, int64(len(pngHeader)), res.Size)
+		assert.Equal(t, sha256Hex([]byte(pngHeader)), res.SHA256)
+	})
+
+	t.Run("Image is broken", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345")
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.
+			EXPECT().
+			StripMetadata(gomock.Any(), "image/png").
+			Return(ioutil.NopCloser(iotest.ErrReader(lib.ErrUnsupportedImage)), true)
+		// ストレージのクライアントは、読み込み中のエラーを包まずに返す場合がある
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.
+			EXPECT().
+			Upload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				_, err := ioutil.ReadAll(body)
+				return fmt.Errorf("upload aborted: %v", err)
+			})
+		fileUploader.EXPECT().Delete("abcde12345.png")
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: imageProcessor,
+			uploadPolicies: policies,
+		}
+
+		_, actual := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(pngHeader))
+
+		assert.True(t, errors.Is(actual, lib.ErrUnsupportedImage))
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("Fail to upload", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		expect := errors.New("Failed to upload")
+
+		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
+		uuidGenerator.EXPECT().Generate().Return("abcde12345")
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expect)
+		fileUploader.EXPECT().Delete("abcde12345.png").Return(errors.New("delete error"))
+
+		service := &WorksServiceImpl{
+			uuidGenerator:  uuidGenerator,
+			fileUploader:   fileUploader,
+			imageProcessor: withoutStripping(ctrl),
+			uploadPolicies: policies,
+		}
+
+		_, actual := service.Stage(ctx, FieldThumbnail, "thumb01.png", strings.NewReader(pngHeader))
+
+		assert.True(t, errors.Is(actual, expect))
+		var appErr *myErr.ApplicationError
+		if errors.As(actual, &appErr) {
+			assert.Equal(t, myErr.WUE99, appErr.Code())
+		} else {
+			assert.Failf(t, "Invalid error type", "%w", actual)
+		}
+	})
+}
+
+func TestStageUpload(t *testing.T) {
+	policies := map[string]UploadPolicy{
+		FieldContent: {MaxSize: 8, AllowedTypes: []string{"application/zip", "image/*"}},
+	}
+	completedAt := time.Now()
+
+	newUpload := func() *entities.Upload {
+		return &entities.Upload{
+			ID:          "upload01",
+			UserID:      subject,
+			Filename:    "content01.zip",
+			StorageKey:  "abcde12345.zip",
+			Length:      4,
+			Offset:      4,
+			CompletedAt: &completedAt,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader(zipHeader)), nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			imageProcessor:    withoutStripping(ctrl),
+			uploadPolicies:    policies,
+		}
+
+		res, err := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &beans.StagedFileBean{
+			Key:         "abcde12345.zip",
+			Filename:    "content01.zip",
+			Size:        4,
+			SHA256:      "8dcc7e601606217f3b754766511182a916b17e9a26a94c9d887104eba92e9bb2",
+			ContentType: "application/zip",
+			UploadID:    "upload01",
+		}, res)
+	})
+
+	t.Run("Type not allowed", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader("%PDF")), nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("Fail to open", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to open")
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(nil, expect)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		service := &WorksServiceImpl{
+			uploadPolicies: policies,
+		}
+
+		_, actual := service.StageUpload(ctx, "other", "upload01")
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := myErr.NewRecordNotFoundError("", nil)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(nil, expect)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+
+	t.Run("Created by other user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.UserID = "other"
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.ExpiresAt = time.Now().Add(-time.Second)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+
+	t.Run("Not completed", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.Offset = 2
+		upload.CompletedAt = nil
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+		// tusで受信中の場合は、まとめたファイルが存在しない
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Stat("abcde12345.zip").Return(nil, lib.ErrObjectNotFound)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE04, actual)
+	})
+
+	t.Run("Direct upload is valid", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.Offset = 0
+		upload.CompletedAt = nil
+		upload.ContentType = "application/zip"
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Stat("abcde12345.zip").Return(&lib.ObjectInfo{Size: 4, ContentType: "Application/Zip; charset=binary"}, nil)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader(zipHeader)), nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			imageProcessor:    withoutStripping(ctrl),
+			uploadPolicies:    policies,
+		}
+
+		res, err := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "abcde12345.zip", res.Key)
+		assert.Equal(t, "upload01", res.UploadID)
+		assert.Equal(t, "application/zip", res.ContentType)
+	})
+
+	t.Run("Direct upload content differs from declared type", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.Length = 8
+		upload.Offset = 0
+		upload.CompletedAt = nil
+		upload.ContentType = "application/zip"
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+		// 許可された形式でも、申告したContent-Typeと異なるものは受け付けない
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Stat("abcde12345.zip").Return(&lib.ObjectInfo{Size: 8, ContentType: "application/zip"}, nil)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE00, actual)
+	})
+
+	for name, info := range map[string]*lib.ObjectInfo{
+		"Direct upload size mismatch":         {Size: 5, ContentType: "application/zip"},
+		"Direct upload content type mismatch": {Size: 4, ContentType: "text/html"},
+	} {
+		info := info
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			defer ctrl.Finish()
+			ctx = setupContext(ctx)
+
+			upload := newUpload()
+			upload.Offset = 0
+			upload.CompletedAt = nil
+			upload.ContentType = "application/zip"
+
+			uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+			uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+			fileUploader := mocks.NewMockStorageClient(ctrl)
+			fileUploader.EXPECT().Stat("abcde12345.zip").Return(info, nil)
+
+			service := &WorksServiceImpl{
+				uploadsRepository: uploadsRepo,
+				fileUploader:      fileUploader,
+				uploadPolicies:    policies,
+			}
+
+			_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+			assertErrorCode(t, myErr.WUE00, actual)
+		})
+	}
+
+	t.Run("Strip metadata from direct upload", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.Filename = "content01.png"
+		upload.StorageKey = "abcde12345.png"
+		upload.Offset = 0
+		upload.CompletedAt = nil
+		upload.ContentType = "image/png"
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+		// 大きさが変わるため、検証済みとして完了させる
+		uploadsRepo.
+			EXPECT().
+			UpdateProgress(gomock.Eq(ctx), gomock.Any(), 0).
+			DoAndReturn(func(ctx context.Context, u *entities.Upload, expectedChunks int) error {
+				assert.Equal(t, int64(4), u.Offset)
+				assert.NotNil(t, u.CompletedAt)
+				return nil
+			})
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Stat("abcde12345.png").Return(&lib.ObjectInfo{Size: 4, ContentType: "image/png"}, nil)
+		fileUploader.EXPECT().Open("abcde12345.png").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+		fileUploader.
+			EXPECT().
+			Upload("abcde12345.png", gomock.Any(), "content01.png", "image/png").
+			DoAndReturn(func(key string, body io.Reader, filename string, contentType string) error {
+				b, err := ioutil.ReadAll(body)
+				assert.Equal(t, []byte("stripped"), b)
+				return err
+			})
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.
+			EXPECT().
+			StripMetadata(gomock.Any(), "image/png").
+			Return(ioutil.NopCloser(strings.NewReader("stripped")), true)
+
+		service := &WorksServiceImpl{
+			transactionRunner: tranRunner,
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			imageProcessor:    imageProcessor,
+			uploadPolicies:    policies,
+		}
+
+		res, err := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "abcde12345.png", res.Key)
+		assert.Equal(t, "image/png", res.ContentType)
+	})
+
+	t.Run("Strip metadata from completed upload", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+		fileUploader.EXPECT().Upload("abcde12345.zip", gomock.Any(), "content01.zip", "image/png").Return(nil)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.
+			EXPECT().
+			StripMetadata(gomock.Any(), "image/png").
+			Return(ioutil.NopCloser(strings.NewReader("stripped")), true)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			imageProcessor:    imageProcessor,
+			uploadPolicies:    policies,
+		}
+
+		_, err := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Fail to strip metadata", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to upload")
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(newUpload(), nil)
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Open("abcde12345.zip").Return(ioutil.NopCloser(strings.NewReader(pngHeader)), nil)
+		fileUploader.EXPECT().Upload("abcde12345.zip", gomock.Any(), "content01.zip", "image/png").Return(expect)
+		imageProcessor := mocks.NewMockImageProcessor(ctrl)
+		imageProcessor.
+			EXPECT().
+			StripMetadata(gomock.Any(), "image/png").
+			Return(ioutil.NopCloser(strings.NewReader("stripped")), true)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			fileUploader:      fileUploader,
+			imageProcessor:    imageProcessor,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assert.True(t, errors.Is(actual, expect))
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("File too large", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		upload := newUpload()
+		upload.Length = 9
+		upload.Offset = 9
+
+		uploadsRepo := mocks.NewMockUploadsRepository(ctrl)
+		uploadsRepo.EXPECT().FindByID(gomock.Eq(ctx), "upload01").Return(upload, nil)
+
+		service := &WorksServiceImpl{
+			uploadsRepository: uploadsRepo,
+			uploadPolicies:    policies,
+		}
+
+		_, actual := service.StageUpload(ctx, FieldContent, "upload01")
+
+		assertErrorCode(t, myErr.WUE03, actual)
+	})
+}
+
+func TestDiscard(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	fileUploader := mocks.NewMockStorageClient(ctrl)
+	fileUploader.EXPECT().Delete("thumb")
+	fileUploader.EXPECT().Delete("content")
+
+	service := &WorksServiceImpl{
+		fileUploader: fileUploader,
+	}
+
+	service.Discard(
+		ctx,
+		&beans.StagedFileBean{Key: "thumb"},
+		nil,
+		&beans.StagedFileBean{Key: "content"},
+		&beans.StagedFileBean{Key: "uploaded", UploadID: "upload01"},
+	)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("Update URL work", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorkUpdateFormBean{
+			Version:     2,
+			Title:       "fuga",
+			Description: "fugafuga",
+			ContentURL:  "https://example.com/new",
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		work := &entities.Work{
+			ID:          1,
+			Type:        constants.ContentTypeURL,
+			Visibility:  constants.VisibilityPublic,
+			Status:      constants.WorkPublished,
+			AuthorID:    subject,
+			Title:       "hoge",
+			Description: "hogehoge",
+			ContentURL:  "https://example.com",
+			Version:     2,
+		}
+		expect := *work
+		expect.Title = form.Title
+		expect.Description = form.Description
+		expect.ContentURL = form.ContentURL
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(work, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), &expect).DoAndReturn(func(ctx context.Context, w *entities.Work) error {
+			w.Version++
+			return nil
+		})
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, revision *entities.WorkRevision) error {
+			assert.Equal(t, uint(3), revision.Version)
+			assert.Equal(t, form.Title, revision.Title)
+			assert.Equal(t, subject, revision.EditorID)
+			return nil
+		})
+
+		service := &WorksServiceImpl{
+			fileUploader:        mocks.NewMockStorageClient(ctrl),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.Update(ctx, 1, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, form.Title, res.Title)
+		assert.Equal(t, form.ContentURL, res.ContentURL)
+		assert.Equal(t, uint(3), res.Version)
+	})
+
+	t.Run("Title omitted", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorkUpdateFormBean{
+			Version:     2,
+			Description: "fugafuga",
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		work := &entities.Work{
+			ID:          1,
+			Type:        constants.ContentTypeURL,
+			Visibility:  constants.VisibilityPublic,
+			Status:      constants.WorkPublished,
+			AuthorID:    subject,
+			Title:       "hoge",
+			Description: "hogehoge",
+			ContentURL:  "https://example.com",
+			Version:     2,
+		}
+		expect := *work
+		expect.Description = form.Description
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(work, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), &expect).Return(nil)
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Return(nil)
+
+		service := &WorksServiceImpl{
+			fileUploader:        mocks.NewMockStorageClient(ctrl),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+		}
+
+		res, err := service.Update(ctx, 1, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "hoge", res.Title)
+	})
+
+	t.Run("Replace tags", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorkUpdateFormBean{
+			Version: 1,
+			Title:   "hoge",
+			Tags:    []string{"art", "Music"},
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		work := &entities.Work{
+			ID:         1,
+			Type:       constants.ContentTypeURL,
+			AuthorID:   subject,
+			Title:      "hoge",
+			ContentURL: "https://example.com",
+			Tags:       []*entities.Tag{{Name: "music"}, {Name: "go"}},
+			Version:    1,
+		}
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(work, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any())
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+		tags := []*entities.Tag{{Name: "art"}, {Name: "music"}}
+		tagsRepo := mocks.NewMockTagsRepository(ctrl)
+		tagsRepo.EXPECT().ReplaceWorkTags(gomock.Eq(ctx), uint64(1), []string{"art", "music"}).Return(tags, nil)
+
+		service := &WorksServiceImpl{
+			fileUploader:        mocks.NewMockStorageClient(ctrl),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
+			tagsRepository:      tagsRepo,
+		}
+
+		res, err := service.Update(ctx, 1, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, tags, res.Tags)
+	})
+
+	t.Run("Replace content", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		form := &beans.WorkUpdateFormBean{
+			Version: 1,
+			Title:   "hoge",
+			Content: &beans.StagedFileBean{
+				Key:         "content02",
+				Size:        2,
+				SHA256:      "newsha256",
+				ContentType: "application/zip",
+			},
+		}
+
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:            1,
+			Type:          constants.ContentTypeFile,
+			Visibility:    constants.VisibilityPublic,
+			AuthorID:      subject,
+			ThumbnailURL:  "https://example.com/thumb01",
+			ContentURL:    "https://example.com/content01",
+			ContentType:   "application/zip",
+			ContentSHA256: "oldsha256",
+			ContentSize:   1,
+			Version:       1,
+		}, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any())
+
+		// 以前のバージョンのファイルは、履歴から参照するため削除しない
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Promote("content02", "content02")
+		fileUploader.EXPECT().URL("content02").Return("https://example.com/content02")
+
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd"}, nil)
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), &entities.ScanResult{
+			WorkID:  1,
+			Field:   FieldContent,
+			Key:     "content02",
+			Scanner: "clamd",
+		})
+		blobsRepo := mocks.NewMockBlobsRepository(ctrl)
+		blobsRepo.EXPECT().Acquire(gomock.Eq(ctx), gomock.Any(), &entities.Blob{Key: "content02", SHA256: "newsha256", Size: 2}).Return(1, nil)
+
+		service := &WorksServiceImpl{
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			revisionsRepository:   withRevisions(ctrl),
+			blobsRepository:       blobsRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
+		}
+
+		res, err := service.Update(ctx, 1, form)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/thumb01", res.ThumbnailURL)
+		assert.Equal(t, "https://example.com/content02", res.ContentURL)
+		assert.Equal(t, "newsha256", res.ContentSHA256)
+		assert.Equal(t, int64(2), res.ContentSize)
+	})
+
+	t.Run("Version is outdated", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
-		form := &beans.WorksFormBean{
-			Type:        constants.ContentTypeFile,
-			Title:       "hoge",
-			Description: "hogehoge",
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
+		form := &beans.WorkUpdateFormBean{
+			Version: 1,
+			Title:   "hoge",
+			Content: &beans.StagedFileBean{Key: "content02"},
 		}
 
-		expect := errors.New("Failed to upload")
-
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uuidGenerator.EXPECT().Generate()
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeFile,
+			AuthorID: subject,
+			Version:  2,
+		}, nil)
 		fileUploader := mocks.NewMockStorageClient(ctrl)
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).Return("", expect)
+		fileUploader.EXPECT().Delete("content02")
 
 		service := &WorksServiceImpl{
-			uuidGenerator: uuidGenerator,
-			fileUploader:  fileUploader,
+			fileUploader:    fileUploader,
+			worksRepository: worksRepo,
 		}
 
-		_, actual := service.Create(ctx, form)
+		res, err := service.Update(ctx, 1, form)
 
-		assert.True(t, errors.Is(actual, expect))
-		var appErr *myErr.ApplicationError
-		if errors.As(actual, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", actual)
-		}
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE07, err)
 	})
 
-	t.Run("Fail to upload content", func(t *testing.T) {
+	t.Run("Updated by another request", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
-		form := &beans.WorksFormBean{
-			Type:        constants.ContentTypeFile,
-			Title:       "hoge",
-			Description: "hogehoge",
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
+		tranRunner := mocks.NewMockTransactionRunner(ctrl)
+		tranRunner.
+			EXPECT().
+			Run(gomock.Eq(ctx), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+				return tranFunc(ctx)
+			})
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeURL,
+			AuthorID: subject,
+			Version:  1,
+		}, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any()).Return(myErr.NewRecordNotFoundError("", nil))
+
+		service := &WorksServiceImpl{
+			fileUploader:        mocks.NewMockStorageClient(ctrl),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: mocks.NewMockWorkRevisionsRepository(ctrl),
 		}
 
-		expect := errors.New("Failed to upload")
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Title: "hoge"})
 
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uuidGenerator.EXPECT().Generate()
-		fileUploader := mocks.NewMockStorageClient(ctrl)
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).Return("https://example.com", nil)
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE07, err)
+	})
 
-		uuidGenerator.EXPECT().Generate()
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).Return("", expect)
+	t.Run("Not the author", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:         1,
+			Type:       constants.ContentTypeURL,
+			Visibility: constants.VisibilityPublic,
+			Status:     constants.WorkPublished,
+			AuthorID:   "other",
+			Version:    1,
+		}, nil)
 
 		service := &WorksServiceImpl{
-			uuidGenerator: uuidGenerator,
-			fileUploader:  fileUploader,
+			fileUploader:    mocks.NewMockStorageClient(ctrl),
+			worksRepository: worksRepo,
 		}
 
-		_, actual := service.Create(ctx, form)
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Title: "hoge"})
 
-		assert.True(t, errors.Is(actual, expect))
-		var appErr *myErr.ApplicationError
-		if errors.As(actual, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", actual)
-		}
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE02, err)
 	})
 
-	t.Run("Fail to run transaction", func(t *testing.T) {
+	t.Run("Infected content", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
-		form := &beans.WorksFormBean{
-			Title:       "hoge",
-			Description: "hogehoge",
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
+		form := &beans.WorkUpdateFormBean{
+			Version: 1,
+			Title:   "hoge",
+			Content: &beans.StagedFileBean{Key: "content02", ContentType: "application/zip"},
 		}
 
-		expect := errors.New("error")
-
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		uuidGenerator.EXPECT().Generate().AnyTimes()
-		fileUploader := mocks.NewMockStorageClient(ctrl)
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).AnyTimes()
-
+		// 作品は更新せず、検査結果のみ記録する
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		tranRunner.
 			EXPECT().
-			Run(gomock.Any(), gomock.Any()).
+			Run(gomock.Eq(ctx), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
-				return expect
+				return tranFunc(ctx)
 			})
 
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:          1,
+			Type:        constants.ContentTypeFile,
+			AuthorID:    subject,
+			ContentType: "application/zip",
+			Version:     1,
+		}, nil)
+
+		fileUploader := mocks.NewMockStorageClient(ctrl)
+		fileUploader.EXPECT().Quarantine("content02")
+
+		scanner := mocks.NewMockScanner(ctrl)
+		scanner.EXPECT().Scan(gomock.Any()).Return(&lib.ScanVerdict{Scanner: "clamd", Signature: "Eicar-Test-Signature"}, nil)
+		scanResultsRepo := mocks.NewMockScanResultsRepository(ctrl)
+		scanResultsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+
 		service := &WorksServiceImpl{
-			uuidGenerator:     uuidGenerator,
-			fileUploader:      fileUploader,
-			transactionRunner: tranRunner,
+			fileUploader:          fileUploader,
+			imageProcessor:        withoutVariants(ctrl, fileUploader),
+			transactionRunner:     tranRunner,
+			worksRepository:       worksRepo,
+			scanResultsRepository: scanResultsRepo,
+			scanner:               scanner,
 		}
 
-		_, actual := service.Create(ctx, form)
+		res, err := service.Update(ctx, 1, form)
 
-		assert.True(t, errors.Is(actual, expect))
-		var appErr *myErr.ApplicationError
-		if errors.As(actual, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", actual)
-		}
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE06, err)
 	})
 
-	t.Run("Fail to save work", func(t *testing.T) {
+	t.Run("Fail to record revision", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
-		form := &beans.WorksFormBean{
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
-		}
-
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		fileUploader := mocks.NewMockStorageClient(ctrl)
-		uuidGenerator.EXPECT().Generate().AnyTimes()
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).AnyTimes()
-
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		tranRunner.
 			EXPECT().
-			Run(gomock.Any(), gomock.Any()).
+			Run(gomock.Eq(ctx), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
 				return tranFunc(ctx)
 			})
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
-		expect := errors.New("error")
-		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeURL,
+			AuthorID: subject,
+			Version:  1,
+		}, nil)
+		worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any())
+
+		dbErr := errors.New("db error")
+		revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+		revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Return(dbErr)
 
 		service := &WorksServiceImpl{
-			uuidGenerator:     uuidGenerator,
-			fileUploader:      fileUploader,
-			transactionRunner: tranRunner,
-			worksRepository:   worksRepo,
+			fileUploader:        mocks.NewMockStorageClient(ctrl),
+			transactionRunner:   tranRunner,
+			worksRepository:     worksRepo,
+			revisionsRepository: revisionsRepo,
 		}
 
-		_, actual := service.Create(ctx, form)
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Title: "hoge"})
 
-		assert.True(t, errors.Is(actual, expect))
-		var appErr *myErr.ApplicationError
-		if errors.As(actual, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", actual)
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, dbErr))
+		assertErrorCode(t, myErr.WUE99, err)
+	})
+}
+
+func TestUpdatePublication(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		current         constants.WorkStatus
+		status          constants.WorkStatus
+		publishAt       time.Time
+		expectStatus    constants.WorkStatus
+		expectScheduled bool
+		publish         bool
+	}{
+		{name: "Draft to published", current: constants.WorkDraft, status: constants.WorkPublished,
+			expectStatus: constants.WorkPublished, publish: true},
+		{name: "Draft to scheduled", current: constants.WorkDraft, status: constants.WorkScheduled, publishAt: future,
+			expectStatus: constants.WorkScheduled, expectScheduled: true},
+		{name: "Draft scheduled in the past", current: constants.WorkDraft, status: constants.WorkScheduled, publishAt: past,
+			expectStatus: constants.WorkPublished, publish: true},
+		{name: "Scheduled to draft", current: constants.WorkScheduled, status: constants.WorkDraft,
+			expectStatus: constants.WorkDraft},
+		{name: "Status omitted", current: constants.WorkDraft,
+			expectStatus: constants.WorkDraft},
+		{name: "Published stays published", current: constants.WorkPublished, status: constants.WorkPublished,
+			expectStatus: constants.WorkPublished},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			defer ctrl.Finish()
+			ctx = setupContext(ctx)
+
+			tranRunner := mocks.NewMockTransactionRunner(ctrl)
+			tranRunner.
+				EXPECT().
+				Run(gomock.Eq(ctx), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+					return tranFunc(ctx)
+				})
+
+			worksRepo := mocks.NewMockWorksRepository(ctrl)
+			worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+				ID:       1,
+				Type:     constants.ContentTypeURL,
+				Status:   tt.current,
+				AuthorID: subject,
+				Title:    "hoge",
+				Version:  1,
+			}, nil)
+			if tt.publish {
+				worksRepo.EXPECT().Publish(gomock.Eq(ctx), uint64(1))
+			}
+			worksRepo.EXPECT().Update(gomock.Eq(ctx), gomock.Any()).Do(func(_ context.Context, w *entities.Work) {
+				assert.Equal(t, tt.expectStatus, w.Status)
+				if tt.expectScheduled {
+					if assert.NotNil(t, w.PublishAt) {
+						assert.True(t, tt.publishAt.Equal(*w.PublishAt))
+					}
+				} else {
+					assert.Nil(t, w.PublishAt)
+				}
+			})
+			revisionsRepo := mocks.NewMockWorkRevisionsRepository(ctrl)
+			revisionsRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any())
+			actRepo := mocks.NewMockActivitiesRepository(ctrl)
+			if tt.publish {
+				actRepo.EXPECT().Create(gomock.Eq(ctx), gomock.Any()).Do(func(_ context.Context, act *entities.Activity) {
+					assert.Equal(t, constants.ActivityAdded, act.Type)
+					assert.Equal(t, subject, act.UserID)
+				})
+			}
+
+			service := &WorksServiceImpl{
+				fileUploader:         mocks.NewMockStorageClient(ctrl),
+				transactionRunner:    tranRunner,
+				worksRepository:      worksRepo,
+				revisionsRepository:  revisionsRepo,
+				activitiesRepository: actRepo,
+			}
+
+			form := &beans.WorkUpdateFormBean{Version: 1, Status: tt.status, PublishAt: tt.publishAt}
+			res, err := service.Update(ctx, 1, form)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectStatus, res.Status)
+		})
+	}
+
+	t.Run("Published work can't go back", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeURL,
+			Status:   constants.WorkPublished,
+			AuthorID: subject,
+			Version:  1,
+		}, nil)
+
+		service := &WorksServiceImpl{
+			fileUploader:      mocks.NewMockStorageClient(ctrl),
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   worksRepo,
 		}
+
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Status: constants.WorkDraft})
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE00, err)
 	})
 
-	t.Run("Fail to save activity", func(t *testing.T) {
+	t.Run("Scheduled without publishAt", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
 
-		form := &beans.WorksFormBean{
-			Thumbnail: &multipart.FileHeader{
-				Filename: "thumb01",
-				Size:     1,
-			},
-			Content: &multipart.FileHeader{
-				Filename: "content01",
-				Size:     1,
-			},
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeURL,
+			Status:   constants.WorkDraft,
+			AuthorID: subject,
+			Version:  1,
+		}, nil)
+
+		service := &WorksServiceImpl{
+			fileUploader:      mocks.NewMockStorageClient(ctrl),
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   worksRepo,
 		}
 
-		uuidGenerator := mocks.NewMockUUIDGenerator(ctrl)
-		fileUploader := mocks.NewMockStorageClient(ctrl)
-		uuidGenerator.EXPECT().Generate().AnyTimes()
-		fileUploader.EXPECT().Upload(gomock.Any(), gomock.Any()).AnyTimes()
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Status: constants.WorkScheduled})
+
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE00, err)
+	})
+
+	t.Run("Published at the same time", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
 
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		tranRunner.
 			EXPECT().
-			Run(gomock.Any(), gomock.Any()).
+			Run(gomock.Eq(ctx), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
 				return tranFunc(ctx)
 			})
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
-		worksRepo.EXPECT().Create(gomock.Any(), gomock.Any())
-		actRepo := mocks.NewMockActivitiesRepository(ctrl)
-		expect := errors.New("error")
-		actRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(expect)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), uint64(1)).Return(&entities.Work{
+			ID:       1,
+			Type:     constants.ContentTypeURL,
+			Status:   constants.WorkScheduled,
+			AuthorID: subject,
+			Version:  1,
+		}, nil)
+		worksRepo.EXPECT().Publish(gomock.Eq(ctx), uint64(1)).Return(myErr.NewRecordNotFoundError("", nil))
 
 		service := &WorksServiceImpl{
-			uuidGenerator:        uuidGenerator,
-			fileUploader:         fileUploader,
-			transactionRunner:    tranRunner,
-			worksRepository:      worksRepo,
-			activitiesRepository: actRepo,
+			fileUploader:      mocks.NewMockStorageClient(ctrl),
+			transactionRunner: tranRunner,
+			worksRepository:   worksRepo,
 		}
 
-		_, actual := service.Create(ctx, form)
+		res, err := service.Update(ctx, 1, &beans.WorkUpdateFormBean{Version: 1, Status: constants.WorkPublished})
 
-		assert.True(t, errors.Is(actual, expect))
-		var appErr *myErr.ApplicationError
-		if errors.As(actual, &appErr) {
-			assert.Equal(t, myErr.WUE99, appErr.Code())
-		} else {
-			assert.Failf(t, "Invalid error type", "%w", actual)
-		}
+		assert.Nil(t, res)
+		assertErrorCode(t, myErr.WUE07, err)
 	})
 }
 
@@ -700,6 +3724,7 @@ func TestDeleteByID(t *testing.T) {
 			})
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Eq(ctx), id).Return(publicWork(1, subject), nil)
 		worksRepo.EXPECT().DeleteByID(gomock.Eq(ctx), id)
 
 		service := &WorksServiceImpl{
@@ -713,21 +3738,14 @@ func TestDeleteByID(t *testing.T) {
 	t.Run("Not found", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
-
-		tranRunner := mocks.NewMockTransactionRunner(ctrl)
-		tranRunner.
-			EXPECT().
-			Run(gomock.Eq(ctx), gomock.Any()).
-			DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
-				return tranFunc(ctx)
-			})
+		ctx = setupContext(ctx)
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
 		expect := myErr.NewRecordNotFoundError("", nil)
-		worksRepo.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(expect)
+		worksRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(nil, expect)
 
 		service := &WorksServiceImpl{
-			transactionRunner: tranRunner,
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
 			worksRepository:   worksRepo,
 		}
 
@@ -742,7 +3760,62 @@ func TestDeleteByID(t *testing.T) {
 		}
 	})
 
-	t.Run("Fail to find record", func(t *testing.T) {
+	t.Run("Private work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, subject)
+		w.AuthorID = "other"
+		w.Visibility = constants.VisibilityPrivate
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+
+		service := &WorksServiceImpl{
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   worksRepo,
+		}
+
+		actual := service.DeleteByID(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE01, actual)
+	})
+
+	t.Run("Work of another user", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		w := publicWork(1, subject)
+		w.AuthorID = "other"
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Any(), uint64(1)).Return(w, nil)
+
+		service := &WorksServiceImpl{
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   worksRepo,
+		}
+
+		actual := service.DeleteByID(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE02, actual)
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+
+		service := &WorksServiceImpl{
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   mocks.NewMockWorksRepository(ctrl),
+		}
+
+		actual := service.DeleteByID(ctx, 1)
+
+		assertErrorCode(t, myErr.WUE99, actual)
+	})
+
+	t.Run("Deleted while deleting", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
 		ctx = setupContext(ctx)
@@ -755,9 +3828,9 @@ func TestDeleteByID(t *testing.T) {
 				return tranFunc(ctx)
 			})
 
-		expect := errors.New("Failed to delete")
-
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		expect := myErr.NewRecordNotFoundError("", nil)
+		worksRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(publicWork(1, subject), nil)
 		worksRepo.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(expect)
 
 		service := &WorksServiceImpl{
@@ -767,6 +3840,27 @@ func TestDeleteByID(t *testing.T) {
 
 		actual := service.DeleteByID(ctx, 1)
 
+		assert.True(t, errors.Is(actual, expect), "%w", actual)
+		assertErrorCode(t, myErr.WUE01, actual)
+	})
+
+	t.Run("Fail to find record", func(t *testing.T) {
+		ctrl, ctx := gomock.WithContext(context.Background(), t)
+		defer ctrl.Finish()
+		ctx = setupContext(ctx)
+
+		expect := errors.New("Failed to find")
+
+		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(nil, expect)
+
+		service := &WorksServiceImpl{
+			transactionRunner: mocks.NewMockTransactionRunner(ctrl),
+			worksRepository:   worksRepo,
+		}
+
+		actual := service.DeleteByID(ctx, 1)
+
 		assert.True(t, errors.Is(actual, expect))
 		var appErr *myErr.ApplicationError
 		if errors.As(actual, &appErr) {
@@ -779,8 +3873,10 @@ func TestDeleteByID(t *testing.T) {
 	t.Run("Failed to run transaction", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
+		ctx = setupContext(ctx)
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(publicWork(1, subject), nil)
 
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		expect := errors.New("error")
@@ -805,6 +3901,7 @@ func TestDeleteByID(t *testing.T) {
 	t.Run("Failed to delete record", func(t *testing.T) {
 		ctrl, ctx := gomock.WithContext(context.Background(), t)
 		defer ctrl.Finish()
+		ctx = setupContext(ctx)
 
 		tranRunner := mocks.NewMockTransactionRunner(ctrl)
 		expect := errors.New("error")
@@ -816,6 +3913,7 @@ func TestDeleteByID(t *testing.T) {
 			})
 
 		worksRepo := mocks.NewMockWorksRepository(ctrl)
+		worksRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(publicWork(1, subject), nil)
 		worksRepo.EXPECT().DeleteByID(gomock.Eq(ctx), gomock.Any()).Return(expect)
 
 		service := &WorksServiceImpl{
@@ -845,3 +3943,27 @@ func setupContext(ctx context.Context) context.Context {
 		},
 	})
 }
+
+// publicWork は、誰でも閲覧できる公開中の作品を返す
+func publicWork(id uint64, authorID string) *entities.Work {
+	return &entities.Work{
+		ID:         id,
+		Type:       constants.ContentTypeFile,
+		AuthorID:   authorID,
+		Visibility: constants.VisibilityPublic,
+		Status:     constants.WorkPublished,
+	}
+}
+
+// runTransactions は、トランザクション関数をそのまま実行するTransactionRunnerのモックを返す
+func runTransactions(ctrl *gomock.Controller) *mocks.MockTransactionRunner {
+	tranRunner := mocks.NewMockTransactionRunner(ctrl)
+	tranRunner.
+		EXPECT().
+		Run(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tranFunc repositories.TransactionFunction) error {
+			return tranFunc(ctx)
+		}).
+		AnyTimes()
+	return tranRunner
+}
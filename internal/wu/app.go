@@ -1,51 +1,138 @@
 package wu
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	"github.com/edy4c7/works-uploader/internal/config"
-	"github.com/edy4c7/works-uploader/internal/entities"
 	"github.com/edy4c7/works-uploader/internal/i18n"
 	"github.com/edy4c7/works-uploader/internal/lib"
 	"github.com/edy4c7/works-uploader/internal/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	dbConnectInitialBackoff = 1 * time.Second
+	dbConnectMaxBackoff     = 30 * time.Second
+)
+
 //Run run app
-func Run() {
-	r := gin.Default()
+func Run(conf *config.Config) error {
+	ctx, stop := notifyShutdown()
+	defer stop()
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_SCHEMA"),
-		os.Getenv("DB_PORT"),
-	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		DisableForeignKeyConstraintWhenMigrating: true,
-	})
+	db, err := openDatabase(ctx, &conf.Database)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	defer sqlDB.Close()
+
+	if conf.Database.MigrateOnStart {
+		if err := migrateUp(ctx, sqlDB, conf.Database.Driver, log.Writer()); err != nil {
+			return err
+		}
+	}
+
+	jwks := lib.NewJWKS(conf.Auth.JWKSURL)
+	if err := jwks.Load(ctx); err != nil {
+		// 起動は継続し、readinessで未読込を通知する
+		log.Printf("failed to load JWKS: %v", err)
+	}
+
+	state := &shutdownState{}
+	r := NewRouter(conf, db, jwks, map[string]lib.HealthChecker{
+		"jwks":   jwks,
+		"server": state,
+	})
+
+	workers := newWorkerGroup()
+	for _, job := range config.InitJobs(db, conf) {
+		job := job
+		workers.Go(func(ctx context.Context) { runJob(ctx, job) })
+	}
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", conf.Server.Port),
+		Handler:           r,
+		ReadHeaderTimeout: conf.Server.ReadHeaderTimeout,
+		ReadTimeout:       conf.Server.ReadTimeout,
+		WriteTimeout:      conf.Server.WriteTimeout,
+		IdleTimeout:       conf.Server.IdleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
 	}
 
-	db.AutoMigrate(entities.Work{}, entities.Activity{}, entities.User{})
+	log.Println("shutting down...")
+	state.begin()
 
-	auth0Audience := os.Getenv("AUTH0_AUDIENCE")
-	auth0Issuer := os.Getenv("AUTH0_ISSUER")
-	auth0JWK := os.Getenv("AUTH0_JWK")
+	// readinessが受付不可を返してから、ロードバランサーが振り分けを止めるまで接続を受け付け続ける
+	if conf.Server.ShutdownDelay > 0 {
+		log.Printf("waiting %s for load balancers to stop routing traffic", conf.Server.ShutdownDelay)
+		time.Sleep(conf.Server.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := workers.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	log.Println("server stopped")
+
+	return nil
+}
 
-	jwtMiddleware := middlewares.NewJWTMiddleware(auth0Audience, auth0Issuer, auth0JWK)
+// NewRouter は、ミドルウェアとルーティングを設定したgin.Engineを生成する
+func NewRouter(conf *config.Config, db *gorm.DB, jwks *lib.JWKS, checkers map[string]lib.HealthChecker) *gin.Engine {
+	r := gin.Default()
+
+	jwtMiddleware := middlewares.NewJWTMiddleware(conf.Auth.Audience, conf.Auth.Issuer, jwks)
+	optionalJWTMiddleware := middlewares.NewOptionalJWTMiddleware(conf.Auth.Audience, conf.Auth.Issuer, jwks)
 	authorizationMiddleware := middlewares.NewAuthorizationMiddleware(
 		jwtMiddleware, middlewares.SkipAuthorization(func(r *http.Request) bool {
-			return r.Method == http.MethodGet
+			// ストレージへの直接送信と非公開のファイルの配信は、URLの署名で認可する
+			return strings.HasPrefix(r.URL.Path, config.StorageUploadPath+"/")
+		}),
+		// 閲覧は匿名でもでき、ログインしている場合は自分の非公開の作品も対象にする。
+		// ただし、/users/me/以下の自分の情報の閲覧にはログインが必要。
+		// OPTIONSは、tusクライアントが対応するプロトコルを確認するために使用する
+		middlewares.OptionalAuthorization(optionalJWTMiddleware, func(r *http.Request) bool {
+			if strings.Contains(r.URL.Path, "/users/me/") {
+				return false
+			}
+			return r.Method == http.MethodGet || r.Method == http.MethodOptions
 		}),
 	)
 	r.Use(authorizationMiddleware)
@@ -55,7 +142,7 @@ func Run() {
 			authHeaderParts := strings.Split(r.Header.Get("Authorization"), " ")
 			token := authHeaderParts[1]
 
-			return lib.CheckJWTScope(auth0JWK, "access:users", token)
+			return lib.CheckJWTScope(jwks, "access:users", token)
 		}
 
 		return true
@@ -64,11 +151,85 @@ func Run() {
 
 	r.Use(middlewares.NewErrorMiddleware(i18n.NewPrinter()))
 
-	config.InitRoutes(r, db)
+	config.InitRoutes(r, db, conf, checkers)
+
+	return r
+}
+
+// notifyShutdown は、SIGINTまたはSIGTERMを受信した時にキャンセルされるcontextを返す
+func notifyShutdown() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
-	port, err := strconv.Atoi(os.Getenv("PORT"))
+	return ctx, func() {
+		signal.Stop(sig)
+		cancel()
+	}
+}
+
+// openDatabase は、設定されたドライバでDBに接続する
+func openDatabase(ctx context.Context, conf *config.DatabaseConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch conf.Driver {
+	case config.DriverSQLite:
+		dialector = sqlite.Open(conf.DSN())
+	default:
+		dialector = postgres.Open(conf.DSN())
+	}
+
+	db, err := openDB(ctx, dialector, &gorm.Config{}, conf.ConnectMaxAttempts)
 	if err != nil {
-		port = 8000
+		return nil, err
+	}
+
+	if conf.Driver == config.DriverSQLite {
+		// SQLiteは書き込みを直列化するため、コネクションを1つに限定してロック待ちを避ける。
+		// ":memory:"の場合はコネクション毎に別のDBになるため、この設定が必須となる。
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	return db, nil
+}
+
+// openDB は、DBへの接続に失敗した場合、指数関数的に間隔を空けながら再試行する
+func openDB(ctx context.Context, dialector gorm.Dialector, conf *gorm.Config, maxAttempts int) (*gorm.DB, error) {
+	backoff := dbConnectInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(dialector, conf)
+		if err == nil {
+			return db, nil
+		}
+
+		if attempt >= maxAttempts {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("failed to connect to database (attempt %d/%d), retrying in %s: %v",
+			attempt, maxAttempts, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > dbConnectMaxBackoff {
+			backoff = dbConnectMaxBackoff
+		}
 	}
-	r.Run(fmt.Sprintf(":%d", port))
 }
@@ -0,0 +1,109 @@
+package wu
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/edy4c7/works-uploader/internal/config"
+	"github.com/edy4c7/works-uploader/internal/migrations"
+)
+
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+)
+
+//Migrate は、"wu migrate up|down|status" を実行する
+func Migrate(conf *config.Config, command string, out io.Writer) error {
+	ctx, stop := notifyShutdown()
+	defer stop()
+
+	db, err := openDatabase(ctx, &conf.Database)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	switch command {
+	case MigrateUp:
+		return migrateUp(ctx, sqlDB, conf.Database.Driver, out)
+	case MigrateDown:
+		return migrateDown(ctx, sqlDB, conf.Database.Driver, out)
+	case MigrateStatus:
+		return migrateStatus(ctx, sqlDB, conf.Database.Driver, out)
+	default:
+		return fmt.Errorf("unknown migrate command %q: must be one of %s, %s, %s",
+			command, MigrateUp, MigrateDown, MigrateStatus)
+	}
+}
+
+func migrateUp(ctx context.Context, db *sql.DB, driver string, out io.Writer) error {
+	m, err := migrations.NewMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+
+	applied, err := m.Up(ctx)
+	for _, mig := range applied {
+		fmt.Fprintf(out, "applied %04d_%s\n", mig.Version, mig.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(out, "no pending migrations")
+	}
+
+	return nil
+}
+
+func migrateDown(ctx context.Context, db *sql.DB, driver string, out io.Writer) error {
+	m, err := migrations.NewMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+
+	reverted, err := m.Down(ctx)
+	if err != nil {
+		return err
+	}
+	if reverted == nil {
+		fmt.Fprintln(out, "no migrations to revert")
+		return nil
+	}
+
+	fmt.Fprintf(out, "reverted %04d_%s\n", reverted.Version, reverted.Name)
+	return nil
+}
+
+func migrateStatus(ctx context.Context, db *sql.DB, driver string, out io.Writer) error {
+	m, err := migrations.NewMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,46 @@
+package wu
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/edy4c7/works-uploader/internal/beans"
+	"github.com/edy4c7/works-uploader/internal/config"
+)
+
+//Verify は、"wu verify" を実行する。公開済みのファイルを読み込み直し、一致しないものがあればエラーを返す。
+func Verify(conf *config.Config, out io.Writer) error {
+	ctx, stop := notifyShutdown()
+	defer stop()
+
+	db, err := openDatabase(ctx, &conf.Database)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tPROBLEM\tEXPECTED\tACTUAL")
+	failures := 0
+	verified, err := config.InitIntegrityService(db, conf).Verify(ctx, func(f *beans.VerificationFailureBean) {
+		failures++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Key, f.Reason, f.Expected, f.Actual)
+	})
+	if flushErr := w.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	fmt.Fprintf(out, "verified %d files, %d mismatches\n", verified, failures)
+	if err != nil {
+		return err
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d files failed verification", failures)
+	}
+
+	return nil
+}
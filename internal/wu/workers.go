@@ -0,0 +1,88 @@
+package wu
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edy4c7/works-uploader/internal/config"
+)
+
+// workerGroup は、バックグラウンドで動作する処理を管理し、停止時にその終了を待ち合わせる
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkerGroup() *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go は、workerを新しいgoroutineで起動する。workerは渡されたcontextがキャンセルされたら終了すること。
+func (r *workerGroup) Go(worker func(context.Context)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		worker(r.ctx)
+	}()
+}
+
+// Shutdown は、全てのworkerに停止を通知し、終了するかctxがキャンセルされるまで待つ
+func (r *workerGroup) Shutdown(ctx context.Context) error {
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runJob は、停止を通知されるまでjobを一定の間隔で実行する。失敗した場合もログに出力して次回に再試行する。
+func runJob(ctx context.Context, job *config.Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("job %q failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// shutdownState は、停止処理中であることをreadinessに反映する
+type shutdownState struct {
+	shuttingDown int32
+}
+
+func (r *shutdownState) begin() {
+	atomic.StoreInt32(&r.shuttingDown, 1)
+}
+
+func (r *shutdownState) Check(ctx context.Context) error {
+	if atomic.LoadInt32(&r.shuttingDown) != 0 {
+		return errors.New("shutting down")
+	}
+	return nil
+}